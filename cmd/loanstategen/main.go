@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/sswastioyono18/loan-engine/internal/loanstate"
+)
+
+func main() {
+	// Define command-line flags
+	var (
+		migrations = flag.String("migrations", "./migrations", "Directory to add the state trigger migration to")
+		diagram    = flag.String("diagram", "./docs/LOAN_STATE_MACHINE.md", "Markdown file to write the state diagram to")
+		help       = flag.Bool("help", false, "Show help message")
+	)
+
+	flag.Parse()
+
+	if *help {
+		showHelp()
+		return
+	}
+
+	if err := writeMigration(*migrations); err != nil {
+		log.Fatal("Failed to write migration:", err)
+	}
+
+	if err := os.WriteFile(*diagram, []byte(loanstate.Document(loanstate.Transitions)), 0o644); err != nil {
+		log.Fatal("Failed to write diagram:", err)
+	}
+	log.Printf("Wrote %s", *diagram)
+}
+
+// writeMigration adds a new migration to dir when the newest generated one no longer matches
+// the transition table. Applied migrations are never rewritten.
+func writeMigration(dir string) error {
+	generated, err := loanstate.GeneratedMigrations(dir)
+	if err != nil {
+		return err
+	}
+
+	var previous, latest string
+	if n := len(generated); n > 0 {
+		if latest, err = readFile(generated[n-1]); err != nil {
+			return err
+		}
+		if n > 1 {
+			if previous, err = readFile(generated[n-2]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if latest != "" && latest == loanstate.TriggerMigration(loanstate.Transitions, previous) {
+		log.Printf("%s is up to date", generated[len(generated)-1])
+		return nil
+	}
+
+	path, err := loanstate.NextMigrationPath(dir, "loan_state_machine")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(loanstate.TriggerMigration(loanstate.Transitions, latest)); err != nil {
+		return err
+	}
+	log.Printf("Wrote %s", path)
+	return nil
+}
+
+func readFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	return string(content), err
+}
+
+func showHelp() {
+	fmt.Println("Loan State Machine Generator")
+	fmt.Println("")
+	fmt.Println("Writes the database trigger migration and the Mermaid diagram for the")
+	fmt.Println("transition table in internal/loanstate. A new numbered migration is added")
+	fmt.Println("whenever the table has changed since the newest generated one; existing")
+	fmt.Println("migrations are never rewritten.")
+	fmt.Println("")
+	fmt.Println("Usage:")
+	fmt.Println("  loanstategen [options]")
+	fmt.Println("")
+	fmt.Println("Options:")
+	fmt.Println("  -migrations  Migration directory to add to (default: ./migrations)")
+	fmt.Println("  -diagram     Markdown file to write (default: ./docs/LOAN_STATE_MACHINE.md)")
+	fmt.Println("  -help        Show this help message")
+	fmt.Println("")
+	fmt.Println("Examples:")
+	fmt.Println("  go run ./cmd/loanstategen                          # Add a migration if the table changed and rewrite the diagram")
+	fmt.Println("  go run ./cmd/loanstategen -migrations ./db/migrate # Use another migration directory")
+}
@@ -1,14 +1,19 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 
+	"github.com/sswastioyono18/loan-engine/internal/authz"
 	"github.com/sswastioyono18/loan-engine/internal/handlers"
+	"github.com/sswastioyono18/loan-engine/internal/repayment"
 	"github.com/sswastioyono18/loan-engine/internal/repositories"
 	"github.com/sswastioyono18/loan-engine/internal/services"
+	"github.com/sswastioyono18/loan-engine/internal/signing"
 	"github.com/sswastioyono18/loan-engine/pkg/external"
 )
 
@@ -20,7 +25,13 @@ func main() {
 	dbPassword := getEnv("DB_PASSWORD", "loan_engine_password")
 	dbName := getEnv("DB_NAME", "loan_engine_db")
 	dbSslMode := getEnv("DB_SSL_MODE", "disable")
-	jwtSecret := getEnv("JWT_SECRET", "your_jwt_secret_key_here")
+	jwtSecret := getEnv("JWT_SECRET", "")
+	jwtKeysFile := getEnv("JWT_KEYS_FILE", "")
+	repaymentWaterfall := getEnv("REPAYMENT_WATERFALL", "penalty,interest,principal")
+	lateFeeRate := getEnv("LATE_FEE_RATE", "0.05")
+	authzPolicyFile := getEnv("AUTHZ_POLICY_FILE", "")
+	bootstrapAdminEmail := getEnv("BOOTSTRAP_ADMIN_EMAIL", "")
+	validateRequests := getEnv("OPENAPI_VALIDATE_REQUESTS", "false")
 
 	// Build connection string
 	connectionString := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
@@ -33,6 +44,12 @@ func main() {
 	}
 	defer db.Close()
 
+	// Load the access token signing keys; never start with a placeholder secret
+	signingKeys, err := signing.Load(jwtKeysFile, jwtSecret)
+	if err != nil {
+		log.Fatal("Invalid signing key configuration:", err)
+	}
+
 	// Initialize external services (mocks for now)
 	emailService := external.NewMockEmailService()
 	storageService := external.NewMockStorageService()
@@ -42,11 +59,42 @@ func main() {
 		repositories.NewRepositoryFactory(db),
 		emailService,
 		storageService,
-		jwtSecret,
+		signingKeys,
 	)
 
+	// Configure how repayments are allocated
+	serviceFactory.RepaymentPolicy, err = repayment.ParsePolicy(repaymentWaterfall, lateFeeRate)
+	if err != nil {
+		log.Fatal("Invalid REPAYMENT_WATERFALL or LATE_FEE_RATE:", err)
+	}
+
+	// Configure who may perform each action
+	if authzPolicyFile != "" {
+		serviceFactory.AccessPolicy, err = authz.LoadPolicy(authzPolicyFile)
+		if err != nil {
+			log.Fatal("Invalid AUTHZ_POLICY_FILE:", err)
+		}
+	}
+
+	// Create the first admin account; everyone else is created by an admin or registers
+	if bootstrapAdminEmail != "" {
+		if err := serviceFactory.UserService().BootstrapAdmin(context.Background(), bootstrapAdminEmail, ""); err != nil {
+			log.Fatal("Failed to bootstrap admin:", err)
+		}
+	}
+
+	// Optionally check requests against the OpenAPI document
+	var routerOptions []handlers.RouterOption
+	validate, err := strconv.ParseBool(validateRequests)
+	if err != nil {
+		log.Fatal("Invalid OPENAPI_VALIDATE_REQUESTS:", err)
+	}
+	if validate {
+		routerOptions = append(routerOptions, handlers.WithRequestValidation())
+	}
+
 	// Create router
-	router := handlers.NewRouter(serviceFactory)
+	router := handlers.NewRouter(serviceFactory, routerOptions...)
 
 	// Get port from environment or use default
 	port := getEnv("PORT", "8080")
@@ -8,10 +8,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path"
 	"testing"
 	"time"
 
+	"github.com/sswastioyono18/loan-engine/internal/authz"
 	"github.com/sswastioyono18/loan-engine/internal/handlers"
+	"github.com/sswastioyono18/loan-engine/internal/ledger"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/repayment"
 	"github.com/sswastioyono18/loan-engine/internal/repositories"
 	"github.com/sswastioyono18/loan-engine/internal/services"
 	"github.com/sswastioyono18/loan-engine/pkg/external"
@@ -28,40 +33,7 @@ import (
 func TestLoanE2EScenario(t *testing.T) {
 	ctx := context.Background()
 
-	postgresC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
-		ContainerRequest: testcontainers.ContainerRequest{
-			Image:        "postgres:15-alpine",
-			ExposedPorts: []string{"5432/tcp"},
-			Env: map[string]string{
-				"POSTGRES_DB":       "loan_engine_db",
-				"POSTGRES_USER":     "loan_engine_user",
-				"POSTGRES_PASSWORD": "loan_engine_password",
-			},
-			WaitingFor: wait.ForLog("database system is ready to accept connections").WithStartupTimeout(60 * time.Second),
-		},
-		Started: true,
-	})
-	require.NoError(t, err)
-	defer postgresC.Terminate(ctx)
-
-	host, _ := postgresC.Host(ctx)
-	port, _ := postgresC.MappedPort(ctx, "5432")
-
-	os.Setenv("DB_HOST", host)
-	os.Setenv("DB_PORT", port.Port())
-	os.Setenv("DB_USER", "loan_engine_user")
-	os.Setenv("DB_PASSWORD", "loan_engine_password")
-	os.Setenv("DB_NAME", "loan_engine_db")
-	os.Setenv("DB_SSL_MODE", "disable")
-
-	time.Sleep(2 * time.Second)
-
-	db, err := util.InitDB()
-	require.NoError(t, err)
-	defer db.Close()
-
-	require.NoError(t, goose.SetDialect("postgres"))
-	require.NoError(t, goose.Up(db.DB, "./migrations"))
+	db := setupE2EDatabase(t, ctx)
 
 	router := setupE2ERouter(db)
 
@@ -91,8 +63,7 @@ func TestLoanE2EScenario(t *testing.T) {
 
 	// Step 3: Approve Loan (State: proposed → approved)
 	approveResp := postJSON(t, router, fmt.Sprintf("/api/v1/loans/%d/approve", loanID), map[string]interface{}{
-		"field_validator_employee_id": "emp001",
-		"proof_image_url":             "https://example.com/proof.jpg",
+		"proof_image_url": "https://example.com/proof.jpg",
 	})
 	assert.True(t, approveResp["success"].(bool))
 	
@@ -121,12 +92,11 @@ func TestLoanE2EScenario(t *testing.T) {
 	loanResp = getJSON(t, router, fmt.Sprintf("/api/v1/loans/%d", loanID))
 	loanData = loanResp["data"].(map[string]interface{})
 	assert.Equal(t, "invested", loanData["current_state"])
-	assert.Equal(t, 1000000.00, loanData["total_invested_amount"])
-	fmt.Printf("✅ Step 5: Loan invested (State: %s, Amount: %.2f)\n", loanData["current_state"], loanData["total_invested_amount"])
+	assert.Equal(t, "1000000.00", loanData["total_invested_amount"])
+	fmt.Printf("✅ Step 5: Loan invested (State: %s, Amount: %s)\n", loanData["current_state"], loanData["total_invested_amount"])
 
 	// Step 6: Disburse Loan (State: invested → disbursed)
 	disburseResp := postJSON(t, router, fmt.Sprintf("/api/v1/loans/%d/disburse", loanID), map[string]interface{}{
-		"field_officer_employee_id":   "emp002",
 		"agreement_letter_signed_url": "https://example.com/signed-agreement.pdf",
 	})
 	assert.True(t, disburseResp["success"].(bool))
@@ -136,46 +106,53 @@ func TestLoanE2EScenario(t *testing.T) {
 	assert.Equal(t, "disbursed", loanData["current_state"])
 	fmt.Printf("✅ Step 6: Loan disbursed (State: %s)\n", loanData["current_state"])
 
-	fmt.Println("\n🎉 E2E Test Complete: Loan lifecycle from proposed → approved → invested → disbursed")
+	// Step 7: Repayment schedule was generated on disbursement (default 12 months, flat)
+	scheduleResp := getJSON(t, router, fmt.Sprintf("/api/v1/loans/%d/schedule", loanID))
+	schedule := scheduleResp["data"].([]interface{})
+	assert.Len(t, schedule, 12)
+	assert.Equal(t, "87499.99", schedule[0].(map[string]interface{})["total_amount"])
+	fmt.Printf("✅ Step 7: Repayment schedule generated (%d installments)\n", len(schedule))
+
+	// Step 8: Borrower repays the first installment on its due date
+	firstInstallment := schedule[0].(map[string]interface{})
+	repaymentResp := postJSON(t, router, fmt.Sprintf("/api/v1/loans/%d/repayments", loanID), map[string]interface{}{
+		"amount":  firstInstallment["total_amount"],
+		"paid_at": firstInstallment["due_date"],
+	})
+	repaymentData := repaymentResp["data"].(map[string]interface{})
+	assert.Equal(t, firstInstallment["interest_amount"], repaymentData["interest_amount"])
+	assert.Equal(t, firstInstallment["principal_amount"], repaymentData["principal_amount"])
+	assert.Equal(t, "0.00", repaymentData["penalty_amount"])
+
+	scheduleResp = getJSON(t, router, fmt.Sprintf("/api/v1/loans/%d/schedule", loanID))
+	schedule = scheduleResp["data"].([]interface{})
+	assert.Equal(t, "paid", schedule[0].(map[string]interface{})["status"])
+	assert.Equal(t, "pending", schedule[1].(map[string]interface{})["status"])
+	fmt.Printf("✅ Step 8: First installment repaid (%s)\n", repaymentData["amount"])
+
+	// Step 9: The sole investor receives the whole repayment because the ROI exceeds the rate
+	payoutsResp := getJSON(t, router, fmt.Sprintf("/api/v1/investors/%d/payouts", investorID))
+	payouts := payoutsResp["data"].([]interface{})
+	require.Len(t, payouts, 1)
+	assert.Equal(t, repaymentData["amount"], payouts[0].(map[string]interface{})["amount"])
+	fmt.Printf("✅ Step 9: Investor paid out (%s)\n", payouts[0].(map[string]interface{})["amount"])
+
+	// Step 10: The ledger balances: every rupiah received was lent out or paid back out
+	cashResp := getJSON(t, router, "/api/v1/ledger/accounts/cash")
+	assert.Equal(t, "0.00", cashResp["data"].(map[string]interface{})["balance"])
+	receivableResp := getJSON(t, router, "/api/v1/ledger/accounts/loans_receivable")
+	assert.Equal(t, "916666.67", receivableResp["data"].(map[string]interface{})["balance"])
+	journalResp := getJSON(t, router, fmt.Sprintf("/api/v1/loans/%d/journal", loanID))
+	assert.Len(t, journalResp["data"].([]interface{}), 4)
+	fmt.Printf("✅ Step 10: Ledger balanced (loans receivable: %s)\n", receivableResp["data"].(map[string]interface{})["balance"])
+
+	fmt.Println("\n🎉 E2E Test Complete: Loan lifecycle from proposed → approved → invested → disbursed → repaying")
 }
 
 func TestLoanPartialInvestmentScenario(t *testing.T) {
 	ctx := context.Background()
 
-	postgresC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
-		ContainerRequest: testcontainers.ContainerRequest{
-			Image:        "postgres:15-alpine",
-			ExposedPorts: []string{"5432/tcp"},
-			Env: map[string]string{
-				"POSTGRES_DB":       "loan_engine_db",
-				"POSTGRES_USER":     "loan_engine_user",
-				"POSTGRES_PASSWORD": "loan_engine_password",
-			},
-			WaitingFor: wait.ForLog("database system is ready to accept connections").WithStartupTimeout(60 * time.Second),
-		},
-		Started: true,
-	})
-	require.NoError(t, err)
-	defer postgresC.Terminate(ctx)
-
-	host, _ := postgresC.Host(ctx)
-	port, _ := postgresC.MappedPort(ctx, "5432")
-
-	os.Setenv("DB_HOST", host)
-	os.Setenv("DB_PORT", port.Port())
-	os.Setenv("DB_USER", "loan_engine_user")
-	os.Setenv("DB_PASSWORD", "loan_engine_password")
-	os.Setenv("DB_NAME", "loan_engine_db")
-	os.Setenv("DB_SSL_MODE", "disable")
-
-	time.Sleep(2 * time.Second)
-
-	db, err := util.InitDB()
-	require.NoError(t, err)
-	defer db.Close()
-
-	require.NoError(t, goose.SetDialect("postgres"))
-	require.NoError(t, goose.Up(db.DB, "./migrations"))
+	db := setupE2EDatabase(t, ctx)
 
 	router := setupE2ERouter(db)
 
@@ -201,12 +178,11 @@ func TestLoanPartialInvestmentScenario(t *testing.T) {
 	loanData := loanResp["data"].(map[string]interface{})
 	loanID := int(loanData["id"].(float64))
 	assert.Equal(t, "proposed", loanData["current_state"])
-	fmt.Printf("✅ Loan created (ID: %d, Principal: %.2f, State: %s)\n", loanID, loanData["principal_amount"], loanData["current_state"])
+	fmt.Printf("✅ Loan created (ID: %d, Principal: %s, State: %s)\n", loanID, loanData["principal_amount"], loanData["current_state"])
 
 	// Approve Loan
 	postJSON(t, router, fmt.Sprintf("/api/v1/loans/%d/approve", loanID), map[string]interface{}{
-		"field_validator_employee_id": "emp001",
-		"proof_image_url":             "https://example.com/proof.jpg",
+		"proof_image_url": "https://example.com/proof.jpg",
 	})
 	fmt.Printf("✅ Loan approved\n")
 
@@ -238,8 +214,8 @@ func TestLoanPartialInvestmentScenario(t *testing.T) {
 	loanResp = getJSON(t, router, fmt.Sprintf("/api/v1/loans/%d", loanID))
 	loanData = loanResp["data"].(map[string]interface{})
 	assert.Equal(t, "approved", loanData["current_state"])
-	assert.Equal(t, 2000000.00, loanData["total_invested_amount"])
-	fmt.Printf("✅ Partial investment 1: %.2f (State: %s, Total: %.2f/%.2f)\n", 
+	assert.Equal(t, "2000000.00", loanData["total_invested_amount"])
+	fmt.Printf("✅ Partial investment 1: %.2f (State: %s, Total: %s/%s)\n", 
 		2000000.00, loanData["current_state"], loanData["total_invested_amount"], loanData["principal_amount"])
 
 	// Partial Investment 2 (3M out of 5M - completes the loan)
@@ -251,34 +227,117 @@ func TestLoanPartialInvestmentScenario(t *testing.T) {
 	loanResp = getJSON(t, router, fmt.Sprintf("/api/v1/loans/%d", loanID))
 	loanData = loanResp["data"].(map[string]interface{})
 	assert.Equal(t, "invested", loanData["current_state"])
-	assert.Equal(t, 5000000.00, loanData["total_invested_amount"])
-	fmt.Printf("✅ Partial investment 2: %.2f (State: %s, Total: %.2f/%.2f)\n", 
+	assert.Equal(t, "5000000.00", loanData["total_invested_amount"])
+	fmt.Printf("✅ Partial investment 2: %.2f (State: %s, Total: %s/%s)\n", 
 		3000000.00, loanData["current_state"], loanData["total_invested_amount"], loanData["principal_amount"])
 
 	fmt.Println("\n🎉 Partial Investment Test Complete: Loan fully funded by multiple investors")
 }
 
+// setupE2EDatabase starts a throwaway Postgres container, applies the migrations and
+// returns a connection to it. The container is terminated when the test finishes.
+func setupE2EDatabase(t *testing.T, ctx context.Context) *util.DB {
+	postgresC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "postgres:15-alpine",
+			ExposedPorts: []string{"5432/tcp"},
+			Env: map[string]string{
+				"POSTGRES_DB":       "loan_engine_db",
+				"POSTGRES_USER":     "loan_engine_user",
+				"POSTGRES_PASSWORD": "loan_engine_password",
+			},
+			WaitingFor: wait.ForLog("database system is ready to accept connections").WithStartupTimeout(60 * time.Second),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { postgresC.Terminate(ctx) })
+
+	host, _ := postgresC.Host(ctx)
+	port, _ := postgresC.MappedPort(ctx, "5432")
+
+	os.Setenv("DB_HOST", host)
+	os.Setenv("DB_PORT", port.Port())
+	os.Setenv("DB_USER", "loan_engine_user")
+	os.Setenv("DB_PASSWORD", "loan_engine_password")
+	os.Setenv("DB_NAME", "loan_engine_db")
+	os.Setenv("DB_SSL_MODE", "disable")
+
+	time.Sleep(2 * time.Second)
+
+	db, err := util.InitDB()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	require.NoError(t, goose.SetDialect("postgres"))
+	require.NoError(t, goose.Up(db.DB, "./migrations"))
+
+	return db
+}
+
+// e2eOperator is the user e2e requests are made as. Every role the harness uses may perform
+// every action, so the flows exercise the loan lifecycle rather than the permission matrix.
+var e2eOperator = &models.User{ID: 1, UserID: "E2E001", Email: "e2e@example.com", UserType: "e2e_operator", IsActive: true}
+
+// e2eStaff makes the requests to routes that only a particular member of staff may perform,
+// keyed by the last segment of the route
+var e2eStaff = map[string]*models.User{
+	"approve":  {ID: 2, UserID: "EMP001", Email: "validator@example.com", UserType: authz.RoleStaff, StaffRole: authz.RoleFieldValidator, IsActive: true},
+	"disburse": {ID: 3, UserID: "EMP002", Email: "officer@example.com", UserType: authz.RoleStaff, StaffRole: authz.RoleFieldOfficer, IsActive: true},
+}
+
 func setupE2ERouter(db *util.DB) *chi.Mux {
 	borrowerRepo := repositories.NewBorrowerRepository(db)
 	loanRepo := repositories.NewLoanRepository(db)
 	loanApprovalRepo := repositories.NewLoanApprovalRepository(db)
+	loanRejectionRepo := repositories.NewLoanRejectionRepository(db)
+	rejectionReasonRepo := repositories.NewLoanRejectionReasonRepository(db)
 	loanDisbursementRepo := repositories.NewLoanDisbursementRepository(db)
 	investorRepo := repositories.NewInvestorRepository(db)
 	loanInvestmentRepo := repositories.NewLoanInvestmentRepository(db)
 	loanStateHistoryRepo := repositories.NewLoanStateHistoryRepository(db)
+	loanInstallmentRepo := repositories.NewLoanInstallmentRepository(db)
+	loanRepaymentRepo := repositories.NewLoanRepaymentRepository(db)
+	payoutRepo := repositories.NewPayoutRepository(db)
+	platformRevenueRepo := repositories.NewPlatformRevenueRepository(db)
+	unitOfWork := repositories.NewUnitOfWork(db)
+	ledgerRepo := ledger.NewRepository(db)
 
 	emailService := external.NewEmailService()
 	storageService := external.NewStorageService()
 
+	grants := map[authz.Action]authz.Scope{}
+	for _, action := range authz.Actions {
+		grants[action] = authz.ScopeAny
+	}
+	accessPolicy := authz.Policy{
+		e2eOperator.UserType:     grants,
+		authz.RoleFieldValidator: grants,
+		authz.RoleFieldOfficer:   grants,
+	}
+
 	borrowerService := services.NewBorrowerService(borrowerRepo)
-	loanService := services.NewLoanService(loanRepo, loanApprovalRepo, loanDisbursementRepo, loanInvestmentRepo, loanStateHistoryRepo, investorRepo, emailService, storageService)
-	investorService := services.NewInvestorService(investorRepo)
+	ledgerService := ledger.NewService(ledgerRepo)
+	loanService := services.NewLoanService(loanRepo, loanApprovalRepo, loanRejectionRepo, rejectionReasonRepo, loanDisbursementRepo, loanInvestmentRepo, loanStateHistoryRepo, loanInstallmentRepo, borrowerRepo, investorRepo, unitOfWork, ledgerService, emailService, storageService, accessPolicy)
+	investorService := services.NewInvestorService(investorRepo, payoutRepo, authz.DefaultPolicy())
+	repaymentService := services.NewRepaymentService(loanRepo, loanInstallmentRepo, loanRepaymentRepo, borrowerRepo, loanInvestmentRepo, payoutRepo, platformRevenueRepo, unitOfWork, ledgerService, repayment.DefaultPolicy())
 
 	borrowerHandler := handlers.NewBorrowerHandler(borrowerService)
 	loanHandler := handlers.NewLoanHandler(loanService, emailService, storageService)
 	investorHandler := handlers.NewInvestorHandler(investorService)
+	repaymentHandler := handlers.NewRepaymentHandler(repaymentService)
+	ledgerHandler := handlers.NewLedgerHandler(ledgerService)
 
 	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := e2eOperator
+			if staff, ok := e2eStaff[path.Base(r.URL.Path)]; ok {
+				user = staff
+			}
+			next.ServeHTTP(w, r.WithContext(authz.WithUser(r.Context(), user)))
+		})
+	})
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Post("/borrowers", borrowerHandler.CreateBorrower)
 		r.Get("/borrowers/{id}", borrowerHandler.GetBorrowerByID)
@@ -288,8 +347,26 @@ func setupE2ERouter(db *util.DB) *chi.Mux {
 		r.Post("/loans/{id}/approve", loanHandler.ApproveLoan)
 		r.Post("/loans/{id}/invest", loanHandler.InvestInLoan)
 		r.Post("/loans/{id}/disburse", loanHandler.DisburseLoan)
-		
+		r.Post("/loans/{id}/reject", loanHandler.RejectLoan)
+		r.Post("/loans/{id}/cancel", loanHandler.CancelLoan)
+		r.Post("/loans/{id}/expire", loanHandler.ExpireLoan)
+		r.Post("/loans/{id}/mark-repaid", loanHandler.MarkLoanRepaid)
+		r.Post("/loans/{id}/close", loanHandler.CloseLoan)
+		r.Post("/loans/{id}/default", loanHandler.DefaultLoan)
+		r.Post("/loans/{id}/write-off", loanHandler.WriteOffLoan)
+
+		r.Get("/loans/{id}/schedule", loanHandler.GetRepaymentSchedule)
+		r.Post("/loans/{id}/repayments", repaymentHandler.RecordRepayment)
+		r.Get("/loans/{id}/repayments", repaymentHandler.ListRepayments)
+		r.Get("/loans/{id}/journal", ledgerHandler.GetLoanJournal)
+		r.Get("/ledger/accounts", ledgerHandler.ListBalances)
+		r.Get("/ledger/accounts/{code}", ledgerHandler.GetBalance)
+		r.Get("/loan-rejection-reasons", loanHandler.ListRejectionReasons)
+		r.Post("/loan-rejection-reasons", loanHandler.CreateRejectionReason)
+		r.Put("/loan-rejection-reasons/{code}", loanHandler.UpdateRejectionReason)
+
 		r.Post("/investors", investorHandler.CreateInvestor)
+		r.Get("/investors/{id}/payouts", investorHandler.ListPayouts)
 	})
 
 	return r
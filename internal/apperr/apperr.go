@@ -0,0 +1,160 @@
+// Package apperr defines the domain errors repositories and services return, so callers
+// can tell a missing record from a conflict or a refused action with errors.Is and
+// errors.As instead of matching messages, and the HTTP layer can answer with the right
+// status and a stable error code.
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Kind is the category of a domain error, which decides its HTTP status
+type Kind string
+
+const (
+	// KindNotFound means the record does not exist
+	KindNotFound Kind = "not_found"
+	// KindConflict means the request clashes with existing data, such as a duplicate email
+	KindConflict Kind = "conflict"
+	// KindInvalidState means the record is not in a state that allows the action, such as
+	// disbursing a loan that is not invested
+	KindInvalidState Kind = "invalid_state"
+	// KindValidation means the request itself is invalid
+	KindValidation Kind = "validation"
+	// KindUnauthenticated means the caller could not be identified
+	KindUnauthenticated Kind = "unauthenticated"
+	// KindForbidden means the caller may not perform the action
+	KindForbidden Kind = "forbidden"
+	// KindLocked means the account is temporarily locked
+	KindLocked Kind = "locked"
+	// KindInternal means something failed that the caller cannot fix
+	KindInternal Kind = "internal"
+)
+
+// Error is a domain error. Code is a stable, machine-readable identifier such as
+// loan_not_found that clients can rely on; Message is safe to show them. Err is the
+// underlying cause, if any, which is never shown to clients. Fields lists the invalid
+// fields of a validation error, when the error is about specific fields of a request.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Err     error
+	Fields  []FieldError
+}
+
+// FieldError is one invalid field of a request. Pointer is a JSON Pointer (RFC 6901) to the
+// field in the request body, such as /principal_amount.
+type FieldError struct {
+	Pointer string `json:"pointer"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil && e.Kind == KindInternal {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches errors with the same code, so sentinel errors keep matching when wrapped or
+// rebuilt, and matches any error of a kind when the target is one of the kind sentinels
+// such as ErrNotFound
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	if t.Code == "" {
+		return t.Kind == e.Kind
+	}
+	return t.Kind == e.Kind && t.Code == e.Code
+}
+
+// WithCause returns a copy of e caused by err
+func (e *Error) WithCause(err error) *Error {
+	copied := *e
+	copied.Err = err
+	return &copied
+}
+
+// Kind sentinels, for checking only the kind of an error: errors.Is(err, apperr.ErrNotFound)
+var (
+	ErrNotFound        = &Error{Kind: KindNotFound}
+	ErrConflict        = &Error{Kind: KindConflict}
+	ErrInvalidState    = &Error{Kind: KindInvalidState}
+	ErrValidation      = &Error{Kind: KindValidation}
+	ErrUnauthenticated = &Error{Kind: KindUnauthenticated}
+	ErrForbidden       = &Error{Kind: KindForbidden}
+	ErrLocked          = &Error{Kind: KindLocked}
+	ErrInternal        = &Error{Kind: KindInternal}
+)
+
+func newError(kind Kind, code, format string, args []interface{}) *Error {
+	return &Error{Kind: kind, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// NotFound returns a not-found error, for example NotFound("loan_not_found", "loan not found")
+func NotFound(code, format string, args ...interface{}) *Error {
+	return newError(KindNotFound, code, format, args)
+}
+
+func Conflict(code, format string, args ...interface{}) *Error {
+	return newError(KindConflict, code, format, args)
+}
+
+func InvalidState(code, format string, args ...interface{}) *Error {
+	return newError(KindInvalidState, code, format, args)
+}
+
+func Validation(code, format string, args ...interface{}) *Error {
+	return newError(KindValidation, code, format, args)
+}
+
+func Unauthenticated(code, format string, args ...interface{}) *Error {
+	return newError(KindUnauthenticated, code, format, args)
+}
+
+func Forbidden(code, format string, args ...interface{}) *Error {
+	return newError(KindForbidden, code, format, args)
+}
+
+func Locked(code, format string, args ...interface{}) *Error {
+	return newError(KindLocked, code, format, args)
+}
+
+// InvalidFields returns a validation error listing every invalid field of a request
+func InvalidFields(fields []FieldError) *Error {
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = strings.TrimPrefix(field.Pointer, "/") + " " + field.Message
+	}
+	return &Error{Kind: KindValidation, Code: "validation_failed", Message: strings.Join(messages, "; "), Fields: fields}
+}
+
+// Internal wraps err as an internal error described by message
+func Internal(err error, message string) *Error {
+	return &Error{Kind: KindInternal, Code: "internal_error", Message: message, Err: err}
+}
+
+// From returns the outermost domain error in err's chain. Errors that are not domain
+// errors are reported as internal errors, since nothing says the caller can fix them.
+func From(err error) *Error {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+	return Internal(err, "internal error")
+}
+
+// KindOf returns the kind of err, KindInternal for errors that are not domain errors
+func KindOf(err error) Kind {
+	return From(err).Kind
+}
@@ -0,0 +1,72 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsMatchesCodeAndKind(t *testing.T) {
+	errLoanNotFound := NotFound("loan_not_found", "loan not found")
+	wrapped := fmt.Errorf("failed to approve loan: %w", errLoanNotFound)
+
+	assert.ErrorIs(t, wrapped, errLoanNotFound)
+	assert.ErrorIs(t, wrapped, ErrNotFound)
+	// A rebuilt error with the same code still matches
+	assert.ErrorIs(t, wrapped, NotFound("loan_not_found", "loan 7 not found"))
+
+	assert.NotErrorIs(t, wrapped, NotFound("borrower_not_found", "borrower not found"))
+	assert.NotErrorIs(t, wrapped, ErrConflict)
+}
+
+func TestFrom(t *testing.T) {
+	cause := errors.New("connection refused")
+
+	t.Run("finds the domain error in the chain", func(t *testing.T) {
+		err := fmt.Errorf("failed to invest: %w", Conflict("already_invested", "investor already invested in this loan"))
+
+		domainErr := From(err)
+
+		assert.Equal(t, KindConflict, domainErr.Kind)
+		assert.Equal(t, "already_invested", domainErr.Code)
+	})
+
+	t.Run("treats other errors as internal", func(t *testing.T) {
+		domainErr := From(cause)
+
+		assert.Equal(t, KindInternal, domainErr.Kind)
+		assert.Equal(t, "internal_error", domainErr.Code)
+		assert.ErrorIs(t, domainErr, cause)
+	})
+
+	t.Run("keeps the cause out of client messages", func(t *testing.T) {
+		err := Conflict("duplicate_record", "a record with this email already exists").WithCause(cause)
+
+		assert.Equal(t, "a record with this email already exists", err.Error())
+		assert.ErrorIs(t, err, cause)
+	})
+}
+
+func TestAs(t *testing.T) {
+	err := fmt.Errorf("refusing: %w", Validation("invalid_loan", "rate must be between %d and %d", 0, 1))
+
+	var domainErr *Error
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "rate must be between 0 and 1", domainErr.Message)
+	assert.Equal(t, KindValidation, KindOf(err))
+}
+
+func TestInvalidFields(t *testing.T) {
+	err := InvalidFields([]FieldError{
+		{Pointer: "/principal_amount", Code: "out_of_range", Message: "must be greater than 0"},
+		{Pointer: "/rate", Code: "out_of_range", Message: "must be between 0 and 1"},
+	})
+
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.Equal(t, "validation_failed", err.Code)
+	assert.EqualError(t, err, "principal_amount must be greater than 0; rate must be between 0 and 1")
+	assert.Len(t, From(fmt.Errorf("invalid loan: %w", err)).Fields, 2)
+}
@@ -0,0 +1,301 @@
+// Package authz decides which users may perform which actions. A Policy maps each role to
+// the actions it may perform and whether it may perform them on any resource or only on
+// its own, so the matrix can be changed by loading a different policy file.
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+// Action names something a user can do
+type Action string
+
+const (
+	ReadBorrowers          Action = "borrowers:read"
+	ManageBorrowers        Action = "borrowers:manage"
+	ReadInvestors          Action = "investors:read"
+	ManageInvestors        Action = "investors:manage"
+	ReadPayouts            Action = "payouts:read"
+	ReadLoans              Action = "loans:read"
+	ManageLoans            Action = "loans:manage"
+	ApproveLoan            Action = "loans:approve"
+	InvestInLoan           Action = "loans:invest"
+	DisburseLoan           Action = "loans:disburse"
+	RejectLoan             Action = "loans:reject"
+	CancelLoan             Action = "loans:cancel"
+	ExpireLoan             Action = "loans:expire"
+	MarkLoanRepaid         Action = "loans:mark_repaid"
+	CloseLoan              Action = "loans:close"
+	DefaultLoan            Action = "loans:default"
+	WriteOffLoan           Action = "loans:write_off"
+	ReadRejectionReasons   Action = "rejection_reasons:read"
+	ManageRejectionReasons Action = "rejection_reasons:manage"
+	ReadRepayments         Action = "repayments:read"
+	RecordRepayments       Action = "repayments:record"
+	ReadLedger             Action = "ledger:read"
+	ReadUsers              Action = "users:read"
+	ManageUsers            Action = "users:manage"
+	ManageAPIKeys          Action = "api_keys:manage"
+)
+
+// Actions lists every action a policy can grant
+var Actions = []Action{
+	ReadBorrowers, ManageBorrowers,
+	ReadInvestors, ManageInvestors, ReadPayouts,
+	ReadLoans, ManageLoans,
+	ApproveLoan, InvestInLoan, DisburseLoan,
+	RejectLoan, CancelLoan, ExpireLoan, MarkLoanRepaid, CloseLoan, DefaultLoan, WriteOffLoan,
+	ReadRejectionReasons, ManageRejectionReasons,
+	ReadRepayments, RecordRepayments,
+	ReadLedger,
+	ReadUsers, ManageUsers,
+	ManageAPIKeys,
+}
+
+// APIKeyScopes maps each scope an API key can be given to the actions it allows. Keys only
+// ever create and read records: loan decisions, investing, user management and managing
+// keys need a signed-in user.
+var APIKeyScopes = map[string][]Action{
+	"borrowers:read":   {ReadBorrowers},
+	"borrowers:write":  {ReadBorrowers, ManageBorrowers},
+	"investors:read":   {ReadInvestors, ReadPayouts},
+	"investors:write":  {ReadInvestors, ReadPayouts, ManageInvestors},
+	"loans:read":       {ReadLoans, ReadRejectionReasons},
+	"loans:write":      {ReadLoans, ReadRejectionReasons, ManageLoans},
+	"repayments:read":  {ReadRepayments},
+	"repayments:write": {ReadRepayments, RecordRepayments},
+	"ledger:read":      {ReadLedger},
+}
+
+// ValidateAPIKeyScopes returns an error when scopes is empty or names an unknown scope
+func ValidateAPIKeyScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return apperr.Validation("invalid_scopes", "an API key needs at least one scope")
+	}
+
+	for _, scope := range scopes {
+		if _, ok := APIKeyScopes[scope]; !ok {
+			return apperr.Validation("invalid_scopes", "unknown API key scope %s", scope)
+		}
+	}
+
+	return nil
+}
+
+// Roles. Staff users act as their staff role when they have one.
+const (
+	RoleAdmin          = "admin"
+	RoleInvestor       = "investor"
+	RoleStaff          = "staff"
+	RoleFieldValidator = "field_validator"
+	RoleFieldOfficer   = "field_officer"
+)
+
+// Scope limits which resources a role may perform an action on
+type Scope string
+
+const (
+	// ScopeAny allows the action on every resource
+	ScopeAny Scope = "any"
+	// ScopeOwn allows the action only on resources the user owns
+	ScopeOwn Scope = "own"
+)
+
+var (
+	// ErrUnauthenticated is returned when no user is attached to the request
+	ErrUnauthenticated = apperr.Unauthenticated("authentication_required", "authentication required")
+	// ErrForbidden is returned when the user may not perform the action
+	ErrForbidden = apperr.Forbidden("permission_denied", "permission denied")
+)
+
+// Resource is what an action is performed on, as far as ownership is concerned
+type Resource struct {
+	// OwnerInvestorID is the ID of the investor the resource belongs to, if any
+	OwnerInvestorID int
+}
+
+// OwnedBy reports whether resource belongs to user, through the investor record linked to
+// the user's account
+func (r *Resource) OwnedBy(user *models.User) bool {
+	return r.OwnerInvestorID != 0 && user.InvestorID != nil && *user.InvestorID == r.OwnerInvestorID
+}
+
+// Policy is the permission matrix: the scope in which each role may perform each action.
+// Actions missing from a role's entry are denied.
+type Policy map[string]map[Action]Scope
+
+// DefaultPolicy lets field validators approve, field officers disburse, investors invest
+// as themselves and read their own payouts, and admins manage users, borrowers, investors and the rest of the loan lifecycle
+func DefaultPolicy() Policy {
+	return Policy{
+		RoleAdmin: {
+			ReadBorrowers:          ScopeAny,
+			ManageBorrowers:        ScopeAny,
+			ReadInvestors:          ScopeAny,
+			ManageInvestors:        ScopeAny,
+			ReadPayouts:            ScopeAny,
+			ReadLoans:              ScopeAny,
+			ManageLoans:            ScopeAny,
+			RejectLoan:             ScopeAny,
+			CancelLoan:             ScopeAny,
+			ExpireLoan:             ScopeAny,
+			MarkLoanRepaid:         ScopeAny,
+			CloseLoan:              ScopeAny,
+			DefaultLoan:            ScopeAny,
+			WriteOffLoan:           ScopeAny,
+			ReadRejectionReasons:   ScopeAny,
+			ManageRejectionReasons: ScopeAny,
+			ReadRepayments:         ScopeAny,
+			RecordRepayments:       ScopeAny,
+			ReadLedger:             ScopeAny,
+			ReadUsers:              ScopeAny,
+			ManageUsers:            ScopeAny,
+			ManageAPIKeys:          ScopeAny,
+		},
+		RoleStaff: {
+			ReadBorrowers:        ScopeAny,
+			ReadLoans:            ScopeAny,
+			ManageLoans:          ScopeAny,
+			ReadRejectionReasons: ScopeAny,
+			ReadRepayments:       ScopeAny,
+			RecordRepayments:     ScopeAny,
+		},
+		RoleFieldValidator: {
+			ReadBorrowers:        ScopeAny,
+			ReadLoans:            ScopeAny,
+			ApproveLoan:          ScopeAny,
+			RejectLoan:           ScopeAny,
+			ReadRejectionReasons: ScopeAny,
+			ReadRepayments:       ScopeAny,
+		},
+		RoleFieldOfficer: {
+			ReadBorrowers:    ScopeAny,
+			ReadLoans:        ScopeAny,
+			DisburseLoan:     ScopeAny,
+			ReadRepayments:   ScopeAny,
+			RecordRepayments: ScopeAny,
+		},
+		RoleInvestor: {
+			ReadLoans:    ScopeAny,
+			InvestInLoan: ScopeOwn,
+			ReadPayouts:  ScopeOwn,
+		},
+	}
+}
+
+// LoadPolicy reads a policy from a JSON file shaped like
+// {"admin": {"loans:read": "any"}, "investor": {"loans:invest": "own"}}
+func LoadPolicy(path string) (Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	var policy Policy
+	err = json.Unmarshal(data, &policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse policy file: %w", err)
+	}
+
+	err = policy.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// Validate returns an error when the policy grants an unknown action or scope
+func (p Policy) Validate() error {
+	known := map[Action]bool{}
+	for _, action := range Actions {
+		known[action] = true
+	}
+
+	for role, grants := range p {
+		for action, scope := range grants {
+			if !known[action] {
+				return fmt.Errorf("role %s: unknown action %s", role, action)
+			}
+			if scope != ScopeAny && scope != ScopeOwn {
+				return fmt.Errorf("role %s: action %s has unknown scope %s", role, action, scope)
+			}
+		}
+	}
+
+	return nil
+}
+
+// RoleOf returns the role a user acts as: the staff role for staff users that have one,
+// otherwise the user type
+func RoleOf(user *models.User) string {
+	if user.UserType == RoleStaff && user.StaffRole != "" {
+		return user.StaffRole
+	}
+	return user.UserType
+}
+
+// Can reports whether user may perform action on resource. A nil resource asks whether the
+// user may perform action on some resource, which is all a route knows before the
+// resource is loaded.
+func (p Policy) Can(user *models.User, action Action, resource *Resource) bool {
+	if user == nil || !user.IsActive {
+		return false
+	}
+
+	switch p[RoleOf(user)][action] {
+	case ScopeAny:
+		return true
+	case ScopeOwn:
+		return resource == nil || resource.OwnedBy(user)
+	default:
+		return false
+	}
+}
+
+// KeyCan reports whether an API key may perform action: the key must be usable and one of
+// its scopes must allow the action. API keys own nothing, so the resource does not matter.
+func KeyCan(key *models.APIKey, action Action) bool {
+	if key == nil || !key.Usable(time.Now()) {
+		return false
+	}
+
+	for _, scope := range key.Scopes {
+		for _, allowed := range APIKeyScopes[scope] {
+			if allowed == action {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Authorize checks that the principal attached to ctx may perform action on resource.
+// Users are checked against the policy and API keys against their scopes.
+func (p Policy) Authorize(ctx context.Context, action Action, resource *Resource) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if principal.APIKey != nil {
+		if !KeyCan(principal.APIKey, action) {
+			return fmt.Errorf("%w: API key %s cannot perform %s", ErrForbidden, principal.APIKey.Prefix, action)
+		}
+		return nil
+	}
+
+	if !p.Can(principal.User, action, resource) {
+		return fmt.Errorf("%w: %s cannot perform %s", ErrForbidden, RoleOf(principal.User), action)
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package authz
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func staff(role string) *models.User {
+	return &models.User{Email: role + "@example.com", UserType: RoleStaff, StaffRole: role, IsActive: true}
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	policy := DefaultPolicy()
+	admin := &models.User{Email: "admin@example.com", UserType: RoleAdmin, IsActive: true}
+	investorID := 3
+	investor := &models.User{Email: "investor@example.com", UserType: RoleInvestor, InvestorID: &investorID, IsActive: true}
+	unlinkedInvestor := &models.User{Email: "investor@example.com", UserType: RoleInvestor, IsActive: true}
+	ownInvestor := &Resource{OwnerInvestorID: 3}
+	otherInvestor := &Resource{OwnerInvestorID: 4}
+
+	tests := []struct {
+		name     string
+		user     *models.User
+		action   Action
+		resource *Resource
+		allowed  bool
+	}{
+		{"field validator approves", staff(RoleFieldValidator), ApproveLoan, nil, true},
+		{"field officer cannot approve", staff(RoleFieldOfficer), ApproveLoan, nil, false},
+		{"field officer disburses", staff(RoleFieldOfficer), DisburseLoan, nil, true},
+		{"field validator cannot disburse", staff(RoleFieldValidator), DisburseLoan, nil, false},
+		{"staff without a staff role cannot approve", &models.User{UserType: RoleStaff, IsActive: true}, ApproveLoan, nil, false},
+		{"admin manages borrowers", admin, ManageBorrowers, nil, true},
+		{"admin cannot approve", admin, ApproveLoan, nil, false},
+		{"investor cannot manage borrowers", investor, ManageBorrowers, nil, false},
+		{"admin manages users", admin, ManageUsers, nil, true},
+		{"staff cannot manage users", staff(RoleFieldValidator), ManageUsers, nil, false},
+		{"investor cannot list users", investor, ReadUsers, nil, false},
+		{"investor invests as themselves", investor, InvestInLoan, ownInvestor, true},
+		{"investor cannot invest as someone else", investor, InvestInLoan, otherInvestor, false},
+		{"investor without an investor record cannot invest", unlinkedInvestor, InvestInLoan, ownInvestor, false},
+		{"investor may reach the invest route", investor, InvestInLoan, nil, true},
+		{"investor reads their own payouts", investor, ReadPayouts, ownInvestor, true},
+		{"investor cannot read another investor's payouts", investor, ReadPayouts, otherInvestor, false},
+		{"investor cannot read investors", investor, ReadInvestors, nil, false},
+		{"admin reads any investor's payouts", admin, ReadPayouts, otherInvestor, true},
+		{"deactivated user is denied", &models.User{UserType: RoleAdmin}, ReadLoans, nil, false},
+		{"no user is denied", nil, ReadLoans, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.allowed, policy.Can(tt.user, tt.action, tt.resource))
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	policy := DefaultPolicy()
+
+	err := policy.Authorize(context.Background(), ApproveLoan, nil)
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+
+	ctx := WithUser(context.Background(), staff(RoleFieldOfficer))
+	err = policy.Authorize(ctx, ApproveLoan, nil)
+	assert.ErrorIs(t, err, ErrForbidden)
+	assert.Contains(t, err.Error(), "field_officer cannot perform loans:approve")
+
+	assert.NoError(t, policy.Authorize(ctx, DisburseLoan, nil))
+}
+
+func TestAuthorizeAPIKey(t *testing.T) {
+	policy := DefaultPolicy()
+	past := time.Now().Add(-time.Hour)
+	key := &models.APIKey{Prefix: "lek_1a2b3c4d", Scopes: []string{"loans:write", "investors:read"}}
+
+	tests := []struct {
+		name    string
+		key     *models.APIKey
+		action  Action
+		allowed bool
+	}{
+		{"write scope creates", key, ManageLoans, true},
+		{"write scope reads", key, ReadLoans, true},
+		{"read scope reads", key, ReadInvestors, true},
+		{"read scope cannot write", key, ManageInvestors, false},
+		{"read scope reads payouts", key, ReadPayouts, true},
+		{"no scope for borrowers", key, ReadBorrowers, false},
+		{"keys never approve", key, ApproveLoan, false},
+		{"keys never invest", key, InvestInLoan, false},
+		{"expired key", &models.APIKey{Scopes: key.Scopes, ExpiresAt: &past}, ReadLoans, false},
+		{"revoked key", &models.APIKey{Scopes: key.Scopes, RevokedAt: &past}, ReadLoans, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithPrincipal(context.Background(), Principal{APIKey: tt.key})
+
+			err := policy.Authorize(ctx, tt.action, nil)
+
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrForbidden)
+			}
+		})
+	}
+}
+
+func TestAPIKeyPrincipal(t *testing.T) {
+	ctx := WithPrincipal(context.Background(), Principal{APIKey: &models.APIKey{Prefix: "lek_1a2b3c4d"}})
+
+	_, isUser := UserFromContext(ctx)
+	assert.False(t, isUser)
+	assert.Equal(t, "api_key:lek_1a2b3c4d", Actor(ctx))
+
+	_, err := StaffMember(ctx, RoleFieldValidator)
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestValidateAPIKeyScopes(t *testing.T) {
+	assert.NoError(t, ValidateAPIKeyScopes([]string{"loans:write", "ledger:read"}))
+	assert.Error(t, ValidateAPIKeyScopes(nil))
+	assert.Error(t, ValidateAPIKeyScopes([]string{"loans:approve"}))
+}
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"admin": {"loans:approve": "any"}, "investor": {"loans:invest": "own"}}`), 0o644))
+
+	policy, err := LoadPolicy(path)
+	require.NoError(t, err)
+
+	admin := &models.User{UserType: RoleAdmin, IsActive: true}
+	assert.True(t, policy.Can(admin, ApproveLoan, nil))
+	assert.False(t, policy.Can(admin, ManageBorrowers, nil))
+	assert.Equal(t, ScopeOwn, policy[RoleInvestor][InvestInLoan])
+
+	invalid := []string{
+		`{"admin": {"loans:launch": "any"}}`,
+		`{"admin": {"loans:approve": "some"}}`,
+		`not json`,
+	}
+	for _, content := range invalid {
+		path := filepath.Join(dir, "invalid.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+		_, err := LoadPolicy(path)
+		assert.Error(t, err, content)
+	}
+}
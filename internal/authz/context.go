@@ -0,0 +1,79 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+type contextKey string
+
+const principalContextKey contextKey = "principal"
+
+// Principal is who a request is made by: a signed-in user or a partner system using an
+// API key. Exactly one of the fields is set.
+type Principal struct {
+	User   *models.User
+	APIKey *models.APIKey
+}
+
+// ID names the principal in audit records and loan history: the user's user ID, or
+// "api_key:" and the key's prefix
+func (p Principal) ID() string {
+	if p.APIKey != nil {
+		return "api_key:" + p.APIKey.Prefix
+	}
+	return p.User.UserID
+}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey, principal)
+}
+
+// PrincipalFromContext returns the principal attached to ctx by WithPrincipal or WithUser
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey).(Principal)
+	return principal, ok && (principal.User != nil || principal.APIKey != nil)
+}
+
+// WithUser returns a copy of ctx carrying the authenticated user
+func WithUser(ctx context.Context, user *models.User) context.Context {
+	return WithPrincipal(ctx, Principal{User: user})
+}
+
+// UserFromContext returns the user attached to ctx. It reports false for requests made
+// with an API key, so actions that need a person cannot be taken with one.
+func UserFromContext(ctx context.Context) (*models.User, bool) {
+	principal, ok := PrincipalFromContext(ctx)
+	return principal.User, ok && principal.User != nil
+}
+
+// SystemActor is recorded as the actor of changes made without a signed-in user
+const SystemActor = "system"
+
+// Actor returns the ID of the principal attached to ctx, or SystemActor when there is none
+func Actor(ctx context.Context) string {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return SystemActor
+	}
+	return principal.ID()
+}
+
+// StaffMember returns the user attached to ctx when they are an active staff member with
+// staffRole, so they can be recorded as the employee performing an action
+func StaffMember(ctx context.Context, staffRole string) (*models.User, error) {
+	user, ok := UserFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	if !user.IsActive || user.UserType != RoleStaff || user.StaffRole != staffRole {
+		return nil, fmt.Errorf("%w: only active %s staff can do this", ErrForbidden, strings.ReplaceAll(staffRole, "_", " "))
+	}
+
+	return user, nil
+}
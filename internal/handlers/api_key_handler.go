@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// APIKeyHandler serves the admin API for managing partner API keys
+type APIKeyHandler struct {
+	apiKeyService services.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// apiKeyRequest is the body of the create and update requests
+type apiKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// ExpiresAt may be left out for a key that does not expire
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreateAPIKey creates a key and returns it in full; it cannot be retrieved again
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req apiKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	key, err := h.apiKeyService.CreateAPIKey(auditContext(r), req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		SendErrorResponse(w, "Failed to create API key", err)
+		return
+	}
+
+	SendSuccessResponse(w, key, "API key created successfully; store the key now, it is not shown again")
+}
+
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	keys, err := h.apiKeyService.ListAPIKeys(r.Context(), offset, limit)
+	if err != nil {
+		SendErrorResponse(w, "Failed to list API keys", err)
+		return
+	}
+
+	SendSuccessResponse(w, keys, "API keys retrieved successfully")
+}
+
+func (h *APIKeyHandler) GetAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		sendBadRequest(w, "Invalid API key ID", err)
+		return
+	}
+
+	key, err := h.apiKeyService.GetAPIKey(r.Context(), id)
+	if err != nil {
+		SendErrorResponse(w, "Failed to get API key", err)
+		return
+	}
+
+	SendSuccessResponse(w, key, "API key retrieved successfully")
+}
+
+// UpdateAPIKey renames a key and replaces its scopes and expiry
+func (h *APIKeyHandler) UpdateAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		sendBadRequest(w, "Invalid API key ID", err)
+		return
+	}
+
+	var req apiKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	key, err := h.apiKeyService.UpdateAPIKey(auditContext(r), id, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		SendErrorResponse(w, "Failed to update API key", err)
+		return
+	}
+
+	SendSuccessResponse(w, key, "API key updated successfully")
+}
+
+// RevokeAPIKey stops a key from working; revoked keys stay listed for the audit trail
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		sendBadRequest(w, "Invalid API key ID", err)
+		return
+	}
+
+	if err := h.apiKeyService.RevokeAPIKey(auditContext(r), id); err != nil {
+		SendErrorResponse(w, "Failed to revoke API key", err)
+		return
+	}
+
+	SendSuccessResponse(w, nil, "API key revoked successfully")
+}
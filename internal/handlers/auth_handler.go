@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"github.com/sswastioyono18/loan-engine/internal/models"
+	"errors"
+	"github.com/sswastioyono18/loan-engine/internal/authz"
 	"github.com/sswastioyono18/loan-engine/internal/services"
+	"net"
 	"net/http"
 )
 
@@ -17,71 +20,506 @@ func NewAuthHandler(authService services.AuthService) *AuthHandler {
 	}
 }
 
-func (h *AuthHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
-	var user struct {
-		UserID   string `json:"user_id"`
-		Email    string `json:"email"`
-		Password string `json:"password"`
-		UserType string `json:"user_type"`
-		FullName string `json:"full_name"`
+// registerRequest is the body of the investor sign-up request
+type registerRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	FullName string `json:"full_name"`
+	Phone    string `json:"phone"`
+	UserType string `json:"user_type"`
+}
+
+func (req *registerRequest) validate() error {
+	var v fieldValidator
+	v.person(req.FullName, req.Email, req.Phone)
+	if v.required("password", req.Password) {
+		v.password("password", req.Password, req.Email)
 	}
+	return v.err()
+}
 
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-		SendErrorResponse(w, "Invalid request body", err)
+// RegisterUser signs up an investor. Staff and admin accounts are created through the user
+// management API instead.
+func (h *AuthHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
+	var req registerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
 		return
 	}
 
-	model := &models.User{
-		UserID:   user.UserID,
-		Email:    user.Email,
-		UserType: user.UserType,
-		FullName: user.FullName,
-		IsActive: true, // Default to active
+	if err := req.validate(); err != nil {
+		SendErrorResponse(w, "Invalid registration", err)
+		return
 	}
 
-	if err := h.authService.RegisterUser(r.Context(), model, user.Password); err != nil {
+	user, err := h.authService.RegisterInvestor(auditContext(r), &services.InvestorRegistration{
+		Email:    req.Email,
+		Password: req.Password,
+		FullName: req.FullName,
+		Phone:    req.Phone,
+		UserType: req.UserType,
+	})
+	if err != nil {
 		SendErrorResponse(w, "Failed to register user", err)
 		return
 	}
 
-	SendSuccessResponse(w, nil, "User registered successfully")
+	SendSuccessResponse(w, user, "User registered successfully; check your email for the verification token")
 }
 
-func (h *AuthHandler) LoginUser(w http.ResponseWriter, r *http.Request) {
-	var credentials struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
+type verifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+func (req *verifyEmailRequest) validate() error {
+	var v fieldValidator
+	v.required("token", req.Token)
+	return v.err()
+}
+
+// VerifyEmail verifies a new investor's email address with the token mailed on registration
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req verifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		SendErrorResponse(w, "Invalid email verification", err)
+		return
+	}
+
+	if err := h.authService.VerifyEmail(auditContext(r), req.Token); err != nil {
+		SendErrorResponse(w, "Failed to verify email", err)
+		return
 	}
 
+	SendSuccessResponse(w, nil, "Email verified successfully")
+}
+
+// emailRequest is the body of the requests that mail a token to an address
+type emailRequest struct {
+	Email string `json:"email"`
+}
+
+func (req *emailRequest) validate() error {
+	var v fieldValidator
+	if v.required("email", req.Email) {
+		v.email("email", req.Email)
+	}
+	return v.err()
+}
+
+// ResendEmailVerification mails a new verification token. It answers the same whether or
+// not the email belongs to an unverified account.
+func (h *AuthHandler) ResendEmailVerification(w http.ResponseWriter, r *http.Request) {
+	var req emailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		SendErrorResponse(w, "Invalid request", err)
+		return
+	}
+
+	if err := h.authService.ResendEmailVerification(auditContext(r), req.Email); err != nil {
+		SendErrorResponse(w, "Failed to resend verification", err)
+		return
+	}
+
+	SendSuccessResponse(w, nil, "If the email belongs to an unverified account, a verification token has been sent to it")
+}
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (req *loginRequest) validate() error {
+	var v fieldValidator
+	v.required("email", req.Email)
+	v.required("password", req.Password)
+	return v.err()
+}
+
+func (h *AuthHandler) LoginUser(w http.ResponseWriter, r *http.Request) {
+	var credentials loginRequest
 	if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil {
-		SendErrorResponse(w, "Invalid request body", err)
+		sendBadRequest(w, "Invalid request body", err)
 		return
 	}
 
-	token, err := h.authService.LoginUser(r.Context(), credentials.Email, credentials.Password)
+	if err := credentials.validate(); err != nil {
+		SendErrorResponse(w, "Invalid login", err)
+		return
+	}
+
+	result, err := h.authService.LoginUser(auditContext(r), credentials.Email, credentials.Password)
 	if err != nil {
 		SendErrorResponse(w, "Login failed", err)
 		return
 	}
 
-	SendSuccessResponse(w, map[string]string{"token": token}, "Login successful")
+	if result.Challenge != nil {
+		SendSuccessResponse(w, result, "TOTP code required to complete login")
+		return
+	}
+
+	SendSuccessResponse(w, result, "Login successful")
 }
 
-func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		RefreshToken string `json:"refresh_token"`
+type totpLoginRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+func (req *totpLoginRequest) validate() error {
+	var v fieldValidator
+	v.required("challenge_token", req.ChallengeToken)
+	v.required("code", req.Code)
+	return v.err()
+}
+
+// VerifyLoginTOTP completes a login with the challenge token from LoginUser and a TOTP or
+// recovery code
+func (h *AuthHandler) VerifyLoginTOTP(w http.ResponseWriter, r *http.Request) {
+	var req totpLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		SendErrorResponse(w, "Invalid login", err)
+		return
+	}
+
+	result, err := h.authService.VerifyLoginTOTP(auditContext(r), req.ChallengeToken, req.Code)
+	// A wrong code is a validation error when a signed-in user confirms enrolment, but here
+	// it fails the login
+	if errors.Is(err, services.ErrInvalidTOTPCode) {
+		SendErrorResponseWithCode(w, "Login failed", err, http.StatusUnauthorized)
+		return
 	}
+	if err != nil {
+		SendErrorResponse(w, "Login failed", err)
+		return
+	}
+
+	SendSuccessResponse(w, result, "Login successful")
+}
+
+// refreshTokenRequest is the body of the refresh and logout requests
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
 
+func (req *refreshTokenRequest) validate() error {
+	var v fieldValidator
+	v.required("refresh_token", req.RefreshToken)
+	return v.err()
+}
+
+func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req refreshTokenRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		SendErrorResponse(w, "Invalid request body", err)
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		SendErrorResponse(w, "Invalid request", err)
 		return
 	}
 
-	newToken, err := h.authService.RefreshToken(r.Context(), req.RefreshToken)
+	tokens, err := h.authService.RefreshToken(auditContext(r), req.RefreshToken)
 	if err != nil {
 		SendErrorResponse(w, "Token refresh failed", err)
 		return
 	}
 
-	SendSuccessResponse(w, map[string]string{"token": newToken}, "Token refreshed successfully")
+	SendSuccessResponse(w, tokens, "Token refreshed successfully")
+}
+
+// Logout ends the session the given refresh token belongs to
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req refreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		SendErrorResponse(w, "Invalid request", err)
+		return
+	}
+
+	if err := h.authService.Logout(r.Context(), req.RefreshToken); err != nil {
+		SendErrorResponse(w, "Logout failed", err)
+		return
+	}
+
+	SendSuccessResponse(w, nil, "Logged out successfully")
+}
+
+// LogoutAll ends every session of the signed-in user
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	user, ok := authz.UserFromContext(r.Context())
+	if !ok {
+		sendAccessDenied(w, authz.ErrUnauthenticated)
+		return
+	}
+
+	if err := h.authService.LogoutAll(r.Context(), user.ID); err != nil {
+		SendErrorResponse(w, "Logout failed", err)
+		return
+	}
+
+	SendSuccessResponse(w, nil, "Logged out of all sessions successfully")
+}
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// validate checks the request of the user with the given email
+func (req *changePasswordRequest) validate(email string) error {
+	var v fieldValidator
+	v.required("current_password", req.CurrentPassword)
+	if v.required("new_password", req.NewPassword) {
+		v.password("new_password", req.NewPassword, email)
+	}
+	return v.err()
+}
+
+// ChangePassword replaces the signed-in user's password after checking the current one
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	user, ok := authz.UserFromContext(r.Context())
+	if !ok {
+		sendAccessDenied(w, authz.ErrUnauthenticated)
+		return
+	}
+
+	var req changePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	if err := req.validate(user.Email); err != nil {
+		SendErrorResponse(w, "Invalid password change", err)
+		return
+	}
+
+	err := h.authService.ChangePassword(auditContext(r), user.ID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		SendErrorResponse(w, "Failed to change password", err)
+		return
+	}
+
+	SendSuccessResponse(w, nil, "Password changed successfully")
+}
+
+// ForgotPassword mails a password reset token. It answers the same whether or not the
+// email is registered.
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req emailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		SendErrorResponse(w, "Invalid request", err)
+		return
+	}
+
+	if err := h.authService.RequestPasswordReset(auditContext(r), req.Email); err != nil {
+		SendErrorResponse(w, "Failed to request password reset", err)
+		return
+	}
+
+	SendSuccessResponse(w, nil, "If the email belongs to an active account, a password reset token has been sent to it")
+}
+
+type resetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+func (req *resetPasswordRequest) validate() error {
+	var v fieldValidator
+	v.required("token", req.Token)
+	// The email is only known once the token is checked, so the service checks the
+	// password against it
+	if v.required("new_password", req.NewPassword) {
+		v.password("new_password", req.NewPassword, "")
+	}
+	return v.err()
+}
+
+// ResetPassword sets a new password with a token from ForgotPassword
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req resetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		SendErrorResponse(w, "Invalid password reset", err)
+		return
+	}
+
+	if err := h.authService.ResetPassword(auditContext(r), req.Token, req.NewPassword); err != nil {
+		SendErrorResponse(w, "Failed to reset password", err)
+		return
+	}
+
+	SendSuccessResponse(w, nil, "Password reset successfully")
+}
+
+// EnrolTOTP starts TOTP enrolment for the signed-in user and returns the secret to add to an
+// authenticator app
+func (h *AuthHandler) EnrolTOTP(w http.ResponseWriter, r *http.Request) {
+	user, ok := authz.UserFromContext(r.Context())
+	if !ok {
+		sendAccessDenied(w, authz.ErrUnauthenticated)
+		return
+	}
+
+	enrolment, err := h.authService.EnrolTOTP(auditContext(r), user.ID)
+	if err != nil {
+		SendErrorResponse(w, "Failed to enrol in two-factor authentication", err)
+		return
+	}
+
+	SendSuccessResponse(w, enrolment, "Confirm with a code from your authenticator app to enable two-factor authentication")
+}
+
+// totpCodeRequest is the body of the requests a signed-in user confirms with a TOTP code
+type totpCodeRequest struct {
+	Code string `json:"code"`
+}
+
+func (req *totpCodeRequest) validate() error {
+	var v fieldValidator
+	v.required("code", req.Code)
+	return v.err()
+}
+
+// recoveryCodesResponse carries the recovery codes of a user, which are only ever shown once
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// ConfirmTOTP enables TOTP for the signed-in user and returns their recovery codes
+func (h *AuthHandler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	user, ok := authz.UserFromContext(r.Context())
+	if !ok {
+		sendAccessDenied(w, authz.ErrUnauthenticated)
+		return
+	}
+
+	var req totpCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		SendErrorResponse(w, "Invalid request", err)
+		return
+	}
+
+	codes, err := h.authService.ConfirmTOTP(auditContext(r), user.ID, req.Code)
+	if err != nil {
+		SendErrorResponse(w, "Failed to enable two-factor authentication", err)
+		return
+	}
+
+	SendSuccessResponse(w, recoveryCodesResponse{RecoveryCodes: codes}, "Two-factor authentication enabled")
+}
+
+type passwordRequest struct {
+	Password string `json:"password"`
+}
+
+func (req *passwordRequest) validate() error {
+	var v fieldValidator
+	v.required("password", req.Password)
+	return v.err()
+}
+
+// DisableTOTP turns TOTP off for the signed-in user after checking their password
+func (h *AuthHandler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	user, ok := authz.UserFromContext(r.Context())
+	if !ok {
+		sendAccessDenied(w, authz.ErrUnauthenticated)
+		return
+	}
+
+	var req passwordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		SendErrorResponse(w, "Invalid request", err)
+		return
+	}
+
+	err := h.authService.DisableTOTP(auditContext(r), user.ID, req.Password)
+	if err != nil {
+		SendErrorResponse(w, "Failed to disable two-factor authentication", err)
+		return
+	}
+
+	SendSuccessResponse(w, nil, "Two-factor authentication disabled")
+}
+
+// RegenerateRecoveryCodes replaces the signed-in user's recovery codes after checking a TOTP code
+func (h *AuthHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	user, ok := authz.UserFromContext(r.Context())
+	if !ok {
+		sendAccessDenied(w, authz.ErrUnauthenticated)
+		return
+	}
+
+	var req totpCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		SendErrorResponse(w, "Invalid request", err)
+		return
+	}
+
+	codes, err := h.authService.RegenerateRecoveryCodes(auditContext(r), user.ID, req.Code)
+	if err != nil {
+		SendErrorResponse(w, "Failed to regenerate recovery codes", err)
+		return
+	}
+
+	SendSuccessResponse(w, recoveryCodesResponse{RecoveryCodes: codes}, "Recovery codes regenerated")
+}
+
+// auditContext returns the request context with the client's address and user agent for the security audit trail
+func auditContext(r *http.Request) context.Context {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return services.WithRequestInfo(r.Context(), services.RequestInfo{
+		IPAddress: ip,
+		UserAgent: r.UserAgent(),
+	})
 }
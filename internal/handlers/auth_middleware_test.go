@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sswastioyono18/loan-engine/internal/authz"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/repositories"
+	"github.com/sswastioyono18/loan-engine/internal/services"
+	"github.com/sswastioyono18/loan-engine/internal/services/mocks"
+	"github.com/sswastioyono18/loan-engine/internal/signing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticateAcceptsValidToken(t *testing.T) {
+	mockAuthService := mocks.NewAuthService(t)
+	user := &models.User{ID: 1, Email: "staff@example.com", UserType: "staff", IsActive: true}
+	mockAuthService.On("ValidateToken", mock.Anything, "valid-token").Return(user, nil)
+
+	var seen *models.User
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = authz.UserFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/api/v1/loans", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	rr := httptest.NewRecorder()
+
+	Authenticate(mockAuthService, nil)(next).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, user, seen)
+}
+
+func TestAuthenticateRejectsRequests(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        string
+		validateUser  *models.User
+		validateError error
+	}{
+		{name: "missing header"},
+		{name: "wrong scheme", header: "Basic dXNlcjpwYXNz"},
+		{name: "empty token", header: "Bearer "},
+		{name: "expired token", header: "Bearer expired-token", validateError: errors.New("invalid token")},
+		{name: "deactivated user", header: "Bearer valid-token", validateUser: &models.User{ID: 1, IsActive: false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuthService := mocks.NewAuthService(t)
+			if tt.validateUser != nil || tt.validateError != nil {
+				mockAuthService.On("ValidateToken", mock.Anything, mock.Anything).Return(tt.validateUser, tt.validateError)
+			}
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Fatal("handler must not run for an unauthenticated request")
+			})
+
+			req := httptest.NewRequest("GET", "/api/v1/loans", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rr := httptest.NewRecorder()
+
+			Authenticate(mockAuthService, nil)(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusUnauthorized, rr.Code)
+			assert.Contains(t, rr.Header().Get("WWW-Authenticate"), "Bearer")
+		})
+	}
+}
+
+func TestAuthenticateAcceptsAPIKey(t *testing.T) {
+	mockAPIKeyService := mocks.NewAPIKeyService(t)
+	key := &models.APIKey{ID: 1, Prefix: "lek_1a2b3c4d", Scopes: []string{"loans:write"}}
+	mockAPIKeyService.On("AuthenticateAPIKey", mock.Anything, "lek_1a2b3c4d.secret").Return(key, nil)
+
+	var seen authz.Principal
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = authz.PrincipalFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("POST", "/api/v1/loans", nil)
+	req.Header.Set("X-API-Key", "lek_1a2b3c4d.secret")
+	rr := httptest.NewRecorder()
+
+	Authenticate(nil, mockAPIKeyService)(next).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, key, seen.APIKey)
+	assert.Nil(t, seen.User)
+}
+
+func TestAuthenticateRejectsAPIKeys(t *testing.T) {
+	t.Run("invalid key", func(t *testing.T) {
+		mockAPIKeyService := mocks.NewAPIKeyService(t)
+		mockAPIKeyService.On("AuthenticateAPIKey", mock.Anything, "lek_1a2b3c4d.wrong").Return(nil, services.ErrInvalidAPIKey)
+
+		req := httptest.NewRequest("GET", "/api/v1/loans", nil)
+		req.Header.Set("X-API-Key", "lek_1a2b3c4d.wrong")
+		rr := httptest.NewRecorder()
+
+		Authenticate(nil, mockAPIKeyService)(http.NotFoundHandler()).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("key and bearer token together", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/loans", nil)
+		req.Header.Set("X-API-Key", "lek_1a2b3c4d.secret")
+		req.Header.Set("Authorization", "Bearer valid-token")
+		rr := httptest.NewRecorder()
+
+		Authenticate(nil, nil)(http.NotFoundHandler()).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
+func TestRouterOnlyLeavesAuthAndHealthOpen(t *testing.T) {
+	signingKeys, err := signing.NewHMACManager("secret")
+	require.NoError(t, err)
+	router := NewRouter(services.NewServiceFactory(repositories.NewRepositoryFactory(nil), nil, nil, signingKeys))
+
+	tests := []struct {
+		method string
+		path   string
+		code   int
+	}{
+		{"GET", "/health", http.StatusOK},
+		{"GET", "/.well-known/jwks.json", http.StatusOK},
+		{"GET", "/openapi.json", http.StatusOK},
+		{"GET", "/docs", http.StatusOK},
+		{"POST", "/api/v1/auth/login", http.StatusBadRequest},
+		{"POST", "/api/v1/auth/logout", http.StatusBadRequest},
+		{"POST", "/api/v1/auth/logout-all", http.StatusUnauthorized},
+		{"POST", "/api/v1/auth/forgot-password", http.StatusBadRequest},
+		{"POST", "/api/v1/auth/reset-password", http.StatusBadRequest},
+		{"POST", "/api/v1/auth/change-password", http.StatusUnauthorized},
+		{"POST", "/api/v1/auth/login/totp", http.StatusBadRequest},
+		{"POST", "/api/v1/auth/totp/enrol", http.StatusUnauthorized},
+		{"POST", "/api/v1/auth/totp/confirm", http.StatusUnauthorized},
+		{"POST", "/api/v1/auth/totp/disable", http.StatusUnauthorized},
+		{"POST", "/api/v1/auth/totp/recovery-codes", http.StatusUnauthorized},
+		{"POST", "/api/v1/auth/verify-email", http.StatusBadRequest},
+		{"POST", "/api/v1/auth/resend-verification", http.StatusBadRequest},
+		{"GET", "/api/v1/users", http.StatusUnauthorized},
+		{"POST", "/api/v1/users", http.StatusUnauthorized},
+		{"GET", "/api/v1/users/1", http.StatusUnauthorized},
+		{"POST", "/api/v1/users/1/deactivate", http.StatusUnauthorized},
+		{"POST", "/api/v1/users/1/activate", http.StatusUnauthorized},
+		{"DELETE", "/api/v1/users/1", http.StatusUnauthorized},
+		{"POST", "/api/v1/users/1/restore", http.StatusUnauthorized},
+		{"PUT", "/api/v1/users/1/role", http.StatusUnauthorized},
+		{"POST", "/api/v1/users/1/reset-password", http.StatusUnauthorized},
+		{"GET", "/api/v1/api-keys", http.StatusUnauthorized},
+		{"POST", "/api/v1/api-keys", http.StatusUnauthorized},
+		{"GET", "/api/v1/api-keys/1", http.StatusUnauthorized},
+		{"PUT", "/api/v1/api-keys/1", http.StatusUnauthorized},
+		{"DELETE", "/api/v1/api-keys/1", http.StatusUnauthorized},
+		{"GET", "/api/v1/loans", http.StatusUnauthorized},
+		{"POST", "/api/v1/loans/1/approve", http.StatusUnauthorized},
+		{"GET", "/api/v1/ledger/accounts", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, tt.code, rr.Code, "%s %s", tt.method, tt.path)
+	}
+}
+
+func TestAuthorizeChecksRoutePermission(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := Authorize(authz.DefaultPolicy(), authz.ApproveLoan)(next)
+
+	tests := []struct {
+		name string
+		user *models.User
+		code int
+	}{
+		{"field validator", &models.User{UserType: "staff", StaffRole: "field_validator", IsActive: true}, http.StatusOK},
+		{"investor", &models.User{UserType: "investor", IsActive: true}, http.StatusForbidden},
+		{"no user", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/loans/1/approve", nil)
+			if tt.user != nil {
+				req = req.WithContext(authz.WithUser(req.Context(), tt.user))
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
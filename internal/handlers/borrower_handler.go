@@ -21,17 +21,33 @@ func NewBorrowerHandler(borrowerService services.BorrowerService) *BorrowerHandl
 	}
 }
 
-func (h *BorrowerHandler) CreateBorrower(w http.ResponseWriter, r *http.Request) {
-	var borrower struct {
-		BorrowerIDNumber string `json:"borrower_id_number"`
-		FullName         string `json:"full_name"`
-		Email            string `json:"email"`
-		Phone            string `json:"phone"`
-		Address          string `json:"address"`
+// borrowerRequest is the body of the create and update borrower requests
+type borrowerRequest struct {
+	BorrowerIDNumber string `json:"borrower_id_number"`
+	FullName         string `json:"full_name"`
+	Email            string `json:"email"`
+	Phone            string `json:"phone"`
+	Address          string `json:"address"`
+}
+
+func (req *borrowerRequest) validate() error {
+	var v fieldValidator
+	if v.required("borrower_id_number", req.BorrowerIDNumber) {
+		v.maxLength("borrower_id_number", req.BorrowerIDNumber, maxIDNumberLength)
 	}
+	v.person(req.FullName, req.Email, req.Phone)
+	return v.err()
+}
 
+func (h *BorrowerHandler) CreateBorrower(w http.ResponseWriter, r *http.Request) {
+	var borrower borrowerRequest
 	if err := json.NewDecoder(r.Body).Decode(&borrower); err != nil {
-		SendErrorResponse(w, "Invalid request body", err)
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	if err := borrower.validate(); err != nil {
+		SendErrorResponse(w, "Invalid borrower", err)
 		return
 	}
 
@@ -54,7 +70,7 @@ func (h *BorrowerHandler) CreateBorrower(w http.ResponseWriter, r *http.Request)
 func (h *BorrowerHandler) GetBorrowerByID(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		SendErrorResponse(w, "Invalid borrower ID", err)
+		sendBadRequest(w, "Invalid borrower ID", err)
 		return
 	}
 
@@ -70,20 +86,18 @@ func (h *BorrowerHandler) GetBorrowerByID(w http.ResponseWriter, r *http.Request
 func (h *BorrowerHandler) UpdateBorrower(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		SendErrorResponse(w, "Invalid borrower ID", err)
+		sendBadRequest(w, "Invalid borrower ID", err)
 		return
 	}
 
-	var borrower struct {
-		BorrowerIDNumber string `json:"borrower_id_number"`
-		FullName         string `json:"full_name"`
-		Email            string `json:"email"`
-		Phone            string `json:"phone"`
-		Address          string `json:"address"`
+	var borrower borrowerRequest
+	if err := json.NewDecoder(r.Body).Decode(&borrower); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&borrower); err != nil {
-		SendErrorResponse(w, "Invalid request body", err)
+	if err := borrower.validate(); err != nil {
+		SendErrorResponse(w, "Invalid borrower", err)
 		return
 	}
 
@@ -107,7 +121,7 @@ func (h *BorrowerHandler) UpdateBorrower(w http.ResponseWriter, r *http.Request)
 func (h *BorrowerHandler) DeleteBorrower(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		SendErrorResponse(w, "Invalid borrower ID", err)
+		sendBadRequest(w, "Invalid borrower ID", err)
 		return
 	}
 
@@ -119,18 +133,15 @@ func (h *BorrowerHandler) DeleteBorrower(w http.ResponseWriter, r *http.Request)
 	SendSuccessResponse(w, nil, "Borrower deleted successfully")
 }
 
+// ListBorrowers lists borrowers a page at a time, sorted by sort and paged with cursor and limit
 func (h *BorrowerHandler) ListBorrowers(w http.ResponseWriter, r *http.Request) {
-	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
-	if err != nil || offset < 0 {
-		offset = 0
-	}
-
-	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
-	if err != nil || limit < 1 {
-		limit = 10
+	page, err := pageRequest(r)
+	if err != nil {
+		sendBadRequest(w, "Invalid page request", err)
+		return
 	}
 
-	borrowers, err := h.borrowerService.ListBorrowers(r.Context(), offset, limit)
+	borrowers, err := h.borrowerService.ListBorrowers(r.Context(), page)
 	if err != nil {
 		SendErrorResponse(w, "Failed to list borrowers", err)
 		return
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/sswastioyono18/loan-engine/internal/authz"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/services"
+)
+
+const (
+	// idempotencyKeyHeader carries the client's key for a request it may retry
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayedHeader marks a response replayed from an earlier request
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	// maxIdempotentBodySize bounds the request body read up front to hash it
+	maxIdempotentBodySize = 1 << 20
+)
+
+// Idempotent makes a route safe to retry. The first request with an Idempotency-Key header
+// is handled and its response stored; retries with the same key and body get that response
+// again with an Idempotent-Replayed header, and the same key with another body fails with
+// 422. A retry that arrives while the first request is still being handled fails with 409.
+// Requests without the header are handled as usual.
+//
+// Keys belong to the principal, so Idempotent runs after Authenticate. Server errors are not
+// stored, so the request can be retried once the server has recovered.
+func Idempotent(idempotencyService services.IdempotencyService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(idempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				sendBadRequest(w, "Invalid idempotency key", fmt.Errorf("%s must be at most %d characters long", idempotencyKeyHeader, maxIdempotencyKeyLength))
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodySize))
+			if err != nil {
+				sendBadRequest(w, "Invalid request body", err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			record, err := idempotencyService.Begin(r.Context(), &models.IdempotencyKey{
+				Owner:       authz.Actor(r.Context()),
+				Key:         key,
+				Method:      r.Method,
+				Path:        r.URL.Path,
+				RequestHash: hashRequestBody(body),
+			})
+			if err != nil {
+				if errors.Is(err, services.ErrIdempotentRequestInProgress) {
+					w.Header().Set("Retry-After", "1")
+				}
+				SendErrorResponse(w, "Failed to process idempotent request", err)
+				return
+			}
+
+			if record.Completed() {
+				replayResponse(w, record)
+				return
+			}
+
+			// The outcome is stored even when the client has gone away, as its retry is coming
+			ctx := context.WithoutCancel(r.Context())
+			recorder := &responseRecorder{ResponseWriter: w}
+			handled := false
+			defer func() {
+				if !handled {
+					// The handler panicked; let the retry try again
+					releaseIdempotencyKey(ctx, idempotencyService, record)
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+			handled = true
+
+			statusCode := recorder.statusCode()
+			if statusCode >= http.StatusInternalServerError {
+				releaseIdempotencyKey(ctx, idempotencyService, record)
+				return
+			}
+
+			err = idempotencyService.Complete(ctx, record, statusCode, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+			if err != nil {
+				// The client already has its response; a retry waits for the claim to go stale
+				log.Printf("Failed to store response for idempotency key %q: %v", key, err)
+			}
+		})
+	}
+}
+
+func hashRequestBody(body []byte) string {
+	hash := sha256.Sum256(body)
+	return hex.EncodeToString(hash[:])
+}
+
+func replayResponse(w http.ResponseWriter, record *models.IdempotencyKey) {
+	if record.ContentType != nil && *record.ContentType != "" {
+		w.Header().Set("Content-Type", *record.ContentType)
+	}
+	w.Header().Set(idempotentReplayedHeader, "true")
+	w.WriteHeader(*record.StatusCode)
+	w.Write(record.ResponseBody)
+}
+
+func releaseIdempotencyKey(ctx context.Context, idempotencyService services.IdempotencyService, record *models.IdempotencyKey) {
+	if err := idempotencyService.Release(ctx, record); err != nil {
+		log.Printf("Failed to release idempotency key %q: %v", record.Key, err)
+	}
+}
+
+// responseRecorder passes a response through to the client and keeps a copy of it
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *responseRecorder) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController and problemInstance reach the wrapped writer
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// statusCode is the status sent, which is 200 when the handler sent nothing
+func (w *responseRecorder) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
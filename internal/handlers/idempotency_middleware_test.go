@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/authz"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/services"
+	"github.com/sswastioyono18/loan-engine/internal/services/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const investBody = `{"investor_id": 3, "investment_amount": "1000000.00"}`
+
+// newIdempotentRequest returns an invest request with an Idempotency-Key from a signed-in investor
+func newIdempotentRequest(key string) *http.Request {
+	req := httptest.NewRequest("POST", "/api/v1/loans/7/invest", bytes.NewBufferString(investBody))
+	req.Header.Set(idempotencyKeyHeader, key)
+	return req.WithContext(authz.WithUser(req.Context(), &models.User{UserID: "USR003", IsActive: true}))
+}
+
+// investHandler counts its calls and answers like InvestInLoan, checking it still gets the body
+func investHandler(t *testing.T, calls *int, statusCode int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, investBody, string(body))
+
+		if statusCode != http.StatusOK {
+			SendErrorResponseWithCode(w, "Failed to invest in loan", assert.AnError, statusCode)
+			return
+		}
+		SendSuccessResponse(w, nil, "Investment completed successfully")
+	})
+}
+
+func TestIdempotentStoresFirstResponse(t *testing.T) {
+	mockIdempotencyService := mocks.NewIdempotencyService(t)
+	calls := 0
+	record := &models.IdempotencyKey{ID: 5}
+
+	mockIdempotencyService.On("Begin", mock.Anything, mock.MatchedBy(func(k *models.IdempotencyKey) bool {
+		return k.Owner == "USR003" && k.Key == "invest-1" && k.Path == "/api/v1/loans/7/invest" && k.RequestHash == hashRequestBody([]byte(investBody))
+	})).Return(record, nil)
+
+	var stored []byte
+	mockIdempotencyService.On("Complete", mock.Anything, record, http.StatusOK, "application/json", mock.Anything).
+		Run(func(args mock.Arguments) { stored = args.Get(4).([]byte) }).
+		Return(nil)
+
+	rr := httptest.NewRecorder()
+	Idempotent(mockIdempotencyService)(investHandler(t, &calls, http.StatusOK)).ServeHTTP(rr, newIdempotentRequest("invest-1"))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, rr.Body.String(), string(stored))
+	assert.Empty(t, rr.Header().Get(idempotentReplayedHeader))
+}
+
+func TestIdempotentReplaysStoredResponse(t *testing.T) {
+	mockIdempotencyService := mocks.NewIdempotencyService(t)
+	calls := 0
+
+	statusCode := http.StatusOK
+	contentType := "application/json"
+	completedAt := time.Now()
+	stored := `{"success":true,"message":"Investment completed successfully"}`
+	mockIdempotencyService.On("Begin", mock.Anything, mock.Anything).Return(&models.IdempotencyKey{
+		StatusCode:   &statusCode,
+		ContentType:  &contentType,
+		ResponseBody: []byte(stored),
+		CompletedAt:  &completedAt,
+	}, nil)
+
+	rr := httptest.NewRecorder()
+	Idempotent(mockIdempotencyService)(investHandler(t, &calls, http.StatusOK)).ServeHTTP(rr, newIdempotentRequest("invest-1"))
+
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, stored, rr.Body.String())
+	assert.Equal(t, "true", rr.Header().Get(idempotentReplayedHeader))
+}
+
+func TestIdempotentRefusesTakenKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+		code       string
+	}{
+		{"different payload", services.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, "idempotency_key_reused"},
+		{"request in flight", services.ErrIdempotentRequestInProgress, http.StatusConflict, "idempotent_request_in_progress"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockIdempotencyService := mocks.NewIdempotencyService(t)
+			calls := 0
+			mockIdempotencyService.On("Begin", mock.Anything, mock.Anything).Return(nil, tt.err)
+
+			rr := httptest.NewRecorder()
+			Idempotent(mockIdempotencyService)(investHandler(t, &calls, http.StatusOK)).ServeHTTP(rr, newIdempotentRequest("invest-1"))
+
+			var response struct {
+				Error ErrorBody `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, 0, calls)
+			assert.Equal(t, tt.statusCode, rr.Code)
+			assert.Equal(t, tt.code, response.Error.Code)
+		})
+	}
+}
+
+func TestIdempotentReleasesKeyOnServerError(t *testing.T) {
+	mockIdempotencyService := mocks.NewIdempotencyService(t)
+	calls := 0
+	record := &models.IdempotencyKey{ID: 5}
+
+	mockIdempotencyService.On("Begin", mock.Anything, mock.Anything).Return(record, nil)
+	mockIdempotencyService.On("Release", mock.Anything, record).Return(nil)
+
+	rr := httptest.NewRecorder()
+	Idempotent(mockIdempotencyService)(investHandler(t, &calls, http.StatusInternalServerError)).ServeHTTP(rr, newIdempotentRequest("invest-1"))
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotentKeepsProblemDetails(t *testing.T) {
+	mockIdempotencyService := mocks.NewIdempotencyService(t)
+	calls := 0
+	record := &models.IdempotencyKey{ID: 5}
+
+	mockIdempotencyService.On("Begin", mock.Anything, mock.Anything).Return(record, nil)
+	mockIdempotencyService.On("Complete", mock.Anything, record, http.StatusConflict, problemJSON, mock.Anything).Return(nil)
+
+	req := newIdempotentRequest("invest-1")
+	req.Header.Set("Accept", problemJSON)
+	rr := httptest.NewRecorder()
+
+	ProblemDetails(Idempotent(mockIdempotencyService)(investHandler(t, &calls, http.StatusConflict))).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, problemJSON, rr.Header().Get("Content-Type"))
+}
+
+func TestIdempotentPassesThroughWithoutKey(t *testing.T) {
+	mockIdempotencyService := mocks.NewIdempotencyService(t)
+	calls := 0
+
+	rr := httptest.NewRecorder()
+	Idempotent(mockIdempotencyService)(investHandler(t, &calls, http.StatusOK)).ServeHTTP(rr, newIdempotentRequest(""))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 1, calls)
+}
@@ -21,16 +21,32 @@ func NewInvestorHandler(investorService services.InvestorService) *InvestorHandl
 	}
 }
 
-func (h *InvestorHandler) CreateInvestor(w http.ResponseWriter, r *http.Request) {
-	var investor struct {
-		InvestorID string `json:"investor_id"`
-		FullName   string `json:"full_name"`
-		Email      string `json:"email"`
-		Phone      string `json:"phone"`
+// investorRequest is the body of the create and update investor requests
+type investorRequest struct {
+	InvestorID string `json:"investor_id"`
+	FullName   string `json:"full_name"`
+	Email      string `json:"email"`
+	Phone      string `json:"phone"`
+}
+
+func (req *investorRequest) validate() error {
+	var v fieldValidator
+	if v.required("investor_id", req.InvestorID) {
+		v.maxLength("investor_id", req.InvestorID, maxIDNumberLength)
 	}
+	v.person(req.FullName, req.Email, req.Phone)
+	return v.err()
+}
 
+func (h *InvestorHandler) CreateInvestor(w http.ResponseWriter, r *http.Request) {
+	var investor investorRequest
 	if err := json.NewDecoder(r.Body).Decode(&investor); err != nil {
-		SendErrorResponse(w, "Invalid request body", err)
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	if err := investor.validate(); err != nil {
+		SendErrorResponse(w, "Invalid investor", err)
 		return
 	}
 
@@ -52,7 +68,7 @@ func (h *InvestorHandler) CreateInvestor(w http.ResponseWriter, r *http.Request)
 func (h *InvestorHandler) GetInvestorByID(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		SendErrorResponse(w, "Invalid investor ID", err)
+		sendBadRequest(w, "Invalid investor ID", err)
 		return
 	}
 
@@ -68,19 +84,18 @@ func (h *InvestorHandler) GetInvestorByID(w http.ResponseWriter, r *http.Request
 func (h *InvestorHandler) UpdateInvestor(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		SendErrorResponse(w, "Invalid investor ID", err)
+		sendBadRequest(w, "Invalid investor ID", err)
 		return
 	}
 
-	var investor struct {
-		InvestorID string `json:"investor_id"`
-		FullName   string `json:"full_name"`
-		Email      string `json:"email"`
-		Phone      string `json:"phone"`
+	var investor investorRequest
+	if err := json.NewDecoder(r.Body).Decode(&investor); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&investor); err != nil {
-		SendErrorResponse(w, "Invalid request body", err)
+	if err := investor.validate(); err != nil {
+		SendErrorResponse(w, "Invalid investor", err)
 		return
 	}
 
@@ -103,7 +118,7 @@ func (h *InvestorHandler) UpdateInvestor(w http.ResponseWriter, r *http.Request)
 func (h *InvestorHandler) DeleteInvestor(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		SendErrorResponse(w, "Invalid investor ID", err)
+		sendBadRequest(w, "Invalid investor ID", err)
 		return
 	}
 
@@ -115,18 +130,15 @@ func (h *InvestorHandler) DeleteInvestor(w http.ResponseWriter, r *http.Request)
 	SendSuccessResponse(w, nil, "Investor deleted successfully")
 }
 
+// ListInvestors lists investors a page at a time, sorted by sort and paged with cursor and limit
 func (h *InvestorHandler) ListInvestors(w http.ResponseWriter, r *http.Request) {
-	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
-	if err != nil || offset < 0 {
-		offset = 0
-	}
-
-	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
-	if err != nil || limit < 1 {
-		limit = 10
+	page, err := pageRequest(r)
+	if err != nil {
+		sendBadRequest(w, "Invalid page request", err)
+		return
 	}
 
-	investors, err := h.investorService.ListInvestors(r.Context(), offset, limit)
+	investors, err := h.investorService.ListInvestors(r.Context(), page)
 	if err != nil {
 		SendErrorResponse(w, "Failed to list investors", err)
 		return
@@ -134,3 +146,19 @@ func (h *InvestorHandler) ListInvestors(w http.ResponseWriter, r *http.Request)
 
 	SendSuccessResponse(w, investors, "Investors retrieved successfully")
 }
+
+func (h *InvestorHandler) ListPayouts(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		sendBadRequest(w, "Invalid investor ID", err)
+		return
+	}
+
+	payouts, err := h.investorService.ListPayouts(r.Context(), id)
+	if err != nil {
+		SendErrorResponse(w, "Failed to get payouts", err)
+		return
+	}
+
+	SendSuccessResponse(w, payouts, "Payouts retrieved successfully")
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sswastioyono18/loan-engine/internal/authz"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	repomocks "github.com/sswastioyono18/loan-engine/internal/repositories/mocks"
+	"github.com/sswastioyono18/loan-engine/internal/services"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInvestorHandlerListPayoutsOnlyOwnForInvestors(t *testing.T) {
+	mockInvestorRepo := repomocks.NewInvestorRepository(t)
+	mockPayoutRepo := repomocks.NewPayoutRepository(t)
+	policy := authz.DefaultPolicy()
+	handler := NewInvestorHandler(services.NewInvestorService(mockInvestorRepo, mockPayoutRepo, policy))
+
+	r := chi.NewRouter()
+	r.With(Authorize(policy, authz.ReadPayouts)).Get("/api/v1/investors/{id}/payouts", handler.ListPayouts)
+
+	investorID := 3
+	investor := &models.User{Email: "investor@example.com", UserType: authz.RoleInvestor, InvestorID: &investorID, IsActive: true}
+
+	payouts := []*models.Payout{{ID: 1, InvestorID: 3, LoanID: 1, Amount: money.MustParse("36.01")}}
+	mockInvestorRepo.On("GetByID", mock.Anything, 3).Return(&models.Investor{ID: 3}, nil)
+	mockPayoutRepo.On("GetByInvestorID", mock.Anything, 3).Return(payouts, nil)
+
+	tests := []struct {
+		name string
+		path string
+		code int
+	}{
+		{"own payouts", "/api/v1/investors/3/payouts", http.StatusOK},
+		{"another investor's payouts", "/api/v1/investors/4/payouts", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req = req.WithContext(authz.WithUser(req.Context(), investor))
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.code, rr.Code)
+		})
+	}
+
+	mockInvestorRepo.AssertNotCalled(t, "GetByID", mock.Anything, 4)
+	mockPayoutRepo.AssertNotCalled(t, "GetByInvestorID", mock.Anything, 4)
+}
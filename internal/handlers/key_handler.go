@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sswastioyono18/loan-engine/internal/signing"
+)
+
+type KeyHandler struct {
+	signingKeys *signing.Manager
+}
+
+func NewKeyHandler(signingKeys *signing.Manager) *KeyHandler {
+	return &KeyHandler{
+		signingKeys: signingKeys,
+	}
+}
+
+// GetJWKS publishes the public signing keys so other services can verify access tokens.
+// It is served as a bare JSON Web Key Set, without the usual response envelope.
+func (h *KeyHandler) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	if err := json.NewEncoder(w).Encode(h.signingKeys.JWKS()); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/sswastioyono18/loan-engine/internal/ledger"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type LedgerHandler struct {
+	ledgerService ledger.Service
+}
+
+func NewLedgerHandler(ledgerService ledger.Service) *LedgerHandler {
+	return &LedgerHandler{
+		ledgerService: ledgerService,
+	}
+}
+
+func (h *LedgerHandler) ListBalances(w http.ResponseWriter, r *http.Request) {
+	balances, err := h.ledgerService.ListBalances(r.Context())
+	if err != nil {
+		SendErrorResponse(w, "Failed to get account balances", err)
+		return
+	}
+
+	SendSuccessResponse(w, balances, "Account balances retrieved successfully")
+}
+
+func (h *LedgerHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	balance, err := h.ledgerService.GetBalance(r.Context(), chi.URLParam(r, "code"))
+	if err != nil {
+		SendErrorResponse(w, "Failed to get account balance", err)
+		return
+	}
+
+	SendSuccessResponse(w, balance, "Account balance retrieved successfully")
+}
+
+func (h *LedgerHandler) GetLoanJournal(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		sendBadRequest(w, "Invalid loan ID", err)
+		return
+	}
+
+	entries, err := h.ledgerService.GetEntriesByLoanID(r.Context(), id)
+	if err != nil {
+		SendErrorResponse(w, "Failed to get journal entries", err)
+		return
+	}
+
+	SendSuccessResponse(w, entries, "Journal entries retrieved successfully")
+}
@@ -1,18 +1,35 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/repayment"
 	"github.com/sswastioyono18/loan-engine/internal/services"
 	"github.com/sswastioyono18/loan-engine/pkg/external"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
 
 	"github.com/go-chi/chi/v5"
 )
 
+// Limits of free-text loan fields
+const (
+	maxNotesLength       = 1000
+	maxReasonCodeLength  = 50
+	maxDescriptionLength = 255
+)
+
+// rejectionReasonCodePattern is the form of rejection reason codes, such as low_credit_score
+var rejectionReasonCodePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
 type LoanHandler struct {
 	loanService    services.LoanService
 	emailService   external.EmailService
@@ -27,17 +44,41 @@ func NewLoanHandler(loanService services.LoanService, emailService external.Emai
 	}
 }
 
+// loanRequest is the body of the create and update loan requests
+type loanRequest struct {
+	BorrowerID          int         `json:"borrower_id"`
+	PrincipalAmount     money.Money `json:"principal_amount"`
+	Rate                money.Rate  `json:"rate"`
+	ROI                 money.Rate  `json:"roi"`
+	AgreementLetterLink string      `json:"agreement_letter_link"`
+	TenorMonths         int         `json:"tenor_months"`
+	RepaymentMethod     string      `json:"repayment_method"`
+}
+
+// validate checks the loan's fields. A new loan needs a borrower and a principal; an
+// update may leave them out, since the terms of an approved loan cannot change anyway.
+func (req *loanRequest) validate(creating bool) error {
+	var v fieldValidator
+	v.check(req.BorrowerID > 0 || (!creating && req.BorrowerID == 0), "borrower_id", fieldRequired, "is required")
+	v.check(req.PrincipalAmount.IsPositive() || (!creating && req.PrincipalAmount.IsZero()), "principal_amount", fieldOutOfRange, "must be greater than 0")
+	v.check(req.Rate >= 0 && req.Rate <= money.OneHundredPercent, "rate", fieldOutOfRange, "must be between 0 and 1")
+	v.check(req.ROI >= 0 && req.ROI <= money.OneHundredPercent, "roi", fieldOutOfRange, "must be between 0 and 1")
+	v.url("agreement_letter_link", req.AgreementLetterLink)
+	// Zero and empty pick the defaults
+	v.check(req.TenorMonths >= 0 && req.TenorMonths <= repayment.MaxTenorMonths, "tenor_months", fieldOutOfRange, fmt.Sprintf("must be between 1 and %d", repayment.MaxTenorMonths))
+	v.check(req.RepaymentMethod == "" || repayment.Method(req.RepaymentMethod).Valid(), "repayment_method", fieldInvalidChoice, "must be one of flat, annuity or bullet")
+	return v.err()
+}
+
 func (h *LoanHandler) CreateLoan(w http.ResponseWriter, r *http.Request) {
-	var loan struct {
-		BorrowerID          int     `json:"borrower_id"`
-		PrincipalAmount     float64 `json:"principal_amount"`
-		Rate                float64 `json:"rate"`
-		ROI                 float64 `json:"roi"`
-		AgreementLetterLink string  `json:"agreement_letter_link"`
+	var loan loanRequest
+	if err := json.NewDecoder(r.Body).Decode(&loan); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&loan); err != nil {
-		SendErrorResponse(w, "Invalid request body", err)
+	if err := loan.validate(true); err != nil {
+		SendErrorResponse(w, "Invalid loan", err)
 		return
 	}
 
@@ -47,6 +88,8 @@ func (h *LoanHandler) CreateLoan(w http.ResponseWriter, r *http.Request) {
 		Rate:                loan.Rate,
 		ROI:                 loan.ROI,
 		AgreementLetterLink: getNullString(loan.AgreementLetterLink),
+		TenorMonths:         loan.TenorMonths,
+		RepaymentMethod:     loan.RepaymentMethod,
 	}
 
 	if err := h.loanService.CreateLoan(r.Context(), model); err != nil {
@@ -60,7 +103,7 @@ func (h *LoanHandler) CreateLoan(w http.ResponseWriter, r *http.Request) {
 func (h *LoanHandler) GetLoanByID(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		SendErrorResponse(w, "Invalid loan ID", err)
+		sendBadRequest(w, "Invalid loan ID", err)
 		return
 	}
 
@@ -76,20 +119,18 @@ func (h *LoanHandler) GetLoanByID(w http.ResponseWriter, r *http.Request) {
 func (h *LoanHandler) UpdateLoan(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		SendErrorResponse(w, "Invalid loan ID", err)
+		sendBadRequest(w, "Invalid loan ID", err)
 		return
 	}
 
-	var loan struct {
-		BorrowerID          int     `json:"borrower_id"`
-		PrincipalAmount     float64 `json:"principal_amount"`
-		Rate                float64 `json:"rate"`
-		ROI                 float64 `json:"roi"`
-		AgreementLetterLink string  `json:"agreement_letter_link"`
+	var loan loanRequest
+	if err := json.NewDecoder(r.Body).Decode(&loan); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&loan); err != nil {
-		SendErrorResponse(w, "Invalid request body", err)
+	if err := loan.validate(false); err != nil {
+		SendErrorResponse(w, "Invalid loan", err)
 		return
 	}
 
@@ -99,6 +140,8 @@ func (h *LoanHandler) UpdateLoan(w http.ResponseWriter, r *http.Request) {
 		Rate:                loan.Rate,
 		ROI:                 loan.ROI,
 		AgreementLetterLink: getNullString(loan.AgreementLetterLink),
+		TenorMonths:         loan.TenorMonths,
+		RepaymentMethod:     loan.RepaymentMethod,
 	}
 
 	if err := h.loanService.UpdateLoan(r.Context(), id, model); err != nil {
@@ -112,7 +155,7 @@ func (h *LoanHandler) UpdateLoan(w http.ResponseWriter, r *http.Request) {
 func (h *LoanHandler) DeleteLoan(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		SendErrorResponse(w, "Invalid loan ID", err)
+		sendBadRequest(w, "Invalid loan ID", err)
 		return
 	}
 
@@ -124,56 +167,146 @@ func (h *LoanHandler) DeleteLoan(w http.ResponseWriter, r *http.Request) {
 	SendSuccessResponse(w, nil, "Loan deleted successfully")
 }
 
+// ListLoans lists loans a page at a time, sorted by sort and paged with cursor and limit,
+// optionally filtered as loanFilter reads
 func (h *LoanHandler) ListLoans(w http.ResponseWriter, r *http.Request) {
-	state := r.URL.Query().Get("state")
-	if state == "" {
-		state = ""
+	filter, err := loanFilter(r.URL.Query())
+	if err != nil {
+		sendBadRequest(w, "Invalid loan filter", err)
+		return
 	}
 
-	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
-	if err != nil || offset < 0 {
-		offset = 0
+	page, err := pageRequest(r)
+	if err != nil {
+		sendBadRequest(w, "Invalid page request", err)
+		return
 	}
 
-	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
-	if err != nil || limit < 1 {
-		limit = 10
+	loans, err := h.loanService.ListLoans(r.Context(), filter, page)
+	if err != nil {
+		SendErrorResponse(w, "Failed to list loans", err)
+		return
 	}
 
-	var statePtr *string
-	if state != "" {
-		statePtr = &state
+	SendSuccessResponse(w, loans, "Loans retrieved successfully")
+}
+
+// loanFilter reads the state, borrower_id, min_principal, max_principal, min_rate, max_rate,
+// created_from, created_to and funding filters of the loan listing
+func loanFilter(query url.Values) (models.LoanFilter, error) {
+	filter := models.LoanFilter{
+		State:   query.Get("state"),
+		Funding: query.Get("funding"),
 	}
 
-	loans, err := h.loanService.ListLoans(r.Context(), statePtr, offset, limit)
+	var err error
+	if borrowerID := query.Get("borrower_id"); borrowerID != "" {
+		if filter.BorrowerID, err = strconv.Atoi(borrowerID); err != nil {
+			return filter, fmt.Errorf("borrower_id must be a number")
+		}
+	}
+	if filter.MinPrincipal, err = amountParam(query, "min_principal"); err != nil {
+		return filter, err
+	}
+	if filter.MaxPrincipal, err = amountParam(query, "max_principal"); err != nil {
+		return filter, err
+	}
+	if filter.MinRate, err = rateParam(query, "min_rate"); err != nil {
+		return filter, err
+	}
+	if filter.MaxRate, err = rateParam(query, "max_rate"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedFrom, err = timeParam(query, "created_from"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = timeParam(query, "created_to"); err != nil {
+		return filter, err
+	}
+
+	if filter.Funding != "" && filter.Funding != models.FundingFull && filter.Funding != models.FundingPartial {
+		return filter, fmt.Errorf("funding must be %s or %s", models.FundingFull, models.FundingPartial)
+	}
+
+	return filter, nil
+}
+
+// amountParam reads an optional amount query parameter such as "1500000.00"
+func amountParam(query url.Values, param string) (*money.Money, error) {
+	value := query.Get(param)
+	if value == "" {
+		return nil, nil
+	}
+
+	amount, err := money.Parse(value, money.DefaultCurrency)
 	if err != nil {
-		SendErrorResponse(w, "Failed to list loans", err)
-		return
+		return nil, fmt.Errorf("%s: %w", param, err)
 	}
+	return &amount, nil
+}
 
-	SendSuccessResponse(w, loans, "Loans retrieved successfully")
+// rateParam reads an optional rate query parameter such as "0.12"
+func rateParam(query url.Values, param string) (*money.Rate, error) {
+	value := query.Get(param)
+	if value == "" {
+		return nil, nil
+	}
+
+	rate, err := money.ParseRate(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", param, err)
+	}
+	return &rate, nil
+}
+
+// timeParam reads an optional RFC 3339 timestamp query parameter
+func timeParam(query url.Values, param string) (*time.Time, error) {
+	value := query.Get(param)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC 3339 timestamp such as 2024-01-31T00:00:00Z", param)
+	}
+	return &t, nil
+}
+
+// approveLoanRequest is the body of the approve request. The field validator is the
+// signed-in user, so the body only carries the evidence.
+type approveLoanRequest struct {
+	ProofImageUrl string `json:"proof_image_url"`
+}
+
+func (req *approveLoanRequest) validate() error {
+	var v fieldValidator
+	if v.required("proof_image_url", req.ProofImageUrl) {
+		v.url("proof_image_url", req.ProofImageUrl)
+	}
+	return v.err()
 }
 
 func (h *LoanHandler) ApproveLoan(w http.ResponseWriter, r *http.Request) {
 	loanID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		SendErrorResponse(w, "Invalid loan ID", err)
+		sendBadRequest(w, "Invalid loan ID", err)
 		return
 	}
 
-	var approvalData struct {
-		FieldValidatorEmployeeID string `json:"field_validator_employee_id"`
-		ProofImageUrl            string `json:"proof_image_url"`
+	var approvalData approveLoanRequest
+	if err := json.NewDecoder(r.Body).Decode(&approvalData); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&approvalData); err != nil {
-		SendErrorResponse(w, "Invalid request body", err)
+	if err := approvalData.validate(); err != nil {
+		SendErrorResponse(w, "Invalid loan approval", err)
 		return
 	}
 
 	model := &models.LoanApproval{
-		FieldValidatorEmployeeID: approvalData.FieldValidatorEmployeeID,
-		ProofImageUrl:            approvalData.ProofImageUrl,
+		ProofImageUrl: approvalData.ProofImageUrl,
 	}
 
 	if err := h.loanService.ApproveLoan(r.Context(), loanID, model); err != nil {
@@ -184,20 +317,34 @@ func (h *LoanHandler) ApproveLoan(w http.ResponseWriter, r *http.Request) {
 	SendSuccessResponse(w, nil, "Loan approved successfully")
 }
 
+// investRequest is the body of the invest request
+type investRequest struct {
+	InvestorID       int         `json:"investor_id"`
+	InvestmentAmount money.Money `json:"investment_amount"`
+}
+
+func (req *investRequest) validate() error {
+	var v fieldValidator
+	v.check(req.InvestorID > 0, "investor_id", fieldRequired, "is required")
+	v.check(req.InvestmentAmount.IsPositive(), "investment_amount", fieldOutOfRange, "must be greater than 0")
+	return v.err()
+}
+
 func (h *LoanHandler) InvestInLoan(w http.ResponseWriter, r *http.Request) {
 	loanID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		SendErrorResponse(w, "Invalid loan ID", err)
+		sendBadRequest(w, "Invalid loan ID", err)
 		return
 	}
 
-	var investmentData struct {
-		InvestorID       int     `json:"investor_id"`
-		InvestmentAmount float64 `json:"investment_amount"`
+	var investmentData investRequest
+	if err := json.NewDecoder(r.Body).Decode(&investmentData); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&investmentData); err != nil {
-		SendErrorResponse(w, "Invalid request body", err)
+	if err := investmentData.validate(); err != nil {
+		SendErrorResponse(w, "Invalid investment", err)
 		return
 	}
 
@@ -214,25 +361,39 @@ func (h *LoanHandler) InvestInLoan(w http.ResponseWriter, r *http.Request) {
 	SendSuccessResponse(w, nil, "Investment completed successfully")
 }
 
+// disburseLoanRequest is the body of the disburse request. The field officer is the
+// signed-in user, so the body only carries the signed agreement.
+type disburseLoanRequest struct {
+	AgreementLetterSignedUrl string `json:"agreement_letter_signed_url"`
+}
+
+func (req *disburseLoanRequest) validate() error {
+	var v fieldValidator
+	if v.required("agreement_letter_signed_url", req.AgreementLetterSignedUrl) {
+		v.url("agreement_letter_signed_url", req.AgreementLetterSignedUrl)
+	}
+	return v.err()
+}
+
 func (h *LoanHandler) DisburseLoan(w http.ResponseWriter, r *http.Request) {
 	loanID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		SendErrorResponse(w, "Invalid loan ID", err)
+		sendBadRequest(w, "Invalid loan ID", err)
 		return
 	}
 
-	var disbursementData struct {
-		FieldOfficerEmployeeID   string `json:"field_officer_employee_id"`
-		AgreementLetterSignedUrl string `json:"agreement_letter_signed_url"`
+	var disbursementData disburseLoanRequest
+	if err := json.NewDecoder(r.Body).Decode(&disbursementData); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&disbursementData); err != nil {
-		SendErrorResponse(w, "Invalid request body", err)
+	if err := disbursementData.validate(); err != nil {
+		SendErrorResponse(w, "Invalid disbursement", err)
 		return
 	}
 
 	model := &models.LoanDisbursement{
-		FieldOfficerEmployeeID:   disbursementData.FieldOfficerEmployeeID,
 		AgreementLetterSignedUrl: disbursementData.AgreementLetterSignedUrl,
 	}
 
@@ -244,6 +405,209 @@ func (h *LoanHandler) DisburseLoan(w http.ResponseWriter, r *http.Request) {
 	SendSuccessResponse(w, nil, "Loan disbursed successfully")
 }
 
+// rejectLoanRequest is the body of the reject request
+type rejectLoanRequest struct {
+	ReasonCode string `json:"reason_code"`
+	Notes      string `json:"notes"`
+}
+
+func (req *rejectLoanRequest) validate() error {
+	var v fieldValidator
+	v.required("reason_code", req.ReasonCode)
+	v.maxLength("notes", req.Notes, maxNotesLength)
+	return v.err()
+}
+
+func (h *LoanHandler) RejectLoan(w http.ResponseWriter, r *http.Request) {
+	loanID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		sendBadRequest(w, "Invalid loan ID", err)
+		return
+	}
+
+	var rejectionData rejectLoanRequest
+	if err := json.NewDecoder(r.Body).Decode(&rejectionData); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	if err := rejectionData.validate(); err != nil {
+		SendErrorResponse(w, "Invalid loan rejection", err)
+		return
+	}
+
+	model := &models.LoanRejection{
+		ReasonCode: rejectionData.ReasonCode,
+		Notes:      rejectionData.Notes,
+	}
+
+	if err := h.loanService.RejectLoan(r.Context(), loanID, model); err != nil {
+		SendErrorResponse(w, "Failed to reject loan", err)
+		return
+	}
+
+	SendSuccessResponse(w, model, "Loan rejected successfully")
+}
+
+func (h *LoanHandler) ListRejectionReasons(w http.ResponseWriter, r *http.Request) {
+	activeOnly := r.URL.Query().Get("active") == "true"
+
+	reasons, err := h.loanService.ListRejectionReasons(r.Context(), activeOnly)
+	if err != nil {
+		SendErrorResponse(w, "Failed to list rejection reasons", err)
+		return
+	}
+
+	SendSuccessResponse(w, reasons, "Rejection reasons retrieved successfully")
+}
+
+// rejectionReasonRequest is the body of the create and update rejection reason requests.
+// The code of an existing reason is in the path, and only updates set IsActive.
+type rejectionReasonRequest struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	IsActive    bool   `json:"is_active"`
+}
+
+func (req *rejectionReasonRequest) validate(creating bool) error {
+	var v fieldValidator
+	if creating && v.required("code", req.Code) {
+		v.check(rejectionReasonCodePattern.MatchString(req.Code), "code", fieldInvalidFormat, "must be lowercase letters, digits and underscores")
+		v.maxLength("code", req.Code, maxReasonCodeLength)
+	}
+	if v.required("description", req.Description) {
+		v.maxLength("description", req.Description, maxDescriptionLength)
+	}
+	return v.err()
+}
+
+func (h *LoanHandler) CreateRejectionReason(w http.ResponseWriter, r *http.Request) {
+	var reasonData rejectionReasonRequest
+	if err := json.NewDecoder(r.Body).Decode(&reasonData); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	if err := reasonData.validate(true); err != nil {
+		SendErrorResponse(w, "Invalid rejection reason", err)
+		return
+	}
+
+	model := &models.LoanRejectionReason{
+		Code:        reasonData.Code,
+		Description: reasonData.Description,
+	}
+
+	if err := h.loanService.CreateRejectionReason(r.Context(), model); err != nil {
+		SendErrorResponse(w, "Failed to create rejection reason", err)
+		return
+	}
+
+	SendSuccessResponse(w, model, "Rejection reason created successfully")
+}
+
+func (h *LoanHandler) UpdateRejectionReason(w http.ResponseWriter, r *http.Request) {
+	code := chi.URLParam(r, "code")
+
+	var reasonData rejectionReasonRequest
+	if err := json.NewDecoder(r.Body).Decode(&reasonData); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	if err := reasonData.validate(false); err != nil {
+		SendErrorResponse(w, "Invalid rejection reason", err)
+		return
+	}
+
+	model := &models.LoanRejectionReason{
+		Description: reasonData.Description,
+		IsActive:    reasonData.IsActive,
+	}
+
+	if err := h.loanService.UpdateRejectionReason(r.Context(), code, model); err != nil {
+		SendErrorResponse(w, "Failed to update rejection reason", err)
+		return
+	}
+
+	SendSuccessResponse(w, model, "Rejection reason updated successfully")
+}
+
+func (h *LoanHandler) CancelLoan(w http.ResponseWriter, r *http.Request) {
+	h.transitionLoan(w, r, h.loanService.CancelLoan, "cancel", "Loan cancelled successfully")
+}
+
+func (h *LoanHandler) ExpireLoan(w http.ResponseWriter, r *http.Request) {
+	h.transitionLoan(w, r, h.loanService.ExpireLoan, "expire", "Loan expired successfully")
+}
+
+func (h *LoanHandler) MarkLoanRepaid(w http.ResponseWriter, r *http.Request) {
+	h.transitionLoan(w, r, h.loanService.MarkLoanRepaid, "mark repaid", "Loan marked as repaid successfully")
+}
+
+func (h *LoanHandler) CloseLoan(w http.ResponseWriter, r *http.Request) {
+	h.transitionLoan(w, r, h.loanService.CloseLoan, "close", "Loan closed successfully")
+}
+
+func (h *LoanHandler) DefaultLoan(w http.ResponseWriter, r *http.Request) {
+	h.transitionLoan(w, r, h.loanService.DefaultLoan, "default", "Loan marked as defaulted successfully")
+}
+
+func (h *LoanHandler) WriteOffLoan(w http.ResponseWriter, r *http.Request) {
+	h.transitionLoan(w, r, h.loanService.WriteOffLoan, "write off", "Loan written off successfully")
+}
+
+// transitionRequest is the body of the state transitions whose only input is a reason
+type transitionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// transitionLoan handles the state transitions whose only input is a reason
+func (h *LoanHandler) transitionLoan(w http.ResponseWriter, r *http.Request, transition func(ctx context.Context, loanID int, reason string) error, action, successMessage string) {
+	loanID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		sendBadRequest(w, "Invalid loan ID", err)
+		return
+	}
+
+	var transitionData transitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&transitionData); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	var v fieldValidator
+	v.required("reason", transitionData.Reason)
+	v.maxLength("reason", transitionData.Reason, maxNotesLength)
+	if err := v.err(); err != nil {
+		SendErrorResponse(w, "Invalid request", err)
+		return
+	}
+
+	if err := transition(r.Context(), loanID, transitionData.Reason); err != nil {
+		SendErrorResponse(w, "Failed to "+action+" loan", err)
+		return
+	}
+
+	SendSuccessResponse(w, nil, successMessage)
+}
+
+func (h *LoanHandler) GetRepaymentSchedule(w http.ResponseWriter, r *http.Request) {
+	loanID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		sendBadRequest(w, "Invalid loan ID", err)
+		return
+	}
+
+	installments, err := h.loanService.GetRepaymentSchedule(r.Context(), loanID)
+	if err != nil {
+		SendErrorResponse(w, "Failed to get repayment schedule", err)
+		return
+	}
+
+	SendSuccessResponse(w, installments, "Repayment schedule retrieved successfully")
+}
+
 func (h *LoanHandler) GetLoansByState(w http.ResponseWriter, r *http.Request) {
 	state := chi.URLParam(r, "state")
 
@@ -8,10 +8,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/sswastioyono18/loan-engine/internal/models"
 	"github.com/sswastioyono18/loan-engine/internal/services/mocks"
 	mocks2 "github.com/sswastioyono18/loan-engine/pkg/external/mocks"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -41,9 +43,9 @@ func TestLoanHandlerCreateLoan(t *testing.T) {
 	// Create expected model that the service will receive
 	expectedModel := &models.Loan{
 		BorrowerID:          1,
-		PrincipalAmount:     10000.0,
-		Rate:                0.05,
-		ROI:                 0.08,
+		PrincipalAmount:     money.MustParse("10000.00"),
+		Rate:                money.MustParseRate("0.05"),
+		ROI:                 money.MustParseRate("0.08"),
 		AgreementLetterLink: sql.NullString{String: "https://example.com/agreement.pdf", Valid: true},
 	}
 
@@ -71,9 +73,9 @@ func TestLoanHandlerGetLoanByID(t *testing.T) {
 	loan := &models.Loan{
 		ID:                  1,
 		BorrowerID:          1,
-		PrincipalAmount:     10000.0,
-		Rate:                0.05,
-		ROI:                 0.08,
+		PrincipalAmount:     money.MustParse("10000.00"),
+		Rate:                money.MustParseRate("0.05"),
+		ROI:                 money.MustParseRate("0.08"),
 		AgreementLetterLink: sql.NullString{String: "https://example.com/agreement.pdf", Valid: true},
 		CurrentState:        "proposed",
 	}
@@ -101,6 +103,9 @@ func TestLoanHandlerGetLoanByID(t *testing.T) {
 	}
 	
 	assert.Equal(t, float64(1), data["id"])
+	assert.Equal(t, "10000.00", data["principal_amount"])
+	assert.Equal(t, "0.0500", data["rate"])
+	assert.Equal(t, "0.0800", data["roi"])
 	mockLoanService.AssertExpectations(t)
 }
 
@@ -127,7 +132,10 @@ func TestLoanHandlerApproveLoan(t *testing.T) {
 	rctx.URLParams.Add("id", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
 
-	mockLoanService.On("ApproveLoan", mock.Anything, 1, mock.AnythingOfType("*models.LoanApproval")).Return(nil)
+	// The employee claimed in the body is ignored; the service takes it from the signed-in user
+	mockLoanService.On("ApproveLoan", mock.Anything, 1, mock.MatchedBy(func(approval *models.LoanApproval) bool {
+		return approval.FieldValidatorEmployeeID == "" && approval.ProofImageUrl == "https://example.com/proof.jpg"
+	})).Return(nil)
 
 	handler.ApproveLoan(rr, req)
 
@@ -144,7 +152,7 @@ func TestLoanHandlerInvestInLoan(t *testing.T) {
 
 	investmentReq := &models.LoanInvestment{
 		InvestorID:       1,
-		InvestmentAmount: 5000.0,
+		InvestmentAmount: money.MustParse("5000.00"),
 	}
 
 	investmentReqBytes, _ := json.Marshal(investmentReq)
@@ -195,4 +203,148 @@ func TestLoanHandlerDisburseLoan(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 	mockLoanService.AssertExpectations(t)
-}
\ No newline at end of file
+}
+func TestLoanHandlerGetRepaymentSchedule(t *testing.T) {
+	mockLoanService := mocks.NewLoanService(t)
+	mockEmailService := mocks2.NewEmailService(t)
+	mockStorageService := mocks2.NewStorageService(t)
+
+	handler := NewLoanHandler(mockLoanService, mockEmailService, mockStorageService)
+
+	installments := []*models.LoanInstallment{
+		{
+			ID:                1,
+			LoanID:            1,
+			InstallmentNumber: 1,
+			PrincipalAmount:   money.MustParse("833.33"),
+			InterestAmount:    money.MustParse("41.66"),
+			TotalAmount:       money.MustParse("874.99"),
+			Status:            "pending",
+		},
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1/loans/1/schedule", nil)
+	rr := httptest.NewRecorder()
+
+	// Set up chi URL parameters
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	mockLoanService.On("GetRepaymentSchedule", mock.Anything, 1).Return(installments, nil)
+
+	handler.GetRepaymentSchedule(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response map[string]interface{}
+	json.Unmarshal(rr.Body.Bytes(), &response)
+
+	data, ok := response["data"].([]interface{})
+	if !ok {
+		t.Fatalf("Expected response.data to be a list, got %T", response["data"])
+	}
+
+	assert.Len(t, data, 1)
+	assert.Equal(t, "874.99", data[0].(map[string]interface{})["total_amount"])
+	mockLoanService.AssertExpectations(t)
+}
+
+func TestLoanHandlerRejectLoan(t *testing.T) {
+	mockLoanService := mocks.NewLoanService(t)
+	mockEmailService := mocks2.NewEmailService(t)
+	mockStorageService := mocks2.NewStorageService(t)
+
+	handler := NewLoanHandler(mockLoanService, mockEmailService, mockStorageService)
+
+	rejectReqBytes, _ := json.Marshal(map[string]string{
+		"reason_code":             "insufficient_income",
+		"notes":                   "Payslips cover three months only",
+		"rejected_by_employee_id": "emp003",
+	})
+
+	req, _ := http.NewRequest("POST", "/api/v1/loans/1/reject", bytes.NewBuffer(rejectReqBytes))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	// Set up chi URL parameters
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	// The employee claimed in the body is ignored; the service takes it from the signed-in user
+	mockLoanService.On("RejectLoan", mock.Anything, 1, mock.MatchedBy(func(rejection *models.LoanRejection) bool {
+		return rejection.ReasonCode == "insufficient_income" &&
+			rejection.Notes == "Payslips cover three months only" &&
+			rejection.RejectedByEmployeeID == ""
+	})).Return(nil)
+
+	handler.RejectLoan(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockLoanService.AssertExpectations(t)
+}
+
+func TestLoanHandlerListLoans(t *testing.T) {
+	mockLoanService := mocks.NewLoanService(t)
+	handler := NewLoanHandler(mockLoanService, mocks2.NewEmailService(t), mocks2.NewStorageService(t))
+
+	createdFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	minPrincipal := money.MustParse("1000000.00")
+	maxRate := money.MustParseRate("0.12")
+	wantFilter := models.LoanFilter{
+		State:        "approved",
+		BorrowerID:   7,
+		MinPrincipal: &minPrincipal,
+		MaxRate:      &maxRate,
+		CreatedFrom:  &createdFrom,
+		Funding:      models.FundingPartial,
+	}
+	wantPage := models.PageRequest{
+		Cursor: "abc",
+		Limit:  20,
+		Sort:   []models.SortField{{Field: "created_at"}, {Field: "principal_amount", Descending: true}},
+	}
+	mockLoanService.On("ListLoans", mock.Anything, wantFilter, wantPage).Return(&models.Page[*models.Loan]{
+		Items:         []*models.Loan{{ID: 1, CurrentState: "approved"}},
+		NextCursor:    "def",
+		TotalEstimate: 35,
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/loans?state=approved&borrower_id=7&min_principal=1000000.00"+
+		"&max_rate=0.12&created_from=2024-01-01T00:00:00Z&funding=partial"+
+		"&cursor=abc&limit=20&sort=created_at,-principal_amount", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ListLoans(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data models.Page[map[string]interface{}] `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Data.Items, 1)
+	assert.Equal(t, "def", response.Data.NextCursor)
+	assert.Equal(t, 35, response.Data.TotalEstimate)
+}
+
+func TestLoanHandlerListLoansRejectsInvalidParameters(t *testing.T) {
+	handler := NewLoanHandler(mocks.NewLoanService(t), mocks2.NewEmailService(t), mocks2.NewStorageService(t))
+
+	for _, query := range []string{
+		"borrower_id=abc",
+		"min_principal=lots",
+		"max_rate=high",
+		"created_to=yesterday",
+		"funding=none",
+		"limit=0",
+		"limit=101",
+		"sort=created_at,,id",
+	} {
+		req := httptest.NewRequest("GET", "/api/v1/loans?"+query, nil)
+		rr := httptest.NewRecorder()
+
+		handler.ListLoans(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
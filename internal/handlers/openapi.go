@@ -0,0 +1,445 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sswastioyono18/loan-engine/internal/authz"
+	"github.com/sswastioyono18/loan-engine/internal/ledger"
+	"github.com/sswastioyono18/loan-engine/internal/loanstate"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/openapi"
+	"github.com/sswastioyono18/loan-engine/internal/services"
+	"github.com/sswastioyono18/loan-engine/internal/signing"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+)
+
+// Security schemes of the API
+const (
+	bearerAuth = "bearerAuth"
+	apiKeyAuth = "apiKeyAuth"
+)
+
+// Fields the listings can be sorted by
+var (
+	loanSortFields   = []string{"id", "created_at", "principal_amount", "rate", "roi", "total_invested_amount"}
+	personSortFields = []string{"id", "created_at", "full_name", "email"}
+)
+
+// endpoint describes a route for the OpenAPI document. Request is the type its body is
+// decoded into and response the type of the data in its Response envelope; either is nil
+// when there is none. Routes that are not public need a signed-in user or an API key and,
+// unless permission is empty, the permission.
+type endpoint struct {
+	method      string
+	path        string
+	operationID string
+	summary     string
+	tag         string
+	public      bool
+	permission  authz.Action
+	idempotent  bool
+	params      []*openapi.Parameter
+	request     interface{}
+	response    interface{}
+}
+
+// endpoints lists every route under /api/v1. Keep it in step with NewRouter; the tests
+// fail for a route that is missing here.
+func endpoints() []endpoint {
+	var (
+		nothing    interface{}
+		pageParams = func(sortFields []string) []*openapi.Parameter {
+			return []*openapi.Parameter{
+				queryParam("cursor", "The next_cursor of the previous page", &openapi.Schema{Type: openapi.Types{"string"}}),
+				queryParam("limit", "Number of items per page", integerSchema(1, maxPageSize)),
+				queryParam("sort", "Comma-separated fields to sort by, each descending when prefixed with -: "+strings.Join(sortFields, ", "), sortSchema(sortFields)),
+			}
+		}
+		offsetParams = []*openapi.Parameter{
+			queryParam("offset", "Number of items to skip", integerSchema(0, -1)),
+			queryParam("limit", "Number of items to return", integerSchema(1, -1)),
+		}
+	)
+
+	loanFilterParams := []*openapi.Parameter{
+		queryParam("state", "Only loans in this state", enumSchema(loanstate.States(loanstate.Transitions)...)),
+		queryParam("borrower_id", "Only loans of this borrower", integerSchema(1, -1)),
+		queryParam("min_principal", "Only loans whose principal amount is at least this", openapi.Ref("Money")),
+		queryParam("max_principal", "Only loans whose principal amount is at most this", openapi.Ref("Money")),
+		queryParam("min_rate", "Only loans whose rate is at least this", openapi.Ref("Rate")),
+		queryParam("max_rate", "Only loans whose rate is at most this", openapi.Ref("Rate")),
+		queryParam("created_from", "Only loans created at or after this time", &openapi.Schema{Type: openapi.Types{"string"}, Format: "date-time"}),
+		queryParam("created_to", "Only loans created at or before this time", &openapi.Schema{Type: openapi.Types{"string"}, Format: "date-time"}),
+		queryParam("funding", "full for loans whose investments cover the principal amount, partial for loans with some but not enough investment", enumSchema(models.FundingFull, models.FundingPartial)),
+	}
+
+	return []endpoint{
+		// Authentication
+		{method: "POST", path: "/auth/register", operationID: "registerUser", summary: "Register an investor", tag: "Authentication", public: true, request: registerRequest{}, response: models.User{}},
+		{method: "POST", path: "/auth/login", operationID: "login", summary: "Log in, or start a login that needs a TOTP code", tag: "Authentication", public: true, request: loginRequest{}, response: services.LoginResult{}},
+		{method: "POST", path: "/auth/login/totp", operationID: "completeTOTPLogin", summary: "Complete a login with a TOTP or recovery code", tag: "Authentication", public: true, request: totpLoginRequest{}, response: services.LoginResult{}},
+		{method: "POST", path: "/auth/refresh", operationID: "refreshToken", summary: "Exchange a refresh token for a new token pair", tag: "Authentication", public: true, request: refreshTokenRequest{}, response: services.TokenPair{}},
+		{method: "POST", path: "/auth/logout", operationID: "logout", summary: "End the session of a refresh token", tag: "Authentication", public: true, request: refreshTokenRequest{}, response: nothing},
+		{method: "POST", path: "/auth/forgot-password", operationID: "forgotPassword", summary: "Mail a password reset token", tag: "Authentication", public: true, request: emailRequest{}, response: nothing},
+		{method: "POST", path: "/auth/reset-password", operationID: "resetPassword", summary: "Set a new password with a reset token", tag: "Authentication", public: true, request: resetPasswordRequest{}, response: nothing},
+		{method: "POST", path: "/auth/verify-email", operationID: "verifyEmail", summary: "Verify an investor's email address", tag: "Authentication", public: true, request: verifyEmailRequest{}, response: nothing},
+		{method: "POST", path: "/auth/resend-verification", operationID: "resendVerification", summary: "Mail a new email verification token", tag: "Authentication", public: true, request: emailRequest{}, response: nothing},
+		{method: "POST", path: "/auth/logout-all", operationID: "logoutAll", summary: "End every session of the signed-in user", tag: "Authentication"},
+		{method: "POST", path: "/auth/change-password", operationID: "changePassword", summary: "Change the signed-in user's password", tag: "Authentication", request: changePasswordRequest{}},
+		{method: "POST", path: "/auth/totp/enrol", operationID: "enrolTOTP", summary: "Start enrolling in two-factor authentication", tag: "Authentication", response: services.TOTPEnrolment{}},
+		{method: "POST", path: "/auth/totp/confirm", operationID: "confirmTOTP", summary: "Enable two-factor authentication", tag: "Authentication", request: totpCodeRequest{}, response: recoveryCodesResponse{}},
+		{method: "POST", path: "/auth/totp/disable", operationID: "disableTOTP", summary: "Disable two-factor authentication", tag: "Authentication", request: passwordRequest{}},
+		{method: "POST", path: "/auth/totp/recovery-codes", operationID: "regenerateRecoveryCodes", summary: "Replace the recovery codes", tag: "Authentication", request: totpCodeRequest{}, response: recoveryCodesResponse{}},
+
+		// Users
+		{method: "GET", path: "/users", operationID: "listUsers", summary: "List users", tag: "Users", permission: authz.ReadUsers, response: services.UserPage{}, params: append([]*openapi.Parameter{
+			queryParam("search", "Part of the email, name or user ID, ignoring case", &openapi.Schema{Type: openapi.Types{"string"}}),
+			queryParam("user_type", "Only users of this type", enumSchema(authz.RoleAdmin, authz.RoleStaff, authz.RoleInvestor)),
+			queryParam("active", "Only active or only deactivated users", &openapi.Schema{Type: openapi.Types{"boolean"}}),
+			queryParam("include_deleted", "Include deleted users", &openapi.Schema{Type: openapi.Types{"boolean"}}),
+		}, offsetParams...)},
+		{method: "POST", path: "/users", operationID: "createUser", summary: "Create a staff or admin user", tag: "Users", permission: authz.ManageUsers, request: createUserRequest{}, response: models.User{}},
+		{method: "GET", path: "/users/{id}", operationID: "getUser", summary: "Get a user", tag: "Users", permission: authz.ReadUsers, response: models.User{}},
+		{method: "POST", path: "/users/{id}/deactivate", operationID: "deactivateUser", summary: "Deactivate a user", tag: "Users", permission: authz.ManageUsers, response: models.User{}},
+		{method: "POST", path: "/users/{id}/activate", operationID: "activateUser", summary: "Activate a user", tag: "Users", permission: authz.ManageUsers, response: models.User{}},
+		{method: "DELETE", path: "/users/{id}", operationID: "deleteUser", summary: "Delete a user", tag: "Users", permission: authz.ManageUsers},
+		{method: "POST", path: "/users/{id}/restore", operationID: "restoreUser", summary: "Restore a deleted user", tag: "Users", permission: authz.ManageUsers},
+		{method: "PUT", path: "/users/{id}/role", operationID: "changeRole", summary: "Change a user's role", tag: "Users", permission: authz.ManageUsers, request: changeRoleRequest{}, response: models.User{}},
+		{method: "POST", path: "/users/{id}/reset-password", operationID: "forcePasswordReset", summary: "Revoke a user's password and mail them a reset token", tag: "Users", permission: authz.ManageUsers},
+
+		// API keys
+		{method: "GET", path: "/api-keys", operationID: "listAPIKeys", summary: "List API keys", tag: "API Keys", permission: authz.ManageAPIKeys, params: offsetParams, response: []*models.APIKey{}},
+		{method: "POST", path: "/api-keys", operationID: "createAPIKey", summary: "Create an API key", tag: "API Keys", permission: authz.ManageAPIKeys, request: apiKeyRequest{}, response: services.CreatedAPIKey{}},
+		{method: "GET", path: "/api-keys/{id}", operationID: "getAPIKey", summary: "Get an API key", tag: "API Keys", permission: authz.ManageAPIKeys, response: models.APIKey{}},
+		{method: "PUT", path: "/api-keys/{id}", operationID: "updateAPIKey", summary: "Update an API key", tag: "API Keys", permission: authz.ManageAPIKeys, request: apiKeyRequest{}, response: models.APIKey{}},
+		{method: "DELETE", path: "/api-keys/{id}", operationID: "revokeAPIKey", summary: "Revoke an API key", tag: "API Keys", permission: authz.ManageAPIKeys},
+
+		// Borrowers
+		{method: "POST", path: "/borrowers", operationID: "createBorrower", summary: "Create a borrower", tag: "Borrowers", permission: authz.ManageBorrowers, request: borrowerRequest{}, response: models.Borrower{}},
+		{method: "GET", path: "/borrowers/{id}", operationID: "getBorrower", summary: "Get a borrower", tag: "Borrowers", permission: authz.ReadBorrowers, response: models.Borrower{}},
+		{method: "PUT", path: "/borrowers/{id}", operationID: "updateBorrower", summary: "Update a borrower", tag: "Borrowers", permission: authz.ManageBorrowers, request: borrowerRequest{}, response: models.Borrower{}},
+		{method: "DELETE", path: "/borrowers/{id}", operationID: "deleteBorrower", summary: "Delete a borrower", tag: "Borrowers", permission: authz.ManageBorrowers},
+		{method: "GET", path: "/borrowers", operationID: "listBorrowers", summary: "List borrowers", tag: "Borrowers", permission: authz.ReadBorrowers, params: pageParams(personSortFields), response: models.Page[*models.Borrower]{}},
+
+		// Investors
+		{method: "POST", path: "/investors", operationID: "createInvestor", summary: "Create an investor", tag: "Investors", permission: authz.ManageInvestors, request: investorRequest{}, response: models.Investor{}},
+		{method: "GET", path: "/investors/{id}", operationID: "getInvestor", summary: "Get an investor", tag: "Investors", permission: authz.ReadInvestors, response: models.Investor{}},
+		{method: "GET", path: "/investors/{id}/payouts", operationID: "listPayouts", summary: "List an investor's payouts", tag: "Investors", permission: authz.ReadPayouts, response: []*models.Payout{}},
+		{method: "PUT", path: "/investors/{id}", operationID: "updateInvestor", summary: "Update an investor", tag: "Investors", permission: authz.ManageInvestors, request: investorRequest{}, response: models.Investor{}},
+		{method: "DELETE", path: "/investors/{id}", operationID: "deleteInvestor", summary: "Delete an investor", tag: "Investors", permission: authz.ManageInvestors},
+		{method: "GET", path: "/investors", operationID: "listInvestors", summary: "List investors", tag: "Investors", permission: authz.ReadInvestors, params: pageParams(personSortFields), response: models.Page[*models.Investor]{}},
+
+		// Loans
+		{method: "POST", path: "/loans", operationID: "createLoan", summary: "Propose a loan", tag: "Loans", permission: authz.ManageLoans, idempotent: true, request: loanRequest{}, response: models.Loan{}},
+		{method: "GET", path: "/loans/{id}", operationID: "getLoan", summary: "Get a loan", tag: "Loans", permission: authz.ReadLoans, response: models.Loan{}},
+		{method: "PUT", path: "/loans/{id}", operationID: "updateLoan", summary: "Update a loan", tag: "Loans", permission: authz.ManageLoans, request: loanRequest{}, response: models.Loan{}},
+		{method: "DELETE", path: "/loans/{id}", operationID: "deleteLoan", summary: "Delete a loan", tag: "Loans", permission: authz.ManageLoans},
+		{method: "GET", path: "/loans", operationID: "listLoans", summary: "List loans", tag: "Loans", permission: authz.ReadLoans, params: append(loanFilterParams, pageParams(loanSortFields)...), response: models.Page[*models.Loan]{}},
+		{method: "GET", path: "/loans/state/{state}", operationID: "getLoansByState", summary: "List the loans in a state", tag: "Loans", permission: authz.ReadLoans, response: []*models.Loan{}},
+		{method: "POST", path: "/loans/{id}/approve", operationID: "approveLoan", summary: "Approve a loan", tag: "Loans", permission: authz.ApproveLoan, request: approveLoanRequest{}},
+		{method: "POST", path: "/loans/{id}/invest", operationID: "investInLoan", summary: "Invest in a loan", tag: "Loans", permission: authz.InvestInLoan, idempotent: true, request: investRequest{}},
+		{method: "POST", path: "/loans/{id}/disburse", operationID: "disburseLoan", summary: "Disburse a loan", tag: "Loans", permission: authz.DisburseLoan, idempotent: true, request: disburseLoanRequest{}},
+		{method: "POST", path: "/loans/{id}/reject", operationID: "rejectLoan", summary: "Reject a loan", tag: "Loans", permission: authz.RejectLoan, request: rejectLoanRequest{}, response: models.LoanRejection{}},
+		{method: "POST", path: "/loans/{id}/cancel", operationID: "cancelLoan", summary: "Cancel a loan", tag: "Loans", permission: authz.CancelLoan, request: transitionRequest{}},
+		{method: "POST", path: "/loans/{id}/expire", operationID: "expireLoan", summary: "Expire a loan", tag: "Loans", permission: authz.ExpireLoan, request: transitionRequest{}},
+		{method: "POST", path: "/loans/{id}/mark-repaid", operationID: "markLoanRepaid", summary: "Mark a loan repaid", tag: "Loans", permission: authz.MarkLoanRepaid, request: transitionRequest{}},
+		{method: "POST", path: "/loans/{id}/close", operationID: "closeLoan", summary: "Close a loan", tag: "Loans", permission: authz.CloseLoan, request: transitionRequest{}},
+		{method: "POST", path: "/loans/{id}/default", operationID: "defaultLoan", summary: "Mark a loan defaulted", tag: "Loans", permission: authz.DefaultLoan, request: transitionRequest{}},
+		{method: "POST", path: "/loans/{id}/write-off", operationID: "writeOffLoan", summary: "Write off a loan", tag: "Loans", permission: authz.WriteOffLoan, request: transitionRequest{}},
+
+		// Rejection reasons
+		{method: "GET", path: "/loan-rejection-reasons", operationID: "listRejectionReasons", summary: "List rejection reasons", tag: "Rejection Reasons", permission: authz.ReadRejectionReasons, response: []*models.LoanRejectionReason{}, params: []*openapi.Parameter{
+			queryParam("active", "Only reasons that can still be used", &openapi.Schema{Type: openapi.Types{"boolean"}}),
+		}},
+		{method: "POST", path: "/loan-rejection-reasons", operationID: "createRejectionReason", summary: "Create a rejection reason", tag: "Rejection Reasons", permission: authz.ManageRejectionReasons, request: rejectionReasonRequest{}, response: models.LoanRejectionReason{}},
+		{method: "PUT", path: "/loan-rejection-reasons/{code}", operationID: "updateRejectionReason", summary: "Update a rejection reason", tag: "Rejection Reasons", permission: authz.ManageRejectionReasons, request: rejectionReasonRequest{}, response: models.LoanRejectionReason{}},
+
+		// Repayments
+		{method: "GET", path: "/loans/{id}/schedule", operationID: "getRepaymentSchedule", summary: "Get a loan's repayment schedule", tag: "Repayments", permission: authz.ReadLoans, response: []*models.LoanInstallment{}},
+		{method: "POST", path: "/loans/{id}/repayments", operationID: "recordRepayment", summary: "Record a repayment", tag: "Repayments", permission: authz.RecordRepayments, request: repaymentRequest{}, response: models.LoanRepayment{}},
+		{method: "GET", path: "/loans/{id}/repayments", operationID: "listRepayments", summary: "List a loan's repayments", tag: "Repayments", permission: authz.ReadRepayments, response: []*models.LoanRepayment{}},
+
+		// Ledger
+		{method: "GET", path: "/loans/{id}/journal", operationID: "getLoanJournal", summary: "List a loan's journal entries", tag: "Ledger", permission: authz.ReadLedger, response: []*ledger.JournalEntry{}},
+		{method: "GET", path: "/ledger/accounts", operationID: "listBalances", summary: "List account balances", tag: "Ledger", permission: authz.ReadLedger, response: []*ledger.AccountBalance{}},
+		{method: "GET", path: "/ledger/accounts/{code}", operationID: "getBalance", summary: "Get an account balance", tag: "Ledger", permission: authz.ReadLedger, response: ledger.AccountBalance{}},
+	}
+}
+
+var apiSpec = sync.OnceValue(buildAPISpec)
+
+// APISpec returns the OpenAPI document of the routes NewRouter serves. Its schemas are built
+// from the types handlers decode requests into and encode responses from.
+func APISpec() *openapi.Document {
+	return apiSpec()
+}
+
+func buildAPISpec() *openapi.Document {
+	spec := openapi.New(openapi.Info{
+		Title:       "Loan Engine API",
+		Version:     "1.0.0",
+		Description: "Borrowers propose loans, field staff approve them, investors fund them and the loans are disbursed and repaid. See docs/API_DOCUMENTATION.md for the workflows.",
+	})
+	spec.Components.SecuritySchemes[bearerAuth] = &openapi.SecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+		Description:  "An access token from login. The permissions listed for an operation are those the user's role must grant.",
+	}
+	spec.Components.SecuritySchemes[apiKeyAuth] = &openapi.SecurityScheme{
+		Type:        "apiKey",
+		In:          "header",
+		Name:        apiKeyHeader,
+		Description: "An API key for partner systems. Operations that a key may call list the scopes that allow them.",
+	}
+	spec.Components.Schemas["ErrorResponse"] = &openapi.Schema{
+		Type: openapi.Types{"object"},
+		Properties: map[string]*openapi.Schema{
+			"success": {Type: openapi.Types{"boolean"}, Enum: []interface{}{false}},
+			"error":   spec.ResponseSchema(ErrorBody{}),
+		},
+		Required: []string{"success", "error"},
+	}
+	spec.ResponseSchema(Problem{})
+	spec.ResponseSchema(money.Money{})
+	spec.ResponseSchema(money.Rate(0))
+
+	addBareEndpoints(spec)
+
+	var tags []string
+	for _, e := range endpoints() {
+		if !slices.Contains(tags, e.tag) {
+			tags = append(tags, e.tag)
+			spec.Tags = append(spec.Tags, openapi.Tag{Name: e.tag})
+		}
+		spec.AddOperation(e.method, "/api/v1"+e.path, e.operation(spec))
+	}
+
+	return spec
+}
+
+func (e endpoint) operation(spec *openapi.Document) *openapi.Operation {
+	operation := &openapi.Operation{
+		OperationID: e.operationID,
+		Summary:     e.summary,
+		Tags:        []string{e.tag},
+		Parameters:  append(pathParams(e.path), e.params...),
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "Success",
+				Content:     jsonContent(successSchema(spec, e.response)),
+			},
+			"default": {
+				Description: "An error in the Response envelope, or as a problem document for clients that accept application/problem+json",
+				Content: map[string]*openapi.MediaType{
+					"application/json": {Schema: openapi.Ref("ErrorResponse")},
+					problemJSON:        {Schema: openapi.Ref("Problem")},
+				},
+			},
+		},
+	}
+
+	if !e.public {
+		permissions := []string{}
+		if e.permission != "" {
+			permissions = []string{string(e.permission)}
+			operation.Description = fmt.Sprintf("Requires the %s permission.", e.permission)
+		}
+		operation.Security = []openapi.SecurityRequirement{{bearerAuth: permissions}}
+		// Any one of the scopes that allow the action will do
+		for _, scope := range apiKeyScopesFor(e.permission) {
+			operation.Security = append(operation.Security, openapi.SecurityRequirement{apiKeyAuth: {scope}})
+		}
+	}
+
+	if e.idempotent {
+		operation.Parameters = append(operation.Parameters, &openapi.Parameter{
+			Name:        idempotencyKeyHeader,
+			In:          "header",
+			Description: "A unique key that makes retries of the request safe; see Idempotent Requests in docs/API_DOCUMENTATION.md",
+			Schema:      &openapi.Schema{Type: openapi.Types{"string"}, MaxLength: intPointer(maxIdempotencyKeyLength)},
+		})
+		operation.Responses["200"].Headers = map[string]*openapi.Header{
+			"Idempotent-Replayed": {Description: "true when the response is the stored response to an earlier request with the same key", Schema: &openapi.Schema{Type: openapi.Types{"string"}}},
+		}
+	}
+
+	if e.request != nil {
+		operation.RequestBody = &openapi.RequestBody{
+			Required: true,
+			Content:  jsonContent(spec.RequestSchema(e.request)),
+		}
+	}
+
+	return operation
+}
+
+// successSchema is the Response envelope of a successful response with data of data's type
+func successSchema(spec *openapi.Document, data interface{}) *openapi.Schema {
+	schema := &openapi.Schema{
+		Type: openapi.Types{"object"},
+		Properties: map[string]*openapi.Schema{
+			"success": {Type: openapi.Types{"boolean"}, Enum: []interface{}{true}},
+			"message": {Type: openapi.Types{"string"}},
+		},
+		Required: []string{"success"},
+	}
+	if data != nil {
+		dataSchema := spec.ResponseSchema(data)
+		// A nil slice is sent as null
+		if dataSchema.Type != nil && dataSchema.Type[0] == "array" {
+			dataSchema.Type = append(dataSchema.Type, "null")
+		}
+		schema.Properties["data"] = dataSchema
+		schema.Required = append(schema.Required, "data")
+	}
+	return schema
+}
+
+// addBareEndpoints documents the routes outside /api/v1, which do not use the Response envelope
+func addBareEndpoints(spec *openapi.Document) {
+	spec.AddOperation("GET", "/health", &openapi.Operation{
+		OperationID: "health",
+		Summary:     "Check that the API is up",
+		Tags:        []string{"Meta"},
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "The API is up", Content: map[string]*openapi.MediaType{"text/plain": {Schema: &openapi.Schema{Type: openapi.Types{"string"}}}}},
+		},
+	})
+	spec.AddOperation("GET", "/.well-known/jwks.json", &openapi.Operation{
+		OperationID: "getJWKS",
+		Summary:     "Get the public keys access tokens are signed with",
+		Tags:        []string{"Meta"},
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "A JSON Web Key Set", Content: jsonContent(spec.ResponseSchema(signing.JWKS{}))},
+		},
+	})
+	spec.AddOperation("GET", "/openapi.json", &openapi.Operation{
+		OperationID: "getOpenAPI",
+		Summary:     "Get this OpenAPI document",
+		Tags:        []string{"Meta"},
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "The OpenAPI document", Content: jsonContent(&openapi.Schema{Type: openapi.Types{"object"}})},
+		},
+	})
+	spec.AddOperation("GET", "/docs", &openapi.Operation{
+		OperationID: "getAPIDocs",
+		Summary:     "Browse this OpenAPI document with Swagger UI",
+		Tags:        []string{"Meta"},
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "The Swagger UI page", Content: map[string]*openapi.MediaType{"text/html": {}}},
+		},
+	})
+	spec.Tags = append(spec.Tags, openapi.Tag{Name: "Meta", Description: "Health, signing keys and API documentation"})
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// pathParams describes the parameters in path. IDs are integers; other parameters, such as
+// codes and states, are strings.
+func pathParams(path string) []*openapi.Parameter {
+	var params []*openapi.Parameter
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		schema := &openapi.Schema{Type: openapi.Types{"string"}}
+		if match[1] == "id" {
+			schema = &openapi.Schema{Type: openapi.Types{"integer"}}
+		}
+		params = append(params, &openapi.Parameter{Name: match[1], In: "path", Required: true, Schema: schema})
+	}
+	return params
+}
+
+// apiKeyScopesFor lists the API key scopes that allow action, sorted
+func apiKeyScopesFor(action authz.Action) []string {
+	var scopes []string
+	for scope, actions := range authz.APIKeyScopes {
+		if action != "" && slices.Contains(actions, action) {
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+func queryParam(name, description string, schema *openapi.Schema) *openapi.Parameter {
+	return &openapi.Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// integerSchema allows integers from minimum to maximum; a negative maximum leaves it open
+func integerSchema(minimum, maximum int) *openapi.Schema {
+	schema := &openapi.Schema{Type: openapi.Types{"integer"}, Minimum: floatPointer(minimum)}
+	if maximum >= 0 {
+		schema.Maximum = floatPointer(maximum)
+	}
+	return schema
+}
+
+func enumSchema(values ...string) *openapi.Schema {
+	enum := make([]interface{}, len(values))
+	for i, value := range values {
+		enum[i] = value
+	}
+	return &openapi.Schema{Type: openapi.Types{"string"}, Enum: enum}
+}
+
+// sortSchema allows a sort parameter of the given fields, each optionally prefixed with -
+func sortSchema(fields []string) *openapi.Schema {
+	field := "-?(" + strings.Join(fields, "|") + ")"
+	return &openapi.Schema{Type: openapi.Types{"string"}, Pattern: "^" + field + "(," + field + ")*$"}
+}
+
+func jsonContent(schema *openapi.Schema) map[string]*openapi.MediaType {
+	return map[string]*openapi.MediaType{"application/json": {Schema: schema}}
+}
+
+func intPointer(n int) *int {
+	return &n
+}
+
+func floatPointer(n int) *float64 {
+	f := float64(n)
+	return &f
+}
+
+// ServeOpenAPI serves the OpenAPI document. Like the key set, it is served bare, without
+// the usual response envelope.
+func ServeOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(APISpec()); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// swaggerUIPage renders the OpenAPI document with Swagger UI, loaded from a CDN
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Loan Engine API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
+
+// ServeSwaggerUI serves a Swagger UI page for browsing and trying out the API
+func ServeSwaggerUI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(swaggerUIPage))
+}
@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/openapi"
+	"github.com/sswastioyono18/loan-engine/internal/repositories"
+	"github.com/sswastioyono18/loan-engine/internal/services"
+	"github.com/sswastioyono18/loan-engine/internal/services/mocks"
+	"github.com/sswastioyono18/loan-engine/internal/signing"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRouter(t *testing.T, options ...RouterOption) http.Handler {
+	signingKeys, err := signing.NewHMACManager("secret")
+	require.NoError(t, err)
+	return NewRouter(services.NewServiceFactory(repositories.NewRepositoryFactory(nil), nil, nil, signingKeys), options...)
+}
+
+func TestAPISpecDescribesEveryRoute(t *testing.T) {
+	var routed []string
+	err := chi.Walk(newTestRouter(t).(chi.Routes), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routed = append(routed, method+" "+route)
+		return nil
+	})
+	require.NoError(t, err)
+	sort.Strings(routed)
+
+	documented := APISpec().Routes()
+	for _, route := range routed {
+		assert.Contains(t, documented, route, "route is not in the OpenAPI document; add it to endpoints")
+	}
+	for _, route := range documented {
+		assert.Contains(t, routed, route, "OpenAPI document describes a route the router does not serve")
+	}
+}
+
+func TestAPISpecOperations(t *testing.T) {
+	spec := APISpec()
+
+	operationIDs := make(map[string]string)
+	for _, route := range spec.Routes() {
+		method, path, _ := strings.Cut(route, " ")
+		operation, ok := spec.Operation(method, path)
+		require.True(t, ok)
+
+		assert.NotEmpty(t, operation.OperationID, route)
+		assert.NotContains(t, operationIDs, operation.OperationID, "%s reuses the operation ID of %s", route, operationIDs[operation.OperationID])
+		operationIDs[operation.OperationID] = route
+
+		for _, param := range operation.Parameters {
+			if param.In == "path" {
+				assert.Contains(t, path, "{"+param.Name+"}", route)
+			}
+		}
+	}
+
+	createLoan, ok := spec.Operation("POST", "/api/v1/loans")
+	require.True(t, ok)
+	assert.Equal(t, []openapi.SecurityRequirement{
+		{bearerAuth: {"loans:manage"}},
+		{apiKeyAuth: {"loans:write"}},
+	}, createLoan.Security)
+
+	approveLoan, ok := spec.Operation("POST", "/api/v1/loans/{id}/approve")
+	require.True(t, ok)
+	assert.Equal(t, []openapi.SecurityRequirement{{bearerAuth: {"loans:approve"}}}, approveLoan.Security, "API keys cannot approve loans")
+	assert.Equal(t, idempotencyKeyHeader, createLoan.Parameters[len(createLoan.Parameters)-1].Name)
+	assert.NotNil(t, createLoan.RequestBody)
+
+	login, ok := spec.Operation("POST", "/api/v1/auth/login")
+	require.True(t, ok)
+	assert.Empty(t, login.Security)
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	req := httptest.NewRequest("GET", "/openapi.json", nil)
+	rr := httptest.NewRecorder()
+
+	newTestRouter(t).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var document struct {
+		OpenAPI    string                                `json:"openapi"`
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &document))
+	assert.Equal(t, "3.1.0", document.OpenAPI)
+	assert.Contains(t, document.Paths["/api/v1/loans/{id}"], "get")
+	assert.Contains(t, document.Components.Schemas, "Loan")
+	assert.Contains(t, document.Components.Schemas, "LoanPage")
+}
+
+func TestServeSwaggerUI(t *testing.T) {
+	req := httptest.NewRequest("GET", "/docs", nil)
+	rr := httptest.NewRecorder()
+
+	newTestRouter(t).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, rr.Body.String(), `url: "/openapi.json"`)
+}
+
+func TestValidateRequests(t *testing.T) {
+	reached := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body), "the body is passed on")
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := ValidateRequests(APISpec())(next)
+
+	t.Run("valid request", func(t *testing.T) {
+		reached = false
+		req := httptest.NewRequest("POST", "/api/v1/loans", strings.NewReader(`{"borrower_id": 1, "principal_amount": "1000000.00", "rate": 0.05, "roi": "0.08"}`))
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.True(t, reached)
+	})
+
+	t.Run("body that does not match its schema", func(t *testing.T) {
+		reached = false
+		req := httptest.NewRequest("POST", "/api/v1/loans", strings.NewReader(`{"borrower_id": "one", "principal_amount": "a lot", "rate": 0.05}`))
+		req.Header.Set("Accept", problemJSON)
+		rr := httptest.NewRecorder()
+
+		ProblemDetails(handler).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		assert.False(t, reached)
+
+		var problem Problem
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+		assert.Equal(t, "validation_failed", problem.Code)
+		assert.Equal(t, []apperr.FieldError{
+			{Pointer: "/borrower_id", Code: "invalid_type", Message: "must be an integer"},
+			{Pointer: "/principal_amount", Code: "invalid_format", Message: `must match ^-?[0-9]+(\.[0-9]+)?$`},
+		}, problem.Errors)
+	})
+
+	t.Run("missing body", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/loans/1/approve", nil)
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("malformed parameters", func(t *testing.T) {
+		for _, target := range []string{
+			"/api/v1/loans/abc",
+			"/api/v1/loans?limit=0",
+			"/api/v1/loans?sort=borrower_name",
+			"/api/v1/loans?state=pending",
+			"/api/v1/users?active=maybe",
+		} {
+			reached = false
+			req := httptest.NewRequest("GET", target, nil)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code, target)
+			assert.False(t, reached, target)
+		}
+	})
+
+	t.Run("undocumented route", func(t *testing.T) {
+		reached = false
+		req := httptest.NewRequest("POST", "/api/v2/loans", strings.NewReader(`{}`))
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.True(t, reached)
+	})
+}
+
+func TestValidateRequestsInRouter(t *testing.T) {
+	router := newTestRouter(t, WithRequestValidation())
+
+	req := httptest.NewRequest("POST", "/api/v1/auth/login", strings.NewReader(`{"email": 42}`))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
+
+// responseValidatingRouter routes to the loan handlers and checks every response against
+// the OpenAPI document
+func responseValidatingRouter(t *testing.T, loanService services.LoanService) http.Handler {
+	handler := NewLoanHandler(loanService, nil, nil)
+
+	router := chi.NewRouter()
+	router.Use(ProblemDetails)
+	router.Use(ValidateResponses(APISpec(), func(r *http.Request, err error) {
+		t.Errorf("response does not match the OpenAPI document: %v", err)
+	}))
+	router.Get("/api/v1/loans", handler.ListLoans)
+	router.Get("/api/v1/loans/{id}", handler.GetLoanByID)
+	router.Get("/api/v1/loans/{id}/schedule", handler.GetRepaymentSchedule)
+	router.Post("/api/v1/loans/{id}/approve", handler.ApproveLoan)
+	return router
+}
+
+func TestResponsesMatchAPISpec(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	loan := &models.Loan{
+		ID:                  1,
+		LoanID:              "LOAN-1",
+		BorrowerID:          1,
+		PrincipalAmount:     money.MustParse("1000000.00"),
+		Rate:                money.MustParseRate("0.05"),
+		ROI:                 money.MustParseRate("0.08"),
+		CurrentState:        "proposed",
+		TotalInvestedAmount: money.MustParse("0"),
+		TenorMonths:         12,
+		RepaymentMethod:     "annuity",
+		CreatedAt:           createdAt,
+		UpdatedAt:           createdAt,
+	}
+
+	mockLoanService := mocks.NewLoanService(t)
+	mockLoanService.On("GetLoanByID", mock.Anything, 1).Return(loan, nil)
+	mockLoanService.On("GetLoanByID", mock.Anything, 2).Return(nil, apperr.NotFound("loan_not_found", "loan 2 not found"))
+	mockLoanService.On("ListLoans", mock.Anything, mock.Anything, mock.Anything).Return(&models.Page[*models.Loan]{Items: []*models.Loan{loan}, TotalEstimate: 1}, nil)
+	mockLoanService.On("GetRepaymentSchedule", mock.Anything, 1).Return(nil, nil)
+	router := responseValidatingRouter(t, mockLoanService)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		accept string
+		code   int
+	}{
+		{"loan", "GET", "/api/v1/loans/1", "", "", http.StatusOK},
+		{"page of loans", "GET", "/api/v1/loans", "", "", http.StatusOK},
+		{"empty schedule", "GET", "/api/v1/loans/1/schedule", "", "", http.StatusOK},
+		{"error", "GET", "/api/v1/loans/2", "", "", http.StatusNotFound},
+		{"problem document", "GET", "/api/v1/loans/2", "", problemJSON, http.StatusNotFound},
+		{"validation problem", "POST", "/api/v1/loans/1/approve", `{}`, problemJSON, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, bytes.NewBufferString(tt.body))
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestValidateResponsesReportsMismatches(t *testing.T) {
+	var reported []error
+	handler := ValidateResponses(APISpec(), func(r *http.Request, err error) {
+		reported = append(reported, err)
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		SendSuccessResponse(w, map[string]interface{}{"id": "one"}, "Loan retrieved successfully")
+	}))
+
+	req := httptest.NewRequest("GET", "/api/v1/loans/1", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, "the response is sent anyway")
+	require.Len(t, reported, 1)
+	assert.Contains(t, reported[0].Error(), "GET /api/v1/loans/1: status 200")
+}
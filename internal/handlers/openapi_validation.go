@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/openapi"
+)
+
+// maxValidatedBodySize bounds the request body read up front to validate it
+const maxValidatedBodySize = 1 << 20
+
+// ValidateRequests rejects requests that do not match spec before they reach a handler:
+// malformed path or query parameters fail with 400, and a body that does not match its
+// schema fails with 422 listing every invalid field. Requests for routes spec does not
+// describe are passed on untouched.
+//
+// It runs before Authenticate, so a malformed request is rejected whoever sends it.
+func ValidateRequests(spec *openapi.Document) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			operation, pathParams, ok := spec.Find(r.Method, r.URL.Path)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if err := spec.ValidateParameters(operation, pathParams, r.URL.Query()); err != nil {
+				sendBadRequest(w, "Invalid request parameters", err)
+				return
+			}
+
+			if operation.RequestBody != nil {
+				body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxValidatedBodySize))
+				if err != nil {
+					sendBadRequest(w, "Invalid request body", err)
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+
+				err = spec.ValidateRequestBody(operation, r.Header.Get("Content-Type"), body)
+				var domainErr *apperr.Error
+				switch {
+				case errors.As(err, &domainErr):
+					SendErrorResponse(w, "Invalid request body", err)
+					return
+				case err != nil:
+					sendBadRequest(w, "Invalid request body", err)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ValidateResponses checks every response to a route spec describes against it and calls
+// report for each one that does not match. The response is sent as it is either way. It
+// keeps a copy of every response body, so it is meant for tests rather than production.
+func ValidateResponses(spec *openapi.Document, report func(r *http.Request, err error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			operation, _, ok := spec.Find(r.Method, r.URL.Path)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+
+			err := spec.ValidateResponse(operation, recorder.statusCode(), recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+			if err != nil {
+				report(r, fmt.Errorf("%s %s: %w", r.Method, r.URL.Path, err))
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+const (
+	defaultPageSize = 10
+	maxPageSize     = 100
+)
+
+// pageRequest reads the cursor, limit and sort query parameters of a cursor-paginated listing
+func pageRequest(r *http.Request) (models.PageRequest, error) {
+	query := r.URL.Query()
+	page := models.PageRequest{Cursor: query.Get("cursor"), Limit: defaultPageSize}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxPageSize {
+			return page, fmt.Errorf("limit must be a number from 1 to %d", maxPageSize)
+		}
+		page.Limit = n
+	}
+
+	sort, err := models.ParseSort(query.Get("sort"))
+	if err != nil {
+		return page, err
+	}
+	page.Sort = sort
+
+	return page, nil
+}
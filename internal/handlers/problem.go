@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+)
+
+const problemJSON = "application/problem+json"
+
+// problemTypePrefix starts the type URI of every problem; the error code completes it, so
+// each error code is its own problem type
+const problemTypePrefix = "urn:loan-engine:problem:"
+
+// Problem is an RFC 7807 problem document. Code is the same stable error code the Response
+// envelope carries, and Errors lists the invalid fields of a validation problem.
+type Problem struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Code     string              `json:"code"`
+	Errors   []apperr.FieldError `json:"errors,omitempty"`
+}
+
+// problemWriter marks a response whose errors are sent as problem documents
+type problemWriter struct {
+	http.ResponseWriter
+	instance string
+}
+
+// ProblemDetails answers errors with RFC 7807 problem documents for clients that accept
+// application/problem+json. Every other client keeps getting the Response envelope.
+func ProblemDetails(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if acceptsProblemJSON(r.Header.Values("Accept")) {
+			w = &problemWriter{ResponseWriter: w, instance: r.URL.Path}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// acceptsProblemJSON reports whether the Accept headers list application/problem+json
+// with a non-zero quality
+func acceptsProblemJSON(accept []string) bool {
+	for _, header := range accept {
+		for _, mediaRange := range strings.Split(header, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+			if err != nil || mediaType != problemJSON {
+				continue
+			}
+			if q, ok := params["q"]; ok && strings.Trim(q, "0.") == "" {
+				continue
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// problemInstance reports whether errors sent on w are problem documents, and the instance
+// they name. It looks through the writers other middleware wrapped around a problemWriter.
+func problemInstance(w http.ResponseWriter) (string, bool) {
+	for {
+		switch writer := w.(type) {
+		case *problemWriter:
+			return writer.instance, true
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = writer.Unwrap()
+		default:
+			return "", false
+		}
+	}
+}
+
+func sendProblem(w http.ResponseWriter, instance string, statusCode int, code, message, detail string, fields []apperr.FieldError) {
+	w.Header().Set("Content-Type", problemJSON)
+	w.WriteHeader(statusCode)
+
+	problem := Problem{
+		Type:     problemTypePrefix + code,
+		Title:    http.StatusText(statusCode),
+		Status:   statusCode,
+		Detail:   message + ": " + detail,
+		Instance: instance,
+		Code:     code,
+		Errors:   fields,
+	}
+
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
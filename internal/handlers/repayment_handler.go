@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/services"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type RepaymentHandler struct {
+	repaymentService services.RepaymentService
+}
+
+func NewRepaymentHandler(repaymentService services.RepaymentService) *RepaymentHandler {
+	return &RepaymentHandler{
+		repaymentService: repaymentService,
+	}
+}
+
+// repaymentRequest is the body of the record repayment request
+type repaymentRequest struct {
+	Amount money.Money `json:"amount"`
+	PaidAt time.Time   `json:"paid_at"`
+}
+
+func (h *RepaymentHandler) RecordRepayment(w http.ResponseWriter, r *http.Request) {
+	loanID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		sendBadRequest(w, "Invalid loan ID", err)
+		return
+	}
+
+	var repaymentData repaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&repaymentData); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	model := &models.LoanRepayment{
+		Amount: repaymentData.Amount,
+		PaidAt: repaymentData.PaidAt,
+	}
+
+	if err := h.repaymentService.RecordRepayment(r.Context(), loanID, model); err != nil {
+		SendErrorResponse(w, "Failed to record repayment", err)
+		return
+	}
+
+	SendSuccessResponse(w, model, "Repayment recorded successfully")
+}
+
+func (h *RepaymentHandler) ListRepayments(w http.ResponseWriter, r *http.Request) {
+	loanID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		sendBadRequest(w, "Invalid loan ID", err)
+		return
+	}
+
+	repayments, err := h.repaymentService.ListRepayments(r.Context(), loanID)
+	if err != nil {
+		SendErrorResponse(w, "Failed to list repayments", err)
+		return
+	}
+
+	SendSuccessResponse(w, repayments, "Repayments retrieved successfully")
+}
@@ -2,7 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"log"
 	"net/http"
+	"strings"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
 )
 
 type Response struct {
@@ -12,16 +16,36 @@ type Response struct {
 	Error   interface{} `json:"error,omitempty"`
 }
 
+// ErrorBody is the error part of a failed response. Code is stable and meant for clients
+// to act on; Message says what the request was trying to do and Error what went wrong.
+type ErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
+// statusByKind is the HTTP status each kind of domain error is answered with
+var statusByKind = map[apperr.Kind]int{
+	apperr.KindNotFound:        http.StatusNotFound,
+	apperr.KindConflict:        http.StatusConflict,
+	apperr.KindInvalidState:    http.StatusConflict,
+	apperr.KindValidation:      http.StatusUnprocessableEntity,
+	apperr.KindUnauthenticated: http.StatusUnauthorized,
+	apperr.KindForbidden:       http.StatusForbidden,
+	apperr.KindLocked:          http.StatusLocked,
+	apperr.KindInternal:        http.StatusInternalServerError,
+}
+
 func SendSuccessResponse(w http.ResponseWriter, data interface{}, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	
+
 	response := Response{
 		Success: true,
 		Data:    data,
 		Message: message,
 	}
-	
+
 	// Encode response as JSON
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
@@ -29,40 +53,64 @@ func SendSuccessResponse(w http.ResponseWriter, data interface{}, message string
 	}
 }
 
+// SendErrorResponse answers with the status and code of the domain error in err's chain.
+// Errors that are not domain errors are internal: they are logged, and the client only
+// learns that something went wrong.
 func SendErrorResponse(w http.ResponseWriter, message string, err error) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusBadRequest)
-	
-	response := Response{
-		Success: false,
-		Error: map[string]interface{}{
-			"message": message,
-			"error":   err.Error(),
-		},
-	}
-	
-	// Encode response as JSON
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	domainErr := apperr.From(err)
+	if domainErr.Kind == apperr.KindInternal {
+		log.Printf("%s: %v", message, err)
+		sendError(w, http.StatusInternalServerError, domainErr.Code, message, "internal server error", nil)
 		return
 	}
+
+	sendError(w, statusByKind[domainErr.Kind], domainErr.Code, message, err.Error(), domainErr.Fields)
 }
 
+// SendErrorResponseWithCode answers with statusCode whatever err is. The error code is
+// taken from err when it is a domain error and from the status otherwise. As with
+// SendErrorResponse, internal errors are logged and not sent.
 func SendErrorResponseWithCode(w http.ResponseWriter, message string, err error, statusCode int) {
+	domainErr := apperr.From(err)
+	if domainErr.Kind == apperr.KindInternal {
+		log.Printf("%s: %v", message, err)
+		code := strings.ToLower(strings.ReplaceAll(http.StatusText(statusCode), " ", "_"))
+		sendError(w, statusCode, code, message, "internal server error", nil)
+		return
+	}
+
+	sendError(w, statusCode, domainErr.Code, message, err.Error(), nil)
+}
+
+// sendBadRequest answers a request the handler could not read, such as a malformed body or
+// a non-numeric ID
+func sendBadRequest(w http.ResponseWriter, message string, err error) {
+	sendError(w, http.StatusBadRequest, "bad_request", message, err.Error(), nil)
+}
+
+// sendError sends an error in the Response envelope, or as a problem document to clients
+// that asked for one
+func sendError(w http.ResponseWriter, statusCode int, code, message, detail string, fields []apperr.FieldError) {
+	if instance, ok := problemInstance(w); ok {
+		sendProblem(w, instance, statusCode, code, message, detail, fields)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	
+
 	response := Response{
 		Success: false,
-		Error: map[string]interface{}{
-			"message": message,
-			"error":   err.Error(),
+		Error: ErrorBody{
+			Code:    code,
+			Message: message,
+			Error:   detail,
 		},
 	}
-	
+
 	// Encode response as JSON
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
-}
\ No newline at end of file
+}
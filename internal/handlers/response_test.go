@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/authz"
+	"github.com/sswastioyono18/loan-engine/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendErrorResponseMapsDomainErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		detail string
+	}{
+		{"not found", repositories.ErrLoanNotFound, http.StatusNotFound, "loan_not_found", "loan not found"},
+		{"conflict", apperr.Conflict("already_invested", "investor already invested in this loan"), http.StatusConflict, "already_invested", "investor already invested in this loan"},
+		{"invalid state", apperr.InvalidState("invalid_loan_state", "loan must be in approved state to be invested"), http.StatusConflict, "invalid_loan_state", "loan must be in approved state to be invested"},
+		{"validation", apperr.Validation("invalid_loan", "rate must be between 0 and 1"), http.StatusUnprocessableEntity, "invalid_loan", "rate must be between 0 and 1"},
+		{"forbidden", fmt.Errorf("%w: investor cannot perform loans:approve", authz.ErrForbidden), http.StatusForbidden, "permission_denied", "permission denied: investor cannot perform loans:approve"},
+		{"locked", apperr.Locked("account_locked", "account is temporarily locked"), http.StatusLocked, "account_locked", "account is temporarily locked"},
+		// The cause of an internal error is logged, never sent
+		{"internal", errors.New("pq: connection refused"), http.StatusInternalServerError, "internal_error", "internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			SendErrorResponse(rr, "Failed to do it", tt.err)
+
+			var body struct {
+				Success bool      `json:"success"`
+				Error   ErrorBody `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+			assert.Equal(t, tt.status, rr.Code)
+			assert.False(t, body.Success)
+			assert.Equal(t, ErrorBody{Code: tt.code, Message: "Failed to do it", Error: tt.detail}, body.Error)
+		})
+	}
+}
+
+func TestSendErrorResponseWithCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   string
+		detail string
+	}{
+		{"domain error", errMissingAuthHeader, "unauthorized", "missing authorization header"},
+		{"wrapped domain error", fmt.Errorf("%w: token is expired", apperr.Unauthenticated("invalid_token", "invalid token")), "invalid_token", "invalid token: token is expired"},
+		// The cause of an internal error is logged, never sent
+		{"internal", errors.New("pq: connection refused"), "unauthorized", "internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			SendErrorResponseWithCode(rr, "Unauthorized", tt.err, http.StatusUnauthorized)
+
+			var body struct {
+				Error ErrorBody `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+			assert.Equal(t, http.StatusUnauthorized, rr.Code)
+			assert.Equal(t, ErrorBody{Code: tt.code, Message: "Unauthorized", Error: tt.detail}, body.Error)
+		})
+	}
+}
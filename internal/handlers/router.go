@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/sswastioyono18/loan-engine/internal/authz"
 	"github.com/sswastioyono18/loan-engine/internal/services"
 
 	"github.com/go-chi/chi/v5"
@@ -11,32 +12,71 @@ import (
 	"github.com/go-chi/cors"
 )
 
-func NewRouter(serviceFactory *services.ServiceFactory) http.Handler {
+// RouterOption configures the router NewRouter builds
+type RouterOption func(*routerOptions)
+
+type routerOptions struct {
+	validateRequests bool
+}
+
+// WithRequestValidation checks requests against the OpenAPI document before they reach a
+// handler; see ValidateRequests
+func WithRequestValidation() RouterOption {
+	return func(o *routerOptions) {
+		o.validateRequests = true
+	}
+}
+
+func NewRouter(serviceFactory *services.ServiceFactory, options ...RouterOption) http.Handler {
+	var opts routerOptions
+	for _, option := range options {
+		option(&opts)
+	}
+
 	router := chi.NewRouter()
 
 	// Middleware
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.Timeout(60 * time.Second))
+	// Errors as problem documents for clients that ask for them
+	router.Use(ProblemDetails)
 
 	// CORS configuration
 	router.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://*", "http://*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "Idempotency-Key", "X-API-Key", "X-CSRF-Token"},
+		ExposedHeaders:   []string{"Idempotent-Replayed", "Link", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
+	if opts.validateRequests {
+		router.Use(ValidateRequests(APISpec()))
+	}
+
 	// Health check endpoint
 	router.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	})
 
+	// Public keys for verifying access tokens
+	router.Get("/.well-known/jwks.json", NewKeyHandler(serviceFactory.SigningKeys).GetJWKS)
+
+	// OpenAPI document and a page for browsing it
+	router.Get("/openapi.json", ServeOpenAPI)
+	router.Get("/docs", ServeSwaggerUI)
+
 	// Initialize handlers
-	authHandler := NewAuthHandler(serviceFactory.AuthService())
+	authService := serviceFactory.AuthService()
+	policy := serviceFactory.AccessPolicy
+	authHandler := NewAuthHandler(authService)
+	userHandler := NewUserHandler(serviceFactory.UserService())
+	apiKeyService := serviceFactory.APIKeyService()
+	apiKeyHandler := NewAPIKeyHandler(apiKeyService)
+	idempotent := Idempotent(serviceFactory.IdempotencyService())
 	borrowerHandler := NewBorrowerHandler(serviceFactory.BorrowerService())
 	loanHandler := NewLoanHandler(
 		serviceFactory.LoanService(),
@@ -44,40 +84,102 @@ func NewRouter(serviceFactory *services.ServiceFactory) http.Handler {
 		serviceFactory.StorageService,
 	)
 	investorHandler := NewInvestorHandler(serviceFactory.InvestorService())
+	repaymentHandler := NewRepaymentHandler(serviceFactory.RepaymentService())
+	ledgerHandler := NewLedgerHandler(serviceFactory.LedgerService())
 
 	// API routes
 	router.Route("/api/v1", func(r chi.Router) {
 		// Authentication routes
 		r.Post("/auth/register", authHandler.RegisterUser)
 		r.Post("/auth/login", authHandler.LoginUser)
+		r.Post("/auth/login/totp", authHandler.VerifyLoginTOTP)
 		r.Post("/auth/refresh", authHandler.RefreshToken)
+		r.Post("/auth/logout", authHandler.Logout)
+		r.Post("/auth/forgot-password", authHandler.ForgotPassword)
+		r.Post("/auth/reset-password", authHandler.ResetPassword)
+		r.Post("/auth/verify-email", authHandler.VerifyEmail)
+		r.Post("/auth/resend-verification", authHandler.ResendEmailVerification)
+
+		// Everything else requires a signed-in user whose role allows the route's action, or
+		// an API key whose scopes do
+		r.Group(func(r chi.Router) {
+			r.Use(Authenticate(authService, apiKeyService))
+
+			r.Post("/auth/logout-all", authHandler.LogoutAll)
+			r.Post("/auth/change-password", authHandler.ChangePassword)
+			r.Post("/auth/totp/enrol", authHandler.EnrolTOTP)
+			r.Post("/auth/totp/confirm", authHandler.ConfirmTOTP)
+			r.Post("/auth/totp/disable", authHandler.DisableTOTP)
+			r.Post("/auth/totp/recovery-codes", authHandler.RegenerateRecoveryCodes)
+
+			// User management routes
+			r.With(Authorize(policy, authz.ReadUsers)).Get("/users", userHandler.ListUsers)
+			r.With(Authorize(policy, authz.ManageUsers)).Post("/users", userHandler.CreateUser)
+			r.With(Authorize(policy, authz.ReadUsers)).Get("/users/{id}", userHandler.GetUser)
+			r.With(Authorize(policy, authz.ManageUsers)).Post("/users/{id}/deactivate", userHandler.DeactivateUser)
+			r.With(Authorize(policy, authz.ManageUsers)).Post("/users/{id}/activate", userHandler.ActivateUser)
+			r.With(Authorize(policy, authz.ManageUsers)).Delete("/users/{id}", userHandler.DeleteUser)
+			r.With(Authorize(policy, authz.ManageUsers)).Post("/users/{id}/restore", userHandler.RestoreUser)
+			r.With(Authorize(policy, authz.ManageUsers)).Put("/users/{id}/role", userHandler.ChangeRole)
+			r.With(Authorize(policy, authz.ManageUsers)).Post("/users/{id}/reset-password", userHandler.ForcePasswordReset)
+
+			// API key routes
+			r.With(Authorize(policy, authz.ManageAPIKeys)).Get("/api-keys", apiKeyHandler.ListAPIKeys)
+			r.With(Authorize(policy, authz.ManageAPIKeys)).Post("/api-keys", apiKeyHandler.CreateAPIKey)
+			r.With(Authorize(policy, authz.ManageAPIKeys)).Get("/api-keys/{id}", apiKeyHandler.GetAPIKey)
+			r.With(Authorize(policy, authz.ManageAPIKeys)).Put("/api-keys/{id}", apiKeyHandler.UpdateAPIKey)
+			r.With(Authorize(policy, authz.ManageAPIKeys)).Delete("/api-keys/{id}", apiKeyHandler.RevokeAPIKey)
+
+			// Borrower routes
+			r.With(Authorize(policy, authz.ManageBorrowers)).Post("/borrowers", borrowerHandler.CreateBorrower)
+			r.With(Authorize(policy, authz.ReadBorrowers)).Get("/borrowers/{id}", borrowerHandler.GetBorrowerByID)
+			r.With(Authorize(policy, authz.ManageBorrowers)).Put("/borrowers/{id}", borrowerHandler.UpdateBorrower)
+			r.With(Authorize(policy, authz.ManageBorrowers)).Delete("/borrowers/{id}", borrowerHandler.DeleteBorrower)
+			r.With(Authorize(policy, authz.ReadBorrowers)).Get("/borrowers", borrowerHandler.ListBorrowers)
+
+			// Investor routes
+			r.With(Authorize(policy, authz.ManageInvestors)).Post("/investors", investorHandler.CreateInvestor)
+			r.With(Authorize(policy, authz.ReadInvestors)).Get("/investors/{id}", investorHandler.GetInvestorByID)
+			r.With(Authorize(policy, authz.ReadPayouts)).Get("/investors/{id}/payouts", investorHandler.ListPayouts)
+			r.With(Authorize(policy, authz.ManageInvestors)).Put("/investors/{id}", investorHandler.UpdateInvestor)
+			r.With(Authorize(policy, authz.ManageInvestors)).Delete("/investors/{id}", investorHandler.DeleteInvestor)
+			r.With(Authorize(policy, authz.ReadInvestors)).Get("/investors", investorHandler.ListInvestors)
+
+			// Loan routes
+			r.With(Authorize(policy, authz.ManageLoans), idempotent).Post("/loans", loanHandler.CreateLoan)
+			r.With(Authorize(policy, authz.ReadLoans)).Get("/loans/{id}", loanHandler.GetLoanByID)
+			r.With(Authorize(policy, authz.ManageLoans)).Put("/loans/{id}", loanHandler.UpdateLoan)
+			r.With(Authorize(policy, authz.ManageLoans)).Delete("/loans/{id}", loanHandler.DeleteLoan)
+			r.With(Authorize(policy, authz.ReadLoans)).Get("/loans", loanHandler.ListLoans)
+			r.With(Authorize(policy, authz.ReadLoans)).Get("/loans/state/{state}", loanHandler.GetLoansByState)
+
+			// Loan state transition routes
+			r.With(Authorize(policy, authz.ApproveLoan)).Post("/loans/{id}/approve", loanHandler.ApproveLoan)
+			r.With(Authorize(policy, authz.InvestInLoan), idempotent).Post("/loans/{id}/invest", loanHandler.InvestInLoan)
+			r.With(Authorize(policy, authz.DisburseLoan), idempotent).Post("/loans/{id}/disburse", loanHandler.DisburseLoan)
+			r.With(Authorize(policy, authz.RejectLoan)).Post("/loans/{id}/reject", loanHandler.RejectLoan)
+			r.With(Authorize(policy, authz.CancelLoan)).Post("/loans/{id}/cancel", loanHandler.CancelLoan)
+			r.With(Authorize(policy, authz.ExpireLoan)).Post("/loans/{id}/expire", loanHandler.ExpireLoan)
+			r.With(Authorize(policy, authz.MarkLoanRepaid)).Post("/loans/{id}/mark-repaid", loanHandler.MarkLoanRepaid)
+			r.With(Authorize(policy, authz.CloseLoan)).Post("/loans/{id}/close", loanHandler.CloseLoan)
+			r.With(Authorize(policy, authz.DefaultLoan)).Post("/loans/{id}/default", loanHandler.DefaultLoan)
+			r.With(Authorize(policy, authz.WriteOffLoan)).Post("/loans/{id}/write-off", loanHandler.WriteOffLoan)
+
+			// Rejection reason catalogue
+			r.With(Authorize(policy, authz.ReadRejectionReasons)).Get("/loan-rejection-reasons", loanHandler.ListRejectionReasons)
+			r.With(Authorize(policy, authz.ManageRejectionReasons)).Post("/loan-rejection-reasons", loanHandler.CreateRejectionReason)
+			r.With(Authorize(policy, authz.ManageRejectionReasons)).Put("/loan-rejection-reasons/{code}", loanHandler.UpdateRejectionReason)
+
+			// Repayment routes
+			r.With(Authorize(policy, authz.ReadLoans)).Get("/loans/{id}/schedule", loanHandler.GetRepaymentSchedule)
+			r.With(Authorize(policy, authz.RecordRepayments)).Post("/loans/{id}/repayments", repaymentHandler.RecordRepayment)
+			r.With(Authorize(policy, authz.ReadRepayments)).Get("/loans/{id}/repayments", repaymentHandler.ListRepayments)
 
-		// Borrower routes
-		r.Post("/borrowers", borrowerHandler.CreateBorrower)
-		r.Get("/borrowers/{id}", borrowerHandler.GetBorrowerByID)
-		r.Put("/borrowers/{id}", borrowerHandler.UpdateBorrower)
-		r.Delete("/borrowers/{id}", borrowerHandler.DeleteBorrower)
-		r.Get("/borrowers", borrowerHandler.ListBorrowers)
-
-		// Investor routes
-		r.Post("/investors", investorHandler.CreateInvestor)
-		r.Get("/investors/{id}", investorHandler.GetInvestorByID)
-		r.Put("/investors/{id}", investorHandler.UpdateInvestor)
-		r.Delete("/investors/{id}", investorHandler.DeleteInvestor)
-		r.Get("/investors", investorHandler.ListInvestors)
-
-		// Loan routes
-		r.Post("/loans", loanHandler.CreateLoan)
-		r.Get("/loans/{id}", loanHandler.GetLoanByID)
-		r.Put("/loans/{id}", loanHandler.UpdateLoan)
-		r.Delete("/loans/{id}", loanHandler.DeleteLoan)
-		r.Get("/loans", loanHandler.ListLoans)
-		r.Get("/loans/state/{state}", loanHandler.GetLoansByState)
-
-		// Loan state transition routes
-		r.Post("/loans/{id}/approve", loanHandler.ApproveLoan)
-		r.Post("/loans/{id}/invest", loanHandler.InvestInLoan)
-		r.Post("/loans/{id}/disburse", loanHandler.DisburseLoan)
+			// Ledger routes
+			r.With(Authorize(policy, authz.ReadLedger)).Get("/loans/{id}/journal", ledgerHandler.GetLoanJournal)
+			r.With(Authorize(policy, authz.ReadLedger)).Get("/ledger/accounts", ledgerHandler.ListBalances)
+			r.With(Authorize(policy, authz.ReadLedger)).Get("/ledger/accounts/{code}", ledgerHandler.GetBalance)
+		})
 	})
 
 	return router
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// UserHandler serves the admin user management API
+type UserHandler struct {
+	userService services.UserService
+}
+
+func NewUserHandler(userService services.UserService) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+// ListUsers lists users a page at a time, optionally filtered by search, user_type, active
+// and include_deleted
+func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.UserFilter{
+		Search:   query.Get("search"),
+		UserType: query.Get("user_type"),
+	}
+
+	if active := query.Get("active"); active != "" {
+		isActive, err := strconv.ParseBool(active)
+		if err != nil {
+			sendBadRequest(w, "Invalid active filter", err)
+			return
+		}
+		filter.IsActive = &isActive
+	}
+
+	if includeDeleted := query.Get("include_deleted"); includeDeleted != "" {
+		var err error
+		if filter.IncludeDeleted, err = strconv.ParseBool(includeDeleted); err != nil {
+			sendBadRequest(w, "Invalid include_deleted filter", err)
+			return
+		}
+	}
+
+	offset, err := strconv.Atoi(query.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	page, err := h.userService.ListUsers(r.Context(), filter, offset, limit)
+	if err != nil {
+		SendErrorResponse(w, "Failed to list users", err)
+		return
+	}
+
+	SendSuccessResponse(w, page, "Users retrieved successfully")
+}
+
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		sendBadRequest(w, "Invalid user ID", err)
+		return
+	}
+
+	user, err := h.userService.GetUser(r.Context(), id)
+	if err != nil {
+		SendErrorResponse(w, "Failed to get user", err)
+		return
+	}
+
+	SendSuccessResponse(w, user, "User retrieved successfully")
+}
+
+// createUserRequest is the body of the create user request
+type createUserRequest struct {
+	UserID    string `json:"user_id"`
+	Email     string `json:"email"`
+	UserType  string `json:"user_type"`
+	StaffRole string `json:"staff_role"`
+	FullName  string `json:"full_name"`
+}
+
+// CreateUser creates a staff or admin account; the new user is mailed a token to set their password with
+func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var req createUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	user := &models.User{
+		UserID:    req.UserID,
+		Email:     req.Email,
+		UserType:  req.UserType,
+		StaffRole: req.StaffRole,
+		FullName:  req.FullName,
+	}
+
+	if err := h.userService.CreateUser(auditContext(r), user); err != nil {
+		SendErrorResponse(w, "Failed to create user", err)
+		return
+	}
+
+	SendSuccessResponse(w, user, "User created successfully; a password reset token has been sent to them")
+}
+
+func (h *UserHandler) DeactivateUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		sendBadRequest(w, "Invalid user ID", err)
+		return
+	}
+
+	user, err := h.userService.DeactivateUser(auditContext(r), id)
+	if err != nil {
+		SendErrorResponse(w, "Failed to deactivate user", err)
+		return
+	}
+
+	SendSuccessResponse(w, user, "User deactivated successfully")
+}
+
+func (h *UserHandler) ActivateUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		sendBadRequest(w, "Invalid user ID", err)
+		return
+	}
+
+	user, err := h.userService.ActivateUser(auditContext(r), id)
+	if err != nil {
+		SendErrorResponse(w, "Failed to activate user", err)
+		return
+	}
+
+	SendSuccessResponse(w, user, "User activated successfully")
+}
+
+// DeleteUser soft-deletes the user; the account is kept for the audit trail
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		sendBadRequest(w, "Invalid user ID", err)
+		return
+	}
+
+	if err := h.userService.DeleteUser(auditContext(r), id); err != nil {
+		SendErrorResponse(w, "Failed to delete user", err)
+		return
+	}
+
+	SendSuccessResponse(w, nil, "User deleted successfully")
+}
+
+func (h *UserHandler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		sendBadRequest(w, "Invalid user ID", err)
+		return
+	}
+
+	if err := h.userService.RestoreUser(auditContext(r), id); err != nil {
+		SendErrorResponse(w, "Failed to restore user", err)
+		return
+	}
+
+	SendSuccessResponse(w, nil, "User restored successfully")
+}
+
+// changeRoleRequest is the body of the change role request
+type changeRoleRequest struct {
+	UserType  string `json:"user_type"`
+	StaffRole string `json:"staff_role"`
+}
+
+func (h *UserHandler) ChangeRole(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		sendBadRequest(w, "Invalid user ID", err)
+		return
+	}
+
+	var req changeRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendBadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	user, err := h.userService.ChangeRole(auditContext(r), id, req.UserType, req.StaffRole)
+	if err != nil {
+		SendErrorResponse(w, "Failed to change role", err)
+		return
+	}
+
+	SendSuccessResponse(w, user, "Role changed successfully")
+}
+
+// ForcePasswordReset revokes the user's password and sessions and mails them a reset token
+func (h *UserHandler) ForcePasswordReset(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		sendBadRequest(w, "Invalid user ID", err)
+		return
+	}
+
+	if err := h.userService.ForcePasswordReset(auditContext(r), id); err != nil {
+		SendErrorResponse(w, "Failed to reset password", err)
+		return
+	}
+
+	SendSuccessResponse(w, nil, "Password revoked; a password reset token has been sent to the user")
+}
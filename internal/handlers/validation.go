@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/services"
+)
+
+// Codes of the field errors request validation reports
+const (
+	fieldRequired      = "required"
+	fieldInvalidFormat = "invalid_format"
+	fieldOutOfRange    = "out_of_range"
+	fieldInvalidChoice = "invalid_choice"
+	fieldTooLong       = "too_long"
+)
+
+// Lengths of the columns people's details are stored in
+const (
+	maxIDNumberLength = 50
+	maxNameLength     = 255
+	maxEmailLength    = 255
+	maxPhoneLength    = 20
+)
+
+// fieldValidator collects every invalid field of a request body, so a client learns about
+// all of them at once instead of fixing one per round trip
+type fieldValidator struct {
+	fields []apperr.FieldError
+}
+
+// check records an invalid field unless ok holds
+func (v *fieldValidator) check(ok bool, field, code, message string) {
+	if !ok {
+		v.fields = append(v.fields, apperr.FieldError{Pointer: "/" + field, Code: code, Message: message})
+	}
+}
+
+func (v *fieldValidator) required(field, value string) bool {
+	present := strings.TrimSpace(value) != ""
+	v.check(present, field, fieldRequired, "is required")
+	return present
+}
+
+func (v *fieldValidator) maxLength(field, value string, max int) {
+	v.check(utf8.RuneCountInString(value) <= max, field, fieldTooLong, fmt.Sprintf("must be at most %d characters long", max))
+}
+
+// email checks that value, when set, is a plain email address
+func (v *fieldValidator) email(field, value string) {
+	if value == "" {
+		return
+	}
+	address, err := mail.ParseAddress(value)
+	v.check(err == nil && address.Address == value, field, fieldInvalidFormat, "must be an email address")
+}
+
+// url checks that value, when set, is an absolute http or https URL
+func (v *fieldValidator) url(field, value string) {
+	if value == "" {
+		return
+	}
+	parsed, err := url.ParseRequestURI(value)
+	v.check(err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != "", field, fieldInvalidFormat, "must be an http or https URL")
+}
+
+// password checks value against the password policy for the account with the given email
+func (v *fieldValidator) password(field, value, email string) {
+	if err := services.ValidatePassword(value, email); err != nil {
+		v.check(false, field, "weak_password", strings.TrimPrefix(err.Error(), "password "))
+	}
+}
+
+// person checks the name, email and phone every borrower, investor and user has
+func (v *fieldValidator) person(fullName, email, phone string) {
+	if v.required("full_name", fullName) {
+		v.maxLength("full_name", fullName, maxNameLength)
+	}
+	if v.required("email", email) {
+		v.email("email", email)
+		v.maxLength("email", email, maxEmailLength)
+	}
+	v.maxLength("phone", phone, maxPhoneLength)
+}
+
+// err returns the validation error for the invalid fields, or nil when there are none
+func (v *fieldValidator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return apperr.InvalidFields(v.fields)
+}
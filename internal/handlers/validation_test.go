@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/services/mocks"
+	mocks2 "github.com/sswastioyono18/loan-engine/pkg/external/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoanRequestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		creating bool
+		pointers []string
+	}{
+		{"valid", `{"borrower_id": 1, "principal_amount": "5000000.00", "rate": "0.12", "roi": "0.10"}`, true, nil},
+		{
+			name:     "every invalid field",
+			body:     `{"principal_amount": "-1.00", "rate": "1.5", "roi": "-0.1", "agreement_letter_link": "agreement.pdf", "tenor_months": 400, "repayment_method": "balloon"}`,
+			creating: true,
+			pointers: []string{"/borrower_id", "/principal_amount", "/rate", "/roi", "/agreement_letter_link", "/tenor_months", "/repayment_method"},
+		},
+		// An update may leave out the terms an approved loan keeps anyway
+		{"update without terms", `{"agreement_letter_link": "https://example.com/agreement.pdf"}`, false, nil},
+		{"update with a negative principal", `{"principal_amount": "-1.00"}`, false, []string{"/principal_amount"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req loanRequest
+			require.NoError(t, json.Unmarshal([]byte(tt.body), &req))
+
+			assert.Equal(t, tt.pointers, fieldPointers(req.validate(tt.creating)))
+		})
+	}
+}
+
+func TestBorrowerRequestValidate(t *testing.T) {
+	req := borrowerRequest{FullName: "John Doe", Email: "john@", Phone: "+62 812 3456 7890 1234 5678"}
+
+	assert.Equal(t, []string{"/borrower_id_number", "/email", "/phone"}, fieldPointers(req.validate()))
+}
+
+func TestRegisterRequestValidate(t *testing.T) {
+	req := registerRequest{Email: "jane@example.com", FullName: "Jane", Password: "short"}
+
+	err := req.validate()
+
+	fields := apperr.From(err).Fields
+	require.Len(t, fields, 1)
+	assert.Equal(t, apperr.FieldError{Pointer: "/password", Code: "weak_password", Message: "does not meet the password policy: it must be at least 12 characters long"}, fields[0])
+}
+
+func TestCreateLoanReportsInvalidFields(t *testing.T) {
+	// The service is never called with an invalid loan
+	handler := NewLoanHandler(mocks.NewLoanService(t), mocks2.NewEmailService(t), mocks2.NewStorageService(t))
+	body := `{"borrower_id": 1, "principal_amount": "0", "rate": "2"}`
+
+	t.Run("in the Response envelope by default", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/loans", bytes.NewBufferString(body))
+		rr := httptest.NewRecorder()
+
+		ProblemDetails(http.HandlerFunc(handler.CreateLoan)).ServeHTTP(rr, req)
+
+		var response struct {
+			Error ErrorBody `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "validation_failed", response.Error.Code)
+		assert.Equal(t, "principal_amount must be greater than 0; rate must be between 0 and 1", response.Error.Error)
+	})
+
+	t.Run("as a problem document when asked for", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/loans", bytes.NewBufferString(body))
+		req.Header.Set("Accept", "application/json, application/problem+json")
+		rr := httptest.NewRecorder()
+
+		ProblemDetails(http.HandlerFunc(handler.CreateLoan)).ServeHTTP(rr, req)
+
+		var problem Problem
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "urn:loan-engine:problem:validation_failed", problem.Type)
+		assert.Equal(t, http.StatusUnprocessableEntity, problem.Status)
+		assert.Equal(t, "/api/v1/loans", problem.Instance)
+		assert.Equal(t, []apperr.FieldError{
+			{Pointer: "/principal_amount", Code: "out_of_range", Message: "must be greater than 0"},
+			{Pointer: "/rate", Code: "out_of_range", Message: "must be between 0 and 1"},
+		}, problem.Errors)
+	})
+}
+
+func TestAcceptsProblemJSON(t *testing.T) {
+	assert.True(t, acceptsProblemJSON([]string{"application/problem+json"}))
+	assert.True(t, acceptsProblemJSON([]string{"application/json", "application/problem+json;q=0.9"}))
+	assert.False(t, acceptsProblemJSON([]string{"application/problem+json;q=0"}))
+	assert.False(t, acceptsProblemJSON([]string{"application/json, */*"}))
+	assert.False(t, acceptsProblemJSON(nil))
+}
+
+// fieldPointers returns the pointers of the invalid fields err reports
+func fieldPointers(err error) []string {
+	if err == nil {
+		return nil
+	}
+
+	var pointers []string
+	for _, field := range apperr.From(err).Fields {
+		pointers = append(pointers, field.Pointer)
+	}
+	return pointers
+}
@@ -0,0 +1,165 @@
+// Package ledger is the engine's append-only double-entry general ledger. Every money
+// movement is a journal entry whose postings debit and credit accounts by equal totals.
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+)
+
+// AccountType decides which side of an account increases its balance
+type AccountType string
+
+const (
+	AccountTypeAsset     AccountType = "asset"
+	AccountTypeLiability AccountType = "liability"
+	AccountTypeEquity    AccountType = "equity"
+	AccountTypeRevenue   AccountType = "revenue"
+	AccountTypeExpense   AccountType = "expense"
+)
+
+// DebitNormal reports whether debits increase the balance of this type of account
+func (t AccountType) DebitNormal() bool {
+	return t == AccountTypeAsset || t == AccountTypeExpense
+}
+
+// Chart of accounts seeded by the ledger migration
+const (
+	// AccountCash is money held by the platform
+	AccountCash = "cash"
+	// AccountLoansReceivable is principal disbursed to borrowers and not yet repaid
+	AccountLoansReceivable = "loans_receivable"
+	// AccountInvestorFunds is money investors committed to loans that are not disbursed yet
+	AccountInvestorFunds = "investor_funds"
+	// AccountInvestorCapital is investor principal lent out and owed back to them
+	AccountInvestorCapital = "investor_capital"
+	// AccountBorrowerCredit is overpayments held for borrowers
+	AccountBorrowerCredit = "borrower_credit"
+	// AccountInterestIncome is interest collected from borrowers
+	AccountInterestIncome = "interest_income"
+	// AccountFeeIncome is late fees collected from borrowers
+	AccountFeeIncome = "fee_income"
+	// AccountInvestorInterest is interest paid out to investors
+	AccountInvestorInterest = "investor_interest_expense"
+)
+
+// Direction is the side of the account a posting lands on
+type Direction string
+
+const (
+	Debit  Direction = "debit"
+	Credit Direction = "credit"
+)
+
+type Account struct {
+	ID        int         `json:"id" db:"id"`
+	Code      string      `json:"code" db:"code"`
+	Name      string      `json:"name" db:"name"`
+	Type      AccountType `json:"type" db:"type"`
+	CreatedAt time.Time   `json:"created_at" db:"created_at"`
+}
+
+// AccountBalance is an account with its balance on the account's normal side, so a
+// positive balance means money held for assets and expenses and owed for the rest
+type AccountBalance struct {
+	Account
+	Balance money.Money `json:"balance" db:"balance"`
+}
+
+// normalize turns a debits-minus-credits sum into a balance on the account's normal side
+func (b *AccountBalance) normalize() {
+	if !b.Type.DebitNormal() {
+		b.Balance = money.Zero(b.Balance.Currency()).Sub(b.Balance)
+	}
+}
+
+// JournalEntry is one balanced money movement. Reference ties it to the record that
+// caused it, such as "loan_repayment:12".
+type JournalEntry struct {
+	ID          int        `json:"id" db:"id"`
+	Reference   string     `json:"reference" db:"reference"`
+	Description string     `json:"description" db:"description"`
+	LoanID      *int       `json:"loan_id,omitempty" db:"loan_id"`
+	Postings    []*Posting `json:"postings" db:"-"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+type Posting struct {
+	ID             int         `json:"id" db:"id"`
+	JournalEntryID int         `json:"journal_entry_id" db:"journal_entry_id"`
+	AccountCode    string      `json:"account_code" db:"account_code"`
+	Direction      Direction   `json:"direction" db:"direction"`
+	Amount         money.Money `json:"amount" db:"amount"`
+}
+
+// NewEntry starts a journal entry for the given loan
+func NewEntry(loanID int, reference, description string) *JournalEntry {
+	return &JournalEntry{
+		Reference:   reference,
+		Description: description,
+		LoanID:      &loanID,
+	}
+}
+
+// Debit adds a debit posting. Zero amounts are skipped so callers can post every
+// component of a movement without checking which ones are empty.
+func (e *JournalEntry) Debit(account string, amount money.Money) *JournalEntry {
+	return e.post(account, Debit, amount)
+}
+
+// Credit adds a credit posting, skipping zero amounts like Debit
+func (e *JournalEntry) Credit(account string, amount money.Money) *JournalEntry {
+	return e.post(account, Credit, amount)
+}
+
+func (e *JournalEntry) post(account string, direction Direction, amount money.Money) *JournalEntry {
+	if !amount.IsZero() {
+		e.Postings = append(e.Postings, &Posting{AccountCode: account, Direction: direction, Amount: amount})
+	}
+	return e
+}
+
+// Validate enforces the double-entry invariant: at least one debit and one credit,
+// positive amounts in a single currency, and debits equal to credits
+func (e *JournalEntry) Validate() error {
+	if e.Reference == "" {
+		return errors.New("journal entry reference is required")
+	}
+
+	if len(e.Postings) < 2 {
+		return errors.New("journal entry needs at least two postings")
+	}
+
+	currency := e.Postings[0].Amount.Currency()
+	debits := money.Zero(currency)
+	credits := money.Zero(currency)
+	for _, posting := range e.Postings {
+		if posting.AccountCode == "" {
+			return errors.New("posting account is required")
+		}
+		if !posting.Amount.IsPositive() {
+			return fmt.Errorf("posting to %s must be greater than 0", posting.AccountCode)
+		}
+		if posting.Amount.Currency() != currency {
+			return errors.New("journal entry postings must share one currency")
+		}
+
+		switch posting.Direction {
+		case Debit:
+			debits = debits.Add(posting.Amount)
+		case Credit:
+			credits = credits.Add(posting.Amount)
+		default:
+			return fmt.Errorf("invalid posting direction: %s", posting.Direction)
+		}
+	}
+
+	if !debits.Equal(credits) {
+		return fmt.Errorf("journal entry is unbalanced: debits %s, credits %s", debits, credits)
+	}
+
+	return nil
+}
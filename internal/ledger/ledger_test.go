@@ -0,0 +1,78 @@
+package ledger_test
+
+import (
+	"testing"
+
+	"github.com/sswastioyono18/loan-engine/internal/ledger"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAcceptsBalancedEntry(t *testing.T) {
+	entry := ledger.NewEntry(1, "loan_repayment:1", "Repayment").
+		Debit(ledger.AccountCash, money.MustParse("110.00")).
+		Credit(ledger.AccountLoansReceivable, money.MustParse("100.00")).
+		Credit(ledger.AccountInterestIncome, money.MustParse("10.00")).
+		Credit(ledger.AccountFeeIncome, money.MustParse("0.00"))
+
+	require.NoError(t, entry.Validate())
+	// Zero amounts are not posted
+	assert.Len(t, entry.Postings, 3)
+	assert.Equal(t, 1, *entry.LoanID)
+}
+
+func TestValidateRefusesUnbalancedEntry(t *testing.T) {
+	entry := ledger.NewEntry(1, "loan_repayment:1", "Repayment").
+		Debit(ledger.AccountCash, money.MustParse("110.00")).
+		Credit(ledger.AccountLoansReceivable, money.MustParse("100.00")).
+		Credit(ledger.AccountInterestIncome, money.MustParse("9.99"))
+
+	err := entry.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unbalanced")
+}
+
+func TestValidateRefusesMalformedEntries(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry *ledger.JournalEntry
+	}{
+		{
+			name:  "no reference",
+			entry: ledger.NewEntry(1, "", "").Debit(ledger.AccountCash, money.MustParse("1.00")).Credit(ledger.AccountInvestorFunds, money.MustParse("1.00")),
+		},
+		{
+			name:  "single posting",
+			entry: ledger.NewEntry(1, "ref", "").Debit(ledger.AccountCash, money.MustParse("1.00")),
+		},
+		{
+			name:  "negative amount",
+			entry: ledger.NewEntry(1, "ref", "").Debit(ledger.AccountCash, money.MustParse("-1.00")).Credit(ledger.AccountInvestorFunds, money.MustParse("-1.00")),
+		},
+		{
+			name:  "mixed currencies",
+			entry: ledger.NewEntry(1, "ref", "").Debit(ledger.AccountCash, money.New(100, "IDR")).Credit(ledger.AccountInvestorFunds, money.New(100, "USD")),
+		},
+		{
+			name: "unknown direction",
+			entry: &ledger.JournalEntry{Reference: "ref", Postings: []*ledger.Posting{
+				{AccountCode: ledger.AccountCash, Direction: "sideways", Amount: money.MustParse("1.00")},
+				{AccountCode: ledger.AccountInvestorFunds, Direction: ledger.Credit, Amount: money.MustParse("1.00")},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, tt.entry.Validate())
+		})
+	}
+}
+
+func TestAccountTypeDebitNormal(t *testing.T) {
+	assert.True(t, ledger.AccountTypeAsset.DebitNormal())
+	assert.True(t, ledger.AccountTypeExpense.DebitNormal())
+	assert.False(t, ledger.AccountTypeLiability.DebitNormal())
+	assert.False(t, ledger.AccountTypeRevenue.DebitNormal())
+}
@@ -0,0 +1,150 @@
+package ledger
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/repositories"
+)
+
+// Repository stores journal entries and reads account balances. Entries are only
+// ever inserted; corrections are posted as new, reversing entries.
+type Repository interface {
+	CreateEntry(ctx context.Context, entry *JournalEntry) error
+	GetEntriesByLoanID(ctx context.Context, loanID int) ([]*JournalEntry, error)
+	GetBalance(ctx context.Context, accountCode string) (*AccountBalance, error)
+	ListBalances(ctx context.Context) ([]*AccountBalance, error)
+}
+
+type repositoryImpl struct {
+	base *repositories.BaseRepository
+}
+
+// NewRepository returns the Postgres ledger repository. Like the other repositories it
+// joins the unit of work carried by the context, so entries commit with the change
+// that caused them.
+func NewRepository(driver repositories.Driver) Repository {
+	return &repositoryImpl{
+		base: repositories.NewBaseRepository(driver),
+	}
+}
+
+func (r *repositoryImpl) CreateEntry(ctx context.Context, entry *JournalEntry) error {
+	query := `
+		INSERT INTO journal_entries (reference, description, loan_id)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+
+	err := r.base.Executor(ctx).QueryRowContext(
+		ctx, query,
+		entry.Reference, entry.Description, entry.LoanID,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	postingQuery := `
+		INSERT INTO ledger_postings (journal_entry_id, account_id, direction, amount)
+		SELECT $1, id, $3, $4 FROM ledger_accounts WHERE code = $2
+		RETURNING id
+	`
+
+	for _, posting := range entry.Postings {
+		posting.JournalEntryID = entry.ID
+		err = r.base.Executor(ctx).QueryRowContext(
+			ctx, postingQuery,
+			entry.ID, posting.AccountCode, posting.Direction, posting.Amount,
+		).Scan(&posting.ID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return apperr.NotFound("ledger_account_not_found", "ledger account %s not found", posting.AccountCode)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *repositoryImpl) GetEntriesByLoanID(ctx context.Context, loanID int) ([]*JournalEntry, error) {
+	query := `
+		SELECT id, reference, description, loan_id, created_at
+		FROM journal_entries WHERE loan_id = $1
+		ORDER BY id ASC
+	`
+
+	var entries []*JournalEntry
+	err := r.base.Executor(ctx).SelectContext(ctx, &entries, query, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	postingQuery := `
+		SELECT p.id, p.journal_entry_id, a.code AS account_code, p.direction, p.amount
+		FROM ledger_postings p
+		JOIN ledger_accounts a ON a.id = p.account_id
+		JOIN journal_entries e ON e.id = p.journal_entry_id
+		WHERE e.loan_id = $1
+		ORDER BY p.id ASC
+	`
+
+	var postings []*Posting
+	err = r.base.Executor(ctx).SelectContext(ctx, &postings, postingQuery, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	byEntry := make(map[int]*JournalEntry, len(entries))
+	for _, entry := range entries {
+		byEntry[entry.ID] = entry
+	}
+	for _, posting := range postings {
+		if entry, ok := byEntry[posting.JournalEntryID]; ok {
+			entry.Postings = append(entry.Postings, posting)
+		}
+	}
+
+	return entries, nil
+}
+
+// balanceQuery sums postings as debits minus credits; callers flip the sign for
+// credit-normal accounts
+const balanceQuery = `
+	SELECT a.id, a.code, a.name, a.type, a.created_at,
+	       COALESCE(SUM(CASE WHEN p.direction = 'debit' THEN p.amount ELSE -p.amount END), 0) AS balance
+	FROM ledger_accounts a
+	LEFT JOIN ledger_postings p ON p.account_id = a.id
+`
+
+func (r *repositoryImpl) GetBalance(ctx context.Context, accountCode string) (*AccountBalance, error) {
+	query := balanceQuery + " WHERE a.code = $1 GROUP BY a.id"
+
+	var balance AccountBalance
+	err := r.base.Executor(ctx).GetContext(ctx, &balance, query, accountCode)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperr.NotFound("ledger_account_not_found", "ledger account %s not found", accountCode)
+		}
+		return nil, err
+	}
+
+	balance.normalize()
+	return &balance, nil
+}
+
+func (r *repositoryImpl) ListBalances(ctx context.Context) ([]*AccountBalance, error) {
+	query := balanceQuery + " GROUP BY a.id ORDER BY a.id ASC"
+
+	var balances []*AccountBalance
+	err := r.base.Executor(ctx).SelectContext(ctx, &balances, query)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, balance := range balances {
+		balance.normalize()
+	}
+	return balances, nil
+}
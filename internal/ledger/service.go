@@ -0,0 +1,50 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+)
+
+// Service posts journal entries and reports balances. It is the only way entries reach
+// the repository, so no unbalanced journal is ever stored.
+type Service interface {
+	Post(ctx context.Context, entry *JournalEntry) error
+	GetEntriesByLoanID(ctx context.Context, loanID int) ([]*JournalEntry, error)
+	GetBalance(ctx context.Context, accountCode string) (*AccountBalance, error)
+	ListBalances(ctx context.Context) ([]*AccountBalance, error)
+}
+
+type serviceImpl struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &serviceImpl{
+		repo: repo,
+	}
+}
+
+// Post validates the entry against the double-entry invariant and stores it
+func (s *serviceImpl) Post(ctx context.Context, entry *JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return fmt.Errorf("refusing journal entry %s: %w", entry.Reference, err)
+	}
+
+	if err := s.repo.CreateEntry(ctx, entry); err != nil {
+		return fmt.Errorf("failed to post journal entry: %w", err)
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) GetEntriesByLoanID(ctx context.Context, loanID int) ([]*JournalEntry, error) {
+	return s.repo.GetEntriesByLoanID(ctx, loanID)
+}
+
+func (s *serviceImpl) GetBalance(ctx context.Context, accountCode string) (*AccountBalance, error) {
+	return s.repo.GetBalance(ctx, accountCode)
+}
+
+func (s *serviceImpl) ListBalances(ctx context.Context) ([]*AccountBalance, error) {
+	return s.repo.ListBalances(ctx)
+}
@@ -0,0 +1,74 @@
+package ledger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sswastioyono18/loan-engine/internal/ledger"
+	"github.com/sswastioyono18/loan-engine/internal/ledger/mocks"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostStoresBalancedEntry(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	service := ledger.NewService(mockRepo)
+
+	entry := ledger.NewEntry(1, "loan_investment:1", "Investment").
+		Debit(ledger.AccountCash, money.MustParse("500.00")).
+		Credit(ledger.AccountInvestorFunds, money.MustParse("500.00"))
+
+	mockRepo.On("CreateEntry", context.Background(), entry).Return(nil)
+
+	err := service.Post(context.Background(), entry)
+
+	assert.NoError(t, err)
+}
+
+func TestPostRefusesUnbalancedEntry(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	service := ledger.NewService(mockRepo)
+
+	entry := ledger.NewEntry(1, "loan_investment:1", "Investment").
+		Debit(ledger.AccountCash, money.MustParse("500.00")).
+		Credit(ledger.AccountInvestorFunds, money.MustParse("499.99"))
+
+	err := service.Post(context.Background(), entry)
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "CreateEntry", mock.Anything, mock.Anything)
+}
+
+func TestPostWrapsRepositoryError(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	service := ledger.NewService(mockRepo)
+
+	entry := ledger.NewEntry(1, "loan_investment:1", "Investment").
+		Debit(ledger.AccountCash, money.MustParse("500.00")).
+		Credit(ledger.AccountInvestorFunds, money.MustParse("500.00"))
+
+	mockRepo.On("CreateEntry", context.Background(), entry).Return(errors.New("ledger account cash not found"))
+
+	err := service.Post(context.Background(), entry)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to post journal entry")
+}
+
+func TestGetBalance(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	service := ledger.NewService(mockRepo)
+
+	balance := &ledger.AccountBalance{
+		Account: ledger.Account{Code: ledger.AccountCash, Type: ledger.AccountTypeAsset},
+		Balance: money.MustParse("500.00"),
+	}
+	mockRepo.On("GetBalance", context.Background(), ledger.AccountCash).Return(balance, nil)
+
+	result, err := service.GetBalance(context.Background(), ledger.AccountCash)
+
+	assert.NoError(t, err)
+	assert.Equal(t, balance, result)
+}
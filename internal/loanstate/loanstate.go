@@ -0,0 +1,216 @@
+// Package loanstate defines the loan state machine: the states a loan can be in, the
+// events that move it between them, and the guards and hooks that run on each move.
+// The same table drives the loan service, the database trigger and the documentation.
+package loanstate
+
+//go:generate go run ../../cmd/loanstategen -migrations ../../migrations -diagram ../../docs/LOAN_STATE_MACHINE.md
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+// Loan states
+const (
+	Proposed   = "proposed"
+	Approved   = "approved"
+	Invested   = "invested"
+	Disbursed  = "disbursed"
+	Repaid     = "repaid"
+	Closed     = "closed"
+	Defaulted  = "defaulted"
+	WrittenOff = "written_off"
+	Rejected   = "rejected"
+	Cancelled  = "cancelled"
+	Expired    = "expired"
+)
+
+// Event names something that happens to a loan and may change its state
+type Event string
+
+const (
+	Approve    Event = "approve"
+	Fund       Event = "fund"
+	Disburse   Event = "disburse"
+	Reject     Event = "reject"
+	Cancel     Event = "cancel"
+	Expire     Event = "expire"
+	MarkRepaid Event = "mark_repaid"
+	Close      Event = "close"
+	Default    Event = "default"
+	WriteOff   Event = "write_off"
+)
+
+// Transition moves a loan in any of the From states to the To state when Event happens
+type Transition struct {
+	Event Event
+	From  []string
+	To    string
+}
+
+// Transitions is the loan state machine. States that no transition leaves are terminal.
+var Transitions = []Transition{
+	{Event: Approve, From: []string{Proposed}, To: Approved},
+	{Event: Fund, From: []string{Approved}, To: Invested},
+	{Event: Disburse, From: []string{Invested}, To: Disbursed},
+	{Event: MarkRepaid, From: []string{Disbursed}, To: Repaid},
+	{Event: Close, From: []string{Repaid}, To: Closed},
+	{Event: Default, From: []string{Disbursed}, To: Defaulted},
+	{Event: WriteOff, From: []string{Defaulted}, To: WrittenOff},
+	{Event: Reject, From: []string{Proposed, Approved}, To: Rejected},
+	{Event: Cancel, From: []string{Proposed, Approved, Invested}, To: Cancelled},
+	{Event: Expire, From: []string{Proposed, Approved}, To: Expired},
+}
+
+// States lists every state in transitions, in the order they first appear
+func States(transitions []Transition) []string {
+	var states []string
+	seen := map[string]bool{}
+	add := func(state string) {
+		if !seen[state] {
+			seen[state] = true
+			states = append(states, state)
+		}
+	}
+
+	for _, t := range transitions {
+		for _, from := range t.From {
+			add(from)
+		}
+		add(t.To)
+	}
+
+	return states
+}
+
+// Guard returns an error when a loan may not take a transition yet
+type Guard func(ctx context.Context, loan *models.Loan) error
+
+// Hook performs a side effect of a transition before the loan's state changes
+type Hook func(ctx context.Context, loan *models.Loan) error
+
+// Machine fires events on loans according to a transition table, running the guards and
+// hooks registered for each event
+type Machine struct {
+	transitions map[Event]Transition
+	states      map[string]bool
+	guards      map[Event][]Guard
+	hooks       map[Event][]Hook
+}
+
+// NewMachine returns a machine for transitions with no guards or hooks registered
+func NewMachine(transitions []Transition) *Machine {
+	m := &Machine{
+		transitions: map[Event]Transition{},
+		states:      map[string]bool{},
+		guards:      map[Event][]Guard{},
+		hooks:       map[Event][]Hook{},
+	}
+
+	for _, t := range transitions {
+		if _, exists := m.transitions[t.Event]; exists {
+			panic(fmt.Sprintf("loanstate: duplicate transition for event %s", t.Event))
+		}
+		m.transitions[t.Event] = t
+	}
+	for _, state := range States(transitions) {
+		m.states[state] = true
+	}
+
+	return m
+}
+
+// Guard registers a guard for event. Guards run in registration order before any hook.
+func (m *Machine) Guard(event Event, guard Guard) *Machine {
+	m.mustHaveEvent(event)
+	m.guards[event] = append(m.guards[event], guard)
+	return m
+}
+
+// Hook registers a side-effect hook for event. Hooks run in registration order once
+// every guard has passed.
+func (m *Machine) Hook(event Event, hook Hook) *Machine {
+	m.mustHaveEvent(event)
+	m.hooks[event] = append(m.hooks[event], hook)
+	return m
+}
+
+func (m *Machine) mustHaveEvent(event Event) {
+	if _, exists := m.transitions[event]; !exists {
+		panic(fmt.Sprintf("loanstate: unknown event %s", event))
+	}
+}
+
+// Allows returns an error unless event can happen to a loan in state, ignoring guards
+func (m *Machine) Allows(state string, event Event) error {
+	t, exists := m.transitions[event]
+	if !exists {
+		return fmt.Errorf("unknown loan event: %s", event)
+	}
+
+	for _, from := range t.From {
+		if from == state {
+			return nil
+		}
+	}
+
+	return apperr.InvalidState("invalid_loan_state", "loan must be in %s state to be %s", strings.Join(t.From, " or "), strings.ReplaceAll(t.To, "_", " "))
+}
+
+// Fire checks that event can happen to loan, then runs its guards, effect and hooks in
+// that order and returns the state the loan moves to. effect is the caller's own work for
+// this transition and may be nil. Fire does not change loan; persisting the new state is
+// up to the caller.
+func (m *Machine) Fire(ctx context.Context, loan *models.Loan, event Event, effect Hook) (string, error) {
+	err := m.Allows(loan.CurrentState, event)
+	if err != nil {
+		return "", err
+	}
+
+	for _, guard := range m.guards[event] {
+		err = guard(ctx, loan)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if effect != nil {
+		err = effect(ctx, loan)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	for _, hook := range m.hooks[event] {
+		err = hook(ctx, loan)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return m.transitions[event].To, nil
+}
+
+// CanTransition reports whether some event moves a loan from one state to another
+func (m *Machine) CanTransition(from, to string) (bool, error) {
+	if !m.states[from] {
+		return false, fmt.Errorf("invalid current state: %s", from)
+	}
+
+	for _, t := range m.transitions {
+		if t.To != to {
+			continue
+		}
+		for _, state := range t.From {
+			if state == from {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
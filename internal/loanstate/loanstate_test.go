@@ -0,0 +1,131 @@
+package loanstate_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sswastioyono18/loan-engine/internal/loanstate"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFireRunsGuardsEffectAndHooksInOrder(t *testing.T) {
+	var calls []string
+	record := func(name string) loanstate.Hook {
+		return func(ctx context.Context, loan *models.Loan) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	machine := loanstate.NewMachine(loanstate.Transitions).
+		Hook(loanstate.Cancel, loanstate.Hook(record("hook"))).
+		Guard(loanstate.Cancel, loanstate.Guard(record("guard")))
+
+	newState, err := machine.Fire(context.Background(), &models.Loan{CurrentState: loanstate.Invested}, loanstate.Cancel, record("effect"))
+
+	require.NoError(t, err)
+	assert.Equal(t, loanstate.Cancelled, newState)
+	assert.Equal(t, []string{"guard", "effect", "hook"}, calls)
+}
+
+func TestFireStopsAtFailingGuard(t *testing.T) {
+	errNotReady := errors.New("not ready")
+	hookRan := false
+
+	machine := loanstate.NewMachine(loanstate.Transitions).
+		Guard(loanstate.MarkRepaid, func(ctx context.Context, loan *models.Loan) error {
+			return errNotReady
+		}).
+		Hook(loanstate.MarkRepaid, func(ctx context.Context, loan *models.Loan) error {
+			hookRan = true
+			return nil
+		})
+
+	_, err := machine.Fire(context.Background(), &models.Loan{CurrentState: loanstate.Disbursed}, loanstate.MarkRepaid, nil)
+
+	assert.ErrorIs(t, err, errNotReady)
+	assert.False(t, hookRan)
+}
+
+func TestFireRejectsEventFromWrongState(t *testing.T) {
+	machine := loanstate.NewMachine(loanstate.Transitions)
+
+	_, err := machine.Fire(context.Background(), &models.Loan{CurrentState: loanstate.Disbursed}, loanstate.WriteOff, nil)
+
+	assert.EqualError(t, err, "loan must be in defaulted state to be written off")
+}
+
+func TestTerminalStatesAcceptNoEvents(t *testing.T) {
+	machine := loanstate.NewMachine(loanstate.Transitions)
+
+	for _, state := range []string{loanstate.Closed, loanstate.WrittenOff, loanstate.Rejected, loanstate.Cancelled, loanstate.Expired} {
+		for _, to := range loanstate.States(loanstate.Transitions) {
+			canTransition, err := machine.CanTransition(state, to)
+			require.NoError(t, err)
+			assert.False(t, canTransition, "%s -> %s", state, to)
+		}
+	}
+}
+
+func TestRegisteringUnknownEventPanics(t *testing.T) {
+	assert.Panics(t, func() {
+		loanstate.NewMachine(loanstate.Transitions).Hook("archive", func(ctx context.Context, loan *models.Loan) error {
+			return nil
+		})
+	})
+}
+
+// The trigger migration and diagram are generated from the transition table; run
+// go generate ./internal/loanstate whenever this test fails to add a new migration for
+// the change and rewrite the diagram
+func TestGeneratedFilesMatchTransitionTable(t *testing.T) {
+	migrations, err := loanstate.GeneratedMigrations("../../migrations")
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(migrations), 2)
+
+	previous, err := os.ReadFile(migrations[len(migrations)-2])
+	require.NoError(t, err)
+	latest, err := os.ReadFile(migrations[len(migrations)-1])
+	require.NoError(t, err)
+	assert.Equal(t, loanstate.TriggerMigration(loanstate.Transitions, string(previous)), string(latest))
+
+	diagram, err := os.ReadFile("../../docs/LOAN_STATE_MACHINE.md")
+	require.NoError(t, err)
+	assert.Equal(t, loanstate.Document(loanstate.Transitions), string(diagram))
+}
+
+func TestTriggerMigrationOnlyValidates(t *testing.T) {
+	migration := loanstate.TriggerMigration(loanstate.Transitions, "")
+
+	assert.NotContains(t, migration, "INSERT INTO loan_state_history", "the loan service records history with the actor")
+	assert.Contains(t, migration, "RAISE EXCEPTION 'Loan is already in % state'", "a same-state update means a stale read")
+	assert.True(t, strings.HasSuffix(migration, "-- +goose Down\n-- +goose StatementBegin\n"+
+		"DROP TRIGGER IF EXISTS validate_loan_state ON loans;\n"+
+		"DROP FUNCTION IF EXISTS validate_loan_state_transition();\n"+
+		"-- +goose StatementEnd\n"), migration)
+}
+
+func TestTriggerMigrationRollsBackToPrevious(t *testing.T) {
+	previous := "-- header\n\n-- +goose Up\nSELECT 1;\n\n-- +goose Down\nSELECT 2;\n"
+
+	migration := loanstate.TriggerMigration(loanstate.Transitions, previous)
+
+	assert.True(t, strings.HasSuffix(migration, "-- +goose Down\nSELECT 1;\n"), migration)
+}
+
+func TestNextMigrationPath(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"001_create.sql", "009_alter.sql", "README.md"} {
+		require.NoError(t, os.WriteFile(dir+"/"+name, nil, 0o644))
+	}
+
+	path, err := loanstate.NextMigrationPath(dir, "loan_state_machine")
+
+	require.NoError(t, err)
+	assert.Equal(t, dir+"/010_loan_state_machine.sql", path)
+}
@@ -0,0 +1,60 @@
+package loanstate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GeneratedMigrations returns the migrations in dir written by cmd/loanstategen, oldest first
+func GeneratedMigrations(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return migrationVersion(paths[i]) < migrationVersion(paths[j])
+	})
+
+	var generated []string
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(string(content), GeneratedHeader) {
+			generated = append(generated, path)
+		}
+	}
+	return generated, nil
+}
+
+// NextMigrationPath returns the path of a new migration called name in dir, numbered one
+// past the newest migration already there
+func NextMigrationPath(dir, name string) (string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return "", err
+	}
+
+	next := 1
+	for _, path := range paths {
+		if version := migrationVersion(path); version >= next {
+			next = version + 1
+		}
+	}
+	return filepath.Join(dir, fmt.Sprintf("%03d_%s.sql", next, name)), nil
+}
+
+// migrationVersion reads the number goose takes from the start of a migration file name
+func migrationVersion(path string) int {
+	prefix, _, _ := strings.Cut(filepath.Base(path), "_")
+	version, err := strconv.Atoi(prefix)
+	if err != nil {
+		return 0
+	}
+	return version
+}
@@ -0,0 +1,137 @@
+package loanstate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GeneratedHeader marks files written by cmd/loanstategen
+const GeneratedHeader = "Code generated by cmd/loanstategen from internal/loanstate; DO NOT EDIT."
+
+// TriggerMigration renders a goose migration that replaces the loans current_state check
+// constraint and the validate_loan_state_transition trigger function with ones that
+// enforce transitions. previous is the migration it follows; rolling back restores the
+// definition previous created, or drops the trigger when there is none.
+func TriggerMigration(transitions []Transition, previous string) string {
+	states := States(transitions)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- %s\n\n", GeneratedHeader)
+	b.WriteString("-- +goose Up\n")
+	b.WriteString("-- +goose StatementBegin\n")
+	b.WriteString("ALTER TABLE loans DROP CONSTRAINT IF EXISTS loans_current_state_check;\n")
+	fmt.Fprintf(&b, "ALTER TABLE loans ADD CONSTRAINT loans_current_state_check CHECK (current_state IN (%s));\n", quoteList(states))
+	b.WriteString("-- +goose StatementEnd\n\n")
+
+	b.WriteString("-- +goose StatementBegin\n")
+	b.WriteString("CREATE OR REPLACE FUNCTION validate_loan_state_transition()\n")
+	b.WriteString("RETURNS TRIGGER AS $$\n")
+	b.WriteString("BEGIN\n")
+	// Only state changes set current_state, so setting it to the state the loan is already in
+	// means two changes raced on a stale read
+	b.WriteString("    IF NEW.current_state = OLD.current_state THEN\n")
+	b.WriteString("        RAISE EXCEPTION 'Loan is already in % state', OLD.current_state;\n")
+	b.WriteString("    END IF;\n\n")
+	b.WriteString("    IF NOT (\n")
+
+	var clauses []string
+	for _, from := range states {
+		targets := targetsFrom(transitions, from)
+		if len(targets) == 0 {
+			continue
+		}
+		clauses = append(clauses, fmt.Sprintf("        (OLD.current_state = '%s' AND NEW.current_state IN (%s))", from, quoteList(targets)))
+	}
+	b.WriteString(strings.Join(clauses, " OR\n"))
+	b.WriteString("\n    ) THEN\n")
+	b.WriteString("        RAISE EXCEPTION 'Loan cannot move from % state to % state', OLD.current_state, NEW.current_state;\n")
+	b.WriteString("    END IF;\n\n")
+	b.WriteString("    -- The loan service records the transition in loan_state_history along with who made it\n")
+	b.WriteString("    RETURN NEW;\n")
+	b.WriteString("END;\n")
+	b.WriteString("$$ LANGUAGE 'plpgsql';\n")
+	b.WriteString("-- +goose StatementEnd\n\n")
+
+	b.WriteString("-- +goose Down\n")
+	if up := upSection(previous); up != "" {
+		// Every generated migration replaces the whole definition, so rolling one back
+		// re-applies the one before it
+		b.WriteString(up)
+	} else {
+		b.WriteString("-- +goose StatementBegin\n")
+		b.WriteString("DROP TRIGGER IF EXISTS validate_loan_state ON loans;\n")
+		b.WriteString("DROP FUNCTION IF EXISTS validate_loan_state_transition();\n")
+		b.WriteString("-- +goose StatementEnd\n")
+	}
+
+	return b.String()
+}
+
+// upSection returns the statements of a migration's Up section, without its annotations
+func upSection(migration string) string {
+	_, up, ok := strings.Cut(migration, "-- +goose Up\n")
+	if !ok {
+		return ""
+	}
+	up, _, _ = strings.Cut(up, "-- +goose Down\n")
+	if up = strings.TrimRight(up, "\n"); up == "" {
+		return ""
+	}
+	return up + "\n"
+}
+
+// Diagram renders transitions as a Mermaid state diagram. The first state is the initial
+// one and states no transition leaves are drawn as final.
+func Diagram(transitions []Transition) string {
+	states := States(transitions)
+
+	var b strings.Builder
+	b.WriteString("stateDiagram-v2\n")
+	if len(states) > 0 {
+		fmt.Fprintf(&b, "    [*] --> %s\n", states[0])
+	}
+	for _, t := range transitions {
+		for _, from := range t.From {
+			fmt.Fprintf(&b, "    %s --> %s: %s\n", from, t.To, t.Event)
+		}
+	}
+	for _, state := range states {
+		if len(targetsFrom(transitions, state)) == 0 {
+			fmt.Fprintf(&b, "    %s --> [*]\n", state)
+		}
+	}
+
+	return b.String()
+}
+
+// Document renders the Markdown page that embeds Diagram
+func Document(transitions []Transition) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<!-- %s -->\n\n", GeneratedHeader)
+	b.WriteString("# Loan State Machine\n\n")
+	b.WriteString("Each arrow is labelled with the event that moves a loan along it. Final states accept no further events.\n\n")
+	b.WriteString("```mermaid\n")
+	b.WriteString(Diagram(transitions))
+	b.WriteString("```\n")
+	return b.String()
+}
+
+func targetsFrom(transitions []Transition, from string) []string {
+	var targets []string
+	for _, t := range transitions {
+		for _, state := range t.From {
+			if state == from {
+				targets = append(targets, t.To)
+			}
+		}
+	}
+	return targets
+}
+
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = "'" + value + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
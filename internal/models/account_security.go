@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// PasswordResetToken is a single-use, expiring token mailed to a user who forgot their
+// password. Like refresh tokens, only its hash is stored.
+type PasswordResetToken struct {
+	ID        int        `json:"id" db:"id"`
+	UserID    int        `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// EmailVerificationToken is a single-use, expiring token mailed to a newly registered user
+// to prove they own the email address. Only its hash is stored.
+type EmailVerificationToken struct {
+	ID        int        `json:"id" db:"id"`
+	UserID    int        `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// SecurityEvent is an entry in the audit trail of account security events such as failed
+// logins, lockouts and password changes. UserID is nil when no account matched.
+type SecurityEvent struct {
+	ID        int       `json:"id" db:"id"`
+	UserID    *int      `json:"user_id,omitempty" db:"user_id"`
+	Email     string    `json:"email" db:"email"`
+	EventType string    `json:"event_type" db:"event_type"`
+	Detail    string    `json:"detail,omitempty" db:"detail"`
+	IPAddress string    `json:"ip_address,omitempty" db:"ip_address"`
+	UserAgent string    `json:"user_agent,omitempty" db:"user_agent"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
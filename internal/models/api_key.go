@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// APIKey lets a partner system call the API without a user login. The key handed out is
+// its prefix followed by a secret; only the secret's hash is stored, and the prefix is
+// what finds the key and identifies it in the audit trail.
+type APIKey struct {
+	ID         int    `json:"id" db:"id"`
+	Name       string `json:"name" db:"name"`
+	Prefix     string `json:"prefix" db:"prefix"`
+	SecretHash string `json:"-" db:"secret_hash"`
+	// Scopes are what the key may do, such as loans:write or investors:read
+	Scopes     pq.StringArray `json:"scopes" db:"scopes"`
+	ExpiresAt  *time.Time     `json:"expires_at,omitempty" db:"expires_at"`
+	LastUsedAt *time.Time     `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time     `json:"revoked_at,omitempty" db:"revoked_at"`
+	// CreatedBy is the user ID of the admin who created the key
+	CreatedBy string    `json:"created_by" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Usable reports whether the key is neither revoked nor expired at now
+func (k *APIKey) Usable(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
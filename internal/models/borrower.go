@@ -1,14 +1,19 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+)
 
 type Borrower struct {
-	ID                int       `json:"id" db:"id"`
-	BorrowerIDNumber  string    `json:"borrower_id_number" db:"id_number"`
-	FullName          string    `json:"full_name" db:"name"`
-	Email             string    `json:"email" db:"email"`
-	Phone             string    `json:"phone" db:"phone"`
-	Address           string    `json:"address" db:"address"`
-	CreatedAt         time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
-}
\ No newline at end of file
+	ID               int         `json:"id" db:"id"`
+	BorrowerIDNumber string      `json:"borrower_id_number" db:"id_number"`
+	FullName         string      `json:"full_name" db:"name"`
+	Email            string      `json:"email" db:"email"`
+	Phone            string      `json:"phone" db:"phone"`
+	Address          string      `json:"address" db:"address"`
+	CreditBalance    money.Money `json:"credit_balance" db:"credit_balance"` // Overpayments kept for the borrower
+	CreatedAt        time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at" db:"updated_at"`
+}
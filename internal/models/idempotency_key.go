@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// IdempotencyKey is a request sent with an Idempotency-Key header and, once it has been
+// handled, the response it got. Keys belong to the principal that sent them, so two
+// clients cannot collide on the same key.
+type IdempotencyKey struct {
+	ID     int    `json:"id" db:"id"`
+	Owner  string `json:"owner" db:"owner"`
+	Key    string `json:"key" db:"idempotency_key"`
+	Method string `json:"method" db:"method"`
+	Path   string `json:"path" db:"path"`
+	// RequestHash identifies the request body, so a key reused for another request is caught
+	RequestHash  string     `json:"-" db:"request_hash"`
+	StatusCode   *int       `json:"status_code,omitempty" db:"status_code"`
+	ContentType  *string    `json:"content_type,omitempty" db:"content_type"`
+	ResponseBody []byte     `json:"-" db:"response_body"`
+	LockedAt     time.Time  `json:"locked_at" db:"locked_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	ExpiresAt    time.Time  `json:"expires_at" db:"expires_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Completed reports whether the request has been handled and its response stored
+func (k *IdempotencyKey) Completed() bool {
+	return k.CompletedAt != nil
+}
+
+// Expired reports whether the key may be reused for a new request at now
+func (k *IdempotencyKey) Expired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
@@ -3,18 +3,45 @@ package models
 import (
 	"database/sql"
 	"time"
+
+	"github.com/sswastioyono18/loan-engine/pkg/money"
 )
 
 type Loan struct {
-	ID                  int           `json:"id" db:"id"`
-	LoanID              string        `json:"loan_id" db:"loan_id"`
-	BorrowerID          int           `json:"borrower_id" db:"borrower_id"`
-	PrincipalAmount     float64       `json:"principal_amount" db:"principal_amount"`
-	Rate                float64       `json:"rate" db:"rate"` // Interest rate percentage
-	ROI                 float64       `json:"roi" db:"roi"`   // Return of investment percentage
+	ID                  int            `json:"id" db:"id"`
+	LoanID              string         `json:"loan_id" db:"loan_id"`
+	BorrowerID          int            `json:"borrower_id" db:"borrower_id"`
+	PrincipalAmount     money.Money    `json:"principal_amount" db:"principal_amount"`
+	Rate                money.Rate     `json:"rate" db:"rate"` // Interest rate charged to the borrower
+	ROI                 money.Rate     `json:"roi" db:"roi"`   // Return of investment paid to investors
 	AgreementLetterLink sql.NullString `json:"agreement_letter_link,omitempty" db:"agreement_letter_link"`
-	CurrentState        string        `json:"current_state" db:"current_state"`
-	TotalInvestedAmount float64       `json:"total_invested_amount" db:"total_invested_amount"`
-	CreatedAt           time.Time     `json:"created_at" db:"created_at"`
-	UpdatedAt           time.Time     `json:"updated_at" db:"updated_at"`
-}
\ No newline at end of file
+	CurrentState        string         `json:"current_state" db:"current_state"`
+	TotalInvestedAmount money.Money    `json:"total_invested_amount" db:"total_invested_amount"`
+	TenorMonths         int            `json:"tenor_months" db:"tenor_months"`
+	RepaymentMethod     string         `json:"repayment_method" db:"repayment_method"`
+	CreatedAt           time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// Funding levels a listing of loans can be narrowed to
+const (
+	// FundingFull matches loans whose principal is fully invested
+	FundingFull = "full"
+	// FundingPartial matches loans that have investments but need more
+	FundingPartial = "partial"
+)
+
+// LoanFilter narrows a listing of loans; its zero value matches every loan. Ranges include
+// their bounds, and a nil bound leaves that side open.
+type LoanFilter struct {
+	State        string
+	BorrowerID   int
+	MinPrincipal *money.Money
+	MaxPrincipal *money.Money
+	MinRate      *money.Rate
+	MaxRate      *money.Rate
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	// Funding is FundingFull or FundingPartial, or empty for any
+	Funding string
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+)
+
+type LoanInstallment struct {
+	ID                int         `json:"id" db:"id"`
+	LoanID            int         `json:"loan_id" db:"loan_id"`
+	InstallmentNumber int         `json:"installment_number" db:"installment_number"`
+	DueDate           time.Time   `json:"due_date" db:"due_date"`
+	PrincipalAmount   money.Money `json:"principal_amount" db:"principal_amount"`
+	InterestAmount    money.Money `json:"interest_amount" db:"interest_amount"`
+	TotalAmount       money.Money `json:"total_amount" db:"total_amount"`
+	PenaltyAmount     money.Money `json:"penalty_amount" db:"penalty_amount"`
+	PrincipalPaid     money.Money `json:"principal_paid" db:"principal_paid"`
+	InterestPaid      money.Money `json:"interest_paid" db:"interest_paid"`
+	PenaltyPaid       money.Money `json:"penalty_paid" db:"penalty_paid"`
+	Status            string      `json:"status" db:"status"`
+	PaidAt            *time.Time  `json:"paid_at,omitempty" db:"paid_at"`
+	CreatedAt         time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time   `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// LoanRejection records why credit staff turned a loan down
+type LoanRejection struct {
+	ID                   int       `json:"id" db:"id"`
+	LoanID               int       `json:"loan_id" db:"loan_id"`
+	ReasonCode           string    `json:"reason_code" db:"reason_code"`
+	Notes                string    `json:"notes" db:"notes"`
+	RejectedByEmployeeID string    `json:"rejected_by_employee_id" db:"rejected_by_employee_id"`
+	RejectionDate        time.Time `json:"rejection_date" db:"rejected_at"`
+	CreatedAt            time.Time `json:"created_at" db:"created_at"`
+}
+
+// LoanRejectionReason is an entry in the catalogue of reasons a loan can be rejected for.
+// Inactive reasons stay on existing rejections but cannot be used for new ones.
+type LoanRejectionReason struct {
+	Code        string    `json:"code" db:"code"`
+	Description string    `json:"description" db:"description"`
+	IsActive    bool      `json:"is_active" db:"is_active"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
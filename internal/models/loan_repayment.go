@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+)
+
+// LoanRepayment is a payment received from a borrower and how it was allocated
+type LoanRepayment struct {
+	ID              int         `json:"id" db:"id"`
+	LoanID          int         `json:"loan_id" db:"loan_id"`
+	Amount          money.Money `json:"amount" db:"amount"`
+	PenaltyAmount   money.Money `json:"penalty_amount" db:"penalty_amount"`
+	InterestAmount  money.Money `json:"interest_amount" db:"interest_amount"`
+	PrincipalAmount money.Money `json:"principal_amount" db:"principal_amount"`
+	CreditAmount    money.Money `json:"credit_amount" db:"credit_amount"`
+	PaidAt          time.Time   `json:"paid_at" db:"paid_at"`
+	CreatedAt       time.Time   `json:"created_at" db:"created_at"`
+}
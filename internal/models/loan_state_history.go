@@ -8,5 +8,6 @@ type LoanStateHistory struct {
 	PreviousState    string    `json:"previous_state" db:"old_state"`
 	NewState         string    `json:"new_state" db:"new_state"`
 	TransitionReason string    `json:"transition_reason" db:"reason"`
+	ChangedBy        string    `json:"changed_by" db:"changed_by"`
 	CreatedAt        time.Time `json:"created_at" db:"created_at"`
 }
\ No newline at end of file
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortField orders a listing by one field, named as in the API, ascending unless Descending
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// ParseSort reads a sort parameter such as "created_at,-principal_amount": fields in order of
+// precedence, each descending when prefixed with "-". It only checks the syntax; which fields
+// a listing can be sorted by is up to the listing.
+func ParseSort(s string) ([]SortField, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var sort []SortField
+	for _, part := range strings.Split(s, ",") {
+		field := SortField{Field: strings.TrimSpace(part)}
+		if rest, ok := strings.CutPrefix(field.Field, "-"); ok {
+			field = SortField{Field: rest, Descending: true}
+		}
+		if field.Field == "" {
+			return nil, fmt.Errorf("sort %q has an empty field", s)
+		}
+		sort = append(sort, field)
+	}
+
+	return sort, nil
+}
+
+// SortString is the sort parameter that ParseSort reads back into sort
+func SortString(sort []SortField) string {
+	parts := make([]string, len(sort))
+	for i, field := range sort {
+		parts[i] = field.Field
+		if field.Descending {
+			parts[i] = "-" + field.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// PageRequest asks for one page of a listing. Cursor is empty for the first page and the
+// previous page's NextCursor after that; it only works with the Sort it was issued for.
+type PageRequest struct {
+	Cursor string
+	Limit  int
+	Sort   []SortField
+}
+
+// Page is one page of a listing. NextCursor is empty on the last page. TotalEstimate counts
+// the matches across all pages, exactly up to a cap and as the cap beyond it, so a client
+// can tell a handful of pages from many without the cost of counting a large table.
+type Page[T any] struct {
+	Items         []T    `json:"items"`
+	NextCursor    string `json:"next_cursor,omitempty"`
+	TotalEstimate int    `json:"total_estimate"`
+}
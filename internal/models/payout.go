@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+)
+
+// Payout is an investor's share of a borrower repayment
+type Payout struct {
+	ID               int         `json:"id" db:"id"`
+	LoanRepaymentID  int         `json:"loan_repayment_id" db:"loan_repayment_id"`
+	LoanID           int         `json:"loan_id" db:"loan_id"`
+	LoanInvestmentID int         `json:"loan_investment_id" db:"loan_investment_id"`
+	InvestorID       int         `json:"investor_id" db:"investor_id"`
+	PrincipalAmount  money.Money `json:"principal_amount" db:"principal_amount"`
+	InterestAmount   money.Money `json:"interest_amount" db:"interest_amount"`
+	Amount           money.Money `json:"amount" db:"amount"`
+	CreatedAt        time.Time   `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+)
+
+// PlatformRevenue is what the platform keeps from a borrower repayment: the interest
+// earned above the investors' ROI plus any late fees
+type PlatformRevenue struct {
+	ID              int         `json:"id" db:"id"`
+	LoanRepaymentID int         `json:"loan_repayment_id" db:"loan_repayment_id"`
+	LoanID          int         `json:"loan_id" db:"loan_id"`
+	MarginAmount    money.Money `json:"margin_amount" db:"margin_amount"`
+	PenaltyAmount   money.Money `json:"penalty_amount" db:"penalty_amount"`
+	Amount          money.Money `json:"amount" db:"amount"`
+	CreatedAt       time.Time   `json:"created_at" db:"created_at"`
+}
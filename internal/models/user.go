@@ -8,8 +8,30 @@ type User struct {
 	Email       string    `json:"email" db:"email"`
 	PasswordHash string   `json:"-" db:"password_hash"`
 	UserType    string    `json:"user_type" db:"user_type"` // staff, investor, admin
+	StaffRole   string    `json:"staff_role,omitempty" db:"staff_role"` // field_validator, field_officer; staff only
 	FullName    string    `json:"full_name" db:"name"`
 	IsActive    bool      `json:"is_active" db:"is_active"`
+	// EmailVerified is false for self-registered investors until they follow the emailed link
+	EmailVerified bool `json:"email_verified" db:"email_verified"`
+	// InvestorID links an investor user to their investor record
+	InvestorID *int `json:"investor_id,omitempty" db:"investor_id"`
+	FailedLoginAttempts int        `json:"-" db:"failed_login_attempts"`
+	LockedUntil         *time.Time `json:"locked_until,omitempty" db:"locked_until"`
+	// TOTPSecret is set from enrolment on; TOTPEnabled once the user has confirmed it with a code
+	TOTPSecret       string `json:"-" db:"totp_secret"`
+	TOTPEnabled      bool   `json:"totp_enabled" db:"totp_enabled"`
+	TOTPLastUsedStep int64  `json:"-" db:"totp_last_used_step"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	// DeletedAt is set once the user is deleted; deleted users are only kept for the audit trail
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// UserFilter narrows a listing of users; its zero value matches every user that is not deleted
+type UserFilter struct {
+	// Search matches part of the email, name or user ID, ignoring case
+	Search         string
+	UserType       string
+	IsActive       *bool
+	IncludeDeleted bool
 }
\ No newline at end of file
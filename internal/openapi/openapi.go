@@ -0,0 +1,227 @@
+// Package openapi describes the API as an OpenAPI 3.1 document, builds the schemas in it
+// from the Go types requests and responses are encoded from, and checks requests and
+// responses against it.
+package openapi
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Version is the OpenAPI version documents are written in
+const Version = "3.1.0"
+
+// Document is an OpenAPI document. Paths are keyed by their full path template, such as
+// /api/v1/loans/{id}.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of a path keyed by their lower-case method, such as "get"
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []SecurityRequirement `json:"security,omitempty"`
+}
+
+// Parameter is a path, query or header parameter. In is "path", "query" or "header".
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+	Content     map[string]*MediaType `json:"content"`
+}
+
+// Response is a response of an operation. Operations key them by status code, or by
+// "default" for every status they do not list.
+type Response struct {
+	Description string                `json:"description"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// SecurityRequirement names the security schemes that together authenticate an operation,
+// each with the permissions the operation needs
+type SecurityRequirement map[string][]string
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+// Schema is the subset of JSON Schema 2020-12 the API's documents use
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+}
+
+// Types are the JSON types a schema allows. A single type is written as a string and
+// several as an array, such as ["string", "null"] for a nullable string.
+type Types []string
+
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// New starts an empty document
+func New(info Info) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas:         make(map[string]*Schema),
+			SecuritySchemes: make(map[string]*SecurityScheme),
+		},
+	}
+}
+
+// AddOperation adds operation under method and path, replacing any operation already there
+func (d *Document) AddOperation(method, path string, operation *Operation) {
+	item, ok := d.Paths[path]
+	if !ok {
+		item = make(PathItem)
+		d.Paths[path] = item
+	}
+	item[strings.ToLower(method)] = operation
+}
+
+// Operation returns the operation under method and the path template path
+func (d *Document) Operation(method, path string) (*Operation, bool) {
+	item, ok := d.Paths[path]
+	if !ok {
+		return nil, false
+	}
+	operation, ok := item[strings.ToLower(method)]
+	return operation, ok
+}
+
+// Find returns the operation a request for method and the concrete path path is handled
+// by, and the values of the path's parameters. Where several templates match, the one whose
+// first parameter comes latest wins, as with the router, so /loans/state/{state} beats
+// /loans/{id}/schedule.
+func (d *Document) Find(method, path string) (*Operation, map[string]string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	var (
+		found  *Operation
+		params map[string]string
+		best   string
+	)
+	for template, item := range d.Paths {
+		operation, ok := item[strings.ToLower(method)]
+		if !ok {
+			continue
+		}
+		values, rank, ok := matchTemplate(strings.Split(strings.Trim(template, "/"), "/"), segments)
+		if ok && rank > best {
+			found, params, best = operation, values, rank
+		}
+	}
+
+	return found, params, found != nil
+}
+
+// matchTemplate matches the segments of a path against those of a template, returning the
+// values of the template's parameters and a rank that orders the matching templates: one
+// character per segment, 1 for a literal and 0 for a parameter
+func matchTemplate(template, segments []string) (map[string]string, string, bool) {
+	if len(template) != len(segments) {
+		return nil, "", false
+	}
+
+	values := make(map[string]string)
+	var rank strings.Builder
+	for i, part := range template {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			if segments[i] == "" {
+				return nil, "", false
+			}
+			values[strings.Trim(part, "{}")] = segments[i]
+			rank.WriteByte('0')
+			continue
+		}
+		if part != segments[i] {
+			return nil, "", false
+		}
+		rank.WriteByte('1')
+	}
+
+	return values, rank.String(), true
+}
+
+// Routes lists every operation as its method and path template, such as
+// "GET /api/v1/loans/{id}", sorted
+func (d *Document) Routes() []string {
+	var routes []string
+	for path, item := range d.Paths {
+		for method := range item {
+			routes = append(routes, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(routes)
+	return routes
+}
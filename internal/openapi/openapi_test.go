@@ -0,0 +1,219 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type base struct {
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type account struct {
+	base
+	Name     string      `json:"name"`
+	Balance  money.Money `json:"balance"`
+	Nickname *string     `json:"nickname"`
+	Tags     []string    `json:"tags,omitempty"`
+	Parent   *account    `json:"parent,omitempty"`
+	internal string
+	Ignored  string `json:"-"`
+}
+
+type page[T any] struct {
+	Items []T `json:"items"`
+}
+
+func operationDocument() *Document {
+	d := New(Info{Title: "Test", Version: "1"})
+	for _, path := range []string{"/loans/{id}", "/loans/state/{state}", "/loans/{id}/schedule", "/loans/{id}/{part}"} {
+		d.AddOperation("GET", path, &Operation{OperationID: path})
+	}
+	return d
+}
+
+func TestFind(t *testing.T) {
+	d := operationDocument()
+
+	tests := []struct {
+		path      string
+		template  string
+		params    map[string]string
+		wantFound bool
+	}{
+		{"/loans/1", "/loans/{id}", map[string]string{"id": "1"}, true},
+		{"/loans/state/approved", "/loans/state/{state}", map[string]string{"state": "approved"}, true},
+		{"/loans/1/schedule", "/loans/{id}/schedule", map[string]string{"id": "1"}, true},
+		{"/loans/1/journal", "/loans/{id}/{part}", map[string]string{"id": "1", "part": "journal"}, true},
+		{"/loans", "", nil, false},
+		{"/loans/1/schedule/2", "", nil, false},
+	}
+
+	for _, tt := range tests {
+		operation, params, ok := d.Find("GET", tt.path)
+		require.Equal(t, tt.wantFound, ok, tt.path)
+		if ok {
+			assert.Equal(t, tt.template, operation.OperationID, tt.path)
+			assert.Equal(t, tt.params, params, tt.path)
+		}
+	}
+
+	_, _, ok := d.Find("POST", "/loans/1")
+	assert.False(t, ok)
+}
+
+func TestRoutes(t *testing.T) {
+	assert.Equal(t, []string{
+		"GET /loans/state/{state}",
+		"GET /loans/{id}",
+		"GET /loans/{id}/schedule",
+		"GET /loans/{id}/{part}",
+	}, operationDocument().Routes())
+}
+
+func TestResponseSchema(t *testing.T) {
+	d := New(Info{})
+
+	assert.Equal(t, Ref("Account"), d.ResponseSchema(&account{}))
+
+	schema := d.Components.Schemas["Account"]
+	require.NotNil(t, schema)
+	assert.ElementsMatch(t, []string{"id", "created_at", "name", "balance", "nickname"}, schema.Required)
+	assert.Len(t, schema.Properties, 7)
+	assert.Equal(t, &Schema{Type: Types{"string"}, Format: "date-time"}, schema.Properties["created_at"])
+	assert.Equal(t, Ref("Money"), schema.Properties["balance"])
+	assert.Equal(t, Types{"string", "null"}, schema.Properties["nickname"].Type)
+	assert.Equal(t, Types{"array"}, schema.Properties["tags"].Type, "omitempty fields are left out rather than null")
+	assert.Equal(t, Ref("Account"), schema.Properties["parent"])
+	assert.Contains(t, d.Components.Schemas, "Money")
+
+	assert.Equal(t, Ref("AccountPage"), d.ResponseSchema(page[*account]{}))
+	assert.Equal(t, Types{"array", "null"}, d.Components.Schemas["AccountPage"].Properties["items"].Type)
+	assert.Equal(t, Ref("Account"), d.Components.Schemas["AccountPage"].Properties["items"].Items)
+}
+
+func TestRequestSchema(t *testing.T) {
+	d := New(Info{})
+
+	schema := d.RequestSchema(struct {
+		Name  string  `json:"name"`
+		Limit *int    `json:"limit"`
+		Rate  float64 `json:"rate,omitempty"`
+	}{})
+
+	assert.Empty(t, schema.Required)
+	assert.Equal(t, Types{"integer", "null"}, schema.Properties["limit"].Type)
+	assert.Equal(t, Types{"number"}, schema.Properties["rate"].Type)
+}
+
+func TestTypesMarshalJSON(t *testing.T) {
+	single, err := json.Marshal(Types{"string"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `"string"`, string(single))
+
+	several, err := json.Marshal(Types{"string", "null"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `["string", "null"]`, string(several))
+}
+
+func TestValidate(t *testing.T) {
+	d := New(Info{})
+	d.ResponseSchema(account{})
+	schema := Ref("Account")
+
+	decode := func(body string) interface{} {
+		var value interface{}
+		decoder := json.NewDecoder(strings.NewReader(body))
+		decoder.UseNumber()
+		require.NoError(t, decoder.Decode(&value))
+		return value
+	}
+
+	assert.Empty(t, d.Validate(schema, decode(`{"id": 1, "created_at": "2024-01-01T00:00:00Z", "name": "a", "balance": "10.00", "nickname": null}`)))
+	assert.Empty(t, d.Validate(schema, decode(`{"id": 1, "created_at": "2024-01-01T00:00:00Z", "name": "a", "balance": 10, "nickname": "b"}`)))
+	assert.Equal(t, []apperr.FieldError{{Pointer: "/parent", Code: CodeInvalidType, Message: "must be an object"}},
+		d.Validate(schema, decode(`{"id": 1, "created_at": "2024-01-01T00:00:00Z", "name": "a", "balance": "1", "nickname": null, "parent": null}`)),
+		"an omitempty pointer is left out rather than null")
+
+	assert.Equal(t, []apperr.FieldError{
+		{Pointer: "/id", Code: CodeRequired, Message: "is required"},
+		{Pointer: "/balance", Code: CodeInvalidFormat, Message: "must match " + decimalPattern},
+		{Pointer: "/created_at", Code: CodeInvalidFormat, Message: "must be an RFC 3339 timestamp"},
+		{Pointer: "/name", Code: CodeInvalidType, Message: "must be a string"},
+		{Pointer: "/nickname", Code: CodeInvalidType, Message: "must be a string or null"},
+		{Pointer: "/tags/1", Code: CodeInvalidType, Message: "must be a string"},
+	}, d.Validate(schema, decode(`{"created_at": "yesterday", "name": 1, "balance": "ten", "nickname": 2, "tags": ["a", 3]}`)))
+
+	maximum := 10.0
+	maxLength := 3
+	assert.Equal(t, []apperr.FieldError{{Pointer: "", Code: CodeOutOfRange, Message: "must be at most 10"}},
+		d.Validate(&Schema{Type: Types{"integer"}, Maximum: &maximum}, json.Number("11")))
+	assert.Equal(t, []apperr.FieldError{{Pointer: "", Code: CodeInvalidType, Message: "must be an integer"}},
+		d.Validate(&Schema{Type: Types{"integer"}}, json.Number("1.5")))
+	assert.Equal(t, []apperr.FieldError{{Pointer: "", Code: CodeTooLong, Message: "must be at most 3 characters long"}},
+		d.Validate(&Schema{Type: Types{"string"}, MaxLength: &maxLength}, "abcd"))
+	assert.Equal(t, []apperr.FieldError{{Pointer: "", Code: CodeInvalidChoice, Message: "must be one of a, b"}},
+		d.Validate(&Schema{Type: Types{"string"}, Enum: []interface{}{"a", "b"}}, "c"))
+}
+
+func TestValidateParameters(t *testing.T) {
+	d := New(Info{})
+	minimum := 1.0
+	operation := &Operation{Parameters: []*Parameter{
+		{Name: "id", In: "path", Required: true, Schema: &Schema{Type: Types{"integer"}}},
+		{Name: "limit", In: "query", Schema: &Schema{Type: Types{"integer"}, Minimum: &minimum}},
+		{Name: "active", In: "query", Schema: &Schema{Type: Types{"boolean"}}},
+		{Name: "Idempotency-Key", In: "header", Schema: &Schema{Type: Types{"string"}}},
+	}}
+
+	assert.NoError(t, d.ValidateParameters(operation, map[string]string{"id": "1"}, url.Values{"limit": {"5"}, "active": {"true"}}))
+	assert.NoError(t, d.ValidateParameters(operation, map[string]string{"id": "1"}, url.Values{"limit": {""}}))
+	assert.EqualError(t, d.ValidateParameters(operation, nil, nil), "id is required")
+	assert.EqualError(t, d.ValidateParameters(operation, map[string]string{"id": "abc"}, nil), "id must be an integer")
+	assert.EqualError(t, d.ValidateParameters(operation, map[string]string{"id": "1"}, url.Values{"limit": {"0"}}), "limit must be at least 1")
+	assert.EqualError(t, d.ValidateParameters(operation, map[string]string{"id": "1"}, url.Values{"active": {"maybe"}}), "active must be a boolean")
+}
+
+func TestValidateRequestBody(t *testing.T) {
+	d := New(Info{})
+	operation := &Operation{RequestBody: &RequestBody{
+		Required: true,
+		Content:  map[string]*MediaType{"application/json": {Schema: &Schema{Type: Types{"object"}, Required: []string{"name"}}}},
+	}}
+
+	assert.NoError(t, d.ValidateRequestBody(operation, "application/json; charset=utf-8", []byte(`{"name": "a"}`)))
+	assert.NoError(t, d.ValidateRequestBody(operation, "", []byte(`{"name": "a"}`)))
+	assert.ErrorIs(t, d.ValidateRequestBody(operation, "application/json", nil), ErrNoBody)
+	assert.Error(t, d.ValidateRequestBody(operation, "application/json", []byte(`{`)))
+	assert.Error(t, d.ValidateRequestBody(operation, "text/plain", []byte(`{"name": "a"}`)))
+
+	err := d.ValidateRequestBody(operation, "application/json", []byte(`{}`))
+	domainErr := apperr.From(err)
+	assert.Equal(t, apperr.KindValidation, domainErr.Kind)
+	assert.Equal(t, []apperr.FieldError{{Pointer: "/name", Code: CodeRequired, Message: "is required"}}, domainErr.Fields)
+}
+
+func TestValidateResponse(t *testing.T) {
+	d := New(Info{})
+	operation := &Operation{Responses: map[string]*Response{
+		"200":     {Description: "OK", Content: map[string]*MediaType{"application/json": {Schema: &Schema{Type: Types{"object"}}}}},
+		"204":     {Description: "No Content"},
+		"default": {Description: "Error", Content: map[string]*MediaType{"application/problem+json": {Schema: &Schema{Type: Types{"object"}}}}},
+	}}
+
+	assert.NoError(t, d.ValidateResponse(operation, 200, "application/json", []byte(`{}`)))
+	assert.NoError(t, d.ValidateResponse(operation, 204, "", nil))
+	assert.NoError(t, d.ValidateResponse(operation, 404, "application/problem+json", []byte(`{}`)))
+	assert.Error(t, d.ValidateResponse(operation, 200, "application/json", []byte(`[]`)))
+	assert.Error(t, d.ValidateResponse(operation, 200, "text/plain", []byte(`{}`)))
+	assert.Error(t, d.ValidateResponse(operation, 204, "", []byte(`{}`)))
+}
@@ -0,0 +1,249 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	moneyType      = reflect.TypeOf(money.Money{})
+	rateType       = reflect.TypeOf(money.Rate(0))
+	bytesType      = reflect.TypeOf([]byte(nil))
+	rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+	marshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// decimalPattern is what money amounts and rates look like in JSON
+const decimalPattern = `^-?[0-9]+(\.[0-9]+)?$`
+
+// RequestSchema returns the schema of the request bodies decoded into v's type. No field is
+// required, as handlers check the values of a request themselves and report every invalid
+// field at once, and fields that are pointers, slices or maps may be null.
+func (d *Document) RequestSchema(v interface{}) *Schema {
+	return d.reflect(reflect.TypeOf(v), false)
+}
+
+// ResponseSchema returns the schema of v's type as responses encode it. Fields without
+// omitempty are required, and those that are pointers, slices or maps may be null.
+//
+// Named structs become components named after their type, such as Loan for models.Loan
+// and LoanPage for models.Page[*models.Loan], and are referred to. A type keeps the schema
+// it was first added with, so a type should not be both a request and a response.
+func (d *Document) ResponseSchema(v interface{}) *Schema {
+	return d.reflect(reflect.TypeOf(v), true)
+}
+
+// Ref refers to the component schema name
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (d *Document) reflect(t reflect.Type, response bool) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	if t.Kind() == reflect.Pointer {
+		return d.reflect(t.Elem(), response)
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: Types{"string"}, Format: "date-time"}
+	case moneyType:
+		return d.define("Money", &Schema{
+			Type:        Types{"string", "number"},
+			Pattern:     decimalPattern,
+			Description: `An amount of money, such as "1500000.00". Responses write it as a string; requests may also send a number.`,
+		})
+	case rateType:
+		return d.define("Rate", &Schema{
+			Type:        Types{"string", "number"},
+			Pattern:     decimalPattern,
+			Description: `A fraction, such as "0.1250" for 12.5%. Responses write it as a string; requests may also send a number.`,
+		})
+	case bytesType:
+		return &Schema{Type: Types{"string"}, Format: "byte"}
+	case rawMessageType:
+		return &Schema{}
+	}
+	// Other types that encode themselves could be anything
+	if t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) {
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t, response)
+		}
+		name := componentName(t)
+		if _, ok := d.Components.Schemas[name]; !ok {
+			// Added before it is filled in, so a type that refers to itself ends
+			schema := &Schema{}
+			d.Components.Schemas[name] = schema
+			*schema = *d.structSchema(t, response)
+		}
+		return Ref(name)
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: Types{"array"}, Items: d.reflect(t.Elem(), response)}
+	case reflect.Map:
+		return &Schema{Type: Types{"object"}, AdditionalProperties: d.reflect(t.Elem(), response)}
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: Types{"integer"}}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		minimum := 0.0
+		return &Schema{Type: Types{"integer"}, Minimum: &minimum}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	default:
+		return &Schema{}
+	}
+}
+
+// define adds the component schema name unless it is there already, and refers to it
+func (d *Document) define(name string, schema *Schema) *Schema {
+	if _, ok := d.Components.Schemas[name]; !ok {
+		d.Components.Schemas[name] = schema
+	}
+	return Ref(name)
+}
+
+func (d *Document) structSchema(t reflect.Type, response bool) *Schema {
+	schema := &Schema{Type: Types{"object"}, Properties: make(map[string]*Schema)}
+	d.addFields(schema, t, response, response)
+	return schema
+}
+
+// addFields adds the fields of t to schema the way encoding/json encodes them: fields of
+// embedded structs are promoted unless a field of t has the same name, and those of an
+// embedded pointer are left out while it is nil, so they are never required.
+func (d *Document) addFields(schema *Schema, t reflect.Type, response, required bool) {
+	var embedded []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitempty, ok := jsonField(field)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			embedded = append(embedded, field)
+			continue
+		}
+
+		fieldSchema := d.reflect(field.Type, response)
+		// Requests may send null for a field that can hold nil; responses write null only
+		// for fields without omitempty
+		if mayBeNull(field.Type) && (!response || !omitempty) {
+			fieldSchema = nullable(fieldSchema)
+		}
+		schema.Properties[name] = fieldSchema
+		if required && !omitempty {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	for _, field := range embedded {
+		fieldType := field.Type
+		byPointer := fieldType.Kind() == reflect.Pointer
+		if byPointer {
+			fieldType = fieldType.Elem()
+		}
+
+		promoted := &Schema{Properties: make(map[string]*Schema)}
+		d.addFields(promoted, fieldType, response, required && !byPointer)
+		for name, fieldSchema := range promoted.Properties {
+			if _, ok := schema.Properties[name]; !ok {
+				schema.Properties[name] = fieldSchema
+			}
+		}
+		for _, name := range promoted.Required {
+			if !slices.Contains(schema.Required, name) {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+}
+
+// jsonField reads how encoding/json names field. The name is empty for an embedded struct
+// whose fields are promoted, and ok is false for fields that are not encoded.
+func jsonField(field reflect.StructField) (name string, omitempty, ok bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	for _, option := range strings.Split(options, ",") {
+		omitempty = omitempty || option == "omitempty" || option == "omitzero"
+	}
+
+	if field.Anonymous && name == "" {
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct {
+			return "", omitempty, true
+		}
+	}
+	if !field.IsExported() {
+		return "", false, false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, omitempty, true
+}
+
+// mayBeNull reports whether encoding/json writes null for a nil value of t
+func mayBeNull(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// nullable allows schema to be null as well
+func nullable(schema *Schema) *Schema {
+	switch {
+	case schema.Ref != "":
+		return &Schema{AnyOf: []*Schema{schema, {Type: Types{"null"}}}}
+	case len(schema.Type) > 0:
+		copied := *schema
+		copied.Type = append(append(Types{}, schema.Type...), "null")
+		return &copied
+	default:
+		return schema
+	}
+}
+
+// componentName names the component of a struct type after the type. A generic type is
+// named after its type arguments too, so models.Page[*models.Loan] is LoanPage.
+func componentName(t reflect.Type) string {
+	base, arguments, generic := strings.Cut(t.Name(), "[")
+	if !generic {
+		return capitalize(base)
+	}
+
+	var name string
+	for _, argument := range strings.Split(strings.TrimSuffix(arguments, "]"), ",") {
+		argument = strings.TrimLeft(argument, "*[]")
+		name += capitalize(argument[strings.LastIndex(argument, ".")+1:])
+	}
+	return name + capitalize(base)
+}
+
+func capitalize(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
@@ -0,0 +1,382 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+)
+
+// Codes of the field errors validation reports; apart from invalid_type they are the codes
+// handlers report for invalid fields
+const (
+	CodeRequired      = "required"
+	CodeInvalidType   = "invalid_type"
+	CodeInvalidFormat = "invalid_format"
+	CodeOutOfRange    = "out_of_range"
+	CodeInvalidChoice = "invalid_choice"
+	CodeTooLong       = "too_long"
+)
+
+// ErrNoBody is returned for a request without the body its operation requires
+var ErrNoBody = errors.New("request body is required")
+
+// Validate checks value, as decoded by a json.Decoder with UseNumber, against schema and
+// returns every place where it does not match, each with a JSON Pointer to it
+func (d *Document) Validate(schema *Schema, value interface{}) []apperr.FieldError {
+	v := validator{doc: d}
+	v.validate(schema, value, "")
+	return v.fields
+}
+
+// ValidateParameters checks the path and query parameters of a request for operation. It
+// returns an error naming the first parameter that is missing or malformed. Like handlers,
+// it takes an empty parameter to be left out.
+func (d *Document) ValidateParameters(operation *Operation, pathParams map[string]string, query url.Values) error {
+	for _, param := range operation.Parameters {
+		var raw string
+		switch param.In {
+		case "path":
+			raw = pathParams[param.Name]
+		case "query":
+			raw = query.Get(param.Name)
+		default:
+			continue
+		}
+
+		if raw == "" {
+			if param.Required {
+				return fmt.Errorf("%s is required", param.Name)
+			}
+			continue
+		}
+
+		schema := d.resolve(param.Schema)
+		value, ok := parameterValue(schema, raw)
+		if !ok {
+			return fmt.Errorf("%s must be %s", param.Name, describeTypes(schema.Type))
+		}
+		if fields := d.Validate(schema, value); len(fields) > 0 {
+			return fmt.Errorf("%s %s", param.Name, fields[0].Message)
+		}
+	}
+	return nil
+}
+
+// parameterValue reads the raw value of a parameter as the JSON value its schema describes
+func parameterValue(schema *Schema, raw string) (interface{}, bool) {
+	switch {
+	case len(schema.Type) == 0 || slices.Contains(schema.Type, "string"):
+		return raw, true
+	case slices.Contains(schema.Type, "integer"), slices.Contains(schema.Type, "number"):
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return nil, false
+		}
+		return json.Number(raw), true
+	case slices.Contains(schema.Type, "boolean"):
+		b, err := strconv.ParseBool(raw)
+		return b, err == nil
+	default:
+		return raw, true
+	}
+}
+
+// ValidateRequestBody checks the body of a request for operation. A body that does not
+// match the schema fails with a validation error listing every invalid field; any other
+// error means the body could not be read at all.
+func (d *Document) ValidateRequestBody(operation *Operation, contentType string, body []byte) error {
+	if operation.RequestBody == nil {
+		return nil
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		if operation.RequestBody.Required {
+			return ErrNoBody
+		}
+		return nil
+	}
+
+	// Handlers read bodies as JSON whatever they are labelled
+	mediaType := "application/json"
+	if contentType != "" {
+		var err error
+		if mediaType, _, err = mime.ParseMediaType(contentType); err != nil {
+			return fmt.Errorf("invalid content type: %w", err)
+		}
+	}
+	media, ok := operation.RequestBody.Content[mediaType]
+	if !ok {
+		return fmt.Errorf("content type %s is not accepted", mediaType)
+	}
+
+	return d.validateBody(media, mediaType, body)
+}
+
+// ValidateResponse checks a response to operation: its status must be documented, its
+// content type one the response lists, and a JSON body must match the schema
+func (d *Document) ValidateResponse(operation *Operation, status int, contentType string, body []byte) error {
+	response, ok := operation.Responses[strconv.Itoa(status)]
+	if !ok {
+		response, ok = operation.Responses["default"]
+	}
+	if !ok {
+		return fmt.Errorf("status %d is not documented", status)
+	}
+
+	if len(response.Content) == 0 {
+		if len(body) > 0 {
+			return fmt.Errorf("status %d is documented without a body", status)
+		}
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("invalid content type %q: %w", contentType, err)
+	}
+	media, ok := response.Content[mediaType]
+	if !ok {
+		return fmt.Errorf("content type %s is not documented for status %d", mediaType, status)
+	}
+
+	if err := d.validateBody(media, mediaType, body); err != nil {
+		return fmt.Errorf("status %d: %w", status, err)
+	}
+	return nil
+}
+
+func (d *Document) validateBody(media *MediaType, mediaType string, body []byte) error {
+	if media.Schema == nil || !isJSON(mediaType) {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if fields := d.Validate(media.Schema, value); len(fields) > 0 {
+		return apperr.InvalidFields(fields)
+	}
+	return nil
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// resolve follows schema's reference to the component it names
+func (d *Document) resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = d.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	if schema == nil {
+		return &Schema{}
+	}
+	return schema
+}
+
+type validator struct {
+	doc    *Document
+	fields []apperr.FieldError
+}
+
+func (v *validator) fail(pointer, code, message string) {
+	v.fields = append(v.fields, apperr.FieldError{Pointer: pointer, Code: code, Message: message})
+}
+
+func (v *validator) validate(schema *Schema, value interface{}, pointer string) {
+	schema = v.doc.resolve(schema)
+
+	if len(schema.AnyOf) > 0 {
+		var first []apperr.FieldError
+		for i, alternative := range schema.AnyOf {
+			fields := v.doc.Validate(alternative, value)
+			if len(fields) == 0 {
+				return
+			}
+			if i == 0 {
+				first = fields
+			}
+		}
+		// Report why the value is not the first alternative, which is the one that is
+		// not null for a nullable schema
+		for _, field := range first {
+			v.fail(pointer+field.Pointer, field.Code, field.Message)
+		}
+		return
+	}
+
+	if len(schema.Type) > 0 && !matchesType(schema.Type, value) {
+		v.fail(pointer, CodeInvalidType, "must be "+describeTypes(schema.Type))
+		return
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		choices := make([]string, len(schema.Enum))
+		for i, choice := range schema.Enum {
+			choices[i] = fmt.Sprint(choice)
+		}
+		v.fail(pointer, CodeInvalidChoice, "must be one of "+strings.Join(choices, ", "))
+		return
+	}
+
+	switch value := value.(type) {
+	case string:
+		v.validateString(schema, value, pointer)
+	case json.Number:
+		v.validateNumber(schema, value, pointer)
+	case map[string]interface{}:
+		v.validateObject(schema, value, pointer)
+	case []interface{}:
+		for i, item := range value {
+			if schema.Items != nil {
+				v.validate(schema.Items, item, pointer+"/"+strconv.Itoa(i))
+			}
+		}
+	}
+}
+
+func (v *validator) validateString(schema *Schema, value, pointer string) {
+	if schema.MaxLength != nil && utf8.RuneCountInString(value) > *schema.MaxLength {
+		v.fail(pointer, CodeTooLong, fmt.Sprintf("must be at most %d characters long", *schema.MaxLength))
+	}
+	if schema.Pattern != "" && !compilePattern(schema.Pattern).MatchString(value) {
+		v.fail(pointer, CodeInvalidFormat, "must match "+schema.Pattern)
+	}
+	if schema.Format == "date-time" {
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			v.fail(pointer, CodeInvalidFormat, "must be an RFC 3339 timestamp")
+		}
+	}
+}
+
+func (v *validator) validateNumber(schema *Schema, value json.Number, pointer string) {
+	n, err := value.Float64()
+	if err != nil {
+		return
+	}
+	if schema.Minimum != nil && n < *schema.Minimum {
+		v.fail(pointer, CodeOutOfRange, fmt.Sprintf("must be at least %v", *schema.Minimum))
+	}
+	if schema.Maximum != nil && n > *schema.Maximum {
+		v.fail(pointer, CodeOutOfRange, fmt.Sprintf("must be at most %v", *schema.Maximum))
+	}
+}
+
+func (v *validator) validateObject(schema *Schema, value map[string]interface{}, pointer string) {
+	for _, name := range schema.Required {
+		if _, ok := value[name]; !ok {
+			v.fail(pointer+"/"+escapePointer(name), CodeRequired, "is required")
+		}
+	}
+
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := schema.Properties[name]
+		if !ok {
+			property = schema.AdditionalProperties
+		}
+		if property != nil {
+			v.validate(property, value[name], pointer+"/"+escapePointer(name))
+		}
+	}
+}
+
+func matchesType(types Types, value interface{}) bool {
+	for _, t := range types {
+		switch value := value.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case json.Number:
+			if t == "number" {
+				return true
+			}
+			if t == "integer" {
+				if n, err := value.Float64(); err == nil && n == math.Trunc(n) {
+					return true
+				}
+			}
+		case map[string]interface{}:
+			if t == "object" {
+				return true
+			}
+		case []interface{}:
+			if t == "array" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func describeTypes(types Types) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		switch t {
+		case "null":
+			names[i] = "null"
+		case "integer", "object", "array":
+			names[i] = "an " + t
+		default:
+			names[i] = "a " + t
+		}
+	}
+	return strings.Join(names, " or ")
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, choice := range enum {
+		if value != nil && fmt.Sprint(choice) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// escapePointer escapes name for use as a JSON Pointer token (RFC 6901)
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+var patterns sync.Map
+
+// compilePattern compiles pattern once. Patterns come from the document, not from requests,
+// so one that does not compile is a bug in the document.
+func compilePattern(pattern string) *regexp.Regexp {
+	if compiled, ok := patterns.Load(pattern); ok {
+		return compiled.(*regexp.Regexp)
+	}
+	compiled := regexp.MustCompile(pattern)
+	patterns.Store(pattern, compiled)
+	return compiled
+}
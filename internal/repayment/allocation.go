@@ -0,0 +1,223 @@
+package repayment
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+)
+
+// Installment statuses after payments have been applied
+const (
+	InstallmentStatusPartiallyPaid = "partially_paid"
+	InstallmentStatusPaid          = "paid"
+	InstallmentStatusOverdue       = "overdue"
+)
+
+// Component is one part of an installment that a payment can settle
+type Component string
+
+const (
+	ComponentPenalty   Component = "penalty"
+	ComponentInterest  Component = "interest"
+	ComponentPrincipal Component = "principal"
+)
+
+// Waterfall is the order in which a payment settles the components of each installment
+type Waterfall []Component
+
+// DefaultWaterfall settles late fees first, then interest, then principal
+var DefaultWaterfall = Waterfall{ComponentPenalty, ComponentInterest, ComponentPrincipal}
+
+// ParseWaterfall reads a comma separated order such as "interest,principal,penalty".
+// Every component must appear exactly once.
+func ParseWaterfall(s string) (Waterfall, error) {
+	seen := map[Component]bool{}
+	var waterfall Waterfall
+	for _, part := range strings.Split(s, ",") {
+		component := Component(strings.TrimSpace(part))
+		switch component {
+		case ComponentPenalty, ComponentInterest, ComponentPrincipal:
+		default:
+			return nil, fmt.Errorf("unknown waterfall component %q", component)
+		}
+		if seen[component] {
+			return nil, fmt.Errorf("waterfall component %q appears more than once", component)
+		}
+		seen[component] = true
+		waterfall = append(waterfall, component)
+	}
+
+	if len(waterfall) != len(DefaultWaterfall) {
+		return nil, fmt.Errorf("waterfall must list penalty, interest and principal")
+	}
+
+	return waterfall, nil
+}
+
+// Policy configures how repayments are applied
+type Policy struct {
+	Waterfall Waterfall
+	// LateFeeRate is charged once on the total of an installment when it becomes overdue
+	LateFeeRate money.Rate
+}
+
+// DefaultPolicy uses the default waterfall and a 5% late fee
+func DefaultPolicy() Policy {
+	return Policy{
+		Waterfall:   DefaultWaterfall,
+		LateFeeRate: 500,
+	}
+}
+
+// ParsePolicy reads a policy from a waterfall as ParseWaterfall reads it and a late fee
+// rate such as "0.05". An empty value keeps the default.
+func ParsePolicy(waterfall, lateFeeRate string) (Policy, error) {
+	policy := DefaultPolicy()
+
+	if waterfall != "" {
+		parsed, err := ParseWaterfall(waterfall)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid waterfall: %w", err)
+		}
+		policy.Waterfall = parsed
+	}
+
+	if lateFeeRate != "" {
+		rate, err := money.ParseRate(lateFeeRate)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid late fee rate: %w", err)
+		}
+		if rate < 0 {
+			return Policy{}, fmt.Errorf("invalid late fee rate: %q is negative", lateFeeRate)
+		}
+		policy.LateFeeRate = rate
+	}
+
+	return policy, nil
+}
+
+// Allocation is how a payment was split across a loan's installments
+type Allocation struct {
+	Penalty   money.Money
+	Interest  money.Money
+	Principal money.Money
+	// Excess is the part of the payment left after every installment was settled
+	Excess money.Money
+	// Changed holds the installments whose paid amounts, penalty or status changed
+	Changed []*models.LoanInstallment
+}
+
+// Apply assesses late fees on installments overdue at paidAt and then settles the
+// installments oldest first, each one component at a time in waterfall order. The
+// installments are updated in place.
+func Apply(amount money.Money, installments []*models.LoanInstallment, paidAt time.Time, policy Policy) Allocation {
+	currency := amount.Currency()
+	allocation := Allocation{
+		Penalty:   money.Zero(currency),
+		Interest:  money.Zero(currency),
+		Principal: money.Zero(currency),
+	}
+
+	remaining := amount
+	for _, installment := range installments {
+		before := *installment
+
+		if installment.Status != InstallmentStatusPaid && isOverdue(installment, paidAt) && installment.PenaltyAmount.IsZero() {
+			installment.PenaltyAmount = lateFee(installment.TotalAmount, policy.LateFeeRate)
+		}
+
+		for _, component := range policy.Waterfall {
+			if !remaining.IsPositive() {
+				break
+			}
+
+			due, paid := componentAmounts(installment, component)
+			outstanding := due.Sub(*paid)
+			if !outstanding.IsPositive() {
+				continue
+			}
+
+			settled := outstanding
+			if remaining.LessThan(outstanding) {
+				settled = remaining
+			}
+			*paid = paid.Add(settled)
+			remaining = remaining.Sub(settled)
+
+			switch component {
+			case ComponentPenalty:
+				allocation.Penalty = allocation.Penalty.Add(settled)
+			case ComponentInterest:
+				allocation.Interest = allocation.Interest.Add(settled)
+			case ComponentPrincipal:
+				allocation.Principal = allocation.Principal.Add(settled)
+			}
+		}
+
+		installment.Status = statusOf(installment, paidAt)
+		if installment.Status == InstallmentStatusPaid && installment.PaidAt == nil {
+			settledAt := paidAt
+			installment.PaidAt = &settledAt
+		}
+
+		if changed(&before, installment) {
+			allocation.Changed = append(allocation.Changed, installment)
+		}
+	}
+
+	allocation.Excess = remaining
+	return allocation
+}
+
+// Outstanding returns what is still owed on an installment, including assessed late fees
+func Outstanding(installment *models.LoanInstallment) money.Money {
+	owed := installment.TotalAmount.Add(installment.PenaltyAmount)
+	paid := installment.PrincipalPaid.Add(installment.InterestPaid).Add(installment.PenaltyPaid)
+	return owed.Sub(paid)
+}
+
+func componentAmounts(installment *models.LoanInstallment, component Component) (due money.Money, paid *money.Money) {
+	switch component {
+	case ComponentPenalty:
+		return installment.PenaltyAmount, &installment.PenaltyPaid
+	case ComponentInterest:
+		return installment.InterestAmount, &installment.InterestPaid
+	default:
+		return installment.PrincipalAmount, &installment.PrincipalPaid
+	}
+}
+
+func statusOf(installment *models.LoanInstallment, asOf time.Time) string {
+	switch {
+	case !Outstanding(installment).IsPositive():
+		return InstallmentStatusPaid
+	case isOverdue(installment, asOf):
+		return InstallmentStatusOverdue
+	case installment.PrincipalPaid.IsPositive() || installment.InterestPaid.IsPositive() || installment.PenaltyPaid.IsPositive():
+		return InstallmentStatusPartiallyPaid
+	default:
+		return InstallmentStatusPending
+	}
+}
+
+// isOverdue reports whether the due date has passed; an installment paid on its due date is on time
+func isOverdue(installment *models.LoanInstallment, asOf time.Time) bool {
+	return !asOf.Before(installment.DueDate.AddDate(0, 0, 1))
+}
+
+func lateFee(total money.Money, rate money.Rate) money.Money {
+	fee := roundHalfUp(big.NewRat(total.Amount()*rate.BasisPoints(), int64(money.OneHundredPercent)))
+	return money.New(fee, total.Currency())
+}
+
+func changed(before, after *models.LoanInstallment) bool {
+	return before.Status != after.Status ||
+		!before.PenaltyAmount.Equal(after.PenaltyAmount) ||
+		!before.PenaltyPaid.Equal(after.PenaltyPaid) ||
+		!before.InterestPaid.Equal(after.InterestPaid) ||
+		!before.PrincipalPaid.Equal(after.PrincipalPaid)
+}
@@ -0,0 +1,137 @@
+package repayment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// twoInstallments is 200.00 repaid flat over two months with 10.00 interest each,
+// due on 28 February and 31 March 2025
+func twoInstallments(t *testing.T) []*models.LoanInstallment {
+	installments, err := Generate(Terms{
+		Principal:   money.MustParse("200.00"),
+		AnnualRate:  money.MustParseRate("0.60"),
+		TenorMonths: 2,
+		Method:      MethodFlat,
+		StartDate:   disbursedAt,
+	})
+	require.NoError(t, err)
+	return installments
+}
+
+func TestApplyPaysInstallmentOnTime(t *testing.T) {
+	installments := twoInstallments(t)
+	paidAt := time.Date(2025, time.February, 28, 12, 0, 0, 0, time.UTC)
+
+	allocation := Apply(money.MustParse("110.00"), installments, paidAt, DefaultPolicy())
+
+	assert.Equal(t, money.MustParse("10.00"), allocation.Interest)
+	assert.Equal(t, money.MustParse("100.00"), allocation.Principal)
+	assert.True(t, allocation.Penalty.IsZero())
+	assert.True(t, allocation.Excess.IsZero())
+	assert.Equal(t, InstallmentStatusPaid, installments[0].Status)
+	assert.NotNil(t, installments[0].PaidAt)
+	assert.Equal(t, InstallmentStatusPending, installments[1].Status)
+	assert.Equal(t, []*models.LoanInstallment{installments[0]}, allocation.Changed)
+}
+
+func TestApplyPartialPaymentFollowsWaterfall(t *testing.T) {
+	installments := twoInstallments(t)
+	paidAt := time.Date(2025, time.February, 10, 0, 0, 0, 0, time.UTC)
+
+	allocation := Apply(money.MustParse("15.00"), installments, paidAt, DefaultPolicy())
+
+	// Interest is settled before principal
+	assert.Equal(t, money.MustParse("10.00"), installments[0].InterestPaid)
+	assert.Equal(t, money.MustParse("5.00"), installments[0].PrincipalPaid)
+	assert.Equal(t, money.MustParse("5.00"), allocation.Principal)
+	assert.Equal(t, InstallmentStatusPartiallyPaid, installments[0].Status)
+}
+
+func TestApplyChargesLateFeeFirst(t *testing.T) {
+	installments := twoInstallments(t)
+	paidAt := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+
+	allocation := Apply(money.MustParse("20.00"), installments, paidAt, DefaultPolicy())
+
+	// 5% of the 110.00 installment is 5.50, paid before interest
+	assert.Equal(t, money.MustParse("5.50"), installments[0].PenaltyAmount)
+	assert.Equal(t, money.MustParse("5.50"), allocation.Penalty)
+	assert.Equal(t, money.MustParse("10.00"), allocation.Interest)
+	assert.Equal(t, money.MustParse("4.50"), allocation.Principal)
+	assert.Equal(t, InstallmentStatusOverdue, installments[0].Status)
+
+	// A second payment does not charge the fee again
+	Apply(money.MustParse("1.00"), installments, paidAt.AddDate(0, 0, 1), DefaultPolicy())
+	assert.Equal(t, money.MustParse("5.50"), installments[0].PenaltyAmount)
+}
+
+func TestApplyWithCustomWaterfall(t *testing.T) {
+	installments := twoInstallments(t)
+	paidAt := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+	policy := Policy{
+		Waterfall:   Waterfall{ComponentPrincipal, ComponentInterest, ComponentPenalty},
+		LateFeeRate: DefaultPolicy().LateFeeRate,
+	}
+
+	allocation := Apply(money.MustParse("105.00"), installments, paidAt, policy)
+
+	assert.Equal(t, money.MustParse("100.00"), allocation.Principal)
+	assert.Equal(t, money.MustParse("5.00"), allocation.Interest)
+	assert.True(t, allocation.Penalty.IsZero())
+}
+
+func TestApplyKeepsOverpaymentAsExcess(t *testing.T) {
+	installments := twoInstallments(t)
+	paidAt := time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	allocation := Apply(money.MustParse("250.00"), installments, paidAt, DefaultPolicy())
+
+	assert.Equal(t, money.MustParse("200.00"), allocation.Principal)
+	assert.Equal(t, money.MustParse("20.00"), allocation.Interest)
+	assert.Equal(t, money.MustParse("30.00"), allocation.Excess)
+	for _, installment := range installments {
+		assert.Equal(t, InstallmentStatusPaid, installment.Status)
+		assert.True(t, Outstanding(installment).IsZero())
+	}
+}
+
+func TestParseWaterfall(t *testing.T) {
+	waterfall, err := ParseWaterfall("interest, principal, penalty")
+	require.NoError(t, err)
+	assert.Equal(t, Waterfall{ComponentInterest, ComponentPrincipal, ComponentPenalty}, waterfall)
+
+	_, err = ParseWaterfall("interest,principal")
+	assert.Error(t, err)
+
+	_, err = ParseWaterfall("interest,interest,principal")
+	assert.Error(t, err)
+
+	_, err = ParseWaterfall("interest,principal,fees")
+	assert.Error(t, err)
+}
+
+func TestParsePolicy(t *testing.T) {
+	policy, err := ParsePolicy("", "")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPolicy(), policy)
+
+	policy, err = ParsePolicy("interest,principal,penalty", "0.02")
+	require.NoError(t, err)
+	assert.Equal(t, Waterfall{ComponentInterest, ComponentPrincipal, ComponentPenalty}, policy.Waterfall)
+	assert.Equal(t, money.MustParseRate("0.02"), policy.LateFeeRate)
+
+	_, err = ParsePolicy("interest,principal", "")
+	assert.Error(t, err)
+
+	_, err = ParsePolicy("", "five percent")
+	assert.Error(t, err)
+
+	_, err = ParsePolicy("", "-0.05")
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package repayment
+
+import (
+	"math/big"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+)
+
+// Distribution is how one repayment is shared between the loan's investors and the platform
+type Distribution struct {
+	Payouts []*models.Payout
+	Revenue *models.PlatformRevenue
+}
+
+// Distribute splits the principal and interest settled by a repayment across the loan's
+// investments in proportion to the amount each invested. Investors earn interest at the
+// loan's ROI rather than its Rate, so their interest is capped at ROI/Rate of what was
+// collected; the rest of the interest and all late fees are platform revenue. Both
+// splits use largest remainder rounding so every cent collected is accounted for.
+// Credit left with the borrower is not distributed.
+func Distribute(loan *models.Loan, loanRepayment *models.LoanRepayment, investments []*models.LoanInvestment) (*Distribution, error) {
+	interest := loanRepayment.InterestAmount
+	investorInterest := investorShare(interest, loan.Rate, loan.ROI)
+
+	distribution := &Distribution{
+		Revenue: &models.PlatformRevenue{
+			LoanRepaymentID: loanRepayment.ID,
+			LoanID:          loan.ID,
+			MarginAmount:    interest.Sub(investorInterest),
+			PenaltyAmount:   loanRepayment.PenaltyAmount,
+			Amount:          interest.Sub(investorInterest).Add(loanRepayment.PenaltyAmount),
+		},
+	}
+
+	if loanRepayment.PrincipalAmount.IsZero() && investorInterest.IsZero() {
+		return distribution, nil
+	}
+
+	weights := make([]int64, len(investments))
+	hasWeight := false
+	for i, investment := range investments {
+		weights[i] = investment.InvestmentAmount.Amount()
+		hasWeight = hasWeight || weights[i] > 0
+	}
+	if !hasWeight {
+		return nil, apperr.InvalidState("no_investments", "loan has no investments to distribute the repayment to")
+	}
+
+	principalShares := loanRepayment.PrincipalAmount.Allocate(weights)
+	interestShares := investorInterest.Allocate(weights)
+
+	for i, investment := range investments {
+		distribution.Payouts = append(distribution.Payouts, &models.Payout{
+			LoanRepaymentID:  loanRepayment.ID,
+			LoanID:           loan.ID,
+			LoanInvestmentID: investment.ID,
+			InvestorID:       investment.InvestorID,
+			PrincipalAmount:  principalShares[i],
+			InterestAmount:   interestShares[i],
+			Amount:           principalShares[i].Add(interestShares[i]),
+		})
+	}
+
+	return distribution, nil
+}
+
+// investorShare is the part of the collected interest owed to investors at the ROI,
+// rounded down so investors never receive more than their ROI
+func investorShare(interest money.Money, rate, roi money.Rate) money.Money {
+	if rate <= 0 || roi <= 0 {
+		return money.Zero(interest.Currency())
+	}
+	if roi >= rate {
+		return interest
+	}
+
+	share := new(big.Int).Mul(big.NewInt(interest.Amount()), big.NewInt(roi.BasisPoints()))
+	share.Quo(share, big.NewInt(rate.BasisPoints()))
+	return money.New(share.Int64(), interest.Currency())
+}
@@ -0,0 +1,87 @@
+package repayment
+
+import (
+	"testing"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func threeEqualInvestments() []*models.LoanInvestment {
+	return []*models.LoanInvestment{
+		{ID: 1, InvestorID: 11, InvestmentAmount: money.MustParse("1000.00")},
+		{ID: 2, InvestorID: 12, InvestmentAmount: money.MustParse("1000.00")},
+		{ID: 3, InvestorID: 13, InvestmentAmount: money.MustParse("1000.00")},
+	}
+}
+
+func TestDistributeSplitsProRataWithoutLosingCents(t *testing.T) {
+	loan := &models.Loan{ID: 1, Rate: money.MustParseRate("0.10"), ROI: money.MustParseRate("0.08")}
+	loanRepayment := &models.LoanRepayment{
+		ID:              5,
+		PenaltyAmount:   money.MustParse("1.00"),
+		InterestAmount:  money.MustParse("10.00"),
+		PrincipalAmount: money.MustParse("100.00"),
+	}
+
+	distribution, err := Distribute(loan, loanRepayment, threeEqualInvestments())
+	require.NoError(t, err)
+
+	require.Len(t, distribution.Payouts, 3)
+	assert.Equal(t, money.MustParse("36.01"), distribution.Payouts[0].Amount)
+	assert.Equal(t, money.MustParse("36.00"), distribution.Payouts[1].Amount)
+	assert.Equal(t, money.MustParse("35.99"), distribution.Payouts[2].Amount)
+
+	paidOut := money.Zero(money.DefaultCurrency)
+	for _, payout := range distribution.Payouts {
+		assert.Equal(t, 5, payout.LoanRepaymentID)
+		assert.Equal(t, payout.PrincipalAmount.Add(payout.InterestAmount), payout.Amount)
+		paidOut = paidOut.Add(payout.Amount)
+	}
+	assert.Equal(t, money.MustParse("108.00"), paidOut)
+
+	assert.Equal(t, money.MustParse("2.00"), distribution.Revenue.MarginAmount)
+	assert.Equal(t, money.MustParse("1.00"), distribution.Revenue.PenaltyAmount)
+	assert.Equal(t, money.MustParse("3.00"), distribution.Revenue.Amount)
+}
+
+func TestDistributeRoundsInvestorInterestDown(t *testing.T) {
+	loan := &models.Loan{ID: 1, Rate: money.MustParseRate("0.12"), ROI: money.MustParseRate("0.10")}
+	loanRepayment := &models.LoanRepayment{InterestAmount: money.MustParse("0.10")}
+
+	distribution, err := Distribute(loan, loanRepayment, threeEqualInvestments())
+	require.NoError(t, err)
+
+	// 0.10 * 10/12 = 0.0833..., so investors get 0.08 and the platform 0.02
+	assert.Equal(t, money.MustParse("0.02"), distribution.Revenue.MarginAmount)
+	assert.Equal(t, money.MustParse("0.03"), distribution.Payouts[0].InterestAmount)
+	assert.Equal(t, money.MustParse("0.03"), distribution.Payouts[1].InterestAmount)
+	assert.Equal(t, money.MustParse("0.02"), distribution.Payouts[2].InterestAmount)
+}
+
+func TestDistributeNeverPaysMoreThanCollected(t *testing.T) {
+	loan := &models.Loan{ID: 1, Rate: money.MustParseRate("0.05"), ROI: money.MustParseRate("0.08")}
+	loanRepayment := &models.LoanRepayment{InterestAmount: money.MustParse("9.00")}
+
+	distribution, err := Distribute(loan, loanRepayment, threeEqualInvestments())
+	require.NoError(t, err)
+
+	assert.True(t, distribution.Revenue.MarginAmount.IsZero())
+	assert.Equal(t, money.MustParse("3.00"), distribution.Payouts[0].InterestAmount)
+}
+
+func TestDistributeRequiresInvestments(t *testing.T) {
+	loan := &models.Loan{ID: 1, Rate: money.MustParseRate("0.10"), ROI: money.MustParseRate("0.08")}
+	loanRepayment := &models.LoanRepayment{PrincipalAmount: money.MustParse("100.00")}
+
+	_, err := Distribute(loan, loanRepayment, nil)
+	assert.Error(t, err)
+
+	// A repayment that only settles late fees has nothing for investors
+	distribution, err := Distribute(loan, &models.LoanRepayment{PenaltyAmount: money.MustParse("5.00")}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, distribution.Payouts)
+	assert.Equal(t, money.MustParse("5.00"), distribution.Revenue.Amount)
+}
@@ -0,0 +1,194 @@
+// Package repayment builds the installment schedule a borrower repays once a loan is disbursed.
+package repayment
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+)
+
+// Method decides how interest accrues and how principal is spread over the tenor
+type Method string
+
+const (
+	// MethodFlat charges interest on the original principal and spreads principal and
+	// interest evenly over every installment
+	MethodFlat Method = "flat"
+	// MethodAnnuity charges effective interest on the outstanding balance with equal
+	// monthly payments
+	MethodAnnuity Method = "annuity"
+	// MethodBullet charges interest every month and repays the whole principal with
+	// the last installment
+	MethodBullet Method = "bullet"
+)
+
+const (
+	DefaultMethod      = MethodFlat
+	DefaultTenorMonths = 12
+	MaxTenorMonths     = 360
+)
+
+const monthsPerYear = 12
+
+// InstallmentStatusPending is the status of an installment that has not been paid yet
+const InstallmentStatusPending = "pending"
+
+// Valid reports whether m is a supported repayment method
+func (m Method) Valid() bool {
+	switch m {
+	case MethodFlat, MethodAnnuity, MethodBullet:
+		return true
+	default:
+		return false
+	}
+}
+
+// Terms are the loan attributes a schedule is generated from
+type Terms struct {
+	Principal   money.Money
+	AnnualRate  money.Rate
+	TenorMonths int
+	Method      Method
+	// StartDate is the disbursement date; the first installment falls due one month later
+	StartDate time.Time
+}
+
+// Generate returns one installment per month of the tenor. Amounts are rounded half up
+// to the minor unit and the last installment absorbs any rounding difference, so the
+// principal portions always sum to the principal and every installment total is its
+// principal plus its interest.
+func Generate(terms Terms) ([]*models.LoanInstallment, error) {
+	if !terms.Principal.IsPositive() {
+		return nil, apperr.Validation("invalid_repayment_terms", "principal must be greater than 0")
+	}
+	if terms.AnnualRate < 0 {
+		return nil, apperr.Validation("invalid_repayment_terms", "rate must not be negative")
+	}
+	if terms.TenorMonths < 1 || terms.TenorMonths > MaxTenorMonths {
+		return nil, apperr.Validation("invalid_repayment_terms", "tenor must be between 1 and %d months", MaxTenorMonths)
+	}
+
+	var principal, interest []int64
+	switch terms.Method {
+	case MethodFlat:
+		principal, interest = flat(terms)
+	case MethodAnnuity:
+		principal, interest = annuity(terms)
+	case MethodBullet:
+		principal, interest = bullet(terms)
+	default:
+		return nil, apperr.Validation("invalid_repayment_terms", "unsupported repayment method %q", terms.Method)
+	}
+
+	currency := terms.Principal.Currency()
+	installments := make([]*models.LoanInstallment, terms.TenorMonths)
+	for i := range installments {
+		installments[i] = &models.LoanInstallment{
+			InstallmentNumber: i + 1,
+			DueDate:           addMonths(terms.StartDate, i+1),
+			PrincipalAmount:   money.New(principal[i], currency),
+			InterestAmount:    money.New(interest[i], currency),
+			TotalAmount:       money.New(principal[i]+interest[i], currency),
+			Status:            InstallmentStatusPending,
+		}
+	}
+
+	return installments, nil
+}
+
+func flat(terms Terms) (principal, interest []int64) {
+	totalInterest := roundHalfUp(new(big.Rat).Mul(
+		big.NewRat(terms.Principal.Amount(), 1),
+		yearFraction(terms.AnnualRate, terms.TenorMonths),
+	))
+
+	return splitEvenly(terms.Principal.Amount(), terms.TenorMonths), splitEvenly(totalInterest, terms.TenorMonths)
+}
+
+func bullet(terms Terms) (principal, interest []int64) {
+	totalInterest := roundHalfUp(new(big.Rat).Mul(
+		big.NewRat(terms.Principal.Amount(), 1),
+		yearFraction(terms.AnnualRate, terms.TenorMonths),
+	))
+
+	principal = make([]int64, terms.TenorMonths)
+	principal[terms.TenorMonths-1] = terms.Principal.Amount()
+
+	return principal, splitEvenly(totalInterest, terms.TenorMonths)
+}
+
+// annuity uses the standard equal payment P·r / (1 − (1 + r)^−n). Each month's interest
+// is the rounded monthly rate on the remaining balance and the rest of the payment goes
+// to principal; the last installment clears whatever balance is left.
+func annuity(terms Terms) (principal, interest []int64) {
+	n := terms.TenorMonths
+	balance := terms.Principal.Amount()
+	monthlyRate := yearFraction(terms.AnnualRate, 1)
+
+	if monthlyRate.Sign() == 0 {
+		return splitEvenly(balance, n), make([]int64, n)
+	}
+
+	// growth = (1 + r)^n
+	onePlusRate := new(big.Rat).Add(big.NewRat(1, 1), monthlyRate)
+	growth := big.NewRat(1, 1)
+	for i := 0; i < n; i++ {
+		growth.Mul(growth, onePlusRate)
+	}
+
+	// payment = P · r · growth / (growth − 1)
+	payment := new(big.Rat).Mul(big.NewRat(balance, 1), monthlyRate)
+	payment.Mul(payment, growth)
+	payment.Quo(payment, new(big.Rat).Sub(growth, big.NewRat(1, 1)))
+	roundedPayment := roundHalfUp(payment)
+
+	principal = make([]int64, n)
+	interest = make([]int64, n)
+	for i := 0; i < n; i++ {
+		interest[i] = roundHalfUp(new(big.Rat).Mul(big.NewRat(balance, 1), monthlyRate))
+		if i == n-1 {
+			principal[i] = balance
+		} else {
+			principal[i] = min(roundedPayment-interest[i], balance)
+		}
+		balance -= principal[i]
+	}
+
+	return principal, interest
+}
+
+// yearFraction returns rate × months / 12 as an exact fraction
+func yearFraction(rate money.Rate, months int) *big.Rat {
+	return big.NewRat(rate.BasisPoints()*int64(months), int64(money.OneHundredPercent)*monthsPerYear)
+}
+
+// roundHalfUp rounds a non-negative fraction to the nearest integer, ties away from zero
+func roundHalfUp(r *big.Rat) int64 {
+	num := new(big.Int).Mul(r.Num(), big.NewInt(2))
+	num.Add(num, r.Denom())
+	den := new(big.Int).Mul(r.Denom(), big.NewInt(2))
+	return num.Div(num, den).Int64()
+}
+
+// splitEvenly divides total into n parts, putting the remainder on the last part
+func splitEvenly(total int64, n int) []int64 {
+	parts := make([]int64, n)
+	share := total / int64(n)
+	for i := range parts {
+		parts[i] = share
+	}
+	parts[n-1] = total - share*int64(n-1)
+	return parts
+}
+
+// addMonths moves start forward by months, clamping to the end of shorter months so a
+// loan disbursed on the 31st falls due on the 30th or 28th rather than spilling over
+func addMonths(start time.Time, months int) time.Time {
+	year, month, day := start.Date()
+	firstOfTarget := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, start.Location())
+	lastDay := firstOfTarget.AddDate(0, 1, -1).Day()
+	return firstOfTarget.AddDate(0, 0, min(day, lastDay)-1)
+}
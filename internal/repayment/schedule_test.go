@@ -0,0 +1,157 @@
+package repayment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var disbursedAt = time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC)
+
+func sumOf(installments []*models.LoanInstallment) (principal, interest, total money.Money) {
+	for _, installment := range installments {
+		principal = principal.Add(installment.PrincipalAmount)
+		interest = interest.Add(installment.InterestAmount)
+		total = total.Add(installment.TotalAmount)
+	}
+	return principal, interest, total
+}
+
+func TestGenerateFlat(t *testing.T) {
+	installments, err := Generate(Terms{
+		Principal:   money.MustParse("1000.00"),
+		AnnualRate:  money.MustParseRate("0.12"),
+		TenorMonths: 3,
+		Method:      MethodFlat,
+		StartDate:   disbursedAt,
+	})
+	require.NoError(t, err)
+	require.Len(t, installments, 3)
+
+	// 1000.00 × 12% × 3/12 = 30.00 interest; 1000.00 / 3 leaves a cent for the last installment
+	assert.Equal(t, money.MustParse("333.33"), installments[0].PrincipalAmount)
+	assert.Equal(t, money.MustParse("10.00"), installments[0].InterestAmount)
+	assert.Equal(t, money.MustParse("343.33"), installments[0].TotalAmount)
+	assert.Equal(t, money.MustParse("333.34"), installments[2].PrincipalAmount)
+
+	principal, interest, total := sumOf(installments)
+	assert.Equal(t, money.MustParse("1000.00"), principal)
+	assert.Equal(t, money.MustParse("30.00"), interest)
+	assert.Equal(t, principal.Add(interest), total)
+}
+
+func TestGenerateAnnuity(t *testing.T) {
+	installments, err := Generate(Terms{
+		Principal:   money.MustParse("10000.00"),
+		AnnualRate:  money.MustParseRate("0.12"),
+		TenorMonths: 12,
+		Method:      MethodAnnuity,
+		StartDate:   disbursedAt,
+	})
+	require.NoError(t, err)
+	require.Len(t, installments, 12)
+
+	// 1% a month over 12 months gives the textbook payment of 888.49
+	for _, installment := range installments[:11] {
+		assert.Equal(t, money.MustParse("888.49"), installment.TotalAmount)
+	}
+	assert.Equal(t, money.MustParse("100.00"), installments[0].InterestAmount)
+	assert.True(t, installments[1].InterestAmount.LessThan(installments[0].InterestAmount))
+
+	principal, interest, total := sumOf(installments)
+	assert.Equal(t, money.MustParse("10000.00"), principal)
+	assert.Equal(t, principal.Add(interest), total)
+}
+
+func TestGenerateAnnuityWithoutInterest(t *testing.T) {
+	installments, err := Generate(Terms{
+		Principal:   money.MustParse("100.00"),
+		TenorMonths: 3,
+		Method:      MethodAnnuity,
+		StartDate:   disbursedAt,
+	})
+	require.NoError(t, err)
+
+	principal, interest, _ := sumOf(installments)
+	assert.Equal(t, money.MustParse("100.00"), principal)
+	assert.True(t, interest.IsZero())
+}
+
+func TestGenerateBullet(t *testing.T) {
+	installments, err := Generate(Terms{
+		Principal:   money.MustParse("5000.00"),
+		AnnualRate:  money.MustParseRate("0.10"),
+		TenorMonths: 6,
+		Method:      MethodBullet,
+		StartDate:   disbursedAt,
+	})
+	require.NoError(t, err)
+	require.Len(t, installments, 6)
+
+	for _, installment := range installments[:5] {
+		assert.True(t, installment.PrincipalAmount.IsZero())
+		assert.Equal(t, money.MustParse("41.66"), installment.InterestAmount)
+	}
+	assert.Equal(t, money.MustParse("5000.00"), installments[5].PrincipalAmount)
+
+	_, interest, _ := sumOf(installments)
+	assert.Equal(t, money.MustParse("250.00"), interest)
+}
+
+func TestGenerateDueDatesClampToMonthEnd(t *testing.T) {
+	installments, err := Generate(Terms{
+		Principal:   money.MustParse("300.00"),
+		TenorMonths: 3,
+		Method:      MethodFlat,
+		StartDate:   disbursedAt,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC), installments[0].DueDate)
+	assert.Equal(t, time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC), installments[1].DueDate)
+	assert.Equal(t, time.Date(2025, time.April, 30, 0, 0, 0, 0, time.UTC), installments[2].DueDate)
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	terms := Terms{
+		Principal:   money.MustParse("7777.77"),
+		AnnualRate:  money.MustParseRate("0.0999"),
+		TenorMonths: 36,
+		Method:      MethodAnnuity,
+		StartDate:   disbursedAt,
+	}
+
+	first, err := Generate(terms)
+	require.NoError(t, err)
+	second, err := Generate(terms)
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+}
+
+func TestGenerateRejectsInvalidTerms(t *testing.T) {
+	valid := Terms{
+		Principal:   money.MustParse("1000.00"),
+		TenorMonths: 12,
+		Method:      MethodFlat,
+	}
+
+	zeroPrincipal := valid
+	zeroPrincipal.Principal = money.Zero(money.DefaultCurrency)
+	_, err := Generate(zeroPrincipal)
+	assert.Error(t, err)
+
+	noTenor := valid
+	noTenor.TenorMonths = 0
+	_, err = Generate(noTenor)
+	assert.Error(t, err)
+
+	unknownMethod := valid
+	unknownMethod.Method = "balloon"
+	_, err = Generate(unknownMethod)
+	assert.Error(t, err)
+}
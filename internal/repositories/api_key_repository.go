@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	GetByID(ctx context.Context, id int) (*models.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	Update(ctx context.Context, key *models.APIKey) error
+	Revoke(ctx context.Context, id int) error
+	TouchLastUsed(ctx context.Context, id int) error
+	List(ctx context.Context, offset, limit int) ([]*models.APIKey, error)
+}
+
+type apiKeyRepositoryImpl struct {
+	base *BaseRepository
+}
+
+func NewAPIKeyRepository(driver Driver) APIKeyRepository {
+	return &apiKeyRepositoryImpl{
+		base: NewBaseRepository(driver),
+	}
+}
+
+func (r *apiKeyRepositoryImpl) Create(ctx context.Context, key *models.APIKey) error {
+	query := `
+		INSERT INTO api_keys (name, prefix, secret_hash, scopes, expires_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.base.Executor(ctx).QueryRowContext(
+		ctx, query,
+		key.Name, key.Prefix, key.SecretHash, key.Scopes, key.ExpiresAt, key.CreatedBy,
+	).Scan(&key.ID, &key.CreatedAt, &key.UpdatedAt)
+
+	return err
+}
+
+func (r *apiKeyRepositoryImpl) GetByID(ctx context.Context, id int) (*models.APIKey, error) {
+	query := `
+		SELECT id, name, prefix, secret_hash, scopes, expires_at, last_used_at, revoked_at,
+		       created_by, created_at, updated_at
+		FROM api_keys WHERE id = $1
+	`
+
+	var key models.APIKey
+	err := r.base.Executor(ctx).GetContext(ctx, &key, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r *apiKeyRepositoryImpl) GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	query := `
+		SELECT id, name, prefix, secret_hash, scopes, expires_at, last_used_at, revoked_at,
+		       created_by, created_at, updated_at
+		FROM api_keys WHERE prefix = $1
+	`
+
+	var key models.APIKey
+	err := r.base.Executor(ctx).GetContext(ctx, &key, query, prefix)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// Update changes the name, scopes and expiry of a key that has not been revoked
+func (r *apiKeyRepositoryImpl) Update(ctx context.Context, key *models.APIKey) error {
+	query := `
+		UPDATE api_keys SET name = $1, scopes = $2, expires_at = $3, updated_at = NOW()
+		WHERE id = $4 AND revoked_at IS NULL
+		RETURNING updated_at
+	`
+
+	err := r.base.Executor(ctx).QueryRowContext(
+		ctx, query,
+		key.Name, key.Scopes, key.ExpiresAt, key.ID,
+	).Scan(&key.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return apperr.NotFound("api_key_not_found", "API key not found or revoked")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Revoke stops the key from working for good. The key is kept so the audit trail and
+// loan history can still name it.
+func (r *apiKeyRepositoryImpl) Revoke(ctx context.Context, id int) error {
+	query := "UPDATE api_keys SET revoked_at = NOW(), updated_at = NOW() WHERE id = $1 AND revoked_at IS NULL"
+
+	result, err := r.base.Executor(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return apperr.NotFound("api_key_not_found", "API key not found or already revoked")
+	}
+
+	return nil
+}
+
+// TouchLastUsed records that the key was just used. It writes at most once a minute per
+// key, so a busy integration does not turn every request into a write.
+func (r *apiKeyRepositoryImpl) TouchLastUsed(ctx context.Context, id int) error {
+	query := `
+		UPDATE api_keys SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+	`
+
+	_, err := r.base.Executor(ctx).ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *apiKeyRepositoryImpl) List(ctx context.Context, offset, limit int) ([]*models.APIKey, error) {
+	query := `
+		SELECT id, name, prefix, secret_hash, scopes, expires_at, last_used_at, revoked_at,
+		       created_by, created_at, updated_at
+		FROM api_keys
+		ORDER BY created_at DESC, id DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	keys := []*models.APIKey{}
+	err := r.base.Executor(ctx).SelectContext(ctx, &keys, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
@@ -11,6 +11,18 @@ type BaseRepository struct {
 	driver Driver
 }
 
+// Executor is the query surface shared by *sqlx.DB and *sqlx.Tx, so repositories
+// run the same statements whether or not they are part of a unit of work
+type Executor interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// txContextKey is the context key under which a unit of work stores its transaction
+type txContextKey struct{}
+
 func NewBaseRepository(driver Driver) *BaseRepository {
 	return &BaseRepository{
 		driver: driver,
@@ -27,15 +39,29 @@ func (r *BaseRepository) GetUtilDB() *sqlx.DB {
 	return r.driver.GetUtilDB()
 }
 
+// Executor returns the transaction carried by ctx when the call is part of a
+// unit of work, and the connection pool otherwise
+func (r *BaseRepository) Executor(ctx context.Context) Executor {
+	if tx := txFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.GetUtilDB()
+}
+
 // Common methods for all repositories
-func (r *BaseRepository) BeginTx(ctx context.Context) (*sql.Tx, error) {
-	return r.GetDB().BeginTx(ctx, nil)
+func (r *BaseRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	return r.GetUtilDB().BeginTxx(ctx, nil)
 }
 
-func (r *BaseRepository) Commit(tx *sql.Tx) error {
+func (r *BaseRepository) Commit(tx *sqlx.Tx) error {
 	return tx.Commit()
 }
 
-func (r *BaseRepository) Rollback(tx *sql.Tx) error {
+func (r *BaseRepository) Rollback(tx *sqlx.Tx) error {
 	return tx.Rollback()
 }
+
+func txFromContext(ctx context.Context) *sqlx.Tx {
+	tx, _ := ctx.Value(txContextKey{}).(*sqlx.Tx)
+	return tx
+}
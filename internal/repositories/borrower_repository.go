@@ -3,9 +3,11 @@ package repositories
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
 )
 
 type BorrowerRepository interface {
@@ -14,7 +16,8 @@ type BorrowerRepository interface {
 	GetByBorrowerIDNumber(ctx context.Context, borrowerIDNumber string) (*models.Borrower, error)
 	Update(ctx context.Context, borrower *models.Borrower) error
 	Delete(ctx context.Context, id int) error
-	List(ctx context.Context, offset, limit int) ([]*models.Borrower, error)
+	List(ctx context.Context, page models.PageRequest) (*models.Page[*models.Borrower], error)
+	AddCreditBalance(ctx context.Context, id int, amount money.Money) error
 }
 
 type borrowerRepositoryImpl struct {
@@ -34,26 +37,26 @@ func (r *borrowerRepositoryImpl) Create(ctx context.Context, borrower *models.Bo
 		RETURNING id, created_at, updated_at
 	`
 
-	err := r.base.GetUtilDB().QueryRowContext(
+	err := r.base.Executor(ctx).QueryRowContext(
 		ctx, query,
 		borrower.BorrowerIDNumber, borrower.FullName, borrower.Email,
 		borrower.Phone, borrower.Address,
 	).Scan(&borrower.ID, &borrower.CreatedAt, &borrower.UpdatedAt)
 
-	return err
+	return translateError(err)
 }
 
 func (r *borrowerRepositoryImpl) GetByID(ctx context.Context, id int) (*models.Borrower, error) {
 	query := `
-		SELECT id, id_number, name, email, phone, address, created_at, updated_at
+		SELECT id, id_number, name, email, phone, address, credit_balance, created_at, updated_at
 		FROM borrowers WHERE id = $1
 	`
 
 	var borrower models.Borrower
-	err := r.base.GetUtilDB().GetContext(ctx, &borrower, query, id)
+	err := r.base.Executor(ctx).GetContext(ctx, &borrower, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("borrower not found")
+			return nil, ErrBorrowerNotFound
 		}
 		return nil, err
 	}
@@ -63,15 +66,15 @@ func (r *borrowerRepositoryImpl) GetByID(ctx context.Context, id int) (*models.B
 
 func (r *borrowerRepositoryImpl) GetByBorrowerIDNumber(ctx context.Context, borrowerIDNumber string) (*models.Borrower, error) {
 	query := `
-		SELECT id, id_number, name, email, phone, address, created_at, updated_at
+		SELECT id, id_number, name, email, phone, address, credit_balance, created_at, updated_at
 		FROM borrowers WHERE id_number = $1
 	`
 
 	var borrower models.Borrower
-	err := r.base.GetUtilDB().GetContext(ctx, &borrower, query, borrowerIDNumber)
+	err := r.base.Executor(ctx).GetContext(ctx, &borrower, query, borrowerIDNumber)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("borrower not found")
+			return nil, ErrBorrowerNotFound
 		}
 		return nil, err
 	}
@@ -87,14 +90,14 @@ func (r *borrowerRepositoryImpl) Update(ctx context.Context, borrower *models.Bo
 		WHERE id = $6
 	`
 
-	result, err := r.base.GetUtilDB().ExecContext(
+	result, err := r.base.Executor(ctx).ExecContext(
 		ctx, query,
 		borrower.BorrowerIDNumber, borrower.FullName, borrower.Email,
 		borrower.Phone, borrower.Address, borrower.ID,
 	)
 
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -103,7 +106,7 @@ func (r *borrowerRepositoryImpl) Update(ctx context.Context, borrower *models.Bo
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("borrower not found")
+		return ErrBorrowerNotFound
 	}
 
 	return nil
@@ -111,9 +114,9 @@ func (r *borrowerRepositoryImpl) Update(ctx context.Context, borrower *models.Bo
 
 func (r *borrowerRepositoryImpl) Delete(ctx context.Context, id int) error {
 	query := "DELETE FROM borrowers WHERE id = $1"
-	result, err := r.base.GetUtilDB().ExecContext(ctx, query, id)
+	result, err := r.base.Executor(ctx).ExecContext(ctx, query, id)
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -122,25 +125,51 @@ func (r *borrowerRepositoryImpl) Delete(ctx context.Context, id int) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("borrower not found")
+		return ErrBorrowerNotFound
 	}
 
 	return nil
 }
 
-func (r *borrowerRepositoryImpl) List(ctx context.Context, offset, limit int) ([]*models.Borrower, error) {
-	query := `
-		SELECT id, id_number, name, email, phone, address, created_at, updated_at
-		FROM borrowers
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
+// borrowerSortKeys are the fields borrowers can be sorted by
+var borrowerSortKeys = map[string]sortKey[*models.Borrower]{
+	"id":         {"id", "integer", func(b *models.Borrower) string { return strconv.Itoa(b.ID) }},
+	"created_at": {"created_at", "timestamptz", func(b *models.Borrower) string { return b.CreatedAt.Format(time.RFC3339Nano) }},
+	"full_name":  {"name", "text", func(b *models.Borrower) string { return b.FullName }},
+	"email":      {"email", "text", func(b *models.Borrower) string { return b.Email }},
+}
+
+// defaultBorrowerSort lists the newest borrowers first
+var defaultBorrowerSort = []models.SortField{{Field: "created_at", Descending: true}}
 
-	var borrowers []*models.Borrower
-	err := r.base.GetUtilDB().SelectContext(ctx, &borrowers, query, limit, offset)
+// List returns the page of borrowers that page asks for
+func (r *borrowerRepositoryImpl) List(ctx context.Context, page models.PageRequest) (*models.Page[*models.Borrower], error) {
+	q, err := newKeysetQuery(borrowerSortKeys, page.Sort, defaultBorrowerSort)
 	if err != nil {
 		return nil, err
 	}
 
-	return borrowers, nil
+	columns := "id, id_number, name, email, phone, address, credit_balance, created_at, updated_at"
+	return q.fetch(ctx, r.base.Executor(ctx), columns, "borrowers", page)
+}
+
+// AddCreditBalance adds amount to the borrower's credit balance in a single statement,
+// so concurrent repayments on different loans of the same borrower do not overwrite each other
+func (r *borrowerRepositoryImpl) AddCreditBalance(ctx context.Context, id int, amount money.Money) error {
+	query := "UPDATE borrowers SET credit_balance = credit_balance + $1, updated_at = NOW() WHERE id = $2"
+	result, err := r.base.Executor(ctx).ExecContext(ctx, query, amount, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrBorrowerNotFound
+	}
+
+	return nil
 }
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+)
+
+// Errors for records that do not exist
+var (
+	ErrUserNotFound                   = apperr.NotFound("user_not_found", "user not found")
+	ErrBorrowerNotFound               = apperr.NotFound("borrower_not_found", "borrower not found")
+	ErrInvestorNotFound               = apperr.NotFound("investor_not_found", "investor not found")
+	ErrLoanNotFound                   = apperr.NotFound("loan_not_found", "loan not found")
+	ErrLoanApprovalNotFound           = apperr.NotFound("loan_approval_not_found", "loan approval not found")
+	ErrLoanRejectionNotFound          = apperr.NotFound("loan_rejection_not_found", "loan rejection not found")
+	ErrLoanDisbursementNotFound       = apperr.NotFound("loan_disbursement_not_found", "loan disbursement not found")
+	ErrLoanInstallmentNotFound        = apperr.NotFound("loan_installment_not_found", "loan installment not found")
+	ErrLoanInvestmentNotFound         = apperr.NotFound("loan_investment_not_found", "loan investment not found")
+	ErrLoanStateHistoryNotFound       = apperr.NotFound("loan_state_history_not_found", "no state history found for loan")
+	ErrRejectionReasonNotFound        = apperr.NotFound("rejection_reason_not_found", "loan rejection reason not found")
+	ErrRefreshTokenNotFound           = apperr.NotFound("refresh_token_not_found", "refresh token not found")
+	ErrPasswordResetTokenNotFound     = apperr.NotFound("password_reset_token_not_found", "password reset token not found")
+	ErrEmailVerificationTokenNotFound = apperr.NotFound("email_verification_token_not_found", "email verification token not found")
+	ErrAPIKeyNotFound                 = apperr.NotFound("api_key_not_found", "API key not found")
+	ErrIdempotencyKeyNotFound         = apperr.NotFound("idempotency_key_not_found", "idempotency key not found")
+)
+
+// translateError turns constraint violations into domain errors, so a duplicate or a
+// dangling reference is reported as the caller's mistake rather than an internal error
+func translateError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code.Name() {
+	case "unique_violation":
+		return apperr.Conflict("duplicate_record", "a record with this %s already exists", constraintColumn(pqErr)).WithCause(err)
+	case "foreign_key_violation":
+		if strings.HasPrefix(pqErr.Message, "update or delete") {
+			return apperr.Conflict("record_in_use", "the record is still referenced by other records").WithCause(err)
+		}
+		return apperr.Validation("invalid_reference", "a referenced record does not exist").WithCause(err)
+	case "check_violation", "not_null_violation":
+		return apperr.Validation("invalid_value", "a value is missing or out of range").WithCause(err)
+	}
+
+	return err
+}
+
+// constraintColumn returns the column named in a constraint violation's detail, which
+// reads like "Key (email)=(jane@example.com) already exists."
+func constraintColumn(pqErr *pq.Error) string {
+	if _, rest, found := strings.Cut(pqErr.Detail, "Key ("); found {
+		if column, _, found := strings.Cut(rest, ")="); found {
+			return strings.ReplaceAll(column, "_", " ")
+		}
+	}
+	return "value"
+}
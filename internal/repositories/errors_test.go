@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		kind    apperr.Kind
+		message string
+	}{
+		{
+			name:    "duplicate",
+			err:     &pq.Error{Code: "23505", Detail: "Key (id_number)=(3174000000000001) already exists."},
+			kind:    apperr.KindConflict,
+			message: "a record with this id number already exists",
+		},
+		{
+			name:    "delete of a referenced record",
+			err:     &pq.Error{Code: "23503", Message: `update or delete on table "borrowers" violates foreign key constraint "loans_borrower_id_fkey" on table "loans"`},
+			kind:    apperr.KindConflict,
+			message: "the record is still referenced by other records",
+		},
+		{
+			name:    "reference to a missing record",
+			err:     &pq.Error{Code: "23503", Message: `insert or update on table "loans" violates foreign key constraint "loans_borrower_id_fkey"`},
+			kind:    apperr.KindValidation,
+			message: "a referenced record does not exist",
+		},
+		{
+			name:    "check constraint",
+			err:     &pq.Error{Code: "23514"},
+			kind:    apperr.KindValidation,
+			message: "a value is missing or out of range",
+		},
+		{
+			name:    "other database error",
+			err:     &pq.Error{Code: "40001", Message: "could not serialize access"},
+			kind:    apperr.KindInternal,
+			message: "pq: could not serialize access",
+		},
+		{
+			name:    "not a database error",
+			err:     errors.New("connection reset"),
+			kind:    apperr.KindInternal,
+			message: "connection reset",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := translateError(tt.err)
+
+			assert.Equal(t, tt.kind, apperr.KindOf(err))
+			assert.EqualError(t, err, tt.message)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	assert.NoError(t, translateError(nil))
+}
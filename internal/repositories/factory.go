@@ -12,6 +12,11 @@ func NewRepositoryFactory(driver Driver) *RepositoryFactory {
 	}
 }
 
+// Driver exposes the database driver to repositories that live outside this package
+func (f *RepositoryFactory) Driver() Driver {
+	return f.driver
+}
+
 func (f *RepositoryFactory) BorrowerRepository() BorrowerRepository {
 	return NewBorrowerRepository(f.driver)
 }
@@ -24,6 +29,14 @@ func (f *RepositoryFactory) LoanApprovalRepository() LoanApprovalRepository {
 	return NewLoanApprovalRepository(f.driver)
 }
 
+func (f *RepositoryFactory) LoanRejectionRepository() LoanRejectionRepository {
+	return NewLoanRejectionRepository(f.driver)
+}
+
+func (f *RepositoryFactory) LoanRejectionReasonRepository() LoanRejectionReasonRepository {
+	return NewLoanRejectionReasonRepository(f.driver)
+}
+
 func (f *RepositoryFactory) LoanDisbursementRepository() LoanDisbursementRepository {
 	return NewLoanDisbursementRepository(f.driver)
 }
@@ -40,6 +53,54 @@ func (f *RepositoryFactory) LoanStateHistoryRepository() LoanStateHistoryReposit
 	return NewLoanStateHistoryRepository(f.driver)
 }
 
+func (f *RepositoryFactory) LoanInstallmentRepository() LoanInstallmentRepository {
+	return NewLoanInstallmentRepository(f.driver)
+}
+
+func (f *RepositoryFactory) LoanRepaymentRepository() LoanRepaymentRepository {
+	return NewLoanRepaymentRepository(f.driver)
+}
+
+func (f *RepositoryFactory) PayoutRepository() PayoutRepository {
+	return NewPayoutRepository(f.driver)
+}
+
+func (f *RepositoryFactory) PlatformRevenueRepository() PlatformRevenueRepository {
+	return NewPlatformRevenueRepository(f.driver)
+}
+
 func (f *RepositoryFactory) UserRepository() UserRepository {
 	return NewUserRepository(f.driver)
-}
\ No newline at end of file
+}
+
+func (f *RepositoryFactory) RefreshTokenRepository() RefreshTokenRepository {
+	return NewRefreshTokenRepository(f.driver)
+}
+
+func (f *RepositoryFactory) PasswordResetTokenRepository() PasswordResetTokenRepository {
+	return NewPasswordResetTokenRepository(f.driver)
+}
+
+func (f *RepositoryFactory) SecurityEventRepository() SecurityEventRepository {
+	return NewSecurityEventRepository(f.driver)
+}
+
+func (f *RepositoryFactory) EmailVerificationTokenRepository() EmailVerificationTokenRepository {
+	return NewEmailVerificationTokenRepository(f.driver)
+}
+
+func (f *RepositoryFactory) RecoveryCodeRepository() RecoveryCodeRepository {
+	return NewRecoveryCodeRepository(f.driver)
+}
+
+func (f *RepositoryFactory) APIKeyRepository() APIKeyRepository {
+	return NewAPIKeyRepository(f.driver)
+}
+
+func (f *RepositoryFactory) IdempotencyKeyRepository() IdempotencyKeyRepository {
+	return NewIdempotencyKeyRepository(f.driver)
+}
+
+func (f *RepositoryFactory) UnitOfWork() UnitOfWork {
+	return NewUnitOfWork(f.driver)
+}
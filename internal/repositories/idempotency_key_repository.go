@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+type IdempotencyKeyRepository interface {
+	Claim(ctx context.Context, key *models.IdempotencyKey, staleBefore time.Time) (bool, error)
+	GetByKey(ctx context.Context, owner, key string) (*models.IdempotencyKey, error)
+	Complete(ctx context.Context, key *models.IdempotencyKey) error
+	Release(ctx context.Context, key *models.IdempotencyKey) error
+}
+
+type idempotencyKeyRepositoryImpl struct {
+	base *BaseRepository
+}
+
+func NewIdempotencyKeyRepository(driver Driver) IdempotencyKeyRepository {
+	return &idempotencyKeyRepositoryImpl{
+		base: NewBaseRepository(driver),
+	}
+}
+
+// Claim stores key as in flight. It also takes over an expired key, and an in-flight key for
+// the same request that was locked before staleBefore, as its request never finished. It
+// reports false when the key is held by another request; the unique constraint makes
+// concurrent claims of one key wait for each other, so only one of them succeeds.
+func (r *idempotencyKeyRepositoryImpl) Claim(ctx context.Context, key *models.IdempotencyKey, staleBefore time.Time) (bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (owner, idempotency_key, method, path, request_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (owner, idempotency_key) DO UPDATE SET
+			method = EXCLUDED.method, path = EXCLUDED.path, request_hash = EXCLUDED.request_hash,
+			status_code = NULL, content_type = NULL, response_body = NULL,
+			locked_at = NOW(), completed_at = NULL, expires_at = EXCLUDED.expires_at, created_at = NOW()
+		WHERE idempotency_keys.expires_at <= NOW()
+		   OR (idempotency_keys.completed_at IS NULL AND idempotency_keys.locked_at < $7
+		       AND idempotency_keys.request_hash = EXCLUDED.request_hash)
+		RETURNING id, locked_at, created_at
+	`
+
+	err := r.base.Executor(ctx).QueryRowContext(
+		ctx, query,
+		key.Owner, key.Key, key.Method, key.Path, key.RequestHash, key.ExpiresAt, staleBefore,
+	).Scan(&key.ID, &key.LockedAt, &key.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *idempotencyKeyRepositoryImpl) GetByKey(ctx context.Context, owner, key string) (*models.IdempotencyKey, error) {
+	query := `
+		SELECT id, owner, idempotency_key, method, path, request_hash, status_code, content_type,
+		       response_body, locked_at, completed_at, expires_at, created_at
+		FROM idempotency_keys WHERE owner = $1 AND idempotency_key = $2
+	`
+
+	var idempotencyKey models.IdempotencyKey
+	err := r.base.Executor(ctx).GetContext(ctx, &idempotencyKey, query, owner, key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrIdempotencyKeyNotFound
+		}
+		return nil, err
+	}
+
+	return &idempotencyKey, nil
+}
+
+// Complete stores the response of the request holding key. It fails with
+// ErrIdempotencyKeyNotFound when the claim was lost, because it went stale and another
+// request took the key over.
+func (r *idempotencyKeyRepositoryImpl) Complete(ctx context.Context, key *models.IdempotencyKey) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $1, content_type = $2, response_body = $3, completed_at = NOW()
+		WHERE id = $4 AND locked_at = $5 AND completed_at IS NULL
+		RETURNING completed_at
+	`
+
+	err := r.base.Executor(ctx).QueryRowContext(
+		ctx, query,
+		key.StatusCode, key.ContentType, key.ResponseBody, key.ID, key.LockedAt,
+	).Scan(&key.CompletedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrIdempotencyKeyNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Release gives up the claim on key without storing a response, so the request can be retried
+func (r *idempotencyKeyRepositoryImpl) Release(ctx context.Context, key *models.IdempotencyKey) error {
+	query := "DELETE FROM idempotency_keys WHERE id = $1 AND locked_at = $2 AND completed_at IS NULL"
+
+	_, err := r.base.Executor(ctx).ExecContext(ctx, query, key.ID, key.LockedAt)
+	return err
+}
@@ -3,7 +3,9 @@ package repositories
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"strconv"
+	"time"
+
 	"github.com/sswastioyono18/loan-engine/internal/models"
 )
 
@@ -14,7 +16,7 @@ type InvestorRepository interface {
 	GetByEmail(ctx context.Context, email string) (*models.Investor, error)
 	Update(ctx context.Context, investor *models.Investor) error
 	Delete(ctx context.Context, id int) error
-	List(ctx context.Context, offset, limit int) ([]*models.Investor, error)
+	List(ctx context.Context, page models.PageRequest) (*models.Page[*models.Investor], error)
 }
 
 type investorRepositoryImpl struct {
@@ -34,12 +36,12 @@ func (r *investorRepositoryImpl) Create(ctx context.Context, investor *models.In
 		RETURNING id, created_at, updated_at
 	`
 
-	err := r.base.GetUtilDB().QueryRowContext(
+	err := r.base.Executor(ctx).QueryRowContext(
 		ctx, query,
 		investor.InvestorID, investor.FullName, investor.Email, investor.Phone,
 	).Scan(&investor.ID, &investor.CreatedAt, &investor.UpdatedAt)
 
-	return err
+	return translateError(err)
 }
 
 func (r *investorRepositoryImpl) GetByID(ctx context.Context, id int) (*models.Investor, error) {
@@ -49,10 +51,10 @@ func (r *investorRepositoryImpl) GetByID(ctx context.Context, id int) (*models.I
 	`
 
 	var investor models.Investor
-	err := r.base.GetUtilDB().GetContext(ctx, &investor, query, id)
+	err := r.base.Executor(ctx).GetContext(ctx, &investor, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("investor not found")
+			return nil, ErrInvestorNotFound
 		}
 		return nil, err
 	}
@@ -67,10 +69,10 @@ func (r *investorRepositoryImpl) GetByInvestorID(ctx context.Context, investorID
 	`
 
 	var investor models.Investor
-	err := r.base.GetUtilDB().GetContext(ctx, &investor, query, investorID)
+	err := r.base.Executor(ctx).GetContext(ctx, &investor, query, investorID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("investor not found")
+			return nil, ErrInvestorNotFound
 		}
 		return nil, err
 	}
@@ -85,10 +87,10 @@ func (r *investorRepositoryImpl) GetByEmail(ctx context.Context, email string) (
 	`
 
 	var investor models.Investor
-	err := r.base.GetUtilDB().GetContext(ctx, &investor, query, email)
+	err := r.base.Executor(ctx).GetContext(ctx, &investor, query, email)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("investor not found")
+			return nil, ErrInvestorNotFound
 		}
 		return nil, err
 	}
@@ -104,14 +106,14 @@ func (r *investorRepositoryImpl) Update(ctx context.Context, investor *models.In
 		WHERE id = $5
 	`
 
-	result, err := r.base.GetUtilDB().ExecContext(
+	result, err := r.base.Executor(ctx).ExecContext(
 		ctx, query,
 		investor.InvestorID, investor.FullName, investor.Email,
 		investor.Phone, investor.ID,
 	)
 
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -120,7 +122,7 @@ func (r *investorRepositoryImpl) Update(ctx context.Context, investor *models.In
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("investor not found")
+		return ErrInvestorNotFound
 	}
 
 	return nil
@@ -128,9 +130,9 @@ func (r *investorRepositoryImpl) Update(ctx context.Context, investor *models.In
 
 func (r *investorRepositoryImpl) Delete(ctx context.Context, id int) error {
 	query := "DELETE FROM investors WHERE id = $1"
-	result, err := r.base.GetUtilDB().ExecContext(ctx, query, id)
+	result, err := r.base.Executor(ctx).ExecContext(ctx, query, id)
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -139,25 +141,30 @@ func (r *investorRepositoryImpl) Delete(ctx context.Context, id int) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("investor not found")
+		return ErrInvestorNotFound
 	}
 
 	return nil
 }
 
-func (r *investorRepositoryImpl) List(ctx context.Context, offset, limit int) ([]*models.Investor, error) {
-	query := `
-		SELECT id, investor_id, name, email, phone, created_at, updated_at
-		FROM investors
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
+// investorSortKeys are the fields investors can be sorted by
+var investorSortKeys = map[string]sortKey[*models.Investor]{
+	"id":         {"id", "integer", func(i *models.Investor) string { return strconv.Itoa(i.ID) }},
+	"created_at": {"created_at", "timestamptz", func(i *models.Investor) string { return i.CreatedAt.Format(time.RFC3339Nano) }},
+	"full_name":  {"name", "text", func(i *models.Investor) string { return i.FullName }},
+	"email":      {"email", "text", func(i *models.Investor) string { return i.Email }},
+}
+
+// defaultInvestorSort lists the newest investors first
+var defaultInvestorSort = []models.SortField{{Field: "created_at", Descending: true}}
 
-	var investors []*models.Investor
-	err := r.base.GetUtilDB().SelectContext(ctx, &investors, query, limit, offset)
+// List returns the page of investors that page asks for
+func (r *investorRepositoryImpl) List(ctx context.Context, page models.PageRequest) (*models.Page[*models.Investor], error) {
+	q, err := newKeysetQuery(investorSortKeys, page.Sort, defaultInvestorSort)
 	if err != nil {
 		return nil, err
 	}
 
-	return investors, nil
+	columns := "id, investor_id, name, email, phone, created_at, updated_at"
+	return q.fetch(ctx, r.base.Executor(ctx), columns, "investors", page)
 }
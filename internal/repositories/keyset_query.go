@@ -0,0 +1,228 @@
+package repositories
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+// totalEstimateCap is how many matches a listing counts at most for its total estimate
+const totalEstimateCap = 10000
+
+var errInvalidCursor = apperr.Validation("invalid_cursor", "the cursor is malformed or was issued for another sort")
+
+// sortKey is a field a listing can be sorted by: its column, the SQL type a cursor value
+// for it is cast to, and how to read that value from a row. The column must not be
+// nullable, as NULLs do not compare.
+type sortKey[T any] struct {
+	column  string
+	sqlType string
+	value   func(T) string
+}
+
+// keysetQuery builds a listing that pages by keyset rather than by offset: the cursor holds
+// the sort values of the last row of a page, and the next page starts after them. Pages
+// stay fast however deep they go, and rows inserted meanwhile do not shift them.
+//
+// Every listing sorts by "id" last, so rows with equal sort values keep a stable order;
+// the keys of a listing must therefore include "id".
+type keysetQuery[T any] struct {
+	keys       map[string]sortKey[T]
+	sort       []models.SortField
+	conditions []string
+	args       []interface{}
+}
+
+// newKeysetQuery starts a listing sorted by sort, or by defaultSort when sort is empty. It
+// fails with a validation error when sort names a field the listing cannot be sorted by.
+func newKeysetQuery[T any](keys map[string]sortKey[T], sort, defaultSort []models.SortField) (*keysetQuery[T], error) {
+	if len(sort) == 0 {
+		sort = defaultSort
+	}
+
+	q := &keysetQuery[T]{keys: keys}
+	seen := make(map[string]bool)
+	for _, field := range sort {
+		if _, ok := keys[field.Field]; !ok {
+			return nil, apperr.Validation("invalid_sort", "cannot sort by %s; sort by %s", field.Field, strings.Join(sortableFields(keys), ", "))
+		}
+		if seen[field.Field] {
+			return nil, apperr.Validation("invalid_sort", "%s is sorted by more than once", field.Field)
+		}
+		seen[field.Field] = true
+
+		q.sort = append(q.sort, field)
+		if field.Field == "id" {
+			// IDs are unique, so nothing after them changes the order
+			return q, nil
+		}
+	}
+
+	q.sort = append(q.sort, models.SortField{Field: "id", Descending: sort[len(sort)-1].Descending})
+	return q, nil
+}
+
+func sortableFields[T any](keys map[string]sortKey[T]) []string {
+	fields := make([]string, 0, len(keys))
+	for field := range keys {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// where narrows the listing by condition, in which each %s stands for one of args
+func (q *keysetQuery[T]) where(condition string, args ...interface{}) {
+	placeholders := make([]interface{}, len(args))
+	for i, arg := range args {
+		q.args = append(q.args, arg)
+		placeholders[i] = fmt.Sprintf("$%d", len(q.args))
+	}
+	q.conditions = append(q.conditions, fmt.Sprintf(condition, placeholders...))
+}
+
+// fetch runs the listing of columns from table and returns the page that page asks for.
+// The total estimate counts the filtered rows of every page, not only those after the cursor.
+func (q *keysetQuery[T]) fetch(ctx context.Context, executor Executor, columns, table string, page models.PageRequest) (*models.Page[T], error) {
+	query, args, err := q.selectQuery(columns, table, page)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []T{}
+	if err := executor.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
+	}
+
+	result := &models.Page[T]{Items: items}
+	if len(items) > page.Limit {
+		result.Items = items[:page.Limit]
+		if result.NextCursor, err = q.cursorAfter(result.Items[page.Limit-1]); err != nil {
+			return nil, err
+		}
+	}
+
+	countQuery, countArgs := q.countQuery(table)
+	if err := executor.GetContext(ctx, &result.TotalEstimate, countQuery, countArgs...); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// countQuery counts the rows that match the filters, stopping at totalEstimateCap
+func (q *keysetQuery[T]) countQuery(table string) (string, []interface{}) {
+	query := fmt.Sprintf(
+		"SELECT COUNT(*) FROM (SELECT 1 FROM %s%s LIMIT %d) AS matches",
+		table, whereClause(q.conditions), totalEstimateCap,
+	)
+	return query, q.args
+}
+
+// selectQuery selects the page after page.Cursor, with one row more than the limit so
+// fetch can tell whether another page follows
+func (q *keysetQuery[T]) selectQuery(columns, table string, page models.PageRequest) (string, []interface{}, error) {
+	conditions := append([]string(nil), q.conditions...)
+	args := append([]interface{}(nil), q.args...)
+	if page.Cursor != "" {
+		values, err := q.decodeCursor(page.Cursor)
+		if err != nil {
+			return "", nil, err
+		}
+
+		var after string
+		after, args = q.afterCondition(values, args)
+		conditions = append(conditions, after)
+	}
+
+	order := make([]string, len(q.sort))
+	for i, field := range q.sort {
+		order[i] = q.keys[field.Field].column + " ASC"
+		if field.Descending {
+			order[i] = q.keys[field.Field].column + " DESC"
+		}
+	}
+
+	query := fmt.Sprintf(
+		"SELECT %s FROM %s%s ORDER BY %s LIMIT %d",
+		columns, table, whereClause(conditions), strings.Join(order, ", "), page.Limit+1,
+	)
+	return query, args, nil
+}
+
+// afterCondition matches the rows that sort after values. With mixed directions a row
+// comparison cannot express this, so it is spelled out: the first field is past its value,
+// or it is equal and the second field is past its value, and so on.
+func (q *keysetQuery[T]) afterCondition(values []string, args []interface{}) (string, []interface{}) {
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		args = append(args, value)
+		placeholders[i] = fmt.Sprintf("$%d::%s", len(args), q.keys[q.sort[i].Field].sqlType)
+	}
+
+	alternatives := make([]string, len(q.sort))
+	for i, field := range q.sort {
+		terms := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			terms = append(terms, fmt.Sprintf("%s = %s", q.keys[q.sort[j].Field].column, placeholders[j]))
+		}
+		operator := ">"
+		if field.Descending {
+			operator = "<"
+		}
+		terms = append(terms, fmt.Sprintf("%s %s %s", q.keys[field.Field].column, operator, placeholders[i]))
+		alternatives[i] = "(" + strings.Join(terms, " AND ") + ")"
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+// cursor is what an opaque cursor holds: the sort it was issued for and the sort values of
+// the row the next page starts after
+type cursor struct {
+	Sort   string   `json:"sort"`
+	Values []string `json:"values"`
+}
+
+func (q *keysetQuery[T]) cursorAfter(row T) (string, error) {
+	c := cursor{Sort: models.SortString(q.sort), Values: make([]string, len(q.sort))}
+	for i, field := range q.sort {
+		c.Values[i] = q.keys[field.Field].value(row)
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func (q *keysetQuery[T]) decodeCursor(encoded string) ([]string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errInvalidCursor.WithCause(err)
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, errInvalidCursor.WithCause(err)
+	}
+	if c.Sort != models.SortString(q.sort) || len(c.Values) != len(q.sort) {
+		return nil, errInvalidCursor
+	}
+
+	return c.Values, nil
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
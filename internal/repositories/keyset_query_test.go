@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeysetQuerySortsByIDLast(t *testing.T) {
+	tests := []struct {
+		name string
+		sort string
+		want string
+	}{
+		{"default sort", "", "-created_at,-id"},
+		{"mixed directions", "created_at,-principal_amount", "created_at,-principal_amount,-id"},
+		{"explicit id ends the sort", "id,created_at", "id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sort, err := models.ParseSort(tt.sort)
+			require.NoError(t, err)
+
+			q, err := newKeysetQuery(loanSortKeys, sort, defaultLoanSort)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, models.SortString(q.sort))
+		})
+	}
+}
+
+func TestKeysetQueryRejectsUnsortableFields(t *testing.T) {
+	for _, sort := range []string{"agreement_letter_link", "created_at,-created_at"} {
+		fields, err := models.ParseSort(sort)
+		require.NoError(t, err)
+
+		_, err = newKeysetQuery(loanSortKeys, fields, defaultLoanSort)
+
+		assert.ErrorIs(t, err, apperr.Validation("invalid_sort", ""), sort)
+	}
+}
+
+func TestKeysetQueryPagesAfterCursor(t *testing.T) {
+	sort := []models.SortField{{Field: "created_at"}, {Field: "principal_amount", Descending: true}}
+	last := &models.Loan{
+		ID:              42,
+		PrincipalAmount: money.MustParse("5000000.00"),
+		CreatedAt:       time.Date(2024, 3, 1, 9, 30, 0, 123456000, time.UTC),
+	}
+
+	first, err := newKeysetQuery(loanSortKeys, sort, defaultLoanSort)
+	require.NoError(t, err)
+	cursor, err := first.cursorAfter(last)
+	require.NoError(t, err)
+
+	q, err := newKeysetQuery(loanSortKeys, sort, defaultLoanSort)
+	require.NoError(t, err)
+	q.where("borrower_id = %s", 7)
+
+	query, args, err := q.selectQuery("id", "loans", models.PageRequest{Cursor: cursor, Limit: 20, Sort: sort})
+
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM loans WHERE borrower_id = $1 AND ("+
+		"(created_at > $2::timestamptz) OR "+
+		"(created_at = $2::timestamptz AND principal_amount < $3::numeric) OR "+
+		"(created_at = $2::timestamptz AND principal_amount = $3::numeric AND id < $4::integer)"+
+		") ORDER BY created_at ASC, principal_amount DESC, id DESC LIMIT 21", query)
+	assert.Equal(t, []interface{}{7, "2024-03-01T09:30:00.123456Z", "5000000.00", "42"}, args)
+
+	countQuery, countArgs := q.countQuery("loans")
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT 1 FROM loans WHERE borrower_id = $1 LIMIT 10000) AS matches", countQuery)
+	assert.Equal(t, []interface{}{7}, countArgs)
+}
+
+func TestKeysetQueryRejectsForeignCursors(t *testing.T) {
+	byPrincipal := []models.SortField{{Field: "principal_amount"}}
+	issuer, err := newKeysetQuery(loanSortKeys, byPrincipal, defaultLoanSort)
+	require.NoError(t, err)
+	cursor, err := issuer.cursorAfter(&models.Loan{ID: 1})
+	require.NoError(t, err)
+
+	for name, page := range map[string]models.PageRequest{
+		"malformed":               {Cursor: "not a cursor", Limit: 10},
+		"issued for another sort": {Cursor: cursor, Limit: 10},
+	} {
+		t.Run(name, func(t *testing.T) {
+			q, err := newKeysetQuery(loanSortKeys, nil, defaultLoanSort)
+			require.NoError(t, err)
+
+			_, _, err = q.selectQuery("id", "loans", page)
+
+			assert.ErrorIs(t, err, errInvalidCursor)
+			assert.Equal(t, apperr.KindValidation, apperr.KindOf(err))
+		})
+	}
+}
@@ -3,7 +3,6 @@ package repositories
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"github.com/sswastioyono18/loan-engine/internal/models"
 )
 
@@ -33,7 +32,7 @@ func (r *loanApprovalRepositoryImpl) Create(ctx context.Context, approval *model
 		RETURNING id, created_at
 	`
 
-	err := r.base.GetUtilDB().QueryRowContext(
+	err := r.base.Executor(ctx).QueryRowContext(
 		ctx, query,
 		approval.LoanID, approval.FieldValidatorEmployeeID,
 		approval.ApprovalDate, approval.ProofImageUrl,
@@ -50,10 +49,10 @@ func (r *loanApprovalRepositoryImpl) GetByLoanID(ctx context.Context, loanID int
 	`
 
 	var approval models.LoanApproval
-	err := r.base.GetUtilDB().GetContext(ctx, &approval, query, loanID)
+	err := r.base.Executor(ctx).GetContext(ctx, &approval, query, loanID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("loan approval not found")
+			return nil, ErrLoanApprovalNotFound
 		}
 		return nil, err
 	}
@@ -69,10 +68,10 @@ func (r *loanApprovalRepositoryImpl) GetByID(ctx context.Context, id int) (*mode
 	`
 
 	var approval models.LoanApproval
-	err := r.base.GetUtilDB().GetContext(ctx, &approval, query, id)
+	err := r.base.Executor(ctx).GetContext(ctx, &approval, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("loan approval not found")
+			return nil, ErrLoanApprovalNotFound
 		}
 		return nil, err
 	}
@@ -88,7 +87,7 @@ func (r *loanApprovalRepositoryImpl) Update(ctx context.Context, approval *model
 		WHERE id = $4
 	`
 
-	result, err := r.base.GetUtilDB().ExecContext(
+	result, err := r.base.Executor(ctx).ExecContext(
 		ctx, query,
 		approval.FieldValidatorEmployeeID, approval.ApprovalDate,
 		approval.ProofImageUrl, approval.ID,
@@ -104,7 +103,7 @@ func (r *loanApprovalRepositoryImpl) Update(ctx context.Context, approval *model
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("loan approval not found")
+		return ErrLoanApprovalNotFound
 	}
 
 	return nil
@@ -112,7 +111,7 @@ func (r *loanApprovalRepositoryImpl) Update(ctx context.Context, approval *model
 
 func (r *loanApprovalRepositoryImpl) Delete(ctx context.Context, id int) error {
 	query := "DELETE FROM loan_approvals WHERE id = $1"
-	result, err := r.base.GetUtilDB().ExecContext(ctx, query, id)
+	result, err := r.base.Executor(ctx).ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -123,7 +122,7 @@ func (r *loanApprovalRepositoryImpl) Delete(ctx context.Context, id int) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("loan approval not found")
+		return ErrLoanApprovalNotFound
 	}
 
 	return nil
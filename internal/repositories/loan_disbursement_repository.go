@@ -3,7 +3,6 @@ package repositories
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"github.com/sswastioyono18/loan-engine/internal/models"
 )
 
@@ -34,7 +33,7 @@ func (r *loanDisbursementRepositoryImpl) Create(ctx context.Context, disbursemen
 		RETURNING id, created_at
 	`
 
-	err := r.base.GetUtilDB().QueryRowContext(
+	err := r.base.Executor(ctx).QueryRowContext(
 		ctx, query,
 		disbursement.LoanID, disbursement.FieldOfficerEmployeeID,
 		disbursement.DisbursementDate, disbursement.AgreementLetterSignedUrl,
@@ -51,10 +50,10 @@ func (r *loanDisbursementRepositoryImpl) GetByLoanID(ctx context.Context, loanID
 	`
 
 	var disbursement models.LoanDisbursement
-	err := r.base.GetUtilDB().GetContext(ctx, &disbursement, query, loanID)
+	err := r.base.Executor(ctx).GetContext(ctx, &disbursement, query, loanID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("loan disbursement not found")
+			return nil, ErrLoanDisbursementNotFound
 		}
 		return nil, err
 	}
@@ -70,10 +69,10 @@ func (r *loanDisbursementRepositoryImpl) GetByID(ctx context.Context, id int) (*
 	`
 
 	var disbursement models.LoanDisbursement
-	err := r.base.GetUtilDB().GetContext(ctx, &disbursement, query, id)
+	err := r.base.Executor(ctx).GetContext(ctx, &disbursement, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("loan disbursement not found")
+			return nil, ErrLoanDisbursementNotFound
 		}
 		return nil, err
 	}
@@ -89,7 +88,7 @@ func (r *loanDisbursementRepositoryImpl) Update(ctx context.Context, disbursemen
 		WHERE id = $4
 	`
 
-	result, err := r.base.GetUtilDB().ExecContext(
+	result, err := r.base.Executor(ctx).ExecContext(
 		ctx, query,
 		disbursement.FieldOfficerEmployeeID, disbursement.DisbursementDate,
 		disbursement.AgreementLetterSignedUrl, disbursement.ID,
@@ -105,7 +104,7 @@ func (r *loanDisbursementRepositoryImpl) Update(ctx context.Context, disbursemen
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("loan disbursement not found")
+		return ErrLoanDisbursementNotFound
 	}
 
 	return nil
@@ -113,7 +112,7 @@ func (r *loanDisbursementRepositoryImpl) Update(ctx context.Context, disbursemen
 
 func (r *loanDisbursementRepositoryImpl) Delete(ctx context.Context, id int) error {
 	query := "DELETE FROM loan_disbursements WHERE id = $1"
-	result, err := r.base.GetUtilDB().ExecContext(ctx, query, id)
+	result, err := r.base.Executor(ctx).ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -124,7 +123,7 @@ func (r *loanDisbursementRepositoryImpl) Delete(ctx context.Context, id int) err
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("loan disbursement not found")
+		return ErrLoanDisbursementNotFound
 	}
 
 	return nil
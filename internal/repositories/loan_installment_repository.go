@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+type LoanInstallmentRepository interface {
+	Create(ctx context.Context, installment *models.LoanInstallment) error
+	GetByLoanID(ctx context.Context, loanID int) ([]*models.LoanInstallment, error)
+	Update(ctx context.Context, installment *models.LoanInstallment) error
+}
+
+type loanInstallmentRepositoryImpl struct {
+	base *BaseRepository
+}
+
+func NewLoanInstallmentRepository(driver Driver) LoanInstallmentRepository {
+	return &loanInstallmentRepositoryImpl{
+		base: NewBaseRepository(driver),
+	}
+}
+
+func (r *loanInstallmentRepositoryImpl) Create(ctx context.Context, installment *models.LoanInstallment) error {
+	query := `
+		INSERT INTO loan_installments (
+			loan_id, installment_number, due_date, principal_amount,
+			interest_amount, total_amount, status
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.base.Executor(ctx).QueryRowContext(
+		ctx, query,
+		installment.LoanID, installment.InstallmentNumber, installment.DueDate,
+		installment.PrincipalAmount, installment.InterestAmount,
+		installment.TotalAmount, installment.Status,
+	).Scan(&installment.ID, &installment.CreatedAt, &installment.UpdatedAt)
+
+	return err
+}
+
+func (r *loanInstallmentRepositoryImpl) GetByLoanID(ctx context.Context, loanID int) ([]*models.LoanInstallment, error) {
+	query := `
+		SELECT id, loan_id, installment_number, due_date, principal_amount,
+		       interest_amount, total_amount, penalty_amount, principal_paid,
+		       interest_paid, penalty_paid, status, paid_at, created_at, updated_at
+		FROM loan_installments WHERE loan_id = $1
+		ORDER BY installment_number ASC
+	`
+
+	var installments []*models.LoanInstallment
+	err := r.base.Executor(ctx).SelectContext(ctx, &installments, query, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	return installments, nil
+}
+
+// Update records the payment progress of an installment; the scheduled amounts never change
+func (r *loanInstallmentRepositoryImpl) Update(ctx context.Context, installment *models.LoanInstallment) error {
+	query := `
+		UPDATE loan_installments SET
+			penalty_amount = $1, principal_paid = $2, interest_paid = $3,
+			penalty_paid = $4, status = $5, paid_at = $6, updated_at = NOW()
+		WHERE id = $7
+	`
+
+	result, err := r.base.Executor(ctx).ExecContext(
+		ctx, query,
+		installment.PenaltyAmount, installment.PrincipalPaid, installment.InterestPaid,
+		installment.PenaltyPaid, installment.Status, installment.PaidAt,
+		installment.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrLoanInstallmentNotFound
+	}
+
+	return nil
+}
@@ -3,8 +3,8 @@ package repositories
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
 )
 
 type LoanInvestmentRepository interface {
@@ -15,7 +15,7 @@ type LoanInvestmentRepository interface {
 	GetByLoanAndInvestor(ctx context.Context, loanID, investorID int) (*models.LoanInvestment, error)
 	Update(ctx context.Context, investment *models.LoanInvestment) error
 	Delete(ctx context.Context, id int) error
-	GetTotalInvestedAmountByLoan(ctx context.Context, loanID int) (float64, error)
+	GetTotalInvestedAmountByLoan(ctx context.Context, loanID int) (money.Money, error)
 }
 
 type loanInvestmentRepositoryImpl struct {
@@ -35,12 +35,12 @@ func (r *loanInvestmentRepositoryImpl) Create(ctx context.Context, investment *m
 		RETURNING id, created_at
 	`
 
-	err := r.base.GetUtilDB().QueryRowContext(
+	err := r.base.Executor(ctx).QueryRowContext(
 		ctx, query,
 		investment.LoanID, investment.InvestorID, investment.InvestmentAmount,
 	).Scan(&investment.ID, &investment.CreatedAt)
 
-	return err
+	return translateError(err)
 }
 
 func (r *loanInvestmentRepositoryImpl) GetByID(ctx context.Context, id int) (*models.LoanInvestment, error) {
@@ -50,10 +50,10 @@ func (r *loanInvestmentRepositoryImpl) GetByID(ctx context.Context, id int) (*mo
 	`
 
 	var investment models.LoanInvestment
-	err := r.base.GetUtilDB().GetContext(ctx, &investment, query, id)
+	err := r.base.Executor(ctx).GetContext(ctx, &investment, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("loan investment not found")
+			return nil, ErrLoanInvestmentNotFound
 		}
 		return nil, err
 	}
@@ -69,7 +69,7 @@ func (r *loanInvestmentRepositoryImpl) GetByLoanID(ctx context.Context, loanID i
 	`
 
 	var investments []*models.LoanInvestment
-	err := r.base.GetUtilDB().SelectContext(ctx, &investments, query, loanID)
+	err := r.base.Executor(ctx).SelectContext(ctx, &investments, query, loanID)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +85,7 @@ func (r *loanInvestmentRepositoryImpl) GetByInvestorID(ctx context.Context, inve
 	`
 
 	var investments []*models.LoanInvestment
-	err := r.base.GetUtilDB().SelectContext(ctx, &investments, query, investorID)
+	err := r.base.Executor(ctx).SelectContext(ctx, &investments, query, investorID)
 	if err != nil {
 		return nil, err
 	}
@@ -100,10 +100,10 @@ func (r *loanInvestmentRepositoryImpl) GetByLoanAndInvestor(ctx context.Context,
 	`
 
 	var investment models.LoanInvestment
-	err := r.base.GetUtilDB().GetContext(ctx, &investment, query, loanID, investorID)
+	err := r.base.Executor(ctx).GetContext(ctx, &investment, query, loanID, investorID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("loan investment not found")
+			return nil, ErrLoanInvestmentNotFound
 		}
 		return nil, err
 	}
@@ -118,7 +118,7 @@ func (r *loanInvestmentRepositoryImpl) Update(ctx context.Context, investment *m
 		WHERE id = $2
 	`
 
-	result, err := r.base.GetUtilDB().ExecContext(
+	result, err := r.base.Executor(ctx).ExecContext(
 		ctx, query,
 		investment.InvestmentAmount, investment.ID,
 	)
@@ -133,7 +133,7 @@ func (r *loanInvestmentRepositoryImpl) Update(ctx context.Context, investment *m
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("loan investment not found")
+		return ErrLoanInvestmentNotFound
 	}
 
 	return nil
@@ -141,7 +141,7 @@ func (r *loanInvestmentRepositoryImpl) Update(ctx context.Context, investment *m
 
 func (r *loanInvestmentRepositoryImpl) Delete(ctx context.Context, id int) error {
 	query := "DELETE FROM loan_investments WHERE id = $1"
-	result, err := r.base.GetUtilDB().ExecContext(ctx, query, id)
+	result, err := r.base.Executor(ctx).ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -152,23 +152,23 @@ func (r *loanInvestmentRepositoryImpl) Delete(ctx context.Context, id int) error
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("loan investment not found")
+		return ErrLoanInvestmentNotFound
 	}
 
 	return nil
 }
 
-func (r *loanInvestmentRepositoryImpl) GetTotalInvestedAmountByLoan(ctx context.Context, loanID int) (float64, error) {
+func (r *loanInvestmentRepositoryImpl) GetTotalInvestedAmountByLoan(ctx context.Context, loanID int) (money.Money, error) {
 	query := `
 		SELECT COALESCE(SUM(investment_amount), 0)
 		FROM loan_investments
 		WHERE loan_id = $1
 	`
 
-	var total float64
-	err := r.base.GetUtilDB().GetContext(ctx, &total, query, loanID)
+	var total money.Money
+	err := r.base.Executor(ctx).GetContext(ctx, &total, query, loanID)
 	if err != nil {
-		return 0, err
+		return money.Money{}, err
 	}
 
 	return total, nil
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+type LoanRejectionReasonRepository interface {
+	Create(ctx context.Context, reason *models.LoanRejectionReason) error
+	GetByCode(ctx context.Context, code string) (*models.LoanRejectionReason, error)
+	Update(ctx context.Context, reason *models.LoanRejectionReason) error
+	List(ctx context.Context, activeOnly bool) ([]*models.LoanRejectionReason, error)
+}
+
+type loanRejectionReasonRepositoryImpl struct {
+	base *BaseRepository
+}
+
+func NewLoanRejectionReasonRepository(driver Driver) LoanRejectionReasonRepository {
+	return &loanRejectionReasonRepositoryImpl{
+		base: NewBaseRepository(driver),
+	}
+}
+
+func (r *loanRejectionReasonRepositoryImpl) Create(ctx context.Context, reason *models.LoanRejectionReason) error {
+	query := `
+		INSERT INTO loan_rejection_reasons (code, description, is_active)
+		VALUES ($1, $2, $3)
+		RETURNING created_at, updated_at
+	`
+
+	err := r.base.Executor(ctx).QueryRowContext(
+		ctx, query,
+		reason.Code, reason.Description, reason.IsActive,
+	).Scan(&reason.CreatedAt, &reason.UpdatedAt)
+
+	return translateError(err)
+}
+
+func (r *loanRejectionReasonRepositoryImpl) GetByCode(ctx context.Context, code string) (*models.LoanRejectionReason, error) {
+	query := `
+		SELECT code, description, is_active, created_at, updated_at
+		FROM loan_rejection_reasons WHERE code = $1
+	`
+
+	var reason models.LoanRejectionReason
+	err := r.base.Executor(ctx).GetContext(ctx, &reason, query, code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrRejectionReasonNotFound
+		}
+		return nil, err
+	}
+
+	return &reason, nil
+}
+
+func (r *loanRejectionReasonRepositoryImpl) Update(ctx context.Context, reason *models.LoanRejectionReason) error {
+	query := `
+		UPDATE loan_rejection_reasons SET description = $1, is_active = $2
+		WHERE code = $3
+		RETURNING updated_at
+	`
+
+	err := r.base.Executor(ctx).QueryRowContext(
+		ctx, query,
+		reason.Description, reason.IsActive, reason.Code,
+	).Scan(&reason.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrRejectionReasonNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *loanRejectionReasonRepositoryImpl) List(ctx context.Context, activeOnly bool) ([]*models.LoanRejectionReason, error) {
+	query := "SELECT code, description, is_active, created_at, updated_at FROM loan_rejection_reasons"
+	if activeOnly {
+		query += " WHERE is_active"
+	}
+	query += " ORDER BY code"
+
+	var reasons []*models.LoanRejectionReason
+	err := r.base.Executor(ctx).SelectContext(ctx, &reasons, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return reasons, nil
+}
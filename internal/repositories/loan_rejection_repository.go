@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+type LoanRejectionRepository interface {
+	Create(ctx context.Context, rejection *models.LoanRejection) error
+	GetByLoanID(ctx context.Context, loanID int) (*models.LoanRejection, error)
+}
+
+type loanRejectionRepositoryImpl struct {
+	base *BaseRepository
+}
+
+func NewLoanRejectionRepository(driver Driver) LoanRejectionRepository {
+	return &loanRejectionRepositoryImpl{
+		base: NewBaseRepository(driver),
+	}
+}
+
+func (r *loanRejectionRepositoryImpl) Create(ctx context.Context, rejection *models.LoanRejection) error {
+	query := `
+		INSERT INTO loan_rejections (
+			loan_id, reason_code, notes, rejected_by_employee_id, rejected_at
+		) VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := r.base.Executor(ctx).QueryRowContext(
+		ctx, query,
+		rejection.LoanID, rejection.ReasonCode, rejection.Notes,
+		rejection.RejectedByEmployeeID, rejection.RejectionDate,
+	).Scan(&rejection.ID, &rejection.CreatedAt)
+
+	return err
+}
+
+func (r *loanRejectionRepositoryImpl) GetByLoanID(ctx context.Context, loanID int) (*models.LoanRejection, error) {
+	query := `
+		SELECT id, loan_id, reason_code, notes, rejected_by_employee_id,
+		       rejected_at, created_at
+		FROM loan_rejections WHERE loan_id = $1
+	`
+
+	var rejection models.LoanRejection
+	err := r.base.Executor(ctx).GetContext(ctx, &rejection, query, loanID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrLoanRejectionNotFound
+		}
+		return nil, err
+	}
+
+	return &rejection, nil
+}
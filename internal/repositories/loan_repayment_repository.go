@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+type LoanRepaymentRepository interface {
+	Create(ctx context.Context, repayment *models.LoanRepayment) error
+	GetByLoanID(ctx context.Context, loanID int) ([]*models.LoanRepayment, error)
+}
+
+type loanRepaymentRepositoryImpl struct {
+	base *BaseRepository
+}
+
+func NewLoanRepaymentRepository(driver Driver) LoanRepaymentRepository {
+	return &loanRepaymentRepositoryImpl{
+		base: NewBaseRepository(driver),
+	}
+}
+
+func (r *loanRepaymentRepositoryImpl) Create(ctx context.Context, repayment *models.LoanRepayment) error {
+	query := `
+		INSERT INTO loan_repayments (
+			loan_id, amount, penalty_amount, interest_amount,
+			principal_amount, credit_amount, paid_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	err := r.base.Executor(ctx).QueryRowContext(
+		ctx, query,
+		repayment.LoanID, repayment.Amount, repayment.PenaltyAmount, repayment.InterestAmount,
+		repayment.PrincipalAmount, repayment.CreditAmount, repayment.PaidAt,
+	).Scan(&repayment.ID, &repayment.CreatedAt)
+
+	return err
+}
+
+func (r *loanRepaymentRepositoryImpl) GetByLoanID(ctx context.Context, loanID int) ([]*models.LoanRepayment, error) {
+	query := `
+		SELECT id, loan_id, amount, penalty_amount, interest_amount,
+		       principal_amount, credit_amount, paid_at, created_at
+		FROM loan_repayments WHERE loan_id = $1
+		ORDER BY paid_at ASC, id ASC
+	`
+
+	var repayments []*models.LoanRepayment
+	err := r.base.Executor(ctx).SelectContext(ctx, &repayments, query, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	return repayments, nil
+}
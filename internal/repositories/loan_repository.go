@@ -3,21 +3,25 @@ package repositories
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"strconv"
+	"time"
+
 	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
 )
 
 type LoanRepository interface {
 	Create(ctx context.Context, loan *models.Loan) error
 	GetByID(ctx context.Context, id int) (*models.Loan, error)
+	GetByIDForUpdate(ctx context.Context, id int) (*models.Loan, error)
 	GetByLoanID(ctx context.Context, loanID string) (*models.Loan, error)
 	Update(ctx context.Context, loan *models.Loan) error
 	Delete(ctx context.Context, id int) error
-	List(ctx context.Context, state *string, offset, limit int) ([]*models.Loan, error)
+	List(ctx context.Context, filter models.LoanFilter, page models.PageRequest) (*models.Page[*models.Loan], error)
 	UpdateState(ctx context.Context, id int, newState string) error
-	UpdateTotalInvestedAmount(ctx context.Context, loanID int, amount float64) error
+	UpdateTotalInvestedAmount(ctx context.Context, loanID int, amount money.Money) error
 	GetByState(ctx context.Context, state string) ([]*models.Loan, error)
-	GetTotalInvestedAmount(ctx context.Context, loanID int) (float64, error)
+	GetTotalInvestedAmount(ctx context.Context, loanID int) (money.Money, error)
 }
 
 type loanRepositoryImpl struct {
@@ -34,40 +38,66 @@ func (r *loanRepositoryImpl) Create(ctx context.Context, loan *models.Loan) erro
 	query := `
 		INSERT INTO loans (
 			borrower_id, principal_amount, rate, roi,
-			agreement_letter_link, current_state, total_invested_amount
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			agreement_letter_link, current_state, total_invested_amount,
+			tenor_months, repayment_method
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id, created_at, updated_at
 	`
 
-	err := r.base.GetUtilDB().QueryRowContext(
+	err := r.base.Executor(ctx).QueryRowContext(
 		ctx, query,
 		loan.BorrowerID, loan.PrincipalAmount,
 		loan.Rate, loan.ROI, loan.AgreementLetterLink,
 		loan.CurrentState, loan.TotalInvestedAmount,
+		loan.TenorMonths, loan.RepaymentMethod,
 	).Scan(&loan.ID, &loan.CreatedAt, &loan.UpdatedAt)
 
 	// After creation, fetch the generated loan_id
 	if err == nil {
 		fetchQuery := "SELECT loan_id FROM loans WHERE id = $1"
-		err = r.base.GetUtilDB().GetContext(ctx, &loan.LoanID, fetchQuery, loan.ID)
+		err = r.base.Executor(ctx).GetContext(ctx, &loan.LoanID, fetchQuery, loan.ID)
 	}
 
-	return err
+	return translateError(err)
 }
 
 func (r *loanRepositoryImpl) GetByID(ctx context.Context, id int) (*models.Loan, error) {
 	query := `
 		SELECT id, loan_id, borrower_id, principal_amount, rate, roi,
 		       agreement_letter_link, current_state, total_invested_amount,
-		       created_at, updated_at
+		       tenor_months, repayment_method, created_at, updated_at
+		FROM loans WHERE id = $1
+	`
+
+	var loan models.Loan
+	err := r.base.Executor(ctx).GetContext(ctx, &loan, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrLoanNotFound
+		}
+		return nil, err
+	}
+
+	return &loan, nil
+}
+
+// GetByIDForUpdate loads a loan and locks its row until the surrounding unit of work
+// commits or rolls back, so concurrent writers to the same loan are serialized.
+// Outside a unit of work the lock is released as soon as the query returns.
+func (r *loanRepositoryImpl) GetByIDForUpdate(ctx context.Context, id int) (*models.Loan, error) {
+	query := `
+		SELECT id, loan_id, borrower_id, principal_amount, rate, roi,
+		       agreement_letter_link, current_state, total_invested_amount,
+		       tenor_months, repayment_method, created_at, updated_at
 		FROM loans WHERE id = $1
+		FOR UPDATE
 	`
 
 	var loan models.Loan
-	err := r.base.GetUtilDB().GetContext(ctx, &loan, query, id)
+	err := r.base.Executor(ctx).GetContext(ctx, &loan, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("loan not found")
+			return nil, ErrLoanNotFound
 		}
 		return nil, err
 	}
@@ -79,15 +109,15 @@ func (r *loanRepositoryImpl) GetByLoanID(ctx context.Context, loanID string) (*m
 	query := `
 		SELECT id, loan_id, borrower_id, principal_amount, rate, roi,
 		       agreement_letter_link, current_state, total_invested_amount,
-		       created_at, updated_at
+		       tenor_months, repayment_method, created_at, updated_at
 		FROM loans WHERE loan_id = $1
 	`
 
 	var loan models.Loan
-	err := r.base.GetUtilDB().GetContext(ctx, &loan, query, loanID)
+	err := r.base.Executor(ctx).GetContext(ctx, &loan, query, loanID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("loan not found")
+			return nil, ErrLoanNotFound
 		}
 		return nil, err
 	}
@@ -99,20 +129,21 @@ func (r *loanRepositoryImpl) Update(ctx context.Context, loan *models.Loan) erro
 	query := `
 		UPDATE loans SET
 			borrower_id = $1, principal_amount = $2, rate = $3, roi = $4,
-			agreement_letter_link = $5, current_state = $6,
-			total_invested_amount = $7, updated_at = NOW()
-		WHERE id = $8
+			agreement_letter_link = $5, total_invested_amount = $6,
+			tenor_months = $7, repayment_method = $8, updated_at = NOW()
+		WHERE id = $9
 	`
 
-	result, err := r.base.GetUtilDB().ExecContext(
+	// The state only changes through UpdateState, which the state trigger checks
+	result, err := r.base.Executor(ctx).ExecContext(
 		ctx, query,
 		loan.BorrowerID, loan.PrincipalAmount, loan.Rate, loan.ROI,
-		loan.AgreementLetterLink, loan.CurrentState, loan.TotalInvestedAmount,
-		loan.ID,
+		loan.AgreementLetterLink, loan.TotalInvestedAmount,
+		loan.TenorMonths, loan.RepaymentMethod, loan.ID,
 	)
 
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -121,7 +152,7 @@ func (r *loanRepositoryImpl) Update(ctx context.Context, loan *models.Loan) erro
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("loan not found")
+		return ErrLoanNotFound
 	}
 
 	return nil
@@ -129,9 +160,9 @@ func (r *loanRepositoryImpl) Update(ctx context.Context, loan *models.Loan) erro
 
 func (r *loanRepositoryImpl) Delete(ctx context.Context, id int) error {
 	query := "DELETE FROM loans WHERE id = $1"
-	result, err := r.base.GetUtilDB().ExecContext(ctx, query, id)
+	result, err := r.base.Executor(ctx).ExecContext(ctx, query, id)
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -140,38 +171,70 @@ func (r *loanRepositoryImpl) Delete(ctx context.Context, id int) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("loan not found")
+		return ErrLoanNotFound
 	}
 
 	return nil
 }
 
-func (r *loanRepositoryImpl) List(ctx context.Context, state *string, offset, limit int) ([]*models.Loan, error) {
-	query := "SELECT id, loan_id, borrower_id, principal_amount, rate, roi, agreement_letter_link, current_state, total_invested_amount, created_at, updated_at FROM loans"
-	args := []interface{}{}
-	paramIndex := 1
-
-	if state != nil {
-		query += fmt.Sprintf(" WHERE current_state = $%d", paramIndex)
-		args = append(args, *state)
-		paramIndex++
-	}
+// loanSortKeys are the fields loans can be sorted by
+var loanSortKeys = map[string]sortKey[*models.Loan]{
+	"id":                    {"id", "integer", func(l *models.Loan) string { return strconv.Itoa(l.ID) }},
+	"created_at":            {"created_at", "timestamptz", func(l *models.Loan) string { return l.CreatedAt.Format(time.RFC3339Nano) }},
+	"principal_amount":      {"principal_amount", "numeric", func(l *models.Loan) string { return l.PrincipalAmount.String() }},
+	"rate":                  {"rate", "numeric", func(l *models.Loan) string { return l.Rate.String() }},
+	"roi":                   {"roi", "numeric", func(l *models.Loan) string { return l.ROI.String() }},
+	"total_invested_amount": {"total_invested_amount", "numeric", func(l *models.Loan) string { return l.TotalInvestedAmount.String() }},
+}
 
-	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", paramIndex, paramIndex+1)
-	args = append(args, limit, offset)
+// defaultLoanSort lists the newest loans first
+var defaultLoanSort = []models.SortField{{Field: "created_at", Descending: true}}
 
-	var loans []*models.Loan
-	err := r.base.GetUtilDB().SelectContext(ctx, &loans, query, args...)
+// List returns the page of loans matching filter that page asks for
+func (r *loanRepositoryImpl) List(ctx context.Context, filter models.LoanFilter, page models.PageRequest) (*models.Page[*models.Loan], error) {
+	q, err := newKeysetQuery(loanSortKeys, page.Sort, defaultLoanSort)
 	if err != nil {
 		return nil, err
 	}
 
-	return loans, nil
+	if filter.State != "" {
+		q.where("current_state = %s", filter.State)
+	}
+	if filter.BorrowerID != 0 {
+		q.where("borrower_id = %s", filter.BorrowerID)
+	}
+	if filter.MinPrincipal != nil {
+		q.where("principal_amount >= %s", *filter.MinPrincipal)
+	}
+	if filter.MaxPrincipal != nil {
+		q.where("principal_amount <= %s", *filter.MaxPrincipal)
+	}
+	if filter.MinRate != nil {
+		q.where("rate >= %s", *filter.MinRate)
+	}
+	if filter.MaxRate != nil {
+		q.where("rate <= %s", *filter.MaxRate)
+	}
+	if filter.CreatedFrom != nil {
+		q.where("created_at >= %s", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		q.where("created_at <= %s", *filter.CreatedTo)
+	}
+	switch filter.Funding {
+	case models.FundingFull:
+		q.where("total_invested_amount >= principal_amount")
+	case models.FundingPartial:
+		q.where("total_invested_amount > 0 AND total_invested_amount < principal_amount")
+	}
+
+	columns := "id, loan_id, borrower_id, principal_amount, rate, roi, agreement_letter_link, current_state, total_invested_amount, tenor_months, repayment_method, created_at, updated_at"
+	return q.fetch(ctx, r.base.Executor(ctx), columns, "loans", page)
 }
 
 func (r *loanRepositoryImpl) UpdateState(ctx context.Context, id int, newState string) error {
 	query := "UPDATE loans SET current_state = $1, updated_at = NOW() WHERE id = $2"
-	result, err := r.base.GetUtilDB().ExecContext(ctx, query, newState, id)
+	result, err := r.base.Executor(ctx).ExecContext(ctx, query, newState, id)
 	if err != nil {
 		return err
 	}
@@ -182,15 +245,15 @@ func (r *loanRepositoryImpl) UpdateState(ctx context.Context, id int, newState s
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("loan not found")
+		return ErrLoanNotFound
 	}
 
 	return nil
 }
 
-func (r *loanRepositoryImpl) UpdateTotalInvestedAmount(ctx context.Context, loanID int, amount float64) error {
+func (r *loanRepositoryImpl) UpdateTotalInvestedAmount(ctx context.Context, loanID int, amount money.Money) error {
 	query := "UPDATE loans SET total_invested_amount = $1, updated_at = NOW() WHERE id = $2"
-	result, err := r.base.GetUtilDB().ExecContext(ctx, query, amount, loanID)
+	result, err := r.base.Executor(ctx).ExecContext(ctx, query, amount, loanID)
 	if err != nil {
 		return err
 	}
@@ -201,17 +264,17 @@ func (r *loanRepositoryImpl) UpdateTotalInvestedAmount(ctx context.Context, loan
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("loan not found")
+		return ErrLoanNotFound
 	}
 
 	return nil
 }
 
 func (r *loanRepositoryImpl) GetByState(ctx context.Context, state string) ([]*models.Loan, error) {
-	query := "SELECT id, loan_id, borrower_id, principal_amount, rate, roi, agreement_letter_link, current_state, total_invested_amount, created_at, updated_at FROM loans WHERE current_state = $1 ORDER BY created_at DESC"
+	query := "SELECT id, loan_id, borrower_id, principal_amount, rate, roi, agreement_letter_link, current_state, total_invested_amount, tenor_months, repayment_method, created_at, updated_at FROM loans WHERE current_state = $1 ORDER BY created_at DESC"
 
 	var loans []*models.Loan
-	err := r.base.GetUtilDB().SelectContext(ctx, &loans, query, state)
+	err := r.base.Executor(ctx).SelectContext(ctx, &loans, query, state)
 	if err != nil {
 		return nil, err
 	}
@@ -219,16 +282,16 @@ func (r *loanRepositoryImpl) GetByState(ctx context.Context, state string) ([]*m
 	return loans, nil
 }
 
-func (r *loanRepositoryImpl) GetTotalInvestedAmount(ctx context.Context, loanID int) (float64, error) {
+func (r *loanRepositoryImpl) GetTotalInvestedAmount(ctx context.Context, loanID int) (money.Money, error) {
 	query := "SELECT total_invested_amount FROM loans WHERE id = $1"
 
-	var amount float64
-	err := r.base.GetUtilDB().GetContext(ctx, &amount, query, loanID)
+	var amount money.Money
+	err := r.base.Executor(ctx).GetContext(ctx, &amount, query, loanID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return 0, fmt.Errorf("loan not found")
+			return money.Money{}, ErrLoanNotFound
 		}
-		return 0, err
+		return money.Money{}, err
 	}
 
 	return amount, nil
@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLoan stores a proposed loan for borrowerID
+func newTestLoan(t *testing.T, loanRepo LoanRepository, borrowerID int) *models.Loan {
+	loan := &models.Loan{
+		BorrowerID:          borrowerID,
+		PrincipalAmount:     money.MustParse("1000.00"),
+		Rate:                money.MustParseRate("0.10"),
+		ROI:                 money.MustParseRate("0.08"),
+		CurrentState:        "proposed",
+		TotalInvestedAmount: money.MustParse("0.00"),
+		TenorMonths:         12,
+		RepaymentMethod:     "flat",
+	}
+	require.NoError(t, loanRepo.Create(context.Background(), loan))
+	return loan
+}
+
+func TestLoanStateTransitionIntegration(t *testing.T) {
+	driver := newMigratedDriver(t)
+	loanRepo := NewLoanRepository(driver)
+	historyRepo := NewLoanStateHistoryRepository(driver)
+	ctx := context.Background()
+
+	borrower := &models.Borrower{BorrowerIDNumber: "3171000000000001", FullName: "Test Borrower", Email: "borrower@example.com"}
+	require.NoError(t, NewBorrowerRepository(driver).Create(ctx, borrower))
+
+	loan := newTestLoan(t, loanRepo, borrower.ID)
+
+	t.Run("one transition records exactly one history row", func(t *testing.T) {
+		// The same two writes the loan service makes for a transition
+		require.NoError(t, loanRepo.UpdateState(ctx, loan.ID, "approved"))
+		require.NoError(t, historyRepo.Create(ctx, &models.LoanStateHistory{
+			LoanID:           loan.ID,
+			PreviousState:    "proposed",
+			NewState:         "approved",
+			TransitionReason: "Loan approved",
+			ChangedBy:        "staff@example.com",
+		}))
+
+		history, err := historyRepo.GetByLoanID(ctx, loan.ID)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, "staff@example.com", history[0].ChangedBy)
+		assert.Equal(t, "approved", history[0].NewState)
+	})
+
+	t.Run("the trigger still rejects a transition the table does not allow", func(t *testing.T) {
+		assert.Error(t, loanRepo.UpdateState(ctx, loan.ID, "closed"))
+	})
+
+	t.Run("the trigger rejects moving a loan to the state it is already in", func(t *testing.T) {
+		assert.Error(t, loanRepo.UpdateState(ctx, loan.ID, "approved"))
+	})
+
+	t.Run("concurrent approvals of one loan record a single transition", func(t *testing.T) {
+		concurrent := newTestLoan(t, loanRepo, borrower.ID)
+		unitOfWork := NewUnitOfWork(driver)
+
+		// Each approval makes the writes the loan service makes, after the same locked read
+		approve := func(actor string) error {
+			return unitOfWork.Do(ctx, func(ctx context.Context) error {
+				loan, err := loanRepo.GetByIDForUpdate(ctx, concurrent.ID)
+				if err != nil {
+					return err
+				}
+				if loan.CurrentState != "proposed" {
+					return fmt.Errorf("loan is %s", loan.CurrentState)
+				}
+
+				// Hold the lock long enough for the other approval to queue behind it
+				time.Sleep(100 * time.Millisecond)
+
+				if err := loanRepo.UpdateState(ctx, loan.ID, "approved"); err != nil {
+					return err
+				}
+				return historyRepo.Create(ctx, &models.LoanStateHistory{
+					LoanID:           loan.ID,
+					PreviousState:    loan.CurrentState,
+					NewState:         "approved",
+					TransitionReason: "Loan approved",
+					ChangedBy:        actor,
+				})
+			})
+		}
+
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = approve(fmt.Sprintf("validator%d@example.com", i))
+			}(i)
+		}
+		wg.Wait()
+
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+			}
+		}
+		assert.Equal(t, 1, succeeded, "errors: %v", errs)
+
+		history, err := historyRepo.GetByLoanID(ctx, concurrent.ID)
+		require.NoError(t, err)
+		assert.Len(t, history, 1)
+	})
+}
+
+func TestConcurrentInvestmentsIntegration(t *testing.T) {
+	driver := newMigratedDriver(t)
+	loanRepo := NewLoanRepository(driver)
+	investorRepo := NewInvestorRepository(driver)
+	investmentRepo := NewLoanInvestmentRepository(driver)
+	unitOfWork := NewUnitOfWork(driver)
+	ctx := context.Background()
+
+	borrower := &models.Borrower{BorrowerIDNumber: "3171000000000002", FullName: "Race Borrower", Email: "race.borrower@example.com"}
+	require.NoError(t, NewBorrowerRepository(driver).Create(ctx, borrower))
+
+	loan := newTestLoan(t, loanRepo, borrower.ID)
+	require.NoError(t, loanRepo.UpdateState(ctx, loan.ID, "approved"))
+
+	// The loan has room for exactly fundingSlots investments, but every investor tries at once
+	const investorCount = 30
+	const fundingSlots = 10
+	investmentAmount := money.MustParse("100.00")
+
+	investorIDs := make([]int, investorCount)
+	for i := range investorIDs {
+		investor := &models.Investor{
+			InvestorID: fmt.Sprintf("RACE%03d", i),
+			FullName:   fmt.Sprintf("Race Investor %d", i),
+			Email:      fmt.Sprintf("race.investor%d@example.com", i),
+		}
+		require.NoError(t, investorRepo.Create(ctx, investor))
+		investorIDs[i] = investor.ID
+	}
+
+	// Each investment makes the checks and writes the loan service makes, after the same locked read
+	invest := func(investorID int) error {
+		return unitOfWork.Do(ctx, func(ctx context.Context) error {
+			current, err := loanRepo.GetByIDForUpdate(ctx, loan.ID)
+			if err != nil {
+				return err
+			}
+
+			remaining := current.PrincipalAmount.Sub(current.TotalInvestedAmount)
+			if investmentAmount.GreaterThan(remaining) {
+				return fmt.Errorf("only %s remaining", remaining)
+			}
+
+			err = investmentRepo.Create(ctx, &models.LoanInvestment{LoanID: current.ID, InvestorID: investorID, InvestmentAmount: investmentAmount})
+			if err != nil {
+				return err
+			}
+			return loanRepo.UpdateTotalInvestedAmount(ctx, current.ID, current.TotalInvestedAmount.Add(investmentAmount))
+		})
+	}
+
+	errs := make([]error, investorCount)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i, investorID := range investorIDs {
+		wg.Add(1)
+		go func(i, investorID int) {
+			defer wg.Done()
+			<-start
+			errs[i] = invest(investorID)
+		}(i, investorID)
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		}
+	}
+	assert.Equal(t, fundingSlots, succeeded)
+
+	funded, err := loanRepo.GetByID(ctx, loan.ID)
+	require.NoError(t, err)
+	assert.Equal(t, loan.PrincipalAmount, funded.TotalInvestedAmount)
+
+	// The counter on the loan must agree with the investment rows that were actually written
+	investments, err := investmentRepo.GetByLoanID(ctx, loan.ID)
+	require.NoError(t, err)
+	assert.Len(t, investments, fundingSlots)
+
+	investedSum := money.MustParse("0.00")
+	for _, investment := range investments {
+		investedSum = investedSum.Add(investment.InvestmentAmount)
+	}
+	assert.Equal(t, loan.PrincipalAmount, investedSum)
+}
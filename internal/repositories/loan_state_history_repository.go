@@ -3,7 +3,6 @@ package repositories
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"github.com/sswastioyono18/loan-engine/internal/models"
 )
 
@@ -27,14 +26,14 @@ func NewLoanStateHistoryRepository(driver Driver) LoanStateHistoryRepository {
 func (r *loanStateHistoryRepositoryImpl) Create(ctx context.Context, history *models.LoanStateHistory) error {
 	query := `
 		INSERT INTO loan_state_history (
-			loan_id, old_state, new_state, reason
-		) VALUES ($1, $2, $3, $4)
+			loan_id, old_state, new_state, reason, changed_by
+		) VALUES ($1, $2, $3, $4, $5)
 		RETURNING id, created_at
 	`
 
-	err := r.base.GetUtilDB().QueryRowContext(
+	err := r.base.Executor(ctx).QueryRowContext(
 		ctx, query,
-		history.LoanID, history.PreviousState, history.NewState, history.TransitionReason,
+		history.LoanID, history.PreviousState, history.NewState, history.TransitionReason, history.ChangedBy,
 	).Scan(&history.ID, &history.CreatedAt)
 
 	return err
@@ -42,13 +41,13 @@ func (r *loanStateHistoryRepositoryImpl) Create(ctx context.Context, history *mo
 
 func (r *loanStateHistoryRepositoryImpl) GetByLoanID(ctx context.Context, loanID int) ([]*models.LoanStateHistory, error) {
 	query := `
-		SELECT id, loan_id, old_state, new_state, reason, created_at
+		SELECT id, loan_id, old_state, new_state, reason, COALESCE(changed_by, '') AS changed_by, created_at
 		FROM loan_state_history WHERE loan_id = $1
 		ORDER BY created_at ASC
 	`
 
 	var histories []*models.LoanStateHistory
-	err := r.base.GetUtilDB().SelectContext(ctx, &histories, query, loanID)
+	err := r.base.Executor(ctx).SelectContext(ctx, &histories, query, loanID)
 	if err != nil {
 		return nil, err
 	}
@@ -58,7 +57,7 @@ func (r *loanStateHistoryRepositoryImpl) GetByLoanID(ctx context.Context, loanID
 
 func (r *loanStateHistoryRepositoryImpl) GetLatestByLoanID(ctx context.Context, loanID int) (*models.LoanStateHistory, error) {
 	query := `
-		SELECT id, loan_id, old_state, new_state, reason, created_at
+		SELECT id, loan_id, old_state, new_state, reason, COALESCE(changed_by, '') AS changed_by, created_at
 		FROM loan_state_history
 		WHERE loan_id = $1
 		ORDER BY created_at DESC
@@ -66,10 +65,10 @@ func (r *loanStateHistoryRepositoryImpl) GetLatestByLoanID(ctx context.Context,
 	`
 
 	var history models.LoanStateHistory
-	err := r.base.GetUtilDB().GetContext(ctx, &history, query, loanID)
+	err := r.base.Executor(ctx).GetContext(ctx, &history, query, loanID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("no state history found for loan")
+			return nil, ErrLoanStateHistoryNotFound
 		}
 		return nil, err
 	}
@@ -79,7 +78,7 @@ func (r *loanStateHistoryRepositoryImpl) GetLatestByLoanID(ctx context.Context,
 
 func (r *loanStateHistoryRepositoryImpl) List(ctx context.Context, loanID int, offset, limit int) ([]*models.LoanStateHistory, error) {
 	query := `
-		SELECT id, loan_id, old_state, new_state, reason, created_at
+		SELECT id, loan_id, old_state, new_state, reason, COALESCE(changed_by, '') AS changed_by, created_at
 		FROM loan_state_history
 		WHERE loan_id = $1
 		ORDER BY created_at DESC
@@ -87,7 +86,7 @@ func (r *loanStateHistoryRepositoryImpl) List(ctx context.Context, loanID int, o
 	`
 
 	var histories []*models.LoanStateHistory
-	err := r.base.GetUtilDB().SelectContext(ctx, &histories, query, loanID, limit, offset)
+	err := r.base.Executor(ctx).SelectContext(ctx, &histories, query, loanID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
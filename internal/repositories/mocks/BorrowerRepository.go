@@ -8,6 +8,7 @@ import (
 	"context"
 
 	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -38,6 +39,69 @@ func (_m *BorrowerRepository) EXPECT() *BorrowerRepository_Expecter {
 	return &BorrowerRepository_Expecter{mock: &_m.Mock}
 }
 
+// AddCreditBalance provides a mock function for the type BorrowerRepository
+func (_mock *BorrowerRepository) AddCreditBalance(ctx context.Context, id int, amount money.Money) error {
+	ret := _mock.Called(ctx, id, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddCreditBalance")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, money.Money) error); ok {
+		r0 = returnFunc(ctx, id, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// BorrowerRepository_AddCreditBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddCreditBalance'
+type BorrowerRepository_AddCreditBalance_Call struct {
+	*mock.Call
+}
+
+// AddCreditBalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - amount money.Money
+func (_e *BorrowerRepository_Expecter) AddCreditBalance(ctx interface{}, id interface{}, amount interface{}) *BorrowerRepository_AddCreditBalance_Call {
+	return &BorrowerRepository_AddCreditBalance_Call{Call: _e.mock.On("AddCreditBalance", ctx, id, amount)}
+}
+
+func (_c *BorrowerRepository_AddCreditBalance_Call) Run(run func(ctx context.Context, id int, amount money.Money)) *BorrowerRepository_AddCreditBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 money.Money
+		if args[2] != nil {
+			arg2 = args[2].(money.Money)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *BorrowerRepository_AddCreditBalance_Call) Return(err error) *BorrowerRepository_AddCreditBalance_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *BorrowerRepository_AddCreditBalance_Call) RunAndReturn(run func(ctx context.Context, id int, amount money.Money) error) *BorrowerRepository_AddCreditBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function for the type BorrowerRepository
 func (_mock *BorrowerRepository) Create(ctx context.Context, borrower *models.Borrower) error {
 	ret := _mock.Called(ctx, borrower)
@@ -289,27 +353,27 @@ func (_c *BorrowerRepository_GetByID_Call) RunAndReturn(run func(ctx context.Con
 }
 
 // List provides a mock function for the type BorrowerRepository
-func (_mock *BorrowerRepository) List(ctx context.Context, offset int, limit int) ([]*models.Borrower, error) {
-	ret := _mock.Called(ctx, offset, limit)
+func (_mock *BorrowerRepository) List(ctx context.Context, page models.PageRequest) (*models.Page[*models.Borrower], error) {
+	ret := _mock.Called(ctx, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
 	}
 
-	var r0 []*models.Borrower
+	var r0 *models.Page[*models.Borrower]
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]*models.Borrower, error)); ok {
-		return returnFunc(ctx, offset, limit)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PageRequest) (*models.Page[*models.Borrower], error)); ok {
+		return returnFunc(ctx, page)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []*models.Borrower); ok {
-		r0 = returnFunc(ctx, offset, limit)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PageRequest) *models.Page[*models.Borrower]); ok {
+		r0 = returnFunc(ctx, page)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Borrower)
+			r0 = ret.Get(0).(*models.Page[*models.Borrower])
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
-		r1 = returnFunc(ctx, offset, limit)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.PageRequest) error); ok {
+		r1 = returnFunc(ctx, page)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -323,41 +387,35 @@ type BorrowerRepository_List_Call struct {
 
 // List is a helper method to define mock.On call
 //   - ctx context.Context
-//   - offset int
-//   - limit int
-func (_e *BorrowerRepository_Expecter) List(ctx interface{}, offset interface{}, limit interface{}) *BorrowerRepository_List_Call {
-	return &BorrowerRepository_List_Call{Call: _e.mock.On("List", ctx, offset, limit)}
+//   - page models.PageRequest
+func (_e *BorrowerRepository_Expecter) List(ctx interface{}, page interface{}) *BorrowerRepository_List_Call {
+	return &BorrowerRepository_List_Call{Call: _e.mock.On("List", ctx, page)}
 }
 
-func (_c *BorrowerRepository_List_Call) Run(run func(ctx context.Context, offset int, limit int)) *BorrowerRepository_List_Call {
+func (_c *BorrowerRepository_List_Call) Run(run func(ctx context.Context, page models.PageRequest)) *BorrowerRepository_List_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 int
+		var arg1 models.PageRequest
 		if args[1] != nil {
-			arg1 = args[1].(int)
-		}
-		var arg2 int
-		if args[2] != nil {
-			arg2 = args[2].(int)
+			arg1 = args[1].(models.PageRequest)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *BorrowerRepository_List_Call) Return(borrowers []*models.Borrower, err error) *BorrowerRepository_List_Call {
-	_c.Call.Return(borrowers, err)
+func (_c *BorrowerRepository_List_Call) Return(v *models.Page[*models.Borrower], err error) *BorrowerRepository_List_Call {
+	_c.Call.Return(v, err)
 	return _c
 }
 
-func (_c *BorrowerRepository_List_Call) RunAndReturn(run func(ctx context.Context, offset int, limit int) ([]*models.Borrower, error)) *BorrowerRepository_List_Call {
+func (_c *BorrowerRepository_List_Call) RunAndReturn(run func(ctx context.Context, page models.PageRequest) (*models.Page[*models.Borrower], error)) *BorrowerRepository_List_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -357,27 +357,27 @@ func (_c *InvestorRepository_GetByInvestorID_Call) RunAndReturn(run func(ctx con
 }
 
 // List provides a mock function for the type InvestorRepository
-func (_mock *InvestorRepository) List(ctx context.Context, offset int, limit int) ([]*models.Investor, error) {
-	ret := _mock.Called(ctx, offset, limit)
+func (_mock *InvestorRepository) List(ctx context.Context, page models.PageRequest) (*models.Page[*models.Investor], error) {
+	ret := _mock.Called(ctx, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
 	}
 
-	var r0 []*models.Investor
+	var r0 *models.Page[*models.Investor]
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]*models.Investor, error)); ok {
-		return returnFunc(ctx, offset, limit)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PageRequest) (*models.Page[*models.Investor], error)); ok {
+		return returnFunc(ctx, page)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []*models.Investor); ok {
-		r0 = returnFunc(ctx, offset, limit)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PageRequest) *models.Page[*models.Investor]); ok {
+		r0 = returnFunc(ctx, page)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Investor)
+			r0 = ret.Get(0).(*models.Page[*models.Investor])
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
-		r1 = returnFunc(ctx, offset, limit)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.PageRequest) error); ok {
+		r1 = returnFunc(ctx, page)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -391,41 +391,35 @@ type InvestorRepository_List_Call struct {
 
 // List is a helper method to define mock.On call
 //   - ctx context.Context
-//   - offset int
-//   - limit int
-func (_e *InvestorRepository_Expecter) List(ctx interface{}, offset interface{}, limit interface{}) *InvestorRepository_List_Call {
-	return &InvestorRepository_List_Call{Call: _e.mock.On("List", ctx, offset, limit)}
+//   - page models.PageRequest
+func (_e *InvestorRepository_Expecter) List(ctx interface{}, page interface{}) *InvestorRepository_List_Call {
+	return &InvestorRepository_List_Call{Call: _e.mock.On("List", ctx, page)}
 }
 
-func (_c *InvestorRepository_List_Call) Run(run func(ctx context.Context, offset int, limit int)) *InvestorRepository_List_Call {
+func (_c *InvestorRepository_List_Call) Run(run func(ctx context.Context, page models.PageRequest)) *InvestorRepository_List_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 int
+		var arg1 models.PageRequest
 		if args[1] != nil {
-			arg1 = args[1].(int)
-		}
-		var arg2 int
-		if args[2] != nil {
-			arg2 = args[2].(int)
+			arg1 = args[1].(models.PageRequest)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *InvestorRepository_List_Call) Return(investors []*models.Investor, err error) *InvestorRepository_List_Call {
-	_c.Call.Return(investors, err)
+func (_c *InvestorRepository_List_Call) Return(v *models.Page[*models.Investor], err error) *InvestorRepository_List_Call {
+	_c.Call.Return(v, err)
 	return _c
 }
 
-func (_c *InvestorRepository_List_Call) RunAndReturn(run func(ctx context.Context, offset int, limit int) ([]*models.Investor, error)) *InvestorRepository_List_Call {
+func (_c *InvestorRepository_List_Call) RunAndReturn(run func(ctx context.Context, page models.PageRequest) (*models.Page[*models.Investor], error)) *InvestorRepository_List_Call {
 	_c.Call.Return(run)
 	return _c
 }
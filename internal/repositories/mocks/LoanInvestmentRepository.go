@@ -8,6 +8,7 @@ import (
 	"context"
 
 	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -431,22 +432,22 @@ func (_c *LoanInvestmentRepository_GetByLoanID_Call) RunAndReturn(run func(ctx c
 }
 
 // GetTotalInvestedAmountByLoan provides a mock function for the type LoanInvestmentRepository
-func (_mock *LoanInvestmentRepository) GetTotalInvestedAmountByLoan(ctx context.Context, loanID int) (float64, error) {
+func (_mock *LoanInvestmentRepository) GetTotalInvestedAmountByLoan(ctx context.Context, loanID int) (money.Money, error) {
 	ret := _mock.Called(ctx, loanID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetTotalInvestedAmountByLoan")
 	}
 
-	var r0 float64
+	var r0 money.Money
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (float64, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (money.Money, error)); ok {
 		return returnFunc(ctx, loanID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) float64); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) money.Money); ok {
 		r0 = returnFunc(ctx, loanID)
 	} else {
-		r0 = ret.Get(0).(float64)
+		r0 = ret.Get(0).(money.Money)
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
 		r1 = returnFunc(ctx, loanID)
@@ -486,12 +487,12 @@ func (_c *LoanInvestmentRepository_GetTotalInvestedAmountByLoan_Call) Run(run fu
 	return _c
 }
 
-func (_c *LoanInvestmentRepository_GetTotalInvestedAmountByLoan_Call) Return(f float64, err error) *LoanInvestmentRepository_GetTotalInvestedAmountByLoan_Call {
-	_c.Call.Return(f, err)
+func (_c *LoanInvestmentRepository_GetTotalInvestedAmountByLoan_Call) Return(money money.Money, err error) *LoanInvestmentRepository_GetTotalInvestedAmountByLoan_Call {
+	_c.Call.Return(money, err)
 	return _c
 }
 
-func (_c *LoanInvestmentRepository_GetTotalInvestedAmountByLoan_Call) RunAndReturn(run func(ctx context.Context, loanID int) (float64, error)) *LoanInvestmentRepository_GetTotalInvestedAmountByLoan_Call {
+func (_c *LoanInvestmentRepository_GetTotalInvestedAmountByLoan_Call) RunAndReturn(run func(ctx context.Context, loanID int) (money.Money, error)) *LoanInvestmentRepository_GetTotalInvestedAmountByLoan_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -8,6 +8,7 @@ import (
 	"context"
 
 	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -220,6 +221,74 @@ func (_c *LoanRepository_GetByID_Call) RunAndReturn(run func(ctx context.Context
 	return _c
 }
 
+// GetByIDForUpdate provides a mock function for the type LoanRepository
+func (_mock *LoanRepository) GetByIDForUpdate(ctx context.Context, id int) (*models.Loan, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIDForUpdate")
+	}
+
+	var r0 *models.Loan
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.Loan, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.Loan); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Loan)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// LoanRepository_GetByIDForUpdate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByIDForUpdate'
+type LoanRepository_GetByIDForUpdate_Call struct {
+	*mock.Call
+}
+
+// GetByIDForUpdate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *LoanRepository_Expecter) GetByIDForUpdate(ctx interface{}, id interface{}) *LoanRepository_GetByIDForUpdate_Call {
+	return &LoanRepository_GetByIDForUpdate_Call{Call: _e.mock.On("GetByIDForUpdate", ctx, id)}
+}
+
+func (_c *LoanRepository_GetByIDForUpdate_Call) Run(run func(ctx context.Context, id int)) *LoanRepository_GetByIDForUpdate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *LoanRepository_GetByIDForUpdate_Call) Return(loan *models.Loan, err error) *LoanRepository_GetByIDForUpdate_Call {
+	_c.Call.Return(loan, err)
+	return _c
+}
+
+func (_c *LoanRepository_GetByIDForUpdate_Call) RunAndReturn(run func(ctx context.Context, id int) (*models.Loan, error)) *LoanRepository_GetByIDForUpdate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetByLoanID provides a mock function for the type LoanRepository
 func (_mock *LoanRepository) GetByLoanID(ctx context.Context, loanID string) (*models.Loan, error) {
 	ret := _mock.Called(ctx, loanID)
@@ -357,22 +426,22 @@ func (_c *LoanRepository_GetByState_Call) RunAndReturn(run func(ctx context.Cont
 }
 
 // GetTotalInvestedAmount provides a mock function for the type LoanRepository
-func (_mock *LoanRepository) GetTotalInvestedAmount(ctx context.Context, loanID int) (float64, error) {
+func (_mock *LoanRepository) GetTotalInvestedAmount(ctx context.Context, loanID int) (money.Money, error) {
 	ret := _mock.Called(ctx, loanID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetTotalInvestedAmount")
 	}
 
-	var r0 float64
+	var r0 money.Money
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (float64, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (money.Money, error)); ok {
 		return returnFunc(ctx, loanID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) float64); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) money.Money); ok {
 		r0 = returnFunc(ctx, loanID)
 	} else {
-		r0 = ret.Get(0).(float64)
+		r0 = ret.Get(0).(money.Money)
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
 		r1 = returnFunc(ctx, loanID)
@@ -412,38 +481,38 @@ func (_c *LoanRepository_GetTotalInvestedAmount_Call) Run(run func(ctx context.C
 	return _c
 }
 
-func (_c *LoanRepository_GetTotalInvestedAmount_Call) Return(f float64, err error) *LoanRepository_GetTotalInvestedAmount_Call {
-	_c.Call.Return(f, err)
+func (_c *LoanRepository_GetTotalInvestedAmount_Call) Return(money money.Money, err error) *LoanRepository_GetTotalInvestedAmount_Call {
+	_c.Call.Return(money, err)
 	return _c
 }
 
-func (_c *LoanRepository_GetTotalInvestedAmount_Call) RunAndReturn(run func(ctx context.Context, loanID int) (float64, error)) *LoanRepository_GetTotalInvestedAmount_Call {
+func (_c *LoanRepository_GetTotalInvestedAmount_Call) RunAndReturn(run func(ctx context.Context, loanID int) (money.Money, error)) *LoanRepository_GetTotalInvestedAmount_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // List provides a mock function for the type LoanRepository
-func (_mock *LoanRepository) List(ctx context.Context, state *string, offset int, limit int) ([]*models.Loan, error) {
-	ret := _mock.Called(ctx, state, offset, limit)
+func (_mock *LoanRepository) List(ctx context.Context, filter models.LoanFilter, page models.PageRequest) (*models.Page[*models.Loan], error) {
+	ret := _mock.Called(ctx, filter, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
 	}
 
-	var r0 []*models.Loan
+	var r0 *models.Page[*models.Loan]
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *string, int, int) ([]*models.Loan, error)); ok {
-		return returnFunc(ctx, state, offset, limit)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.LoanFilter, models.PageRequest) (*models.Page[*models.Loan], error)); ok {
+		return returnFunc(ctx, filter, page)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *string, int, int) []*models.Loan); ok {
-		r0 = returnFunc(ctx, state, offset, limit)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.LoanFilter, models.PageRequest) *models.Page[*models.Loan]); ok {
+		r0 = returnFunc(ctx, filter, page)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Loan)
+			r0 = ret.Get(0).(*models.Page[*models.Loan])
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *string, int, int) error); ok {
-		r1 = returnFunc(ctx, state, offset, limit)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.LoanFilter, models.PageRequest) error); ok {
+		r1 = returnFunc(ctx, filter, page)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -457,47 +526,41 @@ type LoanRepository_List_Call struct {
 
 // List is a helper method to define mock.On call
 //   - ctx context.Context
-//   - state *string
-//   - offset int
-//   - limit int
-func (_e *LoanRepository_Expecter) List(ctx interface{}, state interface{}, offset interface{}, limit interface{}) *LoanRepository_List_Call {
-	return &LoanRepository_List_Call{Call: _e.mock.On("List", ctx, state, offset, limit)}
+//   - filter models.LoanFilter
+//   - page models.PageRequest
+func (_e *LoanRepository_Expecter) List(ctx interface{}, filter interface{}, page interface{}) *LoanRepository_List_Call {
+	return &LoanRepository_List_Call{Call: _e.mock.On("List", ctx, filter, page)}
 }
 
-func (_c *LoanRepository_List_Call) Run(run func(ctx context.Context, state *string, offset int, limit int)) *LoanRepository_List_Call {
+func (_c *LoanRepository_List_Call) Run(run func(ctx context.Context, filter models.LoanFilter, page models.PageRequest)) *LoanRepository_List_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *string
+		var arg1 models.LoanFilter
 		if args[1] != nil {
-			arg1 = args[1].(*string)
+			arg1 = args[1].(models.LoanFilter)
 		}
-		var arg2 int
+		var arg2 models.PageRequest
 		if args[2] != nil {
-			arg2 = args[2].(int)
-		}
-		var arg3 int
-		if args[3] != nil {
-			arg3 = args[3].(int)
+			arg2 = args[2].(models.PageRequest)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *LoanRepository_List_Call) Return(loans []*models.Loan, err error) *LoanRepository_List_Call {
-	_c.Call.Return(loans, err)
+func (_c *LoanRepository_List_Call) Return(v *models.Page[*models.Loan], err error) *LoanRepository_List_Call {
+	_c.Call.Return(v, err)
 	return _c
 }
 
-func (_c *LoanRepository_List_Call) RunAndReturn(run func(ctx context.Context, state *string, offset int, limit int) ([]*models.Loan, error)) *LoanRepository_List_Call {
+func (_c *LoanRepository_List_Call) RunAndReturn(run func(ctx context.Context, filter models.LoanFilter, page models.PageRequest) (*models.Page[*models.Loan], error)) *LoanRepository_List_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -623,7 +686,7 @@ func (_c *LoanRepository_UpdateState_Call) RunAndReturn(run func(ctx context.Con
 }
 
 // UpdateTotalInvestedAmount provides a mock function for the type LoanRepository
-func (_mock *LoanRepository) UpdateTotalInvestedAmount(ctx context.Context, loanID int, amount float64) error {
+func (_mock *LoanRepository) UpdateTotalInvestedAmount(ctx context.Context, loanID int, amount money.Money) error {
 	ret := _mock.Called(ctx, loanID, amount)
 
 	if len(ret) == 0 {
@@ -631,7 +694,7 @@ func (_mock *LoanRepository) UpdateTotalInvestedAmount(ctx context.Context, loan
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, float64) error); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, money.Money) error); ok {
 		r0 = returnFunc(ctx, loanID, amount)
 	} else {
 		r0 = ret.Error(0)
@@ -647,12 +710,12 @@ type LoanRepository_UpdateTotalInvestedAmount_Call struct {
 // UpdateTotalInvestedAmount is a helper method to define mock.On call
 //   - ctx context.Context
 //   - loanID int
-//   - amount float64
+//   - amount money.Money
 func (_e *LoanRepository_Expecter) UpdateTotalInvestedAmount(ctx interface{}, loanID interface{}, amount interface{}) *LoanRepository_UpdateTotalInvestedAmount_Call {
 	return &LoanRepository_UpdateTotalInvestedAmount_Call{Call: _e.mock.On("UpdateTotalInvestedAmount", ctx, loanID, amount)}
 }
 
-func (_c *LoanRepository_UpdateTotalInvestedAmount_Call) Run(run func(ctx context.Context, loanID int, amount float64)) *LoanRepository_UpdateTotalInvestedAmount_Call {
+func (_c *LoanRepository_UpdateTotalInvestedAmount_Call) Run(run func(ctx context.Context, loanID int, amount money.Money)) *LoanRepository_UpdateTotalInvestedAmount_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -662,9 +725,9 @@ func (_c *LoanRepository_UpdateTotalInvestedAmount_Call) Run(run func(ctx contex
 		if args[1] != nil {
 			arg1 = args[1].(int)
 		}
-		var arg2 float64
+		var arg2 money.Money
 		if args[2] != nil {
-			arg2 = args[2].(float64)
+			arg2 = args[2].(money.Money)
 		}
 		run(
 			arg0,
@@ -680,7 +743,7 @@ func (_c *LoanRepository_UpdateTotalInvestedAmount_Call) Return(err error) *Loan
 	return _c
 }
 
-func (_c *LoanRepository_UpdateTotalInvestedAmount_Call) RunAndReturn(run func(ctx context.Context, loanID int, amount float64) error) *LoanRepository_UpdateTotalInvestedAmount_Call {
+func (_c *LoanRepository_UpdateTotalInvestedAmount_Call) RunAndReturn(run func(ctx context.Context, loanID int, amount money.Money) error) *LoanRepository_UpdateTotalInvestedAmount_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/sswastioyono18/loan-engine/internal/models"
 	mock "github.com/stretchr/testify/mock"
@@ -38,6 +39,144 @@ func (_m *UserRepository) EXPECT() *UserRepository_Expecter {
 	return &UserRepository_Expecter{mock: &_m.Mock}
 }
 
+// ClaimTOTPStep provides a mock function for the type UserRepository
+func (_mock *UserRepository) ClaimTOTPStep(ctx context.Context, id int, step int64) (bool, error) {
+	ret := _mock.Called(ctx, id, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimTOTPStep")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int64) (bool, error)); ok {
+		return returnFunc(ctx, id, step)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int64) bool); ok {
+		r0 = returnFunc(ctx, id, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int64) error); ok {
+		r1 = returnFunc(ctx, id, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// UserRepository_ClaimTOTPStep_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimTOTPStep'
+type UserRepository_ClaimTOTPStep_Call struct {
+	*mock.Call
+}
+
+// ClaimTOTPStep is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - step int64
+func (_e *UserRepository_Expecter) ClaimTOTPStep(ctx interface{}, id interface{}, step interface{}) *UserRepository_ClaimTOTPStep_Call {
+	return &UserRepository_ClaimTOTPStep_Call{Call: _e.mock.On("ClaimTOTPStep", ctx, id, step)}
+}
+
+func (_c *UserRepository_ClaimTOTPStep_Call) Run(run func(ctx context.Context, id int, step int64)) *UserRepository_ClaimTOTPStep_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *UserRepository_ClaimTOTPStep_Call) Return(b bool, err error) *UserRepository_ClaimTOTPStep_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *UserRepository_ClaimTOTPStep_Call) RunAndReturn(run func(ctx context.Context, id int, step int64) (bool, error)) *UserRepository_ClaimTOTPStep_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Count provides a mock function for the type UserRepository
+func (_mock *UserRepository) Count(ctx context.Context, filter models.UserFilter) (int, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.UserFilter) (int, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.UserFilter) int); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.UserFilter) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// UserRepository_Count_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Count'
+type UserRepository_Count_Call struct {
+	*mock.Call
+}
+
+// Count is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter models.UserFilter
+func (_e *UserRepository_Expecter) Count(ctx interface{}, filter interface{}) *UserRepository_Count_Call {
+	return &UserRepository_Count_Call{Call: _e.mock.On("Count", ctx, filter)}
+}
+
+func (_c *UserRepository_Count_Call) Run(run func(ctx context.Context, filter models.UserFilter)) *UserRepository_Count_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.UserFilter
+		if args[1] != nil {
+			arg1 = args[1].(models.UserFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *UserRepository_Count_Call) Return(n int, err error) *UserRepository_Count_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *UserRepository_Count_Call) RunAndReturn(run func(ctx context.Context, filter models.UserFilter) (int, error)) *UserRepository_Count_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function for the type UserRepository
 func (_mock *UserRepository) Create(ctx context.Context, user *models.User) error {
 	ret := _mock.Called(ctx, user)
@@ -152,6 +291,120 @@ func (_c *UserRepository_Delete_Call) RunAndReturn(run func(ctx context.Context,
 	return _c
 }
 
+// DisableTOTP provides a mock function for the type UserRepository
+func (_mock *UserRepository) DisableTOTP(ctx context.Context, id int) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableTOTP")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// UserRepository_DisableTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DisableTOTP'
+type UserRepository_DisableTOTP_Call struct {
+	*mock.Call
+}
+
+// DisableTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *UserRepository_Expecter) DisableTOTP(ctx interface{}, id interface{}) *UserRepository_DisableTOTP_Call {
+	return &UserRepository_DisableTOTP_Call{Call: _e.mock.On("DisableTOTP", ctx, id)}
+}
+
+func (_c *UserRepository_DisableTOTP_Call) Run(run func(ctx context.Context, id int)) *UserRepository_DisableTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *UserRepository_DisableTOTP_Call) Return(err error) *UserRepository_DisableTOTP_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *UserRepository_DisableTOTP_Call) RunAndReturn(run func(ctx context.Context, id int) error) *UserRepository_DisableTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EnableTOTP provides a mock function for the type UserRepository
+func (_mock *UserRepository) EnableTOTP(ctx context.Context, id int) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnableTOTP")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// UserRepository_EnableTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnableTOTP'
+type UserRepository_EnableTOTP_Call struct {
+	*mock.Call
+}
+
+// EnableTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *UserRepository_Expecter) EnableTOTP(ctx interface{}, id interface{}) *UserRepository_EnableTOTP_Call {
+	return &UserRepository_EnableTOTP_Call{Call: _e.mock.On("EnableTOTP", ctx, id)}
+}
+
+func (_c *UserRepository_EnableTOTP_Call) Run(run func(ctx context.Context, id int)) *UserRepository_EnableTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *UserRepository_EnableTOTP_Call) Return(err error) *UserRepository_EnableTOTP_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *UserRepository_EnableTOTP_Call) RunAndReturn(run func(ctx context.Context, id int) error) *UserRepository_EnableTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetByEmail provides a mock function for the type UserRepository
 func (_mock *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	ret := _mock.Called(ctx, email)
@@ -356,6 +609,449 @@ func (_c *UserRepository_GetByUserID_Call) RunAndReturn(run func(ctx context.Con
 	return _c
 }
 
+// List provides a mock function for the type UserRepository
+func (_mock *UserRepository) List(ctx context.Context, filter models.UserFilter, offset int, limit int) ([]*models.User, error) {
+	ret := _mock.Called(ctx, filter, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*models.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.UserFilter, int, int) ([]*models.User, error)); ok {
+		return returnFunc(ctx, filter, offset, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.UserFilter, int, int) []*models.User); ok {
+		r0 = returnFunc(ctx, filter, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.UserFilter, int, int) error); ok {
+		r1 = returnFunc(ctx, filter, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// UserRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type UserRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter models.UserFilter
+//   - offset int
+//   - limit int
+func (_e *UserRepository_Expecter) List(ctx interface{}, filter interface{}, offset interface{}, limit interface{}) *UserRepository_List_Call {
+	return &UserRepository_List_Call{Call: _e.mock.On("List", ctx, filter, offset, limit)}
+}
+
+func (_c *UserRepository_List_Call) Run(run func(ctx context.Context, filter models.UserFilter, offset int, limit int)) *UserRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.UserFilter
+		if args[1] != nil {
+			arg1 = args[1].(models.UserFilter)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *UserRepository_List_Call) Return(users []*models.User, err error) *UserRepository_List_Call {
+	_c.Call.Return(users, err)
+	return _c
+}
+
+func (_c *UserRepository_List_Call) RunAndReturn(run func(ctx context.Context, filter models.UserFilter, offset int, limit int) ([]*models.User, error)) *UserRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LockUntil provides a mock function for the type UserRepository
+func (_mock *UserRepository) LockUntil(ctx context.Context, id int, until time.Time) error {
+	ret := _mock.Called(ctx, id, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockUntil")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Time) error); ok {
+		r0 = returnFunc(ctx, id, until)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// UserRepository_LockUntil_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LockUntil'
+type UserRepository_LockUntil_Call struct {
+	*mock.Call
+}
+
+// LockUntil is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - until time.Time
+func (_e *UserRepository_Expecter) LockUntil(ctx interface{}, id interface{}, until interface{}) *UserRepository_LockUntil_Call {
+	return &UserRepository_LockUntil_Call{Call: _e.mock.On("LockUntil", ctx, id, until)}
+}
+
+func (_c *UserRepository_LockUntil_Call) Run(run func(ctx context.Context, id int, until time.Time)) *UserRepository_LockUntil_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *UserRepository_LockUntil_Call) Return(err error) *UserRepository_LockUntil_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *UserRepository_LockUntil_Call) RunAndReturn(run func(ctx context.Context, id int, until time.Time) error) *UserRepository_LockUntil_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkEmailVerified provides a mock function for the type UserRepository
+func (_mock *UserRepository) MarkEmailVerified(ctx context.Context, id int) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkEmailVerified")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// UserRepository_MarkEmailVerified_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkEmailVerified'
+type UserRepository_MarkEmailVerified_Call struct {
+	*mock.Call
+}
+
+// MarkEmailVerified is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *UserRepository_Expecter) MarkEmailVerified(ctx interface{}, id interface{}) *UserRepository_MarkEmailVerified_Call {
+	return &UserRepository_MarkEmailVerified_Call{Call: _e.mock.On("MarkEmailVerified", ctx, id)}
+}
+
+func (_c *UserRepository_MarkEmailVerified_Call) Run(run func(ctx context.Context, id int)) *UserRepository_MarkEmailVerified_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *UserRepository_MarkEmailVerified_Call) Return(err error) *UserRepository_MarkEmailVerified_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *UserRepository_MarkEmailVerified_Call) RunAndReturn(run func(ctx context.Context, id int) error) *UserRepository_MarkEmailVerified_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordFailedLogin provides a mock function for the type UserRepository
+func (_mock *UserRepository) RecordFailedLogin(ctx context.Context, id int) (int, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordFailedLogin")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// UserRepository_RecordFailedLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordFailedLogin'
+type UserRepository_RecordFailedLogin_Call struct {
+	*mock.Call
+}
+
+// RecordFailedLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *UserRepository_Expecter) RecordFailedLogin(ctx interface{}, id interface{}) *UserRepository_RecordFailedLogin_Call {
+	return &UserRepository_RecordFailedLogin_Call{Call: _e.mock.On("RecordFailedLogin", ctx, id)}
+}
+
+func (_c *UserRepository_RecordFailedLogin_Call) Run(run func(ctx context.Context, id int)) *UserRepository_RecordFailedLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *UserRepository_RecordFailedLogin_Call) Return(n int, err error) *UserRepository_RecordFailedLogin_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *UserRepository_RecordFailedLogin_Call) RunAndReturn(run func(ctx context.Context, id int) (int, error)) *UserRepository_RecordFailedLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetFailedLogins provides a mock function for the type UserRepository
+func (_mock *UserRepository) ResetFailedLogins(ctx context.Context, id int) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetFailedLogins")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// UserRepository_ResetFailedLogins_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetFailedLogins'
+type UserRepository_ResetFailedLogins_Call struct {
+	*mock.Call
+}
+
+// ResetFailedLogins is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *UserRepository_Expecter) ResetFailedLogins(ctx interface{}, id interface{}) *UserRepository_ResetFailedLogins_Call {
+	return &UserRepository_ResetFailedLogins_Call{Call: _e.mock.On("ResetFailedLogins", ctx, id)}
+}
+
+func (_c *UserRepository_ResetFailedLogins_Call) Run(run func(ctx context.Context, id int)) *UserRepository_ResetFailedLogins_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *UserRepository_ResetFailedLogins_Call) Return(err error) *UserRepository_ResetFailedLogins_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *UserRepository_ResetFailedLogins_Call) RunAndReturn(run func(ctx context.Context, id int) error) *UserRepository_ResetFailedLogins_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Restore provides a mock function for the type UserRepository
+func (_mock *UserRepository) Restore(ctx context.Context, id int) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// UserRepository_Restore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Restore'
+type UserRepository_Restore_Call struct {
+	*mock.Call
+}
+
+// Restore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *UserRepository_Expecter) Restore(ctx interface{}, id interface{}) *UserRepository_Restore_Call {
+	return &UserRepository_Restore_Call{Call: _e.mock.On("Restore", ctx, id)}
+}
+
+func (_c *UserRepository_Restore_Call) Run(run func(ctx context.Context, id int)) *UserRepository_Restore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *UserRepository_Restore_Call) Return(err error) *UserRepository_Restore_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *UserRepository_Restore_Call) RunAndReturn(run func(ctx context.Context, id int) error) *UserRepository_Restore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetTOTPSecret provides a mock function for the type UserRepository
+func (_mock *UserRepository) SetTOTPSecret(ctx context.Context, id int, secret string) error {
+	ret := _mock.Called(ctx, id, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTOTPSecret")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = returnFunc(ctx, id, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// UserRepository_SetTOTPSecret_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetTOTPSecret'
+type UserRepository_SetTOTPSecret_Call struct {
+	*mock.Call
+}
+
+// SetTOTPSecret is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+//   - secret string
+func (_e *UserRepository_Expecter) SetTOTPSecret(ctx interface{}, id interface{}, secret interface{}) *UserRepository_SetTOTPSecret_Call {
+	return &UserRepository_SetTOTPSecret_Call{Call: _e.mock.On("SetTOTPSecret", ctx, id, secret)}
+}
+
+func (_c *UserRepository_SetTOTPSecret_Call) Run(run func(ctx context.Context, id int, secret string)) *UserRepository_SetTOTPSecret_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *UserRepository_SetTOTPSecret_Call) Return(err error) *UserRepository_SetTOTPSecret_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *UserRepository_SetTOTPSecret_Call) RunAndReturn(run func(ctx context.Context, id int, secret string) error) *UserRepository_SetTOTPSecret_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Update provides a mock function for the type UserRepository
 func (_mock *UserRepository) Update(ctx context.Context, user *models.User) error {
 	ret := _mock.Called(ctx, user)
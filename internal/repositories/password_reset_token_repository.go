@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+type PasswordResetTokenRepository interface {
+	Create(ctx context.Context, token *models.PasswordResetToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error)
+	MarkUsed(ctx context.Context, id int) (bool, error)
+	InvalidateForUser(ctx context.Context, userID int) error
+}
+
+type passwordResetTokenRepositoryImpl struct {
+	base *BaseRepository
+}
+
+func NewPasswordResetTokenRepository(driver Driver) PasswordResetTokenRepository {
+	return &passwordResetTokenRepositoryImpl{
+		base: NewBaseRepository(driver),
+	}
+}
+
+func (r *passwordResetTokenRepositoryImpl) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	query := `
+		INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+
+	err := r.base.Executor(ctx).QueryRowContext(
+		ctx, query,
+		token.UserID, token.TokenHash, token.ExpiresAt,
+	).Scan(&token.ID, &token.CreatedAt)
+
+	return err
+}
+
+func (r *passwordResetTokenRepositoryImpl) GetByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, used_at, created_at
+		FROM password_reset_tokens WHERE token_hash = $1
+	`
+
+	var token models.PasswordResetToken
+	err := r.base.Executor(ctx).GetContext(ctx, &token, query, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrPasswordResetTokenNotFound
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// MarkUsed claims an unused token; it reports false when the token was already used
+func (r *passwordResetTokenRepositoryImpl) MarkUsed(ctx context.Context, id int) (bool, error) {
+	query := "UPDATE password_reset_tokens SET used_at = NOW() WHERE id = $1 AND used_at IS NULL"
+
+	result, err := r.base.Executor(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// InvalidateForUser uses up every outstanding token of the user, so only the latest one mailed works
+func (r *passwordResetTokenRepositoryImpl) InvalidateForUser(ctx context.Context, userID int) error {
+	query := "UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL"
+
+	_, err := r.base.Executor(ctx).ExecContext(ctx, query, userID)
+	return err
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+type PayoutRepository interface {
+	Create(ctx context.Context, payout *models.Payout) error
+	GetByInvestorID(ctx context.Context, investorID int) ([]*models.Payout, error)
+}
+
+type payoutRepositoryImpl struct {
+	base *BaseRepository
+}
+
+func NewPayoutRepository(driver Driver) PayoutRepository {
+	return &payoutRepositoryImpl{
+		base: NewBaseRepository(driver),
+	}
+}
+
+func (r *payoutRepositoryImpl) Create(ctx context.Context, payout *models.Payout) error {
+	query := `
+		INSERT INTO payouts (
+			loan_repayment_id, loan_id, loan_investment_id, investor_id,
+			principal_amount, interest_amount, amount
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	err := r.base.Executor(ctx).QueryRowContext(
+		ctx, query,
+		payout.LoanRepaymentID, payout.LoanID, payout.LoanInvestmentID, payout.InvestorID,
+		payout.PrincipalAmount, payout.InterestAmount, payout.Amount,
+	).Scan(&payout.ID, &payout.CreatedAt)
+
+	return err
+}
+
+func (r *payoutRepositoryImpl) GetByInvestorID(ctx context.Context, investorID int) ([]*models.Payout, error) {
+	query := `
+		SELECT id, loan_repayment_id, loan_id, loan_investment_id, investor_id,
+		       principal_amount, interest_amount, amount, created_at
+		FROM payouts WHERE investor_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+
+	var payouts []*models.Payout
+	err := r.base.Executor(ctx).SelectContext(ctx, &payouts, query, investorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return payouts, nil
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+type PlatformRevenueRepository interface {
+	Create(ctx context.Context, revenue *models.PlatformRevenue) error
+	GetByLoanID(ctx context.Context, loanID int) ([]*models.PlatformRevenue, error)
+}
+
+type platformRevenueRepositoryImpl struct {
+	base *BaseRepository
+}
+
+func NewPlatformRevenueRepository(driver Driver) PlatformRevenueRepository {
+	return &platformRevenueRepositoryImpl{
+		base: NewBaseRepository(driver),
+	}
+}
+
+func (r *platformRevenueRepositoryImpl) Create(ctx context.Context, revenue *models.PlatformRevenue) error {
+	query := `
+		INSERT INTO platform_revenues (
+			loan_repayment_id, loan_id, margin_amount, penalty_amount, amount
+		) VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := r.base.Executor(ctx).QueryRowContext(
+		ctx, query,
+		revenue.LoanRepaymentID, revenue.LoanID, revenue.MarginAmount,
+		revenue.PenaltyAmount, revenue.Amount,
+	).Scan(&revenue.ID, &revenue.CreatedAt)
+
+	return err
+}
+
+func (r *platformRevenueRepositoryImpl) GetByLoanID(ctx context.Context, loanID int) ([]*models.PlatformRevenue, error) {
+	query := `
+		SELECT id, loan_repayment_id, loan_id, margin_amount, penalty_amount, amount, created_at
+		FROM platform_revenues WHERE loan_id = $1
+		ORDER BY created_at ASC, id ASC
+	`
+
+	var revenues []*models.PlatformRevenue
+	err := r.base.Executor(ctx).SelectContext(ctx, &revenues, query, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	return revenues, nil
+}
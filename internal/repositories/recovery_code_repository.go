@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+)
+
+// RecoveryCodeRepository stores the hashed single-use codes that stand in for a TOTP code
+// when the user has lost their authenticator
+type RecoveryCodeRepository interface {
+	ReplaceForUser(ctx context.Context, userID int, codeHashes []string) error
+	Use(ctx context.Context, userID int, codeHash string) (bool, error)
+	DeleteForUser(ctx context.Context, userID int) error
+}
+
+type recoveryCodeRepositoryImpl struct {
+	base *BaseRepository
+}
+
+func NewRecoveryCodeRepository(driver Driver) RecoveryCodeRepository {
+	return &recoveryCodeRepositoryImpl{
+		base: NewBaseRepository(driver),
+	}
+}
+
+// ReplaceForUser discards the user's codes and stores new ones; run it inside a unit of work
+func (r *recoveryCodeRepositoryImpl) ReplaceForUser(ctx context.Context, userID int, codeHashes []string) error {
+	if err := r.DeleteForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	query := "INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)"
+	for _, codeHash := range codeHashes {
+		if _, err := r.base.Executor(ctx).ExecContext(ctx, query, userID, codeHash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Use marks an unused code of the user as used; it reports false when there is no such code
+func (r *recoveryCodeRepositoryImpl) Use(ctx context.Context, userID int, codeHash string) (bool, error) {
+	query := `
+		UPDATE recovery_codes SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	result, err := r.base.Executor(ctx).ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *recoveryCodeRepositoryImpl) DeleteForUser(ctx context.Context, userID int) error {
+	query := "DELETE FROM recovery_codes WHERE user_id = $1"
+
+	_, err := r.base.Executor(ctx).ExecContext(ctx, query, userID)
+	return err
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	MarkUsed(ctx context.Context, id int) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID int) error
+}
+
+type refreshTokenRepositoryImpl struct {
+	base *BaseRepository
+}
+
+func NewRefreshTokenRepository(driver Driver) RefreshTokenRepository {
+	return &refreshTokenRepositoryImpl{
+		base: NewBaseRepository(driver),
+	}
+}
+
+func (r *refreshTokenRepositoryImpl) Create(ctx context.Context, token *models.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	err := r.base.Executor(ctx).QueryRowContext(
+		ctx, query,
+		token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt,
+	).Scan(&token.ID, &token.CreatedAt)
+
+	return err
+}
+
+func (r *refreshTokenRepositoryImpl) GetByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at
+		FROM refresh_tokens WHERE token_hash = $1
+	`
+
+	var token models.RefreshToken
+	err := r.base.Executor(ctx).GetContext(ctx, &token, query, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// MarkUsed claims a live token for rotation. It reports false when the token was already
+// used or revoked, so two concurrent refreshes cannot both succeed.
+func (r *refreshTokenRepositoryImpl) MarkUsed(ctx context.Context, id int) (bool, error) {
+	query := `
+		UPDATE refresh_tokens SET used_at = NOW()
+		WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL
+	`
+
+	result, err := r.base.Executor(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *refreshTokenRepositoryImpl) RevokeFamily(ctx context.Context, familyID string) error {
+	query := "UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL"
+
+	_, err := r.base.Executor(ctx).ExecContext(ctx, query, familyID)
+	return err
+}
+
+func (r *refreshTokenRepositoryImpl) RevokeAllForUser(ctx context.Context, userID int) error {
+	query := "UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL"
+
+	_, err := r.base.Executor(ctx).ExecContext(ctx, query, userID)
+	return err
+}
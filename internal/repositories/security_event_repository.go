@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+// SecurityEventRepository appends to the account security audit trail. Entries are never updated or deleted.
+type SecurityEventRepository interface {
+	Create(ctx context.Context, event *models.SecurityEvent) error
+}
+
+type securityEventRepositoryImpl struct {
+	base *BaseRepository
+}
+
+func NewSecurityEventRepository(driver Driver) SecurityEventRepository {
+	return &securityEventRepositoryImpl{
+		base: NewBaseRepository(driver),
+	}
+}
+
+func (r *securityEventRepositoryImpl) Create(ctx context.Context, event *models.SecurityEvent) error {
+	query := `
+		INSERT INTO security_events (user_id, email, event_type, detail, ip_address, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err := r.base.Executor(ctx).QueryRowContext(
+		ctx, query,
+		event.UserID, event.Email, event.EventType, event.Detail, event.IPAddress, event.UserAgent,
+	).Scan(&event.ID, &event.CreatedAt)
+
+	return err
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+)
+
+// UnitOfWork runs a function inside a single database transaction. Repositories
+// built on BaseRepository join that transaction through the context handed to fn,
+// so every write made by fn is committed or rolled back together.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type unitOfWorkImpl struct {
+	base *BaseRepository
+}
+
+func NewUnitOfWork(driver Driver) UnitOfWork {
+	return &unitOfWorkImpl{
+		base: NewBaseRepository(driver),
+	}
+}
+
+func (u *unitOfWorkImpl) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	// Nested units of work join the outer transaction instead of opening a new one
+	if txFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	tx, err := u.base.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = u.base.Rollback(tx)
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txContextKey{}, tx)); err != nil {
+		if rollbackErr := u.base.Rollback(tx); rollbackErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+		}
+		return err
+	}
+
+	if err := u.base.Commit(tx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
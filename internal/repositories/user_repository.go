@@ -4,6 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
 	"github.com/sswastioyono18/loan-engine/internal/models"
 )
 
@@ -14,7 +18,18 @@ type UserRepository interface {
 	GetByUserID(ctx context.Context, userID string) (*models.User, error)
 	Update(ctx context.Context, user *models.User) error
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
 	UpdatePassword(ctx context.Context, id int, hashedPassword string) error
+	MarkEmailVerified(ctx context.Context, id int) error
+	List(ctx context.Context, filter models.UserFilter, offset, limit int) ([]*models.User, error)
+	Count(ctx context.Context, filter models.UserFilter) (int, error)
+	RecordFailedLogin(ctx context.Context, id int) (int, error)
+	LockUntil(ctx context.Context, id int, until time.Time) error
+	ResetFailedLogins(ctx context.Context, id int) error
+	SetTOTPSecret(ctx context.Context, id int, secret string) error
+	EnableTOTP(ctx context.Context, id int) error
+	DisableTOTP(ctx context.Context, id int) error
+	ClaimTOTPStep(ctx context.Context, id int, step int64) (bool, error)
 }
 
 type userRepositoryImpl struct {
@@ -30,34 +45,36 @@ func NewUserRepository(driver Driver) UserRepository {
 func (r *userRepositoryImpl) Create(ctx context.Context, user *models.User) error {
 	query := `
 		INSERT INTO users (
-			user_id, email, password_hash, user_type, name, is_active
-		) VALUES ($1, $2, $3, $4, $5, $6)
+			user_id, email, password_hash, user_type, staff_role, name, is_active,
+			email_verified, investor_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id, created_at, updated_at
 	`
 
-	db := r.base.GetUtilDB()
+	db := r.base.Executor(ctx)
 	err := db.QueryRowContext(
 		ctx, query,
 		user.UserID, user.Email, user.PasswordHash, user.UserType,
-		user.FullName, user.IsActive,
+		user.StaffRole, user.FullName, user.IsActive, user.EmailVerified, user.InvestorID,
 	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
 
-	return err
+	return translateError(err)
 }
 
 func (r *userRepositoryImpl) GetByID(ctx context.Context, id int) (*models.User, error) {
 	query := `
-		SELECT id, user_id, email, password_hash, user_type, name,
-		       is_active, created_at, updated_at
-		FROM users WHERE id = $1
+		SELECT id, user_id, email, password_hash, user_type, staff_role, name,
+		       is_active, email_verified, investor_id, failed_login_attempts, locked_until,
+		       totp_secret, totp_enabled, totp_last_used_step, created_at, updated_at, deleted_at
+		FROM users WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var user models.User
-	db := r.base.GetUtilDB()
+	db := r.base.Executor(ctx)
 	err := db.GetContext(ctx, &user, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -67,17 +84,18 @@ func (r *userRepositoryImpl) GetByID(ctx context.Context, id int) (*models.User,
 
 func (r *userRepositoryImpl) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	query := `
-		SELECT id, user_id, email, password_hash, user_type, name,
-		       is_active, created_at, updated_at
-		FROM users WHERE email = $1
+		SELECT id, user_id, email, password_hash, user_type, staff_role, name,
+		       is_active, email_verified, investor_id, failed_login_attempts, locked_until,
+		       totp_secret, totp_enabled, totp_last_used_step, created_at, updated_at, deleted_at
+		FROM users WHERE email = $1 AND deleted_at IS NULL
 	`
 
 	var user models.User
-	db := r.base.GetUtilDB()
+	db := r.base.Executor(ctx)
 	err := db.GetContext(ctx, &user, query, email)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -87,17 +105,18 @@ func (r *userRepositoryImpl) GetByEmail(ctx context.Context, email string) (*mod
 
 func (r *userRepositoryImpl) GetByUserID(ctx context.Context, userID string) (*models.User, error) {
 	query := `
-		SELECT id, user_id, email, password_hash, user_type, name,
-		       is_active, created_at, updated_at
-		FROM users WHERE user_id = $1
+		SELECT id, user_id, email, password_hash, user_type, staff_role, name,
+		       is_active, email_verified, investor_id, failed_login_attempts, locked_until,
+		       totp_secret, totp_enabled, totp_last_used_step, created_at, updated_at, deleted_at
+		FROM users WHERE user_id = $1 AND deleted_at IS NULL
 	`
 
 	var user models.User
-	db := r.base.GetUtilDB()
+	db := r.base.Executor(ctx)
 	err := db.GetContext(ctx, &user, query, userID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -108,20 +127,20 @@ func (r *userRepositoryImpl) GetByUserID(ctx context.Context, userID string) (*m
 func (r *userRepositoryImpl) Update(ctx context.Context, user *models.User) error {
 	query := `
 		UPDATE users SET
-			user_id = $1, email = $2, user_type = $3, name = $4,
-			is_active = $5, updated_at = NOW()
-		WHERE id = $6
+			user_id = $1, email = $2, user_type = $3, staff_role = $4, name = $5,
+			is_active = $6, updated_at = NOW()
+		WHERE id = $7 AND deleted_at IS NULL
 	`
 
-	db := r.base.GetUtilDB()
+	db := r.base.Executor(ctx)
 	result, err := db.ExecContext(
 		ctx, query,
-		user.UserID, user.Email, user.UserType, user.FullName,
+		user.UserID, user.Email, user.UserType, user.StaffRole, user.FullName,
 		user.IsActive, user.ID,
 	)
 
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -130,15 +149,18 @@ func (r *userRepositoryImpl) Update(ctx context.Context, user *models.User) erro
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("user not found")
+		return ErrUserNotFound
 	}
 
 	return nil
 }
 
+// Delete soft-deletes the user: the row stays for the audit trail, but the user can no
+// longer be found or log in, and the email is free to be used again. is_active is left as
+// it is, so Restore brings the account back the way it was.
 func (r *userRepositoryImpl) Delete(ctx context.Context, id int) error {
-	query := "DELETE FROM users WHERE id = $1"
-	db := r.base.GetUtilDB()
+	query := "UPDATE users SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL"
+	db := r.base.Executor(ctx)
 	result, err := db.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
@@ -150,7 +172,7 @@ func (r *userRepositoryImpl) Delete(ctx context.Context, id int) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("user not found")
+		return ErrUserNotFound
 	}
 
 	return nil
@@ -159,7 +181,7 @@ func (r *userRepositoryImpl) Delete(ctx context.Context, id int) error {
 func (r *userRepositoryImpl) UpdatePassword(ctx context.Context, id int, hashedPassword string) error {
 	query := "UPDATE users SET password_hash = $1, updated_at = NOW() WHERE id = $2"
 
-	db := r.base.GetUtilDB()
+	db := r.base.Executor(ctx)
 	result, err := db.ExecContext(ctx, query, hashedPassword, id)
 	if err != nil {
 		return err
@@ -171,8 +193,206 @@ func (r *userRepositoryImpl) UpdatePassword(ctx context.Context, id int, hashedP
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("user not found")
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// Restore undoes Delete. A user that was active before being deleted can log in again.
+func (r *userRepositoryImpl) Restore(ctx context.Context, id int) error {
+	query := "UPDATE users SET deleted_at = NULL, updated_at = NOW() WHERE id = $1 AND deleted_at IS NOT NULL"
+
+	result, err := r.base.Executor(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return apperr.NotFound("user_not_found", "deleted user not found")
+	}
+
+	return nil
+}
+
+func (r *userRepositoryImpl) MarkEmailVerified(ctx context.Context, id int) error {
+	query := "UPDATE users SET email_verified = TRUE, updated_at = NOW() WHERE id = $1"
+
+	result, err := r.base.Executor(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// List returns a page of the users matching filter, newest first
+func (r *userRepositoryImpl) List(ctx context.Context, filter models.UserFilter, offset, limit int) ([]*models.User, error) {
+	where, args := userFilterClause(filter)
+	query := fmt.Sprintf(`
+		SELECT id, user_id, email, password_hash, user_type, staff_role, name,
+		       is_active, email_verified, investor_id, failed_login_attempts, locked_until,
+		       totp_secret, totp_enabled, totp_last_used_step, created_at, updated_at, deleted_at
+		FROM users
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+
+	users := []*models.User{}
+	err := r.base.Executor(ctx).SelectContext(ctx, &users, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// Count returns how many users match filter, for paging through List
+func (r *userRepositoryImpl) Count(ctx context.Context, filter models.UserFilter) (int, error) {
+	where, args := userFilterClause(filter)
+	query := "SELECT COUNT(*) FROM users " + where
+
+	var count int
+	err := r.base.Executor(ctx).GetContext(ctx, &count, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// userFilterClause builds the WHERE clause and its arguments for filter
+func userFilterClause(filter models.UserFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+	if filter.Search != "" {
+		args = append(args, "%"+escapeLike(filter.Search)+"%")
+		conditions = append(conditions, fmt.Sprintf("(email ILIKE $%[1]d OR name ILIKE $%[1]d OR user_id ILIKE $%[1]d)", len(args)))
+	}
+	if filter.UserType != "" {
+		args = append(args, filter.UserType)
+		conditions = append(conditions, fmt.Sprintf("user_type = $%d", len(args)))
+	}
+	if filter.IsActive != nil {
+		args = append(args, *filter.IsActive)
+		conditions = append(conditions, fmt.Sprintf("is_active = $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// escapeLike makes s match literally inside a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// RecordFailedLogin counts one more consecutive failed login and returns the new count
+func (r *userRepositoryImpl) RecordFailedLogin(ctx context.Context, id int) (int, error) {
+	query := `
+		UPDATE users SET failed_login_attempts = failed_login_attempts + 1
+		WHERE id = $1
+		RETURNING failed_login_attempts
+	`
+
+	var attempts int
+	err := r.base.Executor(ctx).QueryRowContext(ctx, query, id).Scan(&attempts)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrUserNotFound
+		}
+		return 0, err
+	}
+
+	return attempts, nil
+}
+
+func (r *userRepositoryImpl) LockUntil(ctx context.Context, id int, until time.Time) error {
+	query := "UPDATE users SET locked_until = $1 WHERE id = $2"
+
+	_, err := r.base.Executor(ctx).ExecContext(ctx, query, until, id)
+	return err
+}
+
+// ResetFailedLogins clears the failed login count and any lock
+func (r *userRepositoryImpl) ResetFailedLogins(ctx context.Context, id int) error {
+	query := "UPDATE users SET failed_login_attempts = 0, locked_until = NULL WHERE id = $1"
+
+	_, err := r.base.Executor(ctx).ExecContext(ctx, query, id)
+	return err
+}
+
+// SetTOTPSecret stores a new, not yet confirmed TOTP secret and leaves TOTP disabled until EnableTOTP
+func (r *userRepositoryImpl) SetTOTPSecret(ctx context.Context, id int, secret string) error {
+	query := "UPDATE users SET totp_secret = $1, totp_enabled = FALSE, totp_last_used_step = 0 WHERE id = $2"
+
+	_, err := r.base.Executor(ctx).ExecContext(ctx, query, secret, id)
+	return err
+}
+
+func (r *userRepositoryImpl) EnableTOTP(ctx context.Context, id int) error {
+	query := "UPDATE users SET totp_enabled = TRUE WHERE id = $1 AND totp_secret <> ''"
+
+	result, err := r.base.Executor(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return apperr.InvalidState("totp_not_enrolled", "user not found or not enrolled in TOTP")
 	}
 
 	return nil
 }
+
+func (r *userRepositoryImpl) DisableTOTP(ctx context.Context, id int) error {
+	query := "UPDATE users SET totp_secret = '', totp_enabled = FALSE, totp_last_used_step = 0 WHERE id = $1"
+
+	_, err := r.base.Executor(ctx).ExecContext(ctx, query, id)
+	return err
+}
+
+// ClaimTOTPStep records that the code for step was used. It reports false when a code for
+// that step or a later one was already used, so each code works only once.
+func (r *userRepositoryImpl) ClaimTOTPStep(ctx context.Context, id int, step int64) (bool, error) {
+	query := "UPDATE users SET totp_last_used_step = $1 WHERE id = $2 AND totp_last_used_step < $1"
+
+	result, err := r.base.Executor(ctx).ExecContext(ctx, query, step, id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
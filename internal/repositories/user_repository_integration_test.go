@@ -0,0 +1,200 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// newMigratedDriver starts a Postgres container, runs every migration against it and
+// connects to it. The test is skipped when no container runtime is available.
+func newMigratedDriver(t *testing.T) Driver {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	ctx := context.Background()
+
+	postgresC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "postgres:15-alpine",
+			ExposedPorts: []string{"5432/tcp"},
+			Env: map[string]string{
+				"POSTGRES_DB":       "loan_engine_db",
+				"POSTGRES_USER":     "loan_engine_user",
+				"POSTGRES_PASSWORD": "loan_engine_password",
+			},
+			// Postgres restarts once after initdb, so wait for the second ready message
+			WaitingFor: wait.ForLog("database system is ready to accept connections").WithOccurrence(2).WithStartupTimeout(60 * time.Second),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { postgresC.Terminate(ctx) })
+
+	host, err := postgresC.Host(ctx)
+	require.NoError(t, err)
+	port, err := postgresC.MappedPort(ctx, "5432")
+	require.NoError(t, err)
+
+	driver, err := NewPostgreSQLDriver(fmt.Sprintf(
+		"host=%s port=%s user=loan_engine_user password=loan_engine_password dbname=loan_engine_db sslmode=disable",
+		host, port.Port(),
+	))
+	require.NoError(t, err)
+	t.Cleanup(func() { driver.Close() })
+
+	require.NoError(t, goose.SetDialect("postgres"))
+	require.NoError(t, goose.Up(driver.GetDB(), "../../migrations"))
+
+	return driver
+}
+
+func newTestUser(email, userType string) *models.User {
+	return &models.User{
+		UserID:       "USR-" + email,
+		Email:        email,
+		PasswordHash: "hash",
+		UserType:     userType,
+		FullName:     "Test " + email,
+		IsActive:     true,
+	}
+}
+
+func TestUserRepositoryIntegration(t *testing.T) {
+	driver := newMigratedDriver(t)
+	repo := NewUserRepository(driver)
+	ctx := context.Background()
+
+	t.Run("every model column exists in the users table", func(t *testing.T) {
+		var columns []string
+		err := driver.GetUtilDB().SelectContext(ctx, &columns,
+			"SELECT column_name FROM information_schema.columns WHERE table_name = 'users'")
+		require.NoError(t, err)
+
+		userType := reflect.TypeOf(models.User{})
+		for i := 0; i < userType.NumField(); i++ {
+			if column := userType.Field(i).Tag.Get("db"); column != "" {
+				assert.Contains(t, columns, column, "models.User.%s", userType.Field(i).Name)
+			}
+		}
+	})
+
+	t.Run("creates and reads back every field", func(t *testing.T) {
+		user := newTestUser("roundtrip@example.com", "staff")
+		user.StaffRole = "field_officer"
+		require.NoError(t, repo.Create(ctx, user))
+
+		got, err := repo.GetByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.UserID, got.UserID)
+		assert.Equal(t, "staff", got.UserType)
+		assert.Equal(t, "field_officer", got.StaffRole)
+		assert.Equal(t, user.FullName, got.FullName)
+		assert.True(t, got.IsActive)
+		assert.Nil(t, got.DeletedAt)
+
+		got.IsActive = false
+		got.StaffRole = ""
+		require.NoError(t, repo.Update(ctx, got))
+
+		got, err = repo.GetByEmail(ctx, user.Email)
+		require.NoError(t, err)
+		assert.False(t, got.IsActive)
+		assert.Empty(t, got.StaffRole)
+	})
+
+	t.Run("rejects unknown user types", func(t *testing.T) {
+		assert.Error(t, repo.Create(ctx, newTestUser("superuser@example.com", "superuser")))
+	})
+
+	t.Run("lists, searches and pages", func(t *testing.T) {
+		for _, email := range []string{"alice@search.test", "bob@search.test", "carol@search.test"} {
+			require.NoError(t, repo.Create(ctx, newTestUser(email, "investor")))
+		}
+		inactive := false
+
+		tests := []struct {
+			name   string
+			filter models.UserFilter
+			want   int
+		}{
+			{"search", models.UserFilter{Search: "SEARCH.test"}, 3},
+			{"search matches LIKE wildcards literally", models.UserFilter{Search: "%"}, 0},
+			{"user type", models.UserFilter{Search: "search.test", UserType: "staff"}, 0},
+			{"inactive", models.UserFilter{IsActive: &inactive}, 1},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				count, err := repo.Count(ctx, tt.filter)
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, count)
+			})
+		}
+
+		firstPage, err := repo.List(ctx, models.UserFilter{Search: "search.test"}, 0, 2)
+		require.NoError(t, err)
+		secondPage, err := repo.List(ctx, models.UserFilter{Search: "search.test"}, 2, 2)
+		require.NoError(t, err)
+		require.Len(t, firstPage, 2)
+		require.Len(t, secondPage, 1)
+		// Newest first
+		assert.Equal(t, "carol@search.test", firstPage[0].Email)
+		assert.Equal(t, "alice@search.test", secondPage[0].Email)
+	})
+
+	t.Run("soft-deletes and restores", func(t *testing.T) {
+		user := newTestUser("deleted@example.com", "investor")
+		require.NoError(t, repo.Create(ctx, user))
+
+		require.NoError(t, repo.Delete(ctx, user.ID))
+		assert.Error(t, repo.Delete(ctx, user.ID))
+
+		_, err := repo.GetByID(ctx, user.ID)
+		assert.Error(t, err)
+		_, err = repo.GetByEmail(ctx, user.Email)
+		assert.Error(t, err)
+
+		deleted, err := repo.List(ctx, models.UserFilter{Search: user.Email, IncludeDeleted: true}, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+		assert.NotNil(t, deleted[0].DeletedAt)
+
+		// The email of a deleted user can be used again
+		replacement := newTestUser(user.Email, "investor")
+		replacement.UserID = "USR-replacement"
+		require.NoError(t, repo.Create(ctx, replacement))
+		require.NoError(t, repo.Delete(ctx, replacement.ID))
+
+		// A restored user can log in again: login looks the user up by email and requires an
+		// active account, and so does every authenticated request
+		require.NoError(t, repo.Restore(ctx, user.ID))
+		restored, err := repo.GetByEmail(ctx, user.Email)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, restored.ID)
+		assert.Nil(t, restored.DeletedAt)
+		assert.True(t, restored.IsActive)
+	})
+
+	t.Run("a user deactivated before deletion stays deactivated when restored", func(t *testing.T) {
+		user := newTestUser("deactivated@example.com", "investor")
+		require.NoError(t, repo.Create(ctx, user))
+		user.IsActive = false
+		require.NoError(t, repo.Update(ctx, user))
+
+		require.NoError(t, repo.Delete(ctx, user.ID))
+		require.NoError(t, repo.Restore(ctx, user.ID))
+
+		restored, err := repo.GetByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.False(t, restored.IsActive)
+	})
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/repositories"
+	"github.com/sswastioyono18/loan-engine/internal/repositories/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	mockAPIKeyRepo := mocks.NewAPIKeyRepository(t)
+	mockSecurityEventRepo, events := recordSecurityEvents(t)
+	service := NewAPIKeyService(mockAPIKeyRepo, mockSecurityEventRepo)
+	ctx := adminContext()
+
+	var stored *models.APIKey
+	mockAPIKeyRepo.On("Create", ctx, mock.AnythingOfType("*models.APIKey")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*models.APIKey) }).
+		Return(nil)
+
+	created, err := service.CreateAPIKey(ctx, "Partner channel", []string{"loans:write", "investors:read"}, nil)
+
+	require.NoError(t, err)
+	assert.Regexp(t, `^lek_[0-9a-f]{8}$`, stored.Prefix)
+	assert.True(t, strings.HasPrefix(created.Key, stored.Prefix+"."))
+	// Only the secret's hash is stored
+	assert.NotContains(t, stored.SecretHash, strings.TrimPrefix(created.Key, stored.Prefix+"."))
+	assert.Equal(t, "ADM001", stored.CreatedBy)
+	assert.Equal(t, []string{SecurityEventAPIKeyCreated}, eventTypes(*events))
+}
+
+func TestCreateAPIKeyValidates(t *testing.T) {
+	service := NewAPIKeyService(nil, nil)
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		keyName   string
+		scopes    []string
+		expiresAt *time.Time
+	}{
+		{"no name", "", []string{"loans:read"}, nil},
+		{"no scopes", "Partner", nil, nil},
+		{"unknown scope", "Partner", []string{"loans:approve"}, nil},
+		{"expiry in the past", "Partner", []string{"loans:read"}, &past},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateAPIKey(adminContext(), tt.keyName, tt.scopes, tt.expiresAt)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	ctx := context.Background()
+	past := time.Now().Add(-time.Minute)
+	key := func() *models.APIKey {
+		return &models.APIKey{ID: 7, Prefix: "lek_1a2b3c4d", SecretHash: hashToken("secret"), Scopes: []string{"loans:read"}}
+	}
+
+	t.Run("accepts the key and records its use", func(t *testing.T) {
+		mockAPIKeyRepo := mocks.NewAPIKeyRepository(t)
+		service := NewAPIKeyService(mockAPIKeyRepo, nil)
+		mockAPIKeyRepo.On("GetByPrefix", ctx, "lek_1a2b3c4d").Return(key(), nil)
+		mockAPIKeyRepo.On("TouchLastUsed", ctx, 7).Return(nil)
+
+		authenticated, err := service.AuthenticateAPIKey(ctx, "lek_1a2b3c4d.secret")
+
+		require.NoError(t, err)
+		assert.Equal(t, 7, authenticated.ID)
+	})
+
+	tests := []struct {
+		name   string
+		rawKey string
+		stored *models.APIKey
+		err    error
+	}{
+		{name: "malformed", rawKey: "secret"},
+		{name: "unknown prefix", rawKey: "lek_00000000.secret", err: repositories.ErrAPIKeyNotFound},
+		{name: "wrong secret", rawKey: "lek_1a2b3c4d.guess", stored: key()},
+		{name: "expired", rawKey: "lek_1a2b3c4d.secret", stored: func() *models.APIKey { k := key(); k.ExpiresAt = &past; return k }()},
+		{name: "revoked", rawKey: "lek_1a2b3c4d.secret", stored: func() *models.APIKey { k := key(); k.RevokedAt = &past; return k }()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPIKeyRepo := mocks.NewAPIKeyRepository(t)
+			service := NewAPIKeyService(mockAPIKeyRepo, nil)
+			if tt.stored != nil || tt.err != nil {
+				mockAPIKeyRepo.On("GetByPrefix", ctx, mock.Anything).Return(tt.stored, tt.err)
+			}
+
+			_, err := service.AuthenticateAPIKey(ctx, tt.rawKey)
+
+			assert.ErrorIs(t, err, ErrInvalidAPIKey)
+		})
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	mockAPIKeyRepo := mocks.NewAPIKeyRepository(t)
+	mockSecurityEventRepo, events := recordSecurityEvents(t)
+	service := NewAPIKeyService(mockAPIKeyRepo, mockSecurityEventRepo)
+	ctx := adminContext()
+
+	mockAPIKeyRepo.On("GetByID", ctx, 7).Return(&models.APIKey{ID: 7, Prefix: "lek_1a2b3c4d", Name: "Partner"}, nil)
+	mockAPIKeyRepo.On("Revoke", ctx, 7).Return(nil)
+
+	require.NoError(t, service.RevokeAPIKey(ctx, 7))
+	assert.Equal(t, []string{SecurityEventAPIKeyRevoked}, eventTypes(*events))
+	assert.Equal(t, "lek_1a2b3c4d (Partner) by ADM001", (*events)[0].Detail)
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/authz"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+var (
+	ErrInvestorRegistrationOnly = fmt.Errorf("%w: only investors can register; staff and admin accounts are created by an admin", authz.ErrForbidden)
+	ErrInvalidVerificationToken = apperr.Validation("invalid_verification_token", "invalid or expired email verification token")
+)
+
+// InvestorRegistration is what a prospective investor signs up with
+type InvestorRegistration struct {
+	Email    string
+	Password string
+	FullName string
+	Phone    string
+	// UserType may be left empty; anything but investor is refused
+	UserType string
+}
+
+// RegisterInvestor creates an investor account linked to the investor record with the same
+// email, creating that record when there is none. The account cannot log in until the
+// email address is verified with the token mailed to it.
+func (s *authServiceImpl) RegisterInvestor(ctx context.Context, registration *InvestorRegistration) (*models.User, error) {
+	if registration.UserType != "" && registration.UserType != authz.RoleInvestor {
+		return nil, ErrInvestorRegistrationOnly
+	}
+
+	if strings.TrimSpace(registration.Email) == "" || strings.TrimSpace(registration.FullName) == "" {
+		return nil, apperr.Validation("invalid_registration", "email and full name are required")
+	}
+
+	// Check if user already exists
+	existingUser, err := s.userRepo.GetByEmail(ctx, registration.Email)
+	if err == nil && existingUser != nil {
+		return nil, apperr.Conflict("email_taken", "user with email %s already exists", registration.Email)
+	}
+
+	if err := ValidatePassword(registration.Password, registration.Email); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := s.HashPassword(registration.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := &models.User{
+		Email:        registration.Email,
+		PasswordHash: hashedPassword,
+		UserType:     authz.RoleInvestor,
+		FullName:     registration.FullName,
+		IsActive:     true,
+	}
+
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		investor, err := s.investorRepo.GetByEmail(ctx, registration.Email)
+		if err != nil {
+			investor = &models.Investor{
+				FullName: registration.FullName,
+				Email:    registration.Email,
+				Phone:    registration.Phone,
+			}
+			if investor.InvestorID, err = newPublicID("INV"); err != nil {
+				return err
+			}
+			if err := s.investorRepo.Create(ctx, investor); err != nil {
+				return fmt.Errorf("failed to create investor: %w", err)
+			}
+		}
+
+		user.InvestorID = &investor.ID
+		if user.UserID, err = newPublicID("USR"); err != nil {
+			return err
+		}
+
+		return s.userRepo.Create(ctx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.record(ctx, SecurityEventUserRegistered, user, "", "")
+
+	if err := s.sendEmailVerification(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// VerifyEmail marks the email address of the account a verification token was mailed to as
+// verified, which lets the account log in
+func (s *authServiceImpl) VerifyEmail(ctx context.Context, verificationToken string) error {
+	stored, err := s.verificationTokenRepo.GetByHash(ctx, hashToken(verificationToken))
+	if err != nil || stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return ErrInvalidVerificationToken
+	}
+
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		claimed, err := s.verificationTokenRepo.MarkUsed(ctx, stored.ID)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return ErrInvalidVerificationToken
+		}
+
+		return s.userRepo.MarkEmailVerified(ctx, stored.UserID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.audit.record(ctx, SecurityEventEmailVerified, &models.User{ID: stored.UserID}, "", "")
+
+	return nil
+}
+
+// ResendEmailVerification mails a new verification token to an unverified account. Like
+// RequestPasswordReset it succeeds silently for unknown emails.
+func (s *authServiceImpl) ResendEmailVerification(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil || !user.IsActive || user.EmailVerified {
+		return nil
+	}
+
+	return s.sendEmailVerification(ctx, user)
+}
+
+func (s *authServiceImpl) sendEmailVerification(ctx context.Context, user *models.User) error {
+	verificationToken, err := newOpaqueToken(32)
+	if err != nil {
+		return fmt.Errorf("failed to generate email verification token: %w", err)
+	}
+
+	// Only the most recently mailed token works
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := s.verificationTokenRepo.InvalidateForUser(ctx, user.ID); err != nil {
+			return err
+		}
+
+		return s.verificationTokenRepo.Create(ctx, &models.EmailVerificationToken{
+			UserID:    user.ID,
+			TokenHash: hashToken(verificationToken),
+			ExpiresAt: time.Now().Add(emailVerificationTTL),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create email verification token: %w", err)
+	}
+
+	if err := s.emailService.SendEmailVerification(ctx, user.Email, verificationToken, emailVerificationTTL); err != nil {
+		return fmt.Errorf("failed to send email verification: %w", err)
+	}
+
+	return nil
+}
+
+// newPublicID returns an identifier such as USR-3F9A0C12B7 for records whose public ID the
+// system assigns
+func newPublicID(prefix string) (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate %s ID: %w", prefix, err)
+	}
+
+	return prefix + "-" + strings.ToUpper(hex.EncodeToString(b)), nil
+}
@@ -2,151 +2,567 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
 	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/signing"
+	"github.com/sswastioyono18/loan-engine/pkg/external"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Access tokens are short-lived; clients keep their session going with the refresh token,
+// which is rotated on every use
+const (
+	accessTokenTTL       = 15 * time.Minute
+	refreshTokenTTL      = 30 * 24 * time.Hour
+	passwordResetTTL     = time.Hour
+	emailVerificationTTL = 24 * time.Hour
+)
+
+// Progressive lockout: an account locks after maxFailedLogins wrong passwords in a row, for
+// lockoutBase at first and twice as long after every further failure, up to lockoutMax
+const (
+	maxFailedLogins = 5
+	lockoutBase     = time.Minute
+	lockoutMax      = 24 * time.Hour
+)
+
+var (
+	ErrInvalidCredentials  = apperr.Unauthenticated("invalid_credentials", "invalid credentials")
+	ErrAccountLocked       = apperr.Locked("account_locked", "account is temporarily locked after too many failed logins")
+	ErrInvalidRefreshToken = apperr.Unauthenticated("invalid_refresh_token", "invalid refresh token")
+	ErrRefreshTokenReused  = apperr.Unauthenticated("refresh_token_reused", "refresh token was already used; the session has been revoked")
+	ErrInvalidResetToken   = apperr.Validation("invalid_reset_token", "invalid or expired password reset token")
+	ErrEmailNotVerified    = apperr.Forbidden("email_not_verified", "email address is not verified yet")
+	ErrAccountDeactivated  = apperr.Forbidden("account_deactivated", "user account is deactivated")
+	ErrIncorrectPassword   = apperr.Validation("incorrect_password", "password is incorrect")
+	ErrInvalidToken        = apperr.Unauthenticated("invalid_token", "invalid token")
+)
+
 type AuthService interface {
-	RegisterUser(ctx context.Context, user *models.User, password string) error
-	LoginUser(ctx context.Context, email, password string) (string, error)
-	RefreshToken(ctx context.Context, refreshToken string) (string, error)
+	RegisterInvestor(ctx context.Context, registration *InvestorRegistration) (*models.User, error)
+	VerifyEmail(ctx context.Context, verificationToken string) error
+	ResendEmailVerification(ctx context.Context, email string) error
+	LoginUser(ctx context.Context, email, password string) (*LoginResult, error)
+	VerifyLoginTOTP(ctx context.Context, challengeToken, code string) (*LoginResult, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+	LogoutAll(ctx context.Context, userID int) error
 	ValidateToken(ctx context.Context, token string) (*models.User, error)
 	HashPassword(password string) (string, error)
 	CheckPasswordHash(password, hash string) bool
+
+	// Password management
+	ChangePassword(ctx context.Context, userID int, currentPassword, newPassword string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, resetToken, newPassword string) error
+
+	// Two-factor authentication
+	EnrolTOTP(ctx context.Context, userID int) (*TOTPEnrolment, error)
+	ConfirmTOTP(ctx context.Context, userID int, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, userID int, password string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID int, code string) ([]string, error)
 }
 
 type authServiceImpl struct {
-	userRepo  UserRepository
-	jwtSecret string
+	userRepo              UserRepository
+	investorRepo          InvestorRepository
+	refreshTokenRepo      RefreshTokenRepository
+	resetTokenRepo        PasswordResetTokenRepository
+	verificationTokenRepo EmailVerificationTokenRepository
+	recoveryCodeRepo      RecoveryCodeRepository
+	unitOfWork            UnitOfWork
+	emailService          external.EmailService
+	signingKeys           *signing.Manager
+	resets                passwordResets
+	audit                 securityAudit
 }
 
 type Claims struct {
 	UserID   int    `json:"user_id"`
 	Email    string `json:"email"`
 	UserType string `json:"user_type"`
+	// Purpose is empty for access tokens and names what any other token is for, such as
+	// a TOTP login challenge; ValidateToken accepts access tokens only
+	Purpose string `json:"purpose,omitempty"`
+	// TOTPSecretHash binds a TOTP challenge that enrols the user to the secret it offered
+	TOTPSecretHash string `json:"totp_secret_hash,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func NewAuthService(userRepo UserRepository, jwtSecret string) AuthService {
+// TokenPair is handed to the client on login and on every refresh
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+func NewAuthService(
+	userRepo UserRepository,
+	investorRepo InvestorRepository,
+	refreshTokenRepo RefreshTokenRepository,
+	resetTokenRepo PasswordResetTokenRepository,
+	verificationTokenRepo EmailVerificationTokenRepository,
+	recoveryCodeRepo RecoveryCodeRepository,
+	securityEventRepo SecurityEventRepository,
+	unitOfWork UnitOfWork,
+	emailService external.EmailService,
+	signingKeys *signing.Manager,
+) AuthService {
 	return &authServiceImpl{
-		userRepo:  userRepo,
-		jwtSecret: jwtSecret,
+		userRepo:              userRepo,
+		investorRepo:          investorRepo,
+		refreshTokenRepo:      refreshTokenRepo,
+		resetTokenRepo:        resetTokenRepo,
+		verificationTokenRepo: verificationTokenRepo,
+		recoveryCodeRepo:      recoveryCodeRepo,
+		unitOfWork:            unitOfWork,
+		emailService:          emailService,
+		signingKeys:           signingKeys,
+		resets:                passwordResets{repo: resetTokenRepo, unitOfWork: unitOfWork, emailService: emailService},
+		audit:                 securityAudit{repo: securityEventRepo},
 	}
 }
 
-func (s *authServiceImpl) RegisterUser(ctx context.Context, user *models.User, password string) error {
-	// Check if user already exists
-	existingUser, err := s.userRepo.GetByEmail(ctx, user.Email)
-	if err == nil && existingUser != nil {
-		return fmt.Errorf("user with email %s already exists", user.Email)
+// LoginUser checks the password. Users without two-factor authentication get their tokens
+// straight away; the others get a challenge to answer with VerifyLoginTOTP.
+func (s *authServiceImpl) LoginUser(ctx context.Context, email, password string) (*LoginResult, error) {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		s.audit.record(ctx, SecurityEventLoginFailed, nil, email, "unknown email")
+		return nil, ErrInvalidCredentials
 	}
 
-	// Hash the password
-	hashedPassword, err := s.HashPassword(password)
-	if err != nil {
-		return fmt.Errorf("failed to hash password: %w", err)
+	// Only the password holder learns that the account is deactivated
+	if err := s.verifyPassword(ctx, user, password); err != nil {
+		return nil, err
+	}
+
+	if !user.IsActive {
+		return nil, ErrAccountDeactivated
+	}
+
+	if !user.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+
+	if requiresTOTP(user) {
+		challenge, err := s.newTOTPChallenge(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+
+		return &LoginResult{Challenge: challenge}, nil
 	}
 
-	user.PasswordHash = hashedPassword
-	user.IsActive = true
+	tokens, err := s.completeLogin(ctx, user)
+	if err != nil {
+		return nil, err
+	}
 
-	return s.userRepo.Create(ctx, user)
+	return &LoginResult{TokenPair: tokens}, nil
 }
 
-func (s *authServiceImpl) LoginUser(ctx context.Context, email, password string) (string, error) {
-	user, err := s.userRepo.GetByEmail(ctx, email)
+// completeLogin starts a session for user once every login step has passed
+func (s *authServiceImpl) completeLogin(ctx context.Context, user *models.User) (*TokenPair, error) {
+	// Every login starts a new token family
+	familyID, err := newOpaqueToken(16)
 	if err != nil {
-		return "", fmt.Errorf("invalid credentials")
+		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
-	if !user.IsActive {
-		return "", fmt.Errorf("user account is deactivated")
+	tokens, err := s.issueTokens(ctx, user, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.record(ctx, SecurityEventLoginSucceeded, user, "", "")
+
+	return tokens, nil
+}
+
+// verifyPassword checks password for user under the lockout policy: a locked account is
+// refused outright, a wrong password counts towards the lock, and a right one clears the
+// count unless a TOTP code is still to come
+func (s *authServiceImpl) verifyPassword(ctx context.Context, user *models.User, password string) error {
+	if err := s.checkNotLocked(ctx, user); err != nil {
+		return err
 	}
 
 	if !s.CheckPasswordHash(password, user.PasswordHash) {
-		return "", fmt.Errorf("invalid credentials")
+		if err := s.recordFailedLogin(ctx, user, "wrong password"); err != nil {
+			return err
+		}
+		return ErrInvalidCredentials
 	}
 
-	// Generate JWT token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
-		UserID:   user.ID,
-		Email:    user.Email,
-		UserType: user.UserType,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    "loan-engine",
-		},
-	})
+	if requiresTOTP(user) {
+		return nil
+	}
+
+	return s.clearFailedLogins(ctx, user)
+}
 
-	tokenString, err := token.SignedString([]byte(s.jwtSecret))
+func (s *authServiceImpl) checkNotLocked(ctx context.Context, user *models.User) error {
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		s.audit.record(ctx, SecurityEventLoginBlocked, user, "", "account is locked")
+		return fmt.Errorf("%w: try again after %s", ErrAccountLocked, user.LockedUntil.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// recordFailedLogin counts a failed login step for user and locks the account once there
+// have been too many in a row
+func (s *authServiceImpl) recordFailedLogin(ctx context.Context, user *models.User, reason string) error {
+	failures, err := s.userRepo.RecordFailedLogin(ctx, user.ID)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate token: %w", err)
+		return fmt.Errorf("failed to record failed login: %w", err)
+	}
+	s.audit.record(ctx, SecurityEventLoginFailed, user, "", fmt.Sprintf("%s, %d in a row", reason, failures))
+
+	if failures >= maxFailedLogins {
+		until := time.Now().Add(lockoutDuration(failures))
+		if err := s.userRepo.LockUntil(ctx, user.ID, until); err != nil {
+			return fmt.Errorf("failed to lock account: %w", err)
+		}
+		s.audit.record(ctx, SecurityEventAccountLocked, user, "", fmt.Sprintf("locked until %s", until.UTC().Format(time.RFC3339)))
+	}
+
+	return nil
+}
+
+func (s *authServiceImpl) clearFailedLogins(ctx context.Context, user *models.User) error {
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		if err := s.userRepo.ResetFailedLogins(ctx, user.ID); err != nil {
+			return fmt.Errorf("failed to reset failed logins: %w", err)
+		}
 	}
 
-	return tokenString, nil
+	return nil
 }
 
-func (s *authServiceImpl) RefreshToken(ctx context.Context, refreshToken string) (string, error) {
-	// In a real implementation, you would validate the refresh token
-	// For now, we'll just generate a new access token
-	// This is a simplified implementation - in production, you'd want to store and validate refresh tokens
+// lockoutDuration is how long the account locks for after failures wrong passwords in a row
+func lockoutDuration(failures int) time.Duration {
+	duration := lockoutBase
+	for i := maxFailedLogins; i < failures && duration < lockoutMax; i++ {
+		duration *= 2
+	}
+
+	return min(duration, lockoutMax)
+}
+
+// RefreshToken rotates a refresh token: the presented token is used up and a new pair is
+// issued in the same family. Presenting a token that was already used means it has leaked,
+// so the whole family is revoked and the user has to log in again.
+func (s *authServiceImpl) RefreshToken(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	stored, err := s.refreshTokenRepo.GetByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if stored.UsedAt != nil {
+		return nil, s.revokeReusedFamily(ctx, stored)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if !user.IsActive {
+		return nil, ErrAccountDeactivated
+	}
+
+	var pair *TokenPair
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		// Losing this race to a concurrent refresh with the same token counts as reuse
+		claimed, err := s.refreshTokenRepo.MarkUsed(ctx, stored.ID)
+		if err != nil {
+			return fmt.Errorf("failed to rotate refresh token: %w", err)
+		}
+		if !claimed {
+			return ErrRefreshTokenReused
+		}
 
-	token, err := jwt.ParseWithClaims(refreshToken, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.jwtSecret), nil
+		pair, err = s.issueTokens(ctx, user, stored.FamilyID)
+		return err
 	})
+	if errors.Is(err, ErrRefreshTokenReused) {
+		return nil, s.revokeReusedFamily(ctx, stored)
+	}
+	if err != nil {
+		return nil, err
+	}
 
+	return pair, nil
+}
+
+// Logout revokes the family of the given refresh token, ending that one session
+func (s *authServiceImpl) Logout(ctx context.Context, refreshToken string) error {
+	stored, err := s.refreshTokenRepo.GetByHash(ctx, hashToken(refreshToken))
 	if err != nil {
-		return "", fmt.Errorf("invalid refresh token: %w", err)
+		return ErrInvalidRefreshToken
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		// Generate new access token
-		newToken := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
-			UserID:   claims.UserID,
-			Email:    claims.Email,
-			UserType: claims.UserType,
-			RegisteredClaims: jwt.RegisteredClaims{
-				ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-				IssuedAt:  jwt.NewNumericDate(time.Now()),
-				Issuer:    "loan-engine",
-			},
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
+
+// LogoutAll revokes every refresh token of the user, ending all of their sessions
+func (s *authServiceImpl) LogoutAll(ctx context.Context, userID int) error {
+	if err := s.refreshTokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
+
+func (s *authServiceImpl) revokeReusedFamily(ctx context.Context, stored *models.RefreshToken) error {
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke session after refresh token reuse: %w", err)
+	}
+	s.audit.record(ctx, SecurityEventRefreshTokenReused, &models.User{ID: stored.UserID}, "", "session revoked")
+
+	return ErrRefreshTokenReused
+}
+
+// ChangePassword replaces the password of a signed-in user, who must confirm the current
+// one. Every session is signed out, so a stolen session does not survive the change.
+func (s *authServiceImpl) ChangePassword(ctx context.Context, userID int, currentPassword, newPassword string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.verifyPassword(ctx, user, currentPassword); err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			return fmt.Errorf("current %w", ErrIncorrectPassword)
+		}
+		return err
+	}
+
+	if newPassword == currentPassword {
+		return apperr.Validation("password_unchanged", "new password must differ from the current one")
+	}
+
+	if err := s.setPassword(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	s.audit.record(ctx, SecurityEventPasswordChanged, user, "", "")
+
+	return nil
+}
+
+// RequestPasswordReset mails a single-use reset token to the account with email. Unknown and
+// deactivated accounts get the same silent success, so the endpoint does not reveal which
+// emails are registered.
+func (s *authServiceImpl) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil || !user.IsActive {
+		s.audit.record(ctx, SecurityEventPasswordResetRequested, nil, email, "no active account")
+		return nil
+	}
+
+	if err := s.resets.send(ctx, user); err != nil {
+		return err
+	}
+
+	s.audit.record(ctx, SecurityEventPasswordResetRequested, user, "", "")
+
+	return nil
+}
+
+// passwordResets issues password reset tokens and mails them, when a user asks for one and
+// when an admin forces a reset
+type passwordResets struct {
+	repo         PasswordResetTokenRepository
+	unitOfWork   UnitOfWork
+	emailService external.EmailService
+}
+
+func (p passwordResets) send(ctx context.Context, user *models.User) error {
+	resetToken, err := newOpaqueToken(32)
+	if err != nil {
+		return fmt.Errorf("failed to generate password reset token: %w", err)
+	}
+
+	// Only the most recently mailed token works
+	err = p.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := p.repo.InvalidateForUser(ctx, user.ID); err != nil {
+			return err
+		}
+
+		return p.repo.Create(ctx, &models.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: hashToken(resetToken),
+			ExpiresAt: time.Now().Add(passwordResetTTL),
 		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create password reset token: %w", err)
+	}
 
-		newTokenString, err := newToken.SignedString([]byte(s.jwtSecret))
+	if err := p.emailService.SendPasswordReset(ctx, user.Email, resetToken, passwordResetTTL); err != nil {
+		return fmt.Errorf("failed to send password reset email: %w", err)
+	}
+
+	return nil
+}
+
+// ResetPassword sets a new password with a token from RequestPasswordReset. The token is
+// used up, the account is unlocked and every session is signed out.
+func (s *authServiceImpl) ResetPassword(ctx context.Context, resetToken, newPassword string) error {
+	stored, err := s.resetTokenRepo.GetByHash(ctx, hashToken(resetToken))
+	if err != nil || stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		claimed, err := s.resetTokenRepo.MarkUsed(ctx, stored.ID)
 		if err != nil {
-			return "", fmt.Errorf("failed to generate new token: %w", err)
+			return err
+		}
+		if !claimed {
+			return ErrInvalidResetToken
 		}
 
-		return newTokenString, nil
+		if err := s.userRepo.ResetFailedLogins(ctx, user.ID); err != nil {
+			return err
+		}
+
+		return s.setPassword(ctx, user, newPassword)
+	})
+	if err != nil {
+		return err
 	}
 
-	return "", fmt.Errorf("invalid refresh token")
+	s.audit.record(ctx, SecurityEventPasswordReset, user, "", "")
+
+	return nil
 }
 
-func (s *authServiceImpl) ValidateToken(ctx context.Context, tokenString string) (*models.User, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.jwtSecret), nil
+// setPassword validates, hashes and stores a new password and signs out every session
+func (s *authServiceImpl) setPassword(ctx context.Context, user *models.User, password string) error {
+	if err := ValidatePassword(password, user.Email); err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := s.refreshTokenRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
+
+// issueTokens signs an access token for user and stores a fresh refresh token in familyID
+func (s *authServiceImpl) issueTokens(ctx context.Context, user *models.User, familyID string) (*TokenPair, error) {
+	now := time.Now()
+	accessToken, err := s.signingKeys.Sign(Claims{
+		UserID:   user.ID,
+		Email:    user.Email,
+		UserType: user.UserType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(accessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "loan-engine",
+		},
 	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	refreshToken, err := newOpaqueToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	err = s.refreshTokenRepo.Create(ctx, &models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(refreshTokenTTL),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(accessTokenTTL.Seconds()),
+	}, nil
+}
+
+// newOpaqueToken returns n random bytes encoded for use in URLs and JSON
+func newOpaqueToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken is how refresh tokens are stored and looked up; the raw token never is
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *authServiceImpl) ValidateToken(ctx context.Context, tokenString string) (*models.User, error) {
+	token, err := s.signingKeys.Parse(tokenString, &Claims{})
 
 	if err != nil {
-		return nil, fmt.Errorf("invalid token: %w", err)
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
 	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		if claims.Purpose != "" {
+			return nil, fmt.Errorf("%w: not an access token", ErrInvalidToken)
+		}
+
 		user, err := s.userRepo.GetByID(ctx, claims.UserID)
 		if err != nil {
-			return nil, fmt.Errorf("user not found: %w", err)
+			return nil, err
 		}
 
 		return user, nil
 	}
 
-	return nil, fmt.Errorf("invalid token")
+	return nil, ErrInvalidToken
 }
 
 func (s *authServiceImpl) HashPassword(password string) (string, error) {
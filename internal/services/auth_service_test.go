@@ -2,45 +2,145 @@ package services
 
 import (
 	"context"
-	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/authz"
 	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/repositories"
 	"github.com/sswastioyono18/loan-engine/internal/repositories/mocks"
+	"github.com/sswastioyono18/loan-engine/internal/signing"
+	mocks2 "github.com/sswastioyono18/loan-engine/pkg/external/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
-func TestRegisterUser(t *testing.T) {
+func testSigningKeys(t *testing.T) *signing.Manager {
+	keys, err := signing.NewHMACManager("test-secret")
+	require.NoError(t, err)
+	return keys
+}
+
+// newMockSecurityEvents returns a SecurityEventRepository mock that accepts any event
+func newMockSecurityEvents(t *testing.T) *mocks.SecurityEventRepository {
+	mockSecurityEventRepo := mocks.NewSecurityEventRepository(t)
+	mockSecurityEventRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.SecurityEvent")).Return(nil).Maybe()
+	return mockSecurityEventRepo
+}
+
+// recordSecurityEvents returns a SecurityEventRepository mock and the event types written to it
+func recordSecurityEvents(t *testing.T) (*mocks.SecurityEventRepository, *[]*models.SecurityEvent) {
+	var events []*models.SecurityEvent
+	mockSecurityEventRepo := mocks.NewSecurityEventRepository(t)
+	mockSecurityEventRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.SecurityEvent")).
+		Run(func(args mock.Arguments) { events = append(events, args.Get(1).(*models.SecurityEvent)) }).
+		Return(nil).Maybe()
+	return mockSecurityEventRepo, &events
+}
+
+func eventTypes(events []*models.SecurityEvent) []string {
+	types := make([]string, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.EventType)
+	}
+	return types
+}
+
+func TestRegisterInvestor(t *testing.T) {
 	mockUserRepo := mocks.NewUserRepository(t)
-	service := NewAuthService(mockUserRepo, "test-secret")
+	mockInvestorRepo := mocks.NewInvestorRepository(t)
+	mockVerificationTokenRepo := mocks.NewEmailVerificationTokenRepository(t)
+	mockEmailService := mocks2.NewEmailService(t)
+	service := NewAuthService(mockUserRepo, mockInvestorRepo, nil, nil, mockVerificationTokenRepo, nil, newMockSecurityEvents(t), newMockUnitOfWork(t), mockEmailService, testSigningKeys(t))
 
-	user := &models.User{
+	registration := &InvestorRegistration{
 		Email:    "test@example.com",
-		UserType: "investor",
+		Password: "correct-horse-42",
 		FullName: "Test User",
+		Phone:    "+628123456789",
 	}
 
-	// Test successful registration
-	mockUserRepo.On("GetByEmail", context.Background(), user.Email).Return(nil, errors.New("user not found"))
+	// Test successful registration: a new investor record is created and linked
+	mockUserRepo.On("GetByEmail", context.Background(), registration.Email).Return(nil, repositories.ErrUserNotFound)
+	mockInvestorRepo.On("GetByEmail", context.Background(), registration.Email).Return(nil, repositories.ErrInvestorNotFound)
+	var investor *models.Investor
+	mockInvestorRepo.On("Create", context.Background(), mock.AnythingOfType("*models.Investor")).
+		Run(func(args mock.Arguments) {
+			investor = args.Get(1).(*models.Investor)
+			investor.ID = 7
+		}).
+		Return(nil)
 	mockUserRepo.On("Create", context.Background(), mock.AnythingOfType("*models.User")).Return(nil)
-
-	err := service.RegisterUser(context.Background(), user, "password123")
-
-	assert.NoError(t, err)
+	mockVerificationTokenRepo.On("InvalidateForUser", context.Background(), mock.Anything).Return(nil)
+	var stored *models.EmailVerificationToken
+	mockVerificationTokenRepo.On("Create", context.Background(), mock.AnythingOfType("*models.EmailVerificationToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*models.EmailVerificationToken) }).
+		Return(nil)
+	var mailedToken string
+	mockEmailService.On("SendEmailVerification", context.Background(), registration.Email, mock.AnythingOfType("string"), 24*time.Hour).
+		Run(func(args mock.Arguments) { mailedToken = args.Get(2).(string) }).
+		Return(nil)
+
+	user, err := service.RegisterInvestor(context.Background(), registration)
+
+	require.NoError(t, err)
+	assert.Equal(t, "investor", user.UserType)
 	assert.True(t, len(user.PasswordHash) > 0) // Password should be hashed
 	assert.True(t, user.IsActive)
+	assert.False(t, user.EmailVerified)
+	assert.Regexp(t, `^USR-[0-9A-F]{10}$`, user.UserID)
+	assert.Regexp(t, `^INV-[0-9A-F]{10}$`, investor.InvestorID)
+	assert.Equal(t, registration.Phone, investor.Phone)
+	require.NotNil(t, user.InvestorID)
+	assert.Equal(t, 7, *user.InvestorID)
+	assert.Equal(t, hashToken(mailedToken), stored.TokenHash)
 }
 
-func TestRegisterUserDuplicateEmail(t *testing.T) {
+func TestRegisterInvestorLinksExistingInvestor(t *testing.T) {
 	mockUserRepo := mocks.NewUserRepository(t)
-	service := NewAuthService(mockUserRepo, "test-secret")
+	mockInvestorRepo := mocks.NewInvestorRepository(t)
+	mockVerificationTokenRepo := mocks.NewEmailVerificationTokenRepository(t)
+	mockEmailService := mocks2.NewEmailService(t)
+	service := NewAuthService(mockUserRepo, mockInvestorRepo, nil, nil, mockVerificationTokenRepo, nil, newMockSecurityEvents(t), newMockUnitOfWork(t), mockEmailService, testSigningKeys(t))
 
-	user := &models.User{
-		Email:    "test@example.com",
-		UserType: "investor",
-		FullName: "Test User",
+	registration := &InvestorRegistration{Email: "jane@example.com", Password: "correct-horse-42", FullName: "Jane Smith"}
+
+	mockUserRepo.On("GetByEmail", context.Background(), registration.Email).Return(nil, repositories.ErrUserNotFound)
+	mockInvestorRepo.On("GetByEmail", context.Background(), registration.Email).Return(&models.Investor{ID: 3, Email: registration.Email}, nil)
+	mockUserRepo.On("Create", context.Background(), mock.AnythingOfType("*models.User")).Return(nil)
+	mockVerificationTokenRepo.On("InvalidateForUser", context.Background(), mock.Anything).Return(nil)
+	mockVerificationTokenRepo.On("Create", context.Background(), mock.AnythingOfType("*models.EmailVerificationToken")).Return(nil)
+	mockEmailService.On("SendEmailVerification", context.Background(), registration.Email, mock.AnythingOfType("string"), 24*time.Hour).Return(nil)
+
+	user, err := service.RegisterInvestor(context.Background(), registration)
+
+	require.NoError(t, err)
+	require.NotNil(t, user.InvestorID)
+	assert.Equal(t, 3, *user.InvestorID)
+}
+
+func TestRegisterInvestorRefusesOtherUserTypes(t *testing.T) {
+	service := NewAuthService(nil, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+	for _, userType := range []string{"admin", "staff"} {
+		_, err := service.RegisterInvestor(context.Background(), &InvestorRegistration{
+			Email:    "test@example.com",
+			Password: "correct-horse-42",
+			FullName: "Test User",
+			UserType: userType,
+		})
+
+		assert.ErrorIs(t, err, ErrInvestorRegistrationOnly)
+		assert.ErrorIs(t, err, authz.ErrForbidden)
 	}
+}
+
+func TestRegisterInvestorDuplicateEmail(t *testing.T) {
+	mockUserRepo := mocks.NewUserRepository(t)
+	service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
 
 	existingUser := &models.User{
 		ID:       1,
@@ -50,62 +150,120 @@ func TestRegisterUserDuplicateEmail(t *testing.T) {
 	}
 
 	// Test duplicate email
-	mockUserRepo.On("GetByEmail", context.Background(), user.Email).Return(existingUser, nil)
+	mockUserRepo.On("GetByEmail", context.Background(), existingUser.Email).Return(existingUser, nil)
 
-	err := service.RegisterUser(context.Background(), user, "password123")
+	_, err := service.RegisterInvestor(context.Background(), &InvestorRegistration{Email: existingUser.Email, Password: "correct-horse-42", FullName: "Test User"})
 
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, apperr.ErrConflict)
 	assert.Contains(t, err.Error(), "already exists")
 }
 
-func TestRegisterUserPasswordHashError(t *testing.T) {
+func TestRegisterInvestorWeakPassword(t *testing.T) {
 	mockUserRepo := mocks.NewUserRepository(t)
-	service := NewAuthService(mockUserRepo, "test-secret")
+	service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
 
-	user := &models.User{
-		Email:    "test@example.com",
-		UserType: "investor",
-		FullName: "Test User",
-	}
+	// Passwords that fail the password policy are refused before anything is stored
+	mockUserRepo.On("GetByEmail", context.Background(), "test@example.com").Return(nil, repositories.ErrUserNotFound)
 
-	// Test successful registration with empty password (should still work)
-	mockUserRepo.On("GetByEmail", context.Background(), user.Email).Return(nil, errors.New("user not found"))
-	mockUserRepo.On("Create", context.Background(), mock.AnythingOfType("*models.User")).Return(nil)
+	_, err := service.RegisterInvestor(context.Background(), &InvestorRegistration{Email: "test@example.com", Password: "", FullName: "Test User"}) // Empty password
 
-	err := service.RegisterUser(context.Background(), user, "") // Empty password
+	assert.ErrorIs(t, err, ErrWeakPassword)
+}
 
-	assert.NoError(t, err)
-	assert.True(t, len(user.PasswordHash) > 0) // Password should be hashed even if empty
+func TestVerifyEmail(t *testing.T) {
+	t.Run("verifies the account", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockVerificationTokenRepo := mocks.NewEmailVerificationTokenRepository(t)
+		mockSecurityEventRepo, events := recordSecurityEvents(t)
+		service := NewAuthService(mockUserRepo, nil, nil, nil, mockVerificationTokenRepo, nil, mockSecurityEventRepo, newMockUnitOfWork(t), nil, testSigningKeys(t))
+
+		stored := &models.EmailVerificationToken{ID: 4, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}
+		mockVerificationTokenRepo.On("GetByHash", context.Background(), hashToken("verify-me")).Return(stored, nil)
+		mockVerificationTokenRepo.On("MarkUsed", context.Background(), stored.ID).Return(true, nil)
+		mockUserRepo.On("MarkEmailVerified", context.Background(), stored.UserID).Return(nil)
+
+		err := service.VerifyEmail(context.Background(), "verify-me")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{SecurityEventEmailVerified}, eventTypes(*events))
+	})
+
+	t.Run("refuses an expired token", func(t *testing.T) {
+		mockVerificationTokenRepo := mocks.NewEmailVerificationTokenRepository(t)
+		service := NewAuthService(nil, nil, nil, nil, mockVerificationTokenRepo, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+		stored := &models.EmailVerificationToken{ID: 4, UserID: 1, ExpiresAt: time.Now().Add(-time.Minute)}
+		mockVerificationTokenRepo.On("GetByHash", context.Background(), hashToken("verify-me")).Return(stored, nil)
+
+		err := service.VerifyEmail(context.Background(), "verify-me")
+
+		assert.ErrorIs(t, err, ErrInvalidVerificationToken)
+	})
 }
 
-func TestLoginUser(t *testing.T) {
+func TestLoginUserRequiresVerifiedEmail(t *testing.T) {
 	mockUserRepo := mocks.NewUserRepository(t)
-	service := NewAuthService(mockUserRepo, "test-secret")
+	service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
 
 	user := &models.User{
 		ID:           1,
 		Email:        "test@example.com",
 		UserType:     "investor",
-		FullName:     "Test User",
 		PasswordHash: "$2a$14$qxXQWcJG23rX0daSNJl6FO8I4V9Hj55ibaqUqzZHaa7x0UXv2djLa", // bcrypt hash for "password123"
 		IsActive:     true,
 	}
 
+	mockUserRepo.On("GetByEmail", context.Background(), user.Email).Return(user, nil)
+
+	_, err := service.LoginUser(context.Background(), user.Email, "password123")
+
+	assert.ErrorIs(t, err, ErrEmailNotVerified)
+}
+
+func TestLoginUser(t *testing.T) {
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+	service := NewAuthService(mockUserRepo, nil, mockRefreshTokenRepo, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+	user := &models.User{
+		ID:            1,
+		Email:         "test@example.com",
+		UserType:      "investor",
+		FullName:      "Test User",
+		PasswordHash:  "$2a$14$qxXQWcJG23rX0daSNJl6FO8I4V9Hj55ibaqUqzZHaa7x0UXv2djLa", // bcrypt hash for "password123"
+		IsActive:      true,
+		EmailVerified: true,
+	}
+
 	// Test successful login
 	mockUserRepo.On("GetByEmail", context.Background(), user.Email).Return(user, nil)
+	var stored *models.RefreshToken
+	mockRefreshTokenRepo.On("Create", context.Background(), mock.AnythingOfType("*models.RefreshToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*models.RefreshToken) }).
+		Return(nil)
 
-	token, err := service.LoginUser(context.Background(), user.Email, "password123")
+	tokens, err := service.LoginUser(context.Background(), user.Email, "password123")
 
 	assert.NoError(t, err)
-	assert.NotEmpty(t, token)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	assert.Equal(t, "Bearer", tokens.TokenType)
+	assert.Equal(t, 900, tokens.ExpiresIn)
+
+	// Only the hash of the refresh token is stored, in a new family
+	assert.Equal(t, user.ID, stored.UserID)
+	assert.Equal(t, hashToken(tokens.RefreshToken), stored.TokenHash)
+	assert.NotEqual(t, tokens.RefreshToken, stored.TokenHash)
+	assert.NotEmpty(t, stored.FamilyID)
+	assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), stored.ExpiresAt, time.Minute)
 }
 
 func TestLoginUserInvalidCredentials(t *testing.T) {
 	mockUserRepo := mocks.NewUserRepository(t)
-	service := NewAuthService(mockUserRepo, "test-secret")
+	service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
 
 	// Test invalid email
-	mockUserRepo.On("GetByEmail", context.Background(), "nonexistent@example.com").Return(nil, errors.New("user not found"))
+	mockUserRepo.On("GetByEmail", context.Background(), "nonexistent@example.com").Return(nil, repositories.ErrUserNotFound)
 
 	_, err := service.LoginUser(context.Background(), "nonexistent@example.com", "password123")
 
@@ -115,7 +273,7 @@ func TestLoginUserInvalidCredentials(t *testing.T) {
 
 func TestLoginUserInactiveAccount(t *testing.T) {
 	mockUserRepo := mocks.NewUserRepository(t)
-	service := NewAuthService(mockUserRepo, "test-secret")
+	service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
 
 	user := &models.User{
 		ID:           1,
@@ -135,9 +293,9 @@ func TestLoginUserInactiveAccount(t *testing.T) {
 	assert.Contains(t, err.Error(), "user account is deactivated")
 }
 
-func TestLoginUserInvalidPassword(t *testing.T) {
+func TestLoginUserInactiveAccountWrongPassword(t *testing.T) {
 	mockUserRepo := mocks.NewUserRepository(t)
-	service := NewAuthService(mockUserRepo, "test-secret")
+	service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
 
 	user := &models.User{
 		ID:           1,
@@ -145,11 +303,36 @@ func TestLoginUserInvalidPassword(t *testing.T) {
 		UserType:     "investor",
 		FullName:     "Test User",
 		PasswordHash: "$2a$14$qxXQWcJG23rX0daSNJl6FO8I4V9Hj55ibaqUqzZHaa7x0UXv2djLa", // bcrypt hash for "password123"
-		IsActive:     true,
+		IsActive:     false,
+	}
+
+	// A wrong password must not reveal that the account is deactivated
+	mockUserRepo.On("GetByEmail", context.Background(), user.Email).Return(user, nil)
+	mockUserRepo.On("RecordFailedLogin", context.Background(), user.ID).Return(1, nil)
+
+	_, err := service.LoginUser(context.Background(), user.Email, "wrongpassword")
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.NotErrorIs(t, err, ErrAccountDeactivated)
+}
+
+func TestLoginUserInvalidPassword(t *testing.T) {
+	mockUserRepo := mocks.NewUserRepository(t)
+	service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+	user := &models.User{
+		ID:            1,
+		Email:         "test@example.com",
+		UserType:      "investor",
+		FullName:      "Test User",
+		PasswordHash:  "$2a$14$qxXQWcJG23rX0daSNJl6FO8I4V9Hj55ibaqUqzZHaa7x0UXv2djLa", // bcrypt hash for "password123"
+		IsActive:      true,
+		EmailVerified: true,
 	}
 
 	// Test invalid password
 	mockUserRepo.On("GetByEmail", context.Background(), user.Email).Return(user, nil)
+	mockUserRepo.On("RecordFailedLogin", context.Background(), user.ID).Return(1, nil)
 
 	_, err := service.LoginUser(context.Background(), user.Email, "wrongpassword")
 
@@ -157,23 +340,388 @@ func TestLoginUserInvalidPassword(t *testing.T) {
 	assert.Contains(t, err.Error(), "invalid credentials")
 }
 
-func TestValidateToken(t *testing.T) {
+func TestLoginUserLocksAfterRepeatedFailures(t *testing.T) {
 	mockUserRepo := mocks.NewUserRepository(t)
-	service := NewAuthService(mockUserRepo, "test-secret")
+	mockSecurityEventRepo, events := recordSecurityEvents(t)
+	service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, mockSecurityEventRepo, nil, nil, testSigningKeys(t))
+	ctx := WithRequestInfo(context.Background(), RequestInfo{IPAddress: "203.0.113.7", UserAgent: "curl/8.0"})
 
 	user := &models.User{
-		ID:       1,
-		Email:    "test@example.com",
-		UserType: "investor",
-		FullName: "Test User",
-		IsActive: true,
+		ID:                  1,
+		Email:               "test@example.com",
+		PasswordHash:        "$2a$14$qxXQWcJG23rX0daSNJl6FO8I4V9Hj55ibaqUqzZHaa7x0UXv2djLa", // bcrypt hash for "password123"
+		IsActive:            true,
+		EmailVerified:       true,
+		FailedLoginAttempts: 4,
+	}
+
+	mockUserRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	mockUserRepo.On("RecordFailedLogin", ctx, user.ID).Return(5, nil)
+	var lockedUntil time.Time
+	mockUserRepo.On("LockUntil", ctx, user.ID, mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) { lockedUntil = args.Get(2).(time.Time) }).
+		Return(nil)
+
+	_, err := service.LoginUser(ctx, user.Email, "wrongpassword")
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), lockedUntil, 5*time.Second)
+	assert.Equal(t, []string{SecurityEventLoginFailed, SecurityEventAccountLocked}, eventTypes(*events))
+	assert.Equal(t, user.ID, *(*events)[1].UserID)
+	assert.Equal(t, "203.0.113.7", (*events)[1].IPAddress)
+	assert.Equal(t, "curl/8.0", (*events)[1].UserAgent)
+}
+
+func TestLoginUserRefusedWhileLocked(t *testing.T) {
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockSecurityEventRepo, events := recordSecurityEvents(t)
+	service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, mockSecurityEventRepo, nil, nil, testSigningKeys(t))
+
+	lockedUntil := time.Now().Add(10 * time.Minute)
+	user := &models.User{
+		ID:            1,
+		Email:         "test@example.com",
+		PasswordHash:  "$2a$14$qxXQWcJG23rX0daSNJl6FO8I4V9Hj55ibaqUqzZHaa7x0UXv2djLa", // bcrypt hash for "password123"
+		IsActive:      true,
+		EmailVerified: true,
+		LockedUntil:   &lockedUntil,
+	}
+
+	// Even the right password is refused, and nothing is counted
+	mockUserRepo.On("GetByEmail", context.Background(), user.Email).Return(user, nil)
+
+	_, err := service.LoginUser(context.Background(), user.Email, "password123")
+
+	assert.ErrorIs(t, err, ErrAccountLocked)
+	assert.Equal(t, []string{SecurityEventLoginBlocked}, eventTypes(*events))
+}
+
+func TestLoginUserClearsFailuresOnSuccess(t *testing.T) {
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+	service := NewAuthService(mockUserRepo, nil, mockRefreshTokenRepo, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+	lockedUntil := time.Now().Add(-time.Minute)
+	user := &models.User{
+		ID:                  1,
+		Email:               "test@example.com",
+		PasswordHash:        "$2a$14$qxXQWcJG23rX0daSNJl6FO8I4V9Hj55ibaqUqzZHaa7x0UXv2djLa", // bcrypt hash for "password123"
+		IsActive:            true,
+		EmailVerified:       true,
+		FailedLoginAttempts: 5,
+		LockedUntil:         &lockedUntil,
+	}
+
+	mockUserRepo.On("GetByEmail", context.Background(), user.Email).Return(user, nil)
+	mockUserRepo.On("ResetFailedLogins", context.Background(), user.ID).Return(nil)
+	mockRefreshTokenRepo.On("Create", context.Background(), mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+	_, err := service.LoginUser(context.Background(), user.Email, "password123")
+
+	assert.NoError(t, err)
+}
+
+func TestLockoutDurationIsProgressive(t *testing.T) {
+	assert.Equal(t, time.Minute, lockoutDuration(5))
+	assert.Equal(t, 2*time.Minute, lockoutDuration(6))
+	assert.Equal(t, 4*time.Minute, lockoutDuration(7))
+	assert.Equal(t, 24*time.Hour, lockoutDuration(50))
+}
+
+func TestValidatePassword(t *testing.T) {
+	tests := []struct {
+		password string
+		valid    bool
+	}{
+		{"correct-horse-42", true},
+		{"short-1", false},
+		{"onlylettersherefolks", false},
+		{"123456789012345", false},
+		{"jane.doe-2026-secret", false},
+		{strings.Repeat("a1", 37), false},
+	}
+
+	for _, tt := range tests {
+		err := ValidatePassword(tt.password, "jane.doe@example.com")
+		if tt.valid {
+			assert.NoError(t, err, tt.password)
+		} else {
+			assert.ErrorIs(t, err, ErrWeakPassword, tt.password)
+		}
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	user := &models.User{
+		ID:           1,
+		Email:        "test@example.com",
 		PasswordHash: "$2a$14$qxXQWcJG23rX0daSNJl6FO8I4V9Hj55ibaqUqzZHaa7x0UXv2djLa", // bcrypt hash for "password123"
+		IsActive:     true,
+	}
+
+	t.Run("signs out every session", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+		mockSecurityEventRepo, events := recordSecurityEvents(t)
+		service := NewAuthService(mockUserRepo, nil, mockRefreshTokenRepo, nil, nil, nil, mockSecurityEventRepo, nil, nil, testSigningKeys(t))
+
+		mockUserRepo.On("GetByID", context.Background(), user.ID).Return(user, nil)
+		mockUserRepo.On("UpdatePassword", context.Background(), user.ID, mock.AnythingOfType("string")).Return(nil)
+		mockRefreshTokenRepo.On("RevokeAllForUser", context.Background(), user.ID).Return(nil)
+
+		err := service.ChangePassword(context.Background(), user.ID, "password123", "correct-horse-42")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{SecurityEventPasswordChanged}, eventTypes(*events))
+	})
+
+	t.Run("wrong current password counts as a failed login", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+		mockUserRepo.On("GetByID", context.Background(), user.ID).Return(user, nil)
+		mockUserRepo.On("RecordFailedLogin", context.Background(), user.ID).Return(1, nil)
+
+		err := service.ChangePassword(context.Background(), user.ID, "wrongpassword", "correct-horse-42")
+
+		assert.EqualError(t, err, "current password is incorrect")
+	})
+
+	t.Run("new password must meet the policy", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+		mockUserRepo.On("GetByID", context.Background(), user.ID).Return(user, nil)
+
+		err := service.ChangePassword(context.Background(), user.ID, "password123", "weak")
+
+		assert.ErrorIs(t, err, ErrWeakPassword)
+	})
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	user := &models.User{ID: 1, Email: "test@example.com", IsActive: true}
+
+	t.Run("mails a single-use token", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockResetTokenRepo := mocks.NewPasswordResetTokenRepository(t)
+		mockEmailService := mocks2.NewEmailService(t)
+		service := NewAuthService(mockUserRepo, nil, nil, mockResetTokenRepo, nil, nil, newMockSecurityEvents(t), newMockUnitOfWork(t), mockEmailService, testSigningKeys(t))
+
+		mockUserRepo.On("GetByEmail", context.Background(), user.Email).Return(user, nil)
+		mockResetTokenRepo.On("InvalidateForUser", context.Background(), user.ID).Return(nil)
+		var stored *models.PasswordResetToken
+		mockResetTokenRepo.On("Create", context.Background(), mock.AnythingOfType("*models.PasswordResetToken")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(*models.PasswordResetToken) }).
+			Return(nil)
+		var mailed string
+		mockEmailService.On("SendPasswordReset", context.Background(), user.Email, mock.AnythingOfType("string"), time.Hour).
+			Run(func(args mock.Arguments) { mailed = args.Get(2).(string) }).
+			Return(nil)
+
+		err := service.RequestPasswordReset(context.Background(), user.Email)
+
+		assert.NoError(t, err)
+		assert.Equal(t, hashToken(mailed), stored.TokenHash)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresAt, time.Minute)
+	})
+
+	t.Run("unknown email succeeds silently", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockSecurityEventRepo, events := recordSecurityEvents(t)
+		service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, mockSecurityEventRepo, nil, nil, testSigningKeys(t))
+
+		mockUserRepo.On("GetByEmail", context.Background(), "nobody@example.com").Return(nil, repositories.ErrUserNotFound)
+
+		err := service.RequestPasswordReset(context.Background(), "nobody@example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{SecurityEventPasswordResetRequested}, eventTypes(*events))
+		assert.Nil(t, (*events)[0].UserID)
+	})
+}
+
+func TestResetPassword(t *testing.T) {
+	user := &models.User{ID: 1, Email: "test@example.com", IsActive: true}
+
+	t.Run("sets the password and unlocks the account", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+		mockResetTokenRepo := mocks.NewPasswordResetTokenRepository(t)
+		mockSecurityEventRepo, events := recordSecurityEvents(t)
+		service := NewAuthService(mockUserRepo, nil, mockRefreshTokenRepo, mockResetTokenRepo, nil, nil, mockSecurityEventRepo, newMockUnitOfWork(t), nil, testSigningKeys(t))
+
+		stored := &models.PasswordResetToken{ID: 3, UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)}
+		mockResetTokenRepo.On("GetByHash", context.Background(), hashToken("reset-token")).Return(stored, nil)
+		mockUserRepo.On("GetByID", context.Background(), user.ID).Return(user, nil)
+		mockResetTokenRepo.On("MarkUsed", context.Background(), stored.ID).Return(true, nil)
+		mockUserRepo.On("ResetFailedLogins", context.Background(), user.ID).Return(nil)
+		mockUserRepo.On("UpdatePassword", context.Background(), user.ID, mock.AnythingOfType("string")).Return(nil)
+		mockRefreshTokenRepo.On("RevokeAllForUser", context.Background(), user.ID).Return(nil)
+
+		err := service.ResetPassword(context.Background(), "reset-token", "correct-horse-42")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{SecurityEventPasswordReset}, eventTypes(*events))
+	})
+
+	usedAt := time.Now().Add(-time.Minute)
+	for name, stored := range map[string]*models.PasswordResetToken{
+		"used":    {ID: 3, UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt},
+		"expired": {ID: 3, UserID: user.ID, ExpiresAt: time.Now().Add(-time.Minute)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockResetTokenRepo := mocks.NewPasswordResetTokenRepository(t)
+			service := NewAuthService(nil, nil, nil, mockResetTokenRepo, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+			mockResetTokenRepo.On("GetByHash", context.Background(), hashToken("reset-token")).Return(stored, nil)
+
+			err := service.ResetPassword(context.Background(), "reset-token", "correct-horse-42")
+
+			assert.ErrorIs(t, err, ErrInvalidResetToken)
+		})
+	}
+}
+
+func TestRefreshTokenRotates(t *testing.T) {
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+	service := NewAuthService(mockUserRepo, nil, mockRefreshTokenRepo, nil, nil, nil, newMockSecurityEvents(t), newMockUnitOfWork(t), nil, testSigningKeys(t))
+	ctx := context.Background()
+
+	user := &models.User{ID: 1, Email: "test@example.com", UserType: "investor", IsActive: true}
+	current := &models.RefreshToken{
+		ID:        7,
+		UserID:    user.ID,
+		FamilyID:  "family-1",
+		TokenHash: hashToken("old-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	mockRefreshTokenRepo.On("GetByHash", ctx, hashToken("old-token")).Return(current, nil)
+	mockUserRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	mockRefreshTokenRepo.On("MarkUsed", ctx, current.ID).Return(true, nil)
+	var next *models.RefreshToken
+	mockRefreshTokenRepo.On("Create", ctx, mock.AnythingOfType("*models.RefreshToken")).
+		Run(func(args mock.Arguments) { next = args.Get(1).(*models.RefreshToken) }).
+		Return(nil)
+
+	tokens, err := service.RefreshToken(ctx, "old-token")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEqual(t, "old-token", tokens.RefreshToken)
+	assert.Equal(t, hashToken(tokens.RefreshToken), next.TokenHash)
+	assert.Equal(t, "family-1", next.FamilyID)
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	usedAt := time.Now().Add(-time.Minute)
+	used := &models.RefreshToken{
+		ID:        7,
+		UserID:    1,
+		FamilyID:  "family-1",
+		ExpiresAt: time.Now().Add(time.Hour),
+		UsedAt:    &usedAt,
+	}
+
+	t.Run("already used", func(t *testing.T) {
+		mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+		service := NewAuthService(nil, nil, mockRefreshTokenRepo, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+		mockRefreshTokenRepo.On("GetByHash", context.Background(), hashToken("old-token")).Return(used, nil)
+		mockRefreshTokenRepo.On("RevokeFamily", context.Background(), "family-1").Return(nil)
+
+		_, err := service.RefreshToken(context.Background(), "old-token")
+
+		assert.ErrorIs(t, err, ErrRefreshTokenReused)
+	})
+
+	t.Run("used concurrently", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+		service := NewAuthService(mockUserRepo, nil, mockRefreshTokenRepo, nil, nil, nil, newMockSecurityEvents(t), newMockUnitOfWork(t), nil, testSigningKeys(t))
+		unused := *used
+		unused.UsedAt = nil
+
+		mockRefreshTokenRepo.On("GetByHash", context.Background(), hashToken("old-token")).Return(&unused, nil)
+		mockUserRepo.On("GetByID", context.Background(), 1).Return(&models.User{ID: 1, IsActive: true}, nil)
+		mockRefreshTokenRepo.On("MarkUsed", context.Background(), 7).Return(false, nil)
+		mockRefreshTokenRepo.On("RevokeFamily", context.Background(), "family-1").Return(nil)
+
+		_, err := service.RefreshToken(context.Background(), "old-token")
+
+		assert.ErrorIs(t, err, ErrRefreshTokenReused)
+	})
+}
+
+func TestRefreshTokenRejectsDeadTokens(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name   string
+		stored *models.RefreshToken
+		err    error
+	}{
+		{"unknown", nil, repositories.ErrRefreshTokenNotFound},
+		{"revoked", &models.RefreshToken{FamilyID: "family-1", ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}, nil},
+		{"expired", &models.RefreshToken{FamilyID: "family-1", ExpiresAt: time.Now().Add(-time.Minute)}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+			service := NewAuthService(nil, nil, mockRefreshTokenRepo, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+			mockRefreshTokenRepo.On("GetByHash", context.Background(), hashToken("some-token")).Return(tt.stored, tt.err)
+
+			_, err := service.RefreshToken(context.Background(), "some-token")
+
+			assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+	service := NewAuthService(nil, nil, mockRefreshTokenRepo, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+	stored := &models.RefreshToken{ID: 7, UserID: 1, FamilyID: "family-1"}
+	mockRefreshTokenRepo.On("GetByHash", context.Background(), hashToken("some-token")).Return(stored, nil)
+	mockRefreshTokenRepo.On("RevokeFamily", context.Background(), "family-1").Return(nil)
+
+	assert.NoError(t, service.Logout(context.Background(), "some-token"))
+}
+
+func TestLogoutAll(t *testing.T) {
+	mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+	service := NewAuthService(nil, nil, mockRefreshTokenRepo, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+	mockRefreshTokenRepo.On("RevokeAllForUser", context.Background(), 1).Return(nil)
+
+	assert.NoError(t, service.LogoutAll(context.Background(), 1))
+}
+
+func TestValidateToken(t *testing.T) {
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+	service := NewAuthService(mockUserRepo, nil, mockRefreshTokenRepo, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+	user := &models.User{
+		ID:            1,
+		Email:         "test@example.com",
+		UserType:      "investor",
+		FullName:      "Test User",
+		IsActive:      true,
+		EmailVerified: true,
+		PasswordHash:  "$2a$14$qxXQWcJG23rX0daSNJl6FO8I4V9Hj55ibaqUqzZHaa7x0UXv2djLa", // bcrypt hash for "password123"
 	}
 
 	// Create a valid token first
 	mockUserRepo.On("GetByEmail", context.Background(), user.Email).Return(user, nil)
-	token, err := service.LoginUser(context.Background(), user.Email, "password123")
+	mockRefreshTokenRepo.On("Create", context.Background(), mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+	tokens, err := service.LoginUser(context.Background(), user.Email, "password123")
 	assert.NoError(t, err)
+	token := tokens.AccessToken
 
 	// Now test token validation
 	mockUserRepo.On("GetByID", context.Background(), user.ID).Return(user, nil)
@@ -187,7 +735,7 @@ func TestValidateToken(t *testing.T) {
 
 func TestValidateTokenInvalid(t *testing.T) {
 	mockUserRepo := mocks.NewUserRepository(t)
-	service := NewAuthService(mockUserRepo, "test-secret")
+	service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
 
 	// Test invalid token
 	_, err := service.ValidateToken(context.Background(), "invalid-token")
@@ -198,24 +746,28 @@ func TestValidateTokenInvalid(t *testing.T) {
 
 func TestValidateTokenUserNotFound(t *testing.T) {
 	mockUserRepo := mocks.NewUserRepository(t)
-	service := NewAuthService(mockUserRepo, "test-secret")
+	mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+	service := NewAuthService(mockUserRepo, nil, mockRefreshTokenRepo, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
 
 	user := &models.User{
-		ID:       1,
-		Email:    "test@example.com",
-		UserType: "investor",
-		FullName: "Test User",
-		IsActive: true,
-		PasswordHash: "$2a$14$qxXQWcJG23rX0daSNJl6FO8I4V9Hj55ibaqUqzZHaa7x0UXv2djLa", // bcrypt hash for "password123"
+		ID:            1,
+		Email:         "test@example.com",
+		UserType:      "investor",
+		FullName:      "Test User",
+		IsActive:      true,
+		EmailVerified: true,
+		PasswordHash:  "$2a$14$qxXQWcJG23rX0daSNJl6FO8I4V9Hj55ibaqUqzZHaa7x0UXv2djLa", // bcrypt hash for "password123"
 	}
 
 	// Create a valid token first
 	mockUserRepo.On("GetByEmail", context.Background(), user.Email).Return(user, nil)
-	token, err := service.LoginUser(context.Background(), user.Email, "password123")
+	mockRefreshTokenRepo.On("Create", context.Background(), mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+	tokens, err := service.LoginUser(context.Background(), user.Email, "password123")
 	assert.NoError(t, err)
+	token := tokens.AccessToken
 
 	// Now test token validation with user not found
-	mockUserRepo.On("GetByID", context.Background(), user.ID).Return(nil, errors.New("user not found"))
+	mockUserRepo.On("GetByID", context.Background(), user.ID).Return(nil, repositories.ErrUserNotFound)
 
 	_, err = service.ValidateToken(context.Background(), token)
 
@@ -224,7 +776,7 @@ func TestValidateTokenUserNotFound(t *testing.T) {
 }
 
 func TestHashPassword(t *testing.T) {
-	service := NewAuthService(nil, "test-secret")
+	service := NewAuthService(nil, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
 
 	hash, err := service.HashPassword("password123")
 
@@ -234,7 +786,7 @@ func TestHashPassword(t *testing.T) {
 }
 
 func TestCheckPasswordHash(t *testing.T) {
-	service := NewAuthService(nil, "test-secret")
+	service := NewAuthService(nil, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
 
 	password := "password123"
 	hash, err := service.HashPassword(password)
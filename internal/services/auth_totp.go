@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/authz"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/totp"
+)
+
+// A TOTP login challenge has to be answered within totpChallengeTTL of the password step
+const (
+	totpChallengeTTL     = 5 * time.Minute
+	totpChallengePurpose = "totp_challenge"
+	totpIssuer           = "Loan Engine"
+	recoveryCodeCount    = 10
+)
+
+var (
+	ErrInvalidTOTPChallenge = apperr.Unauthenticated("invalid_totp_challenge", "invalid or expired TOTP challenge")
+	ErrInvalidTOTPCode      = apperr.Validation("invalid_totp_code", "invalid TOTP code")
+	ErrTOTPAlreadyEnabled   = apperr.InvalidState("totp_already_enabled", "two-factor authentication is already enabled")
+	ErrTOTPNotEnrolled      = apperr.InvalidState("totp_not_enrolled", "two-factor authentication is not enrolled")
+	ErrTOTPRequired         = apperr.Forbidden("totp_required", "two-factor authentication is required for staff and admin users")
+)
+
+// LoginResult is the outcome of a login step: the session tokens, or a challenge that has to
+// be answered with a TOTP code first. RecoveryCodes are only set on the login that completes
+// enrolment and are never shown again.
+type LoginResult struct {
+	*TokenPair
+	Challenge     *TOTPChallenge `json:"totp_challenge,omitempty"`
+	RecoveryCodes []string       `json:"recovery_codes,omitempty"`
+}
+
+// TOTPChallenge asks the client for a TOTP code. Enrolment is set when the user still has to
+// add the secret to an authenticator app, which is the case for staff and admin users until
+// they answer a challenge; every challenge carries a new secret.
+type TOTPChallenge struct {
+	ChallengeToken string         `json:"challenge_token"`
+	ExpiresIn      int            `json:"expires_in"`
+	Enrolment      *TOTPEnrolment `json:"enrolment,omitempty"`
+}
+
+// TOTPEnrolment carries a new TOTP secret; ProvisioningURI is what the QR code encodes
+type TOTPEnrolment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// requiresTOTP reports whether user has to pass a TOTP code to log in. Investors opt in,
+// staff and admin users always have to.
+func requiresTOTP(user *models.User) bool {
+	return user.TOTPEnabled || mandatoryTOTP(user)
+}
+
+func mandatoryTOTP(user *models.User) bool {
+	return user.UserType == authz.RoleStaff || user.UserType == authz.RoleAdmin
+}
+
+// newTOTPChallenge signs a short-lived challenge token for user. Users who must use TOTP but
+// have not enabled it yet get a new secret to enrol with on every challenge, and only a code
+// for that secret answers it; a pending secret stored earlier is never offered again.
+func (s *authServiceImpl) newTOTPChallenge(ctx context.Context, user *models.User) (*TOTPChallenge, error) {
+	var enrolment *TOTPEnrolment
+	var secretHash string
+	if !user.TOTPEnabled {
+		secret, err := s.storeTOTPSecret(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		enrolment = newTOTPEnrolment(user, secret)
+		secretHash = hashToken(secret)
+	}
+
+	now := time.Now()
+	challengeToken, err := s.signingKeys.Sign(Claims{
+		UserID:         user.ID,
+		Email:          user.Email,
+		UserType:       user.UserType,
+		Purpose:        totpChallengePurpose,
+		TOTPSecretHash: secretHash,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(totpChallengeTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "loan-engine",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate TOTP challenge: %w", err)
+	}
+
+	return &TOTPChallenge{
+		ChallengeToken: challengeToken,
+		ExpiresIn:      int(totpChallengeTTL.Seconds()),
+		Enrolment:      enrolment,
+	}, nil
+}
+
+// VerifyLoginTOTP answers a challenge from LoginUser with a TOTP code or, once TOTP is
+// enabled, with one of the user's recovery codes. Wrong codes count towards the lockout just
+// like wrong passwords.
+func (s *authServiceImpl) VerifyLoginTOTP(ctx context.Context, challengeToken, code string) (*LoginResult, error) {
+	token, err := s.signingKeys.Parse(challengeToken, &Claims{})
+	if err != nil {
+		return nil, ErrInvalidTOTPChallenge
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid || claims.Purpose != totpChallengePurpose {
+		return nil, ErrInvalidTOTPChallenge
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil || user.TOTPSecret == "" {
+		return nil, ErrInvalidTOTPChallenge
+	}
+
+	// Until TOTP is enabled, only the secret this challenge offered may complete enrolment:
+	// not one stored by EnrolTOTP or replaced by a later login
+	if !user.TOTPEnabled && subtle.ConstantTimeCompare([]byte(hashToken(user.TOTPSecret)), []byte(claims.TOTPSecretHash)) != 1 {
+		return nil, ErrInvalidTOTPChallenge
+	}
+
+	if !user.IsActive {
+		return nil, ErrAccountDeactivated
+	}
+
+	if err := s.checkNotLocked(ctx, user); err != nil {
+		return nil, err
+	}
+
+	valid, err := s.checkTOTPCode(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !valid && user.TOTPEnabled {
+		valid, err = s.recoveryCodeRepo.Use(ctx, user.ID, hashRecoveryCode(code))
+		if err != nil {
+			return nil, fmt.Errorf("failed to check recovery code: %w", err)
+		}
+		if valid {
+			s.audit.record(ctx, SecurityEventRecoveryCodeUsed, user, "", "")
+		}
+	}
+
+	if !valid {
+		if err := s.recordFailedLogin(ctx, user, "wrong TOTP code"); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidTOTPCode
+	}
+
+	if err := s.clearFailedLogins(ctx, user); err != nil {
+		return nil, err
+	}
+
+	result := &LoginResult{}
+	if !user.TOTPEnabled {
+		result.RecoveryCodes, err = s.activateTOTP(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result.TokenPair, err = s.completeLogin(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// EnrolTOTP starts enrolment for a signed-in user with a new secret. TOTP stays off until
+// ConfirmTOTP proves the authenticator app produces the right codes.
+func (s *authServiceImpl) EnrolTOTP(ctx context.Context, userID int) (*TOTPEnrolment, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	secret, err := s.storeTOTPSecret(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTOTPEnrolment(user, secret), nil
+}
+
+// ConfirmTOTP enables TOTP with a code from the enrolled secret and returns the user's
+// recovery codes
+func (s *authServiceImpl) ConfirmTOTP(ctx context.Context, userID int, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	if user.TOTPSecret == "" {
+		return nil, ErrTOTPNotEnrolled
+	}
+
+	valid, err := s.checkTOTPCode(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, ErrInvalidTOTPCode
+	}
+
+	return s.activateTOTP(ctx, user)
+}
+
+// DisableTOTP turns TOTP off for a user who confirms their password. Staff and admin users
+// cannot turn it off.
+func (s *authServiceImpl) DisableTOTP(ctx context.Context, userID int, password string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if mandatoryTOTP(user) {
+		return ErrTOTPRequired
+	}
+
+	if user.TOTPSecret == "" {
+		return ErrTOTPNotEnrolled
+	}
+
+	if err := s.verifyPassword(ctx, user, password); err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			return ErrIncorrectPassword
+		}
+		return err
+	}
+
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.DisableTOTP(ctx, user.ID); err != nil {
+			return err
+		}
+
+		return s.recoveryCodeRepo.DeleteForUser(ctx, user.ID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+
+	s.audit.record(ctx, SecurityEventTOTPDisabled, user, "", "")
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code of the user, used or not, after
+// checking a current TOTP code
+func (s *authServiceImpl) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.TOTPEnabled {
+		return nil, ErrTOTPNotEnrolled
+	}
+
+	valid, err := s.checkTOTPCode(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, ErrInvalidTOTPCode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recoveryCodeRepo.ReplaceForUser(ctx, user.ID, hashes); err != nil {
+		return nil, fmt.Errorf("failed to store recovery codes: %w", err)
+	}
+
+	s.audit.record(ctx, SecurityEventRecoveryCodesRegenerated, user, "", "")
+
+	return codes, nil
+}
+
+// checkTOTPCode reports whether code is valid for the user's secret. A code is accepted only
+// once: its time step is claimed, so a replayed or older code is refused.
+func (s *authServiceImpl) checkTOTPCode(ctx context.Context, user *models.User, code string) (bool, error) {
+	step, ok := totp.Verify(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+
+	claimed, err := s.userRepo.ClaimTOTPStep(ctx, user.ID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to record TOTP code: %w", err)
+	}
+
+	return claimed, nil
+}
+
+func (s *authServiceImpl) storeTOTPSecret(ctx context.Context, user *models.User) (string, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate TOTP secret: %w", err)
+	}
+
+	if err := s.userRepo.SetTOTPSecret(ctx, user.ID, secret); err != nil {
+		return "", fmt.Errorf("failed to store TOTP secret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// activateTOTP enables TOTP for user together with a fresh set of recovery codes
+func (s *authServiceImpl) activateTOTP(ctx context.Context, user *models.User) ([]string, error) {
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.EnableTOTP(ctx, user.ID); err != nil {
+			return err
+		}
+
+		return s.recoveryCodeRepo.ReplaceForUser(ctx, user.ID, hashes)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+
+	s.audit.record(ctx, SecurityEventTOTPEnabled, user, "", "")
+
+	return codes, nil
+}
+
+func newTOTPEnrolment(user *models.User, secret string) *TOTPEnrolment {
+	return &TOTPEnrolment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, totpIssuer, user.Email),
+	}
+}
+
+// newRecoveryCodes returns recoveryCodeCount codes formatted as "xxxxx-xxxxx" for the user
+// and their hashes for storage
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+		}
+
+		raw := strings.ToLower(base32.StdEncoding.EncodeToString(b))[:10]
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode hashes a recovery code the way it is stored, ignoring case, spaces and
+// dashes so the code may be typed back loosely
+func hashRecoveryCode(code string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(code))
+
+	return hashToken(normalized)
+}
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/repositories/mocks"
+	"github.com/sswastioyono18/loan-engine/internal/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func totpUser(userType string, enabled bool) *models.User {
+	return &models.User{
+		ID:            1,
+		Email:         "officer@example.com",
+		UserType:      userType,
+		PasswordHash:  "$2a$14$qxXQWcJG23rX0daSNJl6FO8I4V9Hj55ibaqUqzZHaa7x0UXv2djLa", // bcrypt hash for "password123"
+		IsActive:      true,
+		EmailVerified: true,
+		TOTPSecret:    testTOTPSecret,
+		TOTPEnabled:   enabled,
+	}
+}
+
+func TestLoginUserAsksForTOTPCode(t *testing.T) {
+	mockUserRepo := mocks.NewUserRepository(t)
+	service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+	user := totpUser("investor", true)
+	user.FailedLoginAttempts = 2
+	mockUserRepo.On("GetByEmail", context.Background(), user.Email).Return(user, nil)
+
+	// No tokens yet, and the failure count survives until the code is checked
+	result, err := service.LoginUser(context.Background(), user.Email, "password123")
+
+	require.NoError(t, err)
+	assert.Nil(t, result.TokenPair)
+	require.NotNil(t, result.Challenge)
+	assert.Nil(t, result.Challenge.Enrolment)
+	assert.Equal(t, 300, result.Challenge.ExpiresIn)
+
+	// The challenge is not an access token
+	_, err = service.ValidateToken(context.Background(), result.Challenge.ChallengeToken)
+	assert.Error(t, err)
+}
+
+func TestLoginUserEnrolsStaffInTOTP(t *testing.T) {
+	mockUserRepo := mocks.NewUserRepository(t)
+	service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+	user := totpUser("staff", false)
+	user.TOTPSecret = ""
+	mockUserRepo.On("GetByEmail", context.Background(), user.Email).Return(user, nil)
+	var secret string
+	mockUserRepo.On("SetTOTPSecret", context.Background(), user.ID, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { secret = args.Get(2).(string) }).
+		Return(nil)
+
+	result, err := service.LoginUser(context.Background(), user.Email, "password123")
+
+	require.NoError(t, err)
+	assert.Nil(t, result.TokenPair)
+	require.NotNil(t, result.Challenge)
+	require.NotNil(t, result.Challenge.Enrolment)
+	assert.Equal(t, secret, result.Challenge.Enrolment.Secret)
+	assert.Contains(t, result.Challenge.Enrolment.ProvisioningURI, "otpauth://totp/")
+	assert.Contains(t, result.Challenge.Enrolment.ProvisioningURI, "secret="+secret)
+}
+
+func TestLoginUserNeverOffersStoredTOTPSecret(t *testing.T) {
+	mockUserRepo := mocks.NewUserRepository(t)
+	service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+	// A pending secret from an earlier, unfinished login
+	user := totpUser("staff", false)
+	mockUserRepo.On("GetByEmail", context.Background(), user.Email).Return(user, nil)
+	var secrets []string
+	mockUserRepo.On("SetTOTPSecret", context.Background(), user.ID, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { secrets = append(secrets, args.Get(2).(string)) }).
+		Return(nil)
+
+	first, err := service.LoginUser(context.Background(), user.Email, "password123")
+	require.NoError(t, err)
+	second, err := service.LoginUser(context.Background(), user.Email, "password123")
+	require.NoError(t, err)
+
+	require.Len(t, secrets, 2)
+	assert.Equal(t, secrets[0], first.Challenge.Enrolment.Secret)
+	assert.Equal(t, secrets[1], second.Challenge.Enrolment.Secret)
+	assert.NotEqual(t, testTOTPSecret, first.Challenge.Enrolment.Secret)
+	assert.NotEqual(t, first.Challenge.Enrolment.Secret, second.Challenge.Enrolment.Secret)
+}
+
+func TestVerifyLoginTOTP(t *testing.T) {
+	t.Run("completes login with a TOTP code", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+		service := NewAuthService(mockUserRepo, nil, mockRefreshTokenRepo, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t)).(*authServiceImpl)
+
+		user := totpUser("admin", true)
+		user.FailedLoginAttempts = 1
+		challenge, err := service.newTOTPChallenge(context.Background(), user)
+		require.NoError(t, err)
+
+		now := time.Now()
+		code, err := totp.Code(testTOTPSecret, now)
+		require.NoError(t, err)
+
+		mockUserRepo.On("GetByID", context.Background(), user.ID).Return(user, nil)
+		mockUserRepo.On("ClaimTOTPStep", context.Background(), user.ID, mock.AnythingOfType("int64")).Return(true, nil)
+		mockUserRepo.On("ResetFailedLogins", context.Background(), user.ID).Return(nil)
+		mockRefreshTokenRepo.On("Create", context.Background(), mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+		result, err := service.VerifyLoginTOTP(context.Background(), challenge.ChallengeToken, code)
+
+		require.NoError(t, err)
+		require.NotNil(t, result.TokenPair)
+		assert.NotEmpty(t, result.AccessToken)
+		assert.Empty(t, result.RecoveryCodes)
+	})
+
+	t.Run("first code completes enrolment and returns recovery codes", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+		mockRecoveryCodeRepo := mocks.NewRecoveryCodeRepository(t)
+		mockSecurityEventRepo, events := recordSecurityEvents(t)
+		service := NewAuthService(mockUserRepo, nil, mockRefreshTokenRepo, nil, nil, mockRecoveryCodeRepo, mockSecurityEventRepo, newMockUnitOfWork(t), nil, testSigningKeys(t)).(*authServiceImpl)
+
+		user := totpUser("staff", false)
+		mockUserRepo.On("SetTOTPSecret", context.Background(), user.ID, mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) { user.TOTPSecret = args.Get(2).(string) }).
+			Return(nil)
+		challenge, err := service.newTOTPChallenge(context.Background(), user)
+		require.NoError(t, err)
+		require.NotNil(t, challenge.Enrolment)
+
+		code, err := totp.Code(challenge.Enrolment.Secret, time.Now())
+		require.NoError(t, err)
+
+		mockUserRepo.On("GetByID", context.Background(), user.ID).Return(user, nil)
+		mockUserRepo.On("ClaimTOTPStep", context.Background(), user.ID, mock.AnythingOfType("int64")).Return(true, nil)
+		mockUserRepo.On("EnableTOTP", context.Background(), user.ID).Return(nil)
+		var hashes []string
+		mockRecoveryCodeRepo.On("ReplaceForUser", context.Background(), user.ID, mock.AnythingOfType("[]string")).
+			Run(func(args mock.Arguments) { hashes = args.Get(2).([]string) }).
+			Return(nil)
+		mockRefreshTokenRepo.On("Create", context.Background(), mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+		result, err := service.VerifyLoginTOTP(context.Background(), challenge.ChallengeToken, code)
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.AccessToken)
+		require.Len(t, result.RecoveryCodes, 10)
+		require.Len(t, hashes, 10)
+		for i, recoveryCode := range result.RecoveryCodes {
+			assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, recoveryCode)
+			assert.Equal(t, hashRecoveryCode(recoveryCode), hashes[i])
+		}
+		assert.Equal(t, []string{SecurityEventTOTPEnabled, SecurityEventLoginSucceeded}, eventTypes(*events))
+	})
+
+	t.Run("accepts a recovery code once TOTP is enabled", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+		mockRecoveryCodeRepo := mocks.NewRecoveryCodeRepository(t)
+		mockSecurityEventRepo, events := recordSecurityEvents(t)
+		service := NewAuthService(mockUserRepo, nil, mockRefreshTokenRepo, nil, nil, mockRecoveryCodeRepo, mockSecurityEventRepo, nil, nil, testSigningKeys(t)).(*authServiceImpl)
+
+		user := totpUser("staff", true)
+		challenge, err := service.newTOTPChallenge(context.Background(), user)
+		require.NoError(t, err)
+
+		mockUserRepo.On("GetByID", context.Background(), user.ID).Return(user, nil)
+		mockRecoveryCodeRepo.On("Use", context.Background(), user.ID, hashRecoveryCode("abcde-fghij")).Return(true, nil)
+		mockRefreshTokenRepo.On("Create", context.Background(), mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+		result, err := service.VerifyLoginTOTP(context.Background(), challenge.ChallengeToken, "ABCDE FGHIJ")
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.AccessToken)
+		assert.Equal(t, []string{SecurityEventRecoveryCodeUsed, SecurityEventLoginSucceeded}, eventTypes(*events))
+	})
+
+	t.Run("a replayed code counts as a failed login", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockRecoveryCodeRepo := mocks.NewRecoveryCodeRepository(t)
+		service := NewAuthService(mockUserRepo, nil, nil, nil, nil, mockRecoveryCodeRepo, newMockSecurityEvents(t), nil, nil, testSigningKeys(t)).(*authServiceImpl)
+
+		user := totpUser("staff", true)
+		challenge, err := service.newTOTPChallenge(context.Background(), user)
+		require.NoError(t, err)
+
+		code, err := totp.Code(testTOTPSecret, time.Now())
+		require.NoError(t, err)
+
+		mockUserRepo.On("GetByID", context.Background(), user.ID).Return(user, nil)
+		mockUserRepo.On("ClaimTOTPStep", context.Background(), user.ID, mock.AnythingOfType("int64")).Return(false, nil)
+		mockRecoveryCodeRepo.On("Use", context.Background(), user.ID, hashRecoveryCode(code)).Return(false, nil)
+		mockUserRepo.On("RecordFailedLogin", context.Background(), user.ID).Return(1, nil)
+
+		_, err = service.VerifyLoginTOTP(context.Background(), challenge.ChallengeToken, code)
+
+		assert.ErrorIs(t, err, ErrInvalidTOTPCode)
+	})
+
+	t.Run("refuses a pending secret the challenge did not offer", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t)).(*authServiceImpl)
+
+		user := totpUser("staff", false)
+		mockUserRepo.On("SetTOTPSecret", context.Background(), user.ID, mock.AnythingOfType("string")).Return(nil)
+		challenge, err := service.newTOTPChallenge(context.Background(), user)
+		require.NoError(t, err)
+
+		// The stored secret is still the one from before, such as one set by EnrolTOTP
+		code, err := totp.Code(testTOTPSecret, time.Now())
+		require.NoError(t, err)
+		mockUserRepo.On("GetByID", context.Background(), user.ID).Return(user, nil)
+
+		_, err = service.VerifyLoginTOTP(context.Background(), challenge.ChallengeToken, code)
+
+		assert.ErrorIs(t, err, ErrInvalidTOTPChallenge)
+	})
+
+	t.Run("rejects an access token as challenge", func(t *testing.T) {
+		mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+		service := NewAuthService(nil, nil, mockRefreshTokenRepo, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t)).(*authServiceImpl)
+
+		mockRefreshTokenRepo.On("Create", context.Background(), mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+		tokens, err := service.issueTokens(context.Background(), totpUser("staff", true), "family")
+		require.NoError(t, err)
+
+		_, err = service.VerifyLoginTOTP(context.Background(), tokens.AccessToken, "123456")
+
+		assert.ErrorIs(t, err, ErrInvalidTOTPChallenge)
+	})
+}
+
+func TestEnrolAndConfirmTOTP(t *testing.T) {
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockRecoveryCodeRepo := mocks.NewRecoveryCodeRepository(t)
+	service := NewAuthService(mockUserRepo, nil, nil, nil, nil, mockRecoveryCodeRepo, newMockSecurityEvents(t), newMockUnitOfWork(t), nil, testSigningKeys(t))
+
+	user := totpUser("investor", false)
+	user.TOTPSecret = ""
+	mockUserRepo.On("GetByID", context.Background(), user.ID).Return(user, nil)
+	mockUserRepo.On("SetTOTPSecret", context.Background(), user.ID, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { user.TOTPSecret = args.Get(2).(string) }).
+		Return(nil)
+
+	enrolment, err := service.EnrolTOTP(context.Background(), user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user.TOTPSecret, enrolment.Secret)
+
+	// A wrong code leaves TOTP disabled
+	_, err = service.ConfirmTOTP(context.Background(), user.ID, "000000x")
+	assert.ErrorIs(t, err, ErrInvalidTOTPCode)
+
+	code, err := totp.Code(user.TOTPSecret, time.Now())
+	require.NoError(t, err)
+	mockUserRepo.On("ClaimTOTPStep", context.Background(), user.ID, mock.AnythingOfType("int64")).Return(true, nil)
+	mockUserRepo.On("EnableTOTP", context.Background(), user.ID).Return(nil)
+	mockRecoveryCodeRepo.On("ReplaceForUser", context.Background(), user.ID, mock.AnythingOfType("[]string")).Return(nil)
+
+	codes, err := service.ConfirmTOTP(context.Background(), user.ID, code)
+
+	require.NoError(t, err)
+	assert.Len(t, codes, 10)
+}
+
+func TestEnrolTOTPRefusedWhenEnabled(t *testing.T) {
+	mockUserRepo := mocks.NewUserRepository(t)
+	service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+	user := totpUser("investor", true)
+	mockUserRepo.On("GetByID", context.Background(), user.ID).Return(user, nil)
+
+	_, err := service.EnrolTOTP(context.Background(), user.ID)
+
+	assert.ErrorIs(t, err, ErrTOTPAlreadyEnabled)
+}
+
+func TestDisableTOTP(t *testing.T) {
+	t.Run("staff cannot turn TOTP off", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		service := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+		user := totpUser("staff", true)
+		mockUserRepo.On("GetByID", context.Background(), user.ID).Return(user, nil)
+
+		err := service.DisableTOTP(context.Background(), user.ID, "password123")
+
+		assert.ErrorIs(t, err, ErrTOTPRequired)
+	})
+
+	t.Run("investor turns TOTP off with their password", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockRecoveryCodeRepo := mocks.NewRecoveryCodeRepository(t)
+		mockSecurityEventRepo, events := recordSecurityEvents(t)
+		service := NewAuthService(mockUserRepo, nil, nil, nil, nil, mockRecoveryCodeRepo, mockSecurityEventRepo, newMockUnitOfWork(t), nil, testSigningKeys(t))
+
+		user := totpUser("investor", true)
+		mockUserRepo.On("GetByID", context.Background(), user.ID).Return(user, nil)
+		mockUserRepo.On("DisableTOTP", context.Background(), user.ID).Return(nil)
+		mockRecoveryCodeRepo.On("DeleteForUser", context.Background(), user.ID).Return(nil)
+
+		err := service.DisableTOTP(context.Background(), user.ID, "password123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{SecurityEventTOTPDisabled}, eventTypes(*events))
+	})
+}
+
+func TestRegenerateRecoveryCodes(t *testing.T) {
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockRecoveryCodeRepo := mocks.NewRecoveryCodeRepository(t)
+	service := NewAuthService(mockUserRepo, nil, nil, nil, nil, mockRecoveryCodeRepo, newMockSecurityEvents(t), nil, nil, testSigningKeys(t))
+
+	user := totpUser("admin", true)
+	code, err := totp.Code(testTOTPSecret, time.Now())
+	require.NoError(t, err)
+
+	mockUserRepo.On("GetByID", context.Background(), user.ID).Return(user, nil)
+	mockUserRepo.On("ClaimTOTPStep", context.Background(), user.ID, mock.AnythingOfType("int64")).Return(true, nil)
+	mockRecoveryCodeRepo.On("ReplaceForUser", context.Background(), user.ID, mock.AnythingOfType("[]string")).Return(nil)
+
+	codes, err := service.RegenerateRecoveryCodes(context.Background(), user.ID, code)
+
+	require.NoError(t, err)
+	assert.Len(t, codes, 10)
+}
+
+func TestHashRecoveryCodeIgnoresFormatting(t *testing.T) {
+	assert.Equal(t, hashRecoveryCode("abcde-fghij"), hashRecoveryCode("ABCDE FGHIJ"))
+	assert.Equal(t, hashRecoveryCode("abcde-fghij"), hashRecoveryCode(strings.ReplaceAll("abcde-fghij", "-", "")))
+	assert.NotEqual(t, hashRecoveryCode("abcde-fghij"), hashRecoveryCode("abcde-fghik"))
+}
@@ -11,7 +11,7 @@ type BorrowerService interface {
 	GetBorrowerByBorrowerIDNumber(ctx context.Context, borrowerIDNumber string) (*models.Borrower, error)
 	UpdateBorrower(ctx context.Context, id int, borrower *models.Borrower) error
 	DeleteBorrower(ctx context.Context, id int) error
-	ListBorrowers(ctx context.Context, offset, limit int) ([]*models.Borrower, error)
+	ListBorrowers(ctx context.Context, page models.PageRequest) (*models.Page[*models.Borrower], error)
 }
 
 type borrowerServiceImpl struct {
@@ -54,6 +54,6 @@ func (s *borrowerServiceImpl) DeleteBorrower(ctx context.Context, id int) error
 	return s.repo.Delete(ctx, id)
 }
 
-func (s *borrowerServiceImpl) ListBorrowers(ctx context.Context, offset, limit int) ([]*models.Borrower, error) {
-	return s.repo.List(ctx, offset, limit)
+func (s *borrowerServiceImpl) ListBorrowers(ctx context.Context, page models.PageRequest) (*models.Page[*models.Borrower], error) {
+	return s.repo.List(ctx, page)
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/repositories"
 	"github.com/sswastioyono18/loan-engine/internal/repositories/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -79,7 +80,7 @@ func TestGetBorrowerByIDNotFound(t *testing.T) {
 	service := NewBorrowerService(mockRepo)
 
 	// Test not found
-	mockRepo.On("GetByID", context.Background(), 1).Return(nil, errors.New("borrower not found"))
+	mockRepo.On("GetByID", context.Background(), 1).Return(nil, repositories.ErrBorrowerNotFound)
 
 	_, err := service.GetBorrowerByID(context.Background(), 1)
 
@@ -114,7 +115,7 @@ func TestGetBorrowerByBorrowerIDNumberNotFound(t *testing.T) {
 	service := NewBorrowerService(mockRepo)
 
 	// Test not found by ID number
-	mockRepo.On("GetByBorrowerIDNumber", context.Background(), "B001").Return(nil, errors.New("borrower not found"))
+	mockRepo.On("GetByBorrowerIDNumber", context.Background(), "B001").Return(nil, repositories.ErrBorrowerNotFound)
 
 	_, err := service.GetBorrowerByBorrowerIDNumber(context.Background(), "B001")
 
@@ -168,7 +169,7 @@ func TestUpdateBorrowerNotFound(t *testing.T) {
 	}
 
 	// Test update when borrower doesn't exist
-	mockRepo.On("GetByID", context.Background(), 1).Return(nil, errors.New("borrower not found"))
+	mockRepo.On("GetByID", context.Background(), 1).Return(nil, repositories.ErrBorrowerNotFound)
 
 	err := service.UpdateBorrower(context.Background(), 1, updatedBorrower)
 
@@ -257,12 +258,14 @@ func TestListBorrowers(t *testing.T) {
 	}
 
 	// Test successful listing
-	mockRepo.On("List", context.Background(), 0, 10).Return(borrowers, nil)
+	page := models.PageRequest{Limit: 10}
+	listed := &models.Page[*models.Borrower]{Items: borrowers, TotalEstimate: 2}
+	mockRepo.On("List", context.Background(), page).Return(listed, nil)
 
-	result, err := service.ListBorrowers(context.Background(), 0, 10)
+	result, err := service.ListBorrowers(context.Background(), page)
 
 	assert.NoError(t, err)
-	assert.Equal(t, borrowers, result)
+	assert.Equal(t, listed, result)
 }
 
 func TestListBorrowersError(t *testing.T) {
@@ -270,9 +273,9 @@ func TestListBorrowersError(t *testing.T) {
 	service := NewBorrowerService(mockRepo)
 
 	// Test listing error
-	mockRepo.On("List", context.Background(), 0, 10).Return(nil, errors.New("list failed"))
+	mockRepo.On("List", context.Background(), models.PageRequest{Limit: 10}).Return(nil, errors.New("list failed"))
 
-	_, err := service.ListBorrowers(context.Background(), 0, 10)
+	_, err := service.ListBorrowers(context.Background(), models.PageRequest{Limit: 10})
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "list failed")
@@ -1,7 +1,11 @@
 package services
 
 import (
+	"github.com/sswastioyono18/loan-engine/internal/authz"
+	"github.com/sswastioyono18/loan-engine/internal/ledger"
+	"github.com/sswastioyono18/loan-engine/internal/repayment"
 	"github.com/sswastioyono18/loan-engine/internal/repositories"
+	"github.com/sswastioyono18/loan-engine/internal/signing"
 	"github.com/sswastioyono18/loan-engine/pkg/external"
 )
 
@@ -9,20 +13,27 @@ type ServiceFactory struct {
 	RepoFactory    *repositories.RepositoryFactory
 	EmailService   external.EmailService
 	StorageService external.StorageService
-	JwtSecret      string
+	// SigningKeys signs and verifies access tokens
+	SigningKeys *signing.Manager
+	// RepaymentPolicy decides how repayments are allocated; defaults to repayment.DefaultPolicy
+	RepaymentPolicy repayment.Policy
+	// AccessPolicy decides who may perform each action; defaults to authz.DefaultPolicy
+	AccessPolicy authz.Policy
 }
 
 func NewServiceFactory(
 	repoFactory *repositories.RepositoryFactory,
 	emailService external.EmailService,
 	storageService external.StorageService,
-	jwtSecret string,
+	signingKeys *signing.Manager,
 ) *ServiceFactory {
 	return &ServiceFactory{
-		RepoFactory:    repoFactory,
-		EmailService:   emailService,
-		StorageService: storageService,
-		JwtSecret:      jwtSecret,
+		RepoFactory:     repoFactory,
+		EmailService:    emailService,
+		StorageService:  storageService,
+		SigningKeys:     signingKeys,
+		RepaymentPolicy: repayment.DefaultPolicy(),
+		AccessPolicy:    authz.DefaultPolicy(),
 	}
 }
 
@@ -34,19 +45,78 @@ func (f *ServiceFactory) LoanService() LoanService {
 	return NewLoanService(
 		f.RepoFactory.LoanRepository(),
 		f.RepoFactory.LoanApprovalRepository(),
+		f.RepoFactory.LoanRejectionRepository(),
+		f.RepoFactory.LoanRejectionReasonRepository(),
 		f.RepoFactory.LoanDisbursementRepository(),
 		f.RepoFactory.LoanInvestmentRepository(),
 		f.RepoFactory.LoanStateHistoryRepository(),
+		f.RepoFactory.LoanInstallmentRepository(),
+		f.RepoFactory.BorrowerRepository(),
 		f.RepoFactory.InvestorRepository(),
+		f.RepoFactory.UnitOfWork(),
+		f.LedgerService(),
 		f.EmailService,
 		f.StorageService,
+		f.AccessPolicy,
 	)
 }
 
+func (f *ServiceFactory) RepaymentService() RepaymentService {
+	return NewRepaymentService(
+		f.RepoFactory.LoanRepository(),
+		f.RepoFactory.LoanInstallmentRepository(),
+		f.RepoFactory.LoanRepaymentRepository(),
+		f.RepoFactory.BorrowerRepository(),
+		f.RepoFactory.LoanInvestmentRepository(),
+		f.RepoFactory.PayoutRepository(),
+		f.RepoFactory.PlatformRevenueRepository(),
+		f.RepoFactory.UnitOfWork(),
+		f.LedgerService(),
+		f.RepaymentPolicy,
+	)
+}
+
+func (f *ServiceFactory) LedgerService() ledger.Service {
+	return ledger.NewService(ledger.NewRepository(f.RepoFactory.Driver()))
+}
+
 func (f *ServiceFactory) InvestorService() InvestorService {
-	return NewInvestorService(f.RepoFactory.InvestorRepository())
+	return NewInvestorService(f.RepoFactory.InvestorRepository(), f.RepoFactory.PayoutRepository(), f.AccessPolicy)
 }
 
 func (f *ServiceFactory) AuthService() AuthService {
-	return NewAuthService(f.RepoFactory.UserRepository(), f.JwtSecret)
+	return NewAuthService(
+		f.RepoFactory.UserRepository(),
+		f.RepoFactory.InvestorRepository(),
+		f.RepoFactory.RefreshTokenRepository(),
+		f.RepoFactory.PasswordResetTokenRepository(),
+		f.RepoFactory.EmailVerificationTokenRepository(),
+		f.RepoFactory.RecoveryCodeRepository(),
+		f.RepoFactory.SecurityEventRepository(),
+		f.RepoFactory.UnitOfWork(),
+		f.EmailService,
+		f.SigningKeys,
+	)
+}
+
+func (f *ServiceFactory) APIKeyService() APIKeyService {
+	return NewAPIKeyService(
+		f.RepoFactory.APIKeyRepository(),
+		f.RepoFactory.SecurityEventRepository(),
+	)
+}
+
+func (f *ServiceFactory) IdempotencyService() IdempotencyService {
+	return NewIdempotencyService(f.RepoFactory.IdempotencyKeyRepository())
+}
+
+func (f *ServiceFactory) UserService() UserService {
+	return NewUserService(
+		f.RepoFactory.UserRepository(),
+		f.RepoFactory.RefreshTokenRepository(),
+		f.RepoFactory.PasswordResetTokenRepository(),
+		f.RepoFactory.SecurityEventRepository(),
+		f.RepoFactory.UnitOfWork(),
+		f.EmailService,
+	)
 }
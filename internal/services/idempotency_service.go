@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+const (
+	// idempotencyKeyTTL is how long the response to a request is replayed for its key
+	idempotencyKeyTTL = 24 * time.Hour
+	// idempotencyLockTimeout is how long a request may hold its key before it is presumed to
+	// have died with the server; it is well above the server's request timeout
+	idempotencyLockTimeout = 5 * time.Minute
+	// idempotencyClaimAttempts bounds how often Begin tries again when the key it found taken
+	// is freed before it can be read
+	idempotencyClaimAttempts = 3
+)
+
+var (
+	ErrIdempotencyKeyReused        = apperr.Validation("idempotency_key_reused", "idempotency key was already used for a different request")
+	ErrIdempotentRequestInProgress = apperr.Conflict("idempotent_request_in_progress", "a request with this idempotency key is still being processed")
+)
+
+// IdempotencyService makes retried requests safe: the first request with a key is handled
+// and its response stored, and retries with the same key get that response again instead
+// of repeating the work
+type IdempotencyService interface {
+	Begin(ctx context.Context, request *models.IdempotencyKey) (*models.IdempotencyKey, error)
+	Complete(ctx context.Context, key *models.IdempotencyKey, statusCode int, contentType string, body []byte) error
+	Release(ctx context.Context, key *models.IdempotencyKey) error
+}
+
+type idempotencyServiceImpl struct {
+	idempotencyKeyRepo IdempotencyKeyRepository
+}
+
+func NewIdempotencyService(idempotencyKeyRepo IdempotencyKeyRepository) IdempotencyService {
+	return &idempotencyServiceImpl{
+		idempotencyKeyRepo: idempotencyKeyRepo,
+	}
+}
+
+// Begin claims the key of request, which names its owner, key, method, path and request
+// hash. When the claim succeeds the request is handled and then completed or released;
+// when the key already has a completed response for the same request, that is returned
+// for replay. A key that is in flight, or that was used for a different request, fails.
+func (s *idempotencyServiceImpl) Begin(ctx context.Context, request *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	for attempt := 0; attempt < idempotencyClaimAttempts; attempt++ {
+		now := time.Now()
+		request.ExpiresAt = now.Add(idempotencyKeyTTL)
+
+		claimed, err := s.idempotencyKeyRepo.Claim(ctx, request, now.Add(-idempotencyLockTimeout))
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+		}
+		if claimed {
+			return request, nil
+		}
+
+		existing, err := s.idempotencyKeyRepo.GetByKey(ctx, request.Owner, request.Key)
+		if errors.Is(err, apperr.ErrNotFound) {
+			// Released by a failed request in the meantime
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+		}
+
+		switch {
+		case existing.Expired(now):
+			continue
+		case existing.Method != request.Method || existing.Path != request.Path || existing.RequestHash != request.RequestHash:
+			return nil, ErrIdempotencyKeyReused
+		case !existing.Completed():
+			return nil, ErrIdempotentRequestInProgress
+		}
+
+		return existing, nil
+	}
+
+	return nil, ErrIdempotentRequestInProgress
+}
+
+// Complete stores the response to the request holding key, so retries replay it
+func (s *idempotencyServiceImpl) Complete(ctx context.Context, key *models.IdempotencyKey, statusCode int, contentType string, body []byte) error {
+	key.StatusCode = &statusCode
+	key.ContentType = &contentType
+	key.ResponseBody = body
+
+	if err := s.idempotencyKeyRepo.Complete(ctx, key); err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+// Release frees key without storing a response, for requests that failed in a way a retry
+// may get past, so the retry is handled afresh
+func (s *idempotencyServiceImpl) Release(ctx context.Context, key *models.IdempotencyKey) error {
+	if err := s.idempotencyKeyRepo.Release(ctx, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/repositories"
+	"github.com/sswastioyono18/loan-engine/internal/repositories/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newIdempotentRequest() *models.IdempotencyKey {
+	return &models.IdempotencyKey{
+		Owner:       "USR001",
+		Key:         "invest-42",
+		Method:      "POST",
+		Path:        "/api/v1/loans/7/invest",
+		RequestHash: "hash-of-body",
+	}
+}
+
+func TestBeginClaimsNewKey(t *testing.T) {
+	mockIdempotencyKeyRepo := mocks.NewIdempotencyKeyRepository(t)
+	service := NewIdempotencyService(mockIdempotencyKeyRepo)
+	ctx := context.Background()
+	request := newIdempotentRequest()
+
+	mockIdempotencyKeyRepo.On("Claim", ctx, request, mock.AnythingOfType("time.Time")).Return(true, nil)
+
+	record, err := service.Begin(ctx, request)
+
+	require.NoError(t, err)
+	assert.Same(t, request, record)
+	assert.False(t, record.Completed())
+	assert.WithinDuration(t, time.Now().Add(idempotencyKeyTTL), record.ExpiresAt, time.Minute)
+}
+
+func TestBeginWithTakenKey(t *testing.T) {
+	completedAt := time.Now().Add(-time.Minute)
+	statusCode := 200
+
+	tests := []struct {
+		name     string
+		existing func(*models.IdempotencyKey)
+		err      error
+	}{
+		{
+			name: "replays the response to the same request",
+			existing: func(k *models.IdempotencyKey) {
+				k.CompletedAt = &completedAt
+				k.StatusCode = &statusCode
+			},
+		},
+		{
+			name:     "refuses a different request",
+			existing: func(k *models.IdempotencyKey) { k.RequestHash = "hash-of-another-body" },
+			err:      ErrIdempotencyKeyReused,
+		},
+		{
+			name:     "refuses another route",
+			existing: func(k *models.IdempotencyKey) { k.Path = "/api/v1/loans/8/invest" },
+			err:      ErrIdempotencyKeyReused,
+		},
+		{
+			name:     "refuses while the first request is in flight",
+			existing: func(k *models.IdempotencyKey) {},
+			err:      ErrIdempotentRequestInProgress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockIdempotencyKeyRepo := mocks.NewIdempotencyKeyRepository(t)
+			service := NewIdempotencyService(mockIdempotencyKeyRepo)
+			ctx := context.Background()
+
+			existing := newIdempotentRequest()
+			existing.ID = 3
+			existing.ExpiresAt = time.Now().Add(time.Hour)
+			tt.existing(existing)
+
+			mockIdempotencyKeyRepo.On("Claim", ctx, mock.Anything, mock.Anything).Return(false, nil)
+			mockIdempotencyKeyRepo.On("GetByKey", ctx, "USR001", "invest-42").Return(existing, nil)
+
+			record, err := service.Begin(ctx, newIdempotentRequest())
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Same(t, existing, record)
+		})
+	}
+}
+
+func TestBeginClaimsKeyReleasedMeanwhile(t *testing.T) {
+	mockIdempotencyKeyRepo := mocks.NewIdempotencyKeyRepository(t)
+	service := NewIdempotencyService(mockIdempotencyKeyRepo)
+	ctx := context.Background()
+
+	mockIdempotencyKeyRepo.On("Claim", ctx, mock.Anything, mock.Anything).Return(false, nil).Once()
+	mockIdempotencyKeyRepo.On("GetByKey", ctx, "USR001", "invest-42").Return(nil, repositories.ErrIdempotencyKeyNotFound).Once()
+	mockIdempotencyKeyRepo.On("Claim", ctx, mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	record, err := service.Begin(ctx, newIdempotentRequest())
+
+	require.NoError(t, err)
+	assert.False(t, record.Completed())
+}
+
+func TestCompleteStoresResponse(t *testing.T) {
+	mockIdempotencyKeyRepo := mocks.NewIdempotencyKeyRepository(t)
+	service := NewIdempotencyService(mockIdempotencyKeyRepo)
+	ctx := context.Background()
+	record := newIdempotentRequest()
+
+	mockIdempotencyKeyRepo.On("Complete", ctx, record).Return(nil)
+
+	err := service.Complete(ctx, record, 200, "application/json", []byte(`{"success":true}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, 200, *record.StatusCode)
+	assert.Equal(t, "application/json", *record.ContentType)
+	assert.Equal(t, `{"success":true}`, string(record.ResponseBody))
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 
+	"github.com/sswastioyono18/loan-engine/internal/authz"
 	"github.com/sswastioyono18/loan-engine/internal/models"
 )
 
@@ -13,16 +14,21 @@ type InvestorService interface {
 	GetInvestorByEmail(ctx context.Context, email string) (*models.Investor, error)
 	UpdateInvestor(ctx context.Context, id int, investor *models.Investor) error
 	DeleteInvestor(ctx context.Context, id int) error
-	ListInvestors(ctx context.Context, offset, limit int) ([]*models.Investor, error)
+	ListInvestors(ctx context.Context, page models.PageRequest) (*models.Page[*models.Investor], error)
+	ListPayouts(ctx context.Context, id int) ([]*models.Payout, error)
 }
 
 type investorServiceImpl struct {
-	repo InvestorRepository
+	repo         InvestorRepository
+	payoutRepo   PayoutRepository
+	accessPolicy authz.Policy
 }
 
-func NewInvestorService(repo InvestorRepository) InvestorService {
+func NewInvestorService(repo InvestorRepository, payoutRepo PayoutRepository, accessPolicy authz.Policy) InvestorService {
 	return &investorServiceImpl{
-		repo: repo,
+		repo:         repo,
+		payoutRepo:   payoutRepo,
+		accessPolicy: accessPolicy,
 	}
 }
 
@@ -60,6 +66,21 @@ func (s *investorServiceImpl) DeleteInvestor(ctx context.Context, id int) error
 	return s.repo.Delete(ctx, id)
 }
 
-func (s *investorServiceImpl) ListInvestors(ctx context.Context, offset, limit int) ([]*models.Investor, error) {
-	return s.repo.List(ctx, offset, limit)
+func (s *investorServiceImpl) ListInvestors(ctx context.Context, page models.PageRequest) (*models.Page[*models.Investor], error) {
+	return s.repo.List(ctx, page)
+}
+
+// ListPayouts returns the repayment shares paid to an investor, newest first. Investors
+// may only list their own.
+func (s *investorServiceImpl) ListPayouts(ctx context.Context, id int) ([]*models.Payout, error) {
+	err := s.accessPolicy.Authorize(ctx, authz.ReadPayouts, &authz.Resource{OwnerInvestorID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.payoutRepo.GetByInvestorID(ctx, id)
 }
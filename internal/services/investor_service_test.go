@@ -5,15 +5,18 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/sswastioyono18/loan-engine/internal/authz"
 	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/repositories"
 	"github.com/sswastioyono18/loan-engine/internal/repositories/mocks"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestCreateInvestor(t *testing.T) {
 	mockRepo := mocks.NewInvestorRepository(t)
-	service := NewInvestorService(mockRepo)
+	service := NewInvestorService(mockRepo, mocks.NewPayoutRepository(t), authz.DefaultPolicy())
 
 	investor := &models.Investor{
 		InvestorID: "INV001",
@@ -32,7 +35,7 @@ func TestCreateInvestor(t *testing.T) {
 
 func TestCreateInvestorError(t *testing.T) {
 	mockRepo := mocks.NewInvestorRepository(t)
-	service := NewInvestorService(mockRepo)
+	service := NewInvestorService(mockRepo, mocks.NewPayoutRepository(t), authz.DefaultPolicy())
 
 	investor := &models.Investor{
 		InvestorID: "INV001",
@@ -52,7 +55,7 @@ func TestCreateInvestorError(t *testing.T) {
 
 func TestGetInvestorByID(t *testing.T) {
 	mockRepo := mocks.NewInvestorRepository(t)
-	service := NewInvestorService(mockRepo)
+	service := NewInvestorService(mockRepo, mocks.NewPayoutRepository(t), authz.DefaultPolicy())
 
 	investor := &models.Investor{
 		ID:         1,
@@ -73,10 +76,10 @@ func TestGetInvestorByID(t *testing.T) {
 
 func TestGetInvestorByIDNotFound(t *testing.T) {
 	mockRepo := mocks.NewInvestorRepository(t)
-	service := NewInvestorService(mockRepo)
+	service := NewInvestorService(mockRepo, mocks.NewPayoutRepository(t), authz.DefaultPolicy())
 
 	// Test not found
-	mockRepo.On("GetByID", context.Background(), 1).Return(nil, errors.New("investor not found"))
+	mockRepo.On("GetByID", context.Background(), 1).Return(nil, repositories.ErrInvestorNotFound)
 
 	_, err := service.GetInvestorByID(context.Background(), 1)
 
@@ -86,7 +89,7 @@ func TestGetInvestorByIDNotFound(t *testing.T) {
 
 func TestGetInvestorByInvestorID(t *testing.T) {
 	mockRepo := mocks.NewInvestorRepository(t)
-	service := NewInvestorService(mockRepo)
+	service := NewInvestorService(mockRepo, mocks.NewPayoutRepository(t), authz.DefaultPolicy())
 
 	investor := &models.Investor{
 		ID:         1,
@@ -107,10 +110,10 @@ func TestGetInvestorByInvestorID(t *testing.T) {
 
 func TestGetInvestorByInvestorIDNotFound(t *testing.T) {
 	mockRepo := mocks.NewInvestorRepository(t)
-	service := NewInvestorService(mockRepo)
+	service := NewInvestorService(mockRepo, mocks.NewPayoutRepository(t), authz.DefaultPolicy())
 
 	// Test not found by investor ID
-	mockRepo.On("GetByInvestorID", context.Background(), "INV001").Return(nil, errors.New("investor not found"))
+	mockRepo.On("GetByInvestorID", context.Background(), "INV001").Return(nil, repositories.ErrInvestorNotFound)
 
 	_, err := service.GetInvestorByInvestorID(context.Background(), "INV001")
 
@@ -120,7 +123,7 @@ func TestGetInvestorByInvestorIDNotFound(t *testing.T) {
 
 func TestGetInvestorByEmail(t *testing.T) {
 	mockRepo := mocks.NewInvestorRepository(t)
-	service := NewInvestorService(mockRepo)
+	service := NewInvestorService(mockRepo, mocks.NewPayoutRepository(t), authz.DefaultPolicy())
 
 	investor := &models.Investor{
 		ID:         1,
@@ -141,10 +144,10 @@ func TestGetInvestorByEmail(t *testing.T) {
 
 func TestGetInvestorByEmailNotFound(t *testing.T) {
 	mockRepo := mocks.NewInvestorRepository(t)
-	service := NewInvestorService(mockRepo)
+	service := NewInvestorService(mockRepo, mocks.NewPayoutRepository(t), authz.DefaultPolicy())
 
 	// Test not found by email
-	mockRepo.On("GetByEmail", context.Background(), "nonexistent@example.com").Return(nil, errors.New("investor not found"))
+	mockRepo.On("GetByEmail", context.Background(), "nonexistent@example.com").Return(nil, repositories.ErrInvestorNotFound)
 
 	_, err := service.GetInvestorByEmail(context.Background(), "nonexistent@example.com")
 
@@ -154,7 +157,7 @@ func TestGetInvestorByEmailNotFound(t *testing.T) {
 
 func TestUpdateInvestor(t *testing.T) {
 	mockRepo := mocks.NewInvestorRepository(t)
-	service := NewInvestorService(mockRepo)
+	service := NewInvestorService(mockRepo, mocks.NewPayoutRepository(t), authz.DefaultPolicy())
 
 	existingInvestor := &models.Investor{
 		ID:         1,
@@ -185,7 +188,7 @@ func TestUpdateInvestor(t *testing.T) {
 
 func TestUpdateInvestorNotFound(t *testing.T) {
 	mockRepo := mocks.NewInvestorRepository(t)
-	service := NewInvestorService(mockRepo)
+	service := NewInvestorService(mockRepo, mocks.NewPayoutRepository(t), authz.DefaultPolicy())
 
 	updatedInvestor := &models.Investor{
 		InvestorID: "INV002",
@@ -195,7 +198,7 @@ func TestUpdateInvestorNotFound(t *testing.T) {
 	}
 
 	// Test update when investor doesn't exist
-	mockRepo.On("GetByID", context.Background(), 1).Return(nil, errors.New("investor not found"))
+	mockRepo.On("GetByID", context.Background(), 1).Return(nil, repositories.ErrInvestorNotFound)
 
 	err := service.UpdateInvestor(context.Background(), 1, updatedInvestor)
 
@@ -205,7 +208,7 @@ func TestUpdateInvestorNotFound(t *testing.T) {
 
 func TestUpdateInvestorUpdateError(t *testing.T) {
 	mockRepo := mocks.NewInvestorRepository(t)
-	service := NewInvestorService(mockRepo)
+	service := NewInvestorService(mockRepo, mocks.NewPayoutRepository(t), authz.DefaultPolicy())
 
 	existingInvestor := &models.Investor{
 		ID:         1,
@@ -235,7 +238,7 @@ func TestUpdateInvestorUpdateError(t *testing.T) {
 
 func TestDeleteInvestor(t *testing.T) {
 	mockRepo := mocks.NewInvestorRepository(t)
-	service := NewInvestorService(mockRepo)
+	service := NewInvestorService(mockRepo, mocks.NewPayoutRepository(t), authz.DefaultPolicy())
 
 	// Test successful deletion
 	mockRepo.On("Delete", context.Background(), 1).Return(nil)
@@ -247,7 +250,7 @@ func TestDeleteInvestor(t *testing.T) {
 
 func TestDeleteInvestorError(t *testing.T) {
 	mockRepo := mocks.NewInvestorRepository(t)
-	service := NewInvestorService(mockRepo)
+	service := NewInvestorService(mockRepo, mocks.NewPayoutRepository(t), authz.DefaultPolicy())
 
 	// Test deletion error
 	mockRepo.On("Delete", context.Background(), 1).Return(errors.New("delete failed"))
@@ -260,7 +263,7 @@ func TestDeleteInvestorError(t *testing.T) {
 
 func TestListInvestors(t *testing.T) {
 	mockRepo := mocks.NewInvestorRepository(t)
-	service := NewInvestorService(mockRepo)
+	service := NewInvestorService(mockRepo, mocks.NewPayoutRepository(t), authz.DefaultPolicy())
 
 	investors := []*models.Investor{
 		{
@@ -280,23 +283,75 @@ func TestListInvestors(t *testing.T) {
 	}
 
 	// Test successful listing
-	mockRepo.On("List", context.Background(), 0, 10).Return(investors, nil)
+	page := models.PageRequest{Limit: 10}
+	listed := &models.Page[*models.Investor]{Items: investors, TotalEstimate: 2}
+	mockRepo.On("List", context.Background(), page).Return(listed, nil)
 
-	result, err := service.ListInvestors(context.Background(), 0, 10)
+	result, err := service.ListInvestors(context.Background(), page)
 
 	assert.NoError(t, err)
-	assert.Equal(t, investors, result)
+	assert.Equal(t, listed, result)
 }
 
 func TestListInvestorsError(t *testing.T) {
 	mockRepo := mocks.NewInvestorRepository(t)
-	service := NewInvestorService(mockRepo)
+	service := NewInvestorService(mockRepo, mocks.NewPayoutRepository(t), authz.DefaultPolicy())
 
 	// Test listing error
-	mockRepo.On("List", context.Background(), 0, 10).Return(nil, errors.New("list failed"))
+	mockRepo.On("List", context.Background(), models.PageRequest{Limit: 10}).Return(nil, errors.New("list failed"))
 
-	_, err := service.ListInvestors(context.Background(), 0, 10)
+	_, err := service.ListInvestors(context.Background(), models.PageRequest{Limit: 10})
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "list failed")
 }
+
+func TestListPayouts(t *testing.T) {
+	mockRepo := mocks.NewInvestorRepository(t)
+	mockPayoutRepo := mocks.NewPayoutRepository(t)
+	service := NewInvestorService(mockRepo, mockPayoutRepo, authz.DefaultPolicy())
+
+	payouts := []*models.Payout{
+		{ID: 1, InvestorID: 1, LoanID: 1, Amount: money.MustParse("36.01")},
+	}
+	ctx := asUser(authz.RoleAdmin)
+
+	mockRepo.On("GetByID", ctx, 1).Return(&models.Investor{ID: 1}, nil)
+	mockPayoutRepo.On("GetByInvestorID", ctx, 1).Return(payouts, nil)
+
+	result, err := service.ListPayouts(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, payouts, result)
+}
+
+func TestListPayoutsUnknownInvestor(t *testing.T) {
+	mockRepo := mocks.NewInvestorRepository(t)
+	mockPayoutRepo := mocks.NewPayoutRepository(t)
+	service := NewInvestorService(mockRepo, mockPayoutRepo, authz.DefaultPolicy())
+	ctx := asUser(authz.RoleAdmin)
+
+	mockRepo.On("GetByID", ctx, 99).Return(nil, repositories.ErrInvestorNotFound)
+
+	_, err := service.ListPayouts(ctx, 99)
+
+	assert.Error(t, err)
+	mockPayoutRepo.AssertNotCalled(t, "GetByInvestorID", mock.Anything, mock.Anything)
+}
+
+func TestListPayoutsOnlyOwnForInvestors(t *testing.T) {
+	mockRepo := mocks.NewInvestorRepository(t)
+	mockPayoutRepo := mocks.NewPayoutRepository(t)
+	service := NewInvestorService(mockRepo, mockPayoutRepo, authz.DefaultPolicy())
+	ctx := asInvestor(&models.Investor{ID: 3, Email: "investor@example.com"})
+
+	mockRepo.On("GetByID", ctx, 3).Return(&models.Investor{ID: 3}, nil)
+	mockPayoutRepo.On("GetByInvestorID", ctx, 3).Return([]*models.Payout{}, nil)
+
+	_, err := service.ListPayouts(ctx, 3)
+	assert.NoError(t, err)
+
+	_, err = service.ListPayouts(ctx, 4)
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, 4)
+}
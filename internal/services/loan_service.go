@@ -4,9 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/authz"
+	"github.com/sswastioyono18/loan-engine/internal/ledger"
+	"github.com/sswastioyono18/loan-engine/internal/loanstate"
 	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/repayment"
 	"github.com/sswastioyono18/loan-engine/pkg/external"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
 )
 
 type LoanService interface {
@@ -15,7 +23,7 @@ type LoanService interface {
 	GetLoanByLoanID(ctx context.Context, loanID string) (*models.Loan, error)
 	UpdateLoan(ctx context.Context, id int, loan *models.Loan) error
 	DeleteLoan(ctx context.Context, id int) error
-	ListLoans(ctx context.Context, state *string, offset, limit int) ([]*models.Loan, error)
+	ListLoans(ctx context.Context, filter models.LoanFilter, page models.PageRequest) (*models.Page[*models.Loan], error)
 	GetLoansByState(ctx context.Context, state string) ([]*models.Loan, error)
 
 	// State transition methods
@@ -23,61 +31,131 @@ type LoanService interface {
 	InvestInLoan(ctx context.Context, loanID int, investment *models.LoanInvestment) error
 	DisburseLoan(ctx context.Context, loanID int, disbursementData *models.LoanDisbursement) error
 
+	// Rejection with a reason from the managed catalogue
+	RejectLoan(ctx context.Context, loanID int, rejection *models.LoanRejection) error
+	ListRejectionReasons(ctx context.Context, activeOnly bool) ([]*models.LoanRejectionReason, error)
+	CreateRejectionReason(ctx context.Context, reason *models.LoanRejectionReason) error
+	UpdateRejectionReason(ctx context.Context, code string, reason *models.LoanRejectionReason) error
+
+	// Transitions out of the lending flow; each requires a reason for the state history
+	CancelLoan(ctx context.Context, loanID int, reason string) error
+	ExpireLoan(ctx context.Context, loanID int, reason string) error
+	MarkLoanRepaid(ctx context.Context, loanID int, reason string) error
+	CloseLoan(ctx context.Context, loanID int, reason string) error
+	DefaultLoan(ctx context.Context, loanID int, reason string) error
+	WriteOffLoan(ctx context.Context, loanID int, reason string) error
+
+	// Repayment schedule, generated when the loan is disbursed
+	GetRepaymentSchedule(ctx context.Context, loanID int) ([]*models.LoanInstallment, error)
+
 	// Helper methods
-	GetTotalInvestedAmount(ctx context.Context, loanID int) (float64, error)
+	GetTotalInvestedAmount(ctx context.Context, loanID int) (money.Money, error)
 	CanTransitionToState(ctx context.Context, loanID int, newState string) (bool, error)
 }
 
 type loanServiceImpl struct {
 	loanRepo             LoanRepository
 	loanApprovalRepo     LoanApprovalRepository
+	loanRejectionRepo    LoanRejectionRepository
+	rejectionReasonRepo  LoanRejectionReasonRepository
 	loanDisbursementRepo LoanDisbursementRepository
 	loanInvestmentRepo   LoanInvestmentRepository
 	loanStateHistoryRepo LoanStateHistoryRepository
+	loanInstallmentRepo  LoanInstallmentRepository
+	borrowerRepo         BorrowerRepository
 	investorRepo         InvestorRepository
+	unitOfWork           UnitOfWork
+	generalLedger        Ledger
 	emailService         external.EmailService
 	storageService       external.StorageService
+	accessPolicy         authz.Policy
+	stateMachine         *loanstate.Machine
 }
 
 func NewLoanService(
 	loanRepo LoanRepository,
 	loanApprovalRepo LoanApprovalRepository,
+	loanRejectionRepo LoanRejectionRepository,
+	rejectionReasonRepo LoanRejectionReasonRepository,
 	loanDisbursementRepo LoanDisbursementRepository,
 	loanInvestmentRepo LoanInvestmentRepository,
 	loanStateHistoryRepo LoanStateHistoryRepository,
+	loanInstallmentRepo LoanInstallmentRepository,
+	borrowerRepo BorrowerRepository,
 	investorRepo InvestorRepository,
+	unitOfWork UnitOfWork,
+	generalLedger Ledger,
 	emailService external.EmailService,
 	storageService external.StorageService,
+	accessPolicy authz.Policy,
 ) LoanService {
-	return &loanServiceImpl{
+	service := &loanServiceImpl{
 		loanRepo:             loanRepo,
 		loanApprovalRepo:     loanApprovalRepo,
+		loanRejectionRepo:    loanRejectionRepo,
+		rejectionReasonRepo:  rejectionReasonRepo,
 		loanDisbursementRepo: loanDisbursementRepo,
 		loanInvestmentRepo:   loanInvestmentRepo,
 		loanStateHistoryRepo: loanStateHistoryRepo,
+		loanInstallmentRepo:  loanInstallmentRepo,
+		borrowerRepo:         borrowerRepo,
 		investorRepo:         investorRepo,
+		unitOfWork:           unitOfWork,
+		generalLedger:        generalLedger,
 		emailService:         emailService,
 		storageService:       storageService,
+		accessPolicy:         accessPolicy,
 	}
+	service.stateMachine = service.newStateMachine()
+	return service
+}
+
+// newStateMachine attaches the guards and side-effect hooks of each transition to the
+// loan state machine
+func (s *loanServiceImpl) newStateMachine() *loanstate.Machine {
+	return loanstate.NewMachine(loanstate.Transitions).
+		Guard(loanstate.Fund, s.requireFullyInvested).
+		Guard(loanstate.Disburse, s.requireExactlyFunded).
+		Guard(loanstate.MarkRepaid, s.requireFullyRepaid).
+		Hook(loanstate.Reject, s.refundInvestors).
+		Hook(loanstate.Cancel, s.refundInvestors).
+		Hook(loanstate.Expire, s.refundInvestors).
+		Hook(loanstate.WriteOff, s.writeOffPrincipal)
 }
 
 func (s *loanServiceImpl) CreateLoan(ctx context.Context, loan *models.Loan) error {
 	// Validate required fields
-	if loan.PrincipalAmount <= 0 {
-		return errors.New("principal amount must be greater than 0")
+	if !loan.PrincipalAmount.IsPositive() {
+		return apperr.Validation("invalid_loan", "principal amount must be greater than 0")
+	}
+
+	if loan.Rate < 0 || loan.Rate > money.OneHundredPercent {
+		return apperr.Validation("invalid_loan", "rate must be between 0 and 1")
 	}
 
-	if loan.Rate < 0 || loan.Rate > 100 {
-		return errors.New("rate must be between 0 and 100")
+	if loan.ROI < 0 || loan.ROI > money.OneHundredPercent {
+		return apperr.Validation("invalid_loan", "ROI must be between 0 and 1")
 	}
 
-	if loan.ROI < 0 || loan.ROI > 100 {
-		return errors.New("ROI must be between 0 and 100")
+	if loan.TenorMonths == 0 {
+		loan.TenorMonths = repayment.DefaultTenorMonths
+	}
+
+	if loan.TenorMonths < 1 || loan.TenorMonths > repayment.MaxTenorMonths {
+		return apperr.Validation("invalid_loan", "tenor must be between 1 and %d months", repayment.MaxTenorMonths)
+	}
+
+	if loan.RepaymentMethod == "" {
+		loan.RepaymentMethod = string(repayment.DefaultMethod)
+	}
+
+	if !repayment.Method(loan.RepaymentMethod).Valid() {
+		return apperr.Validation("invalid_loan", "repayment method must be one of flat, annuity or bullet")
 	}
 
 	// Set initial state to proposed
-	loan.CurrentState = "proposed"
-	loan.TotalInvestedAmount = 0.0
+	loan.CurrentState = loanstate.Proposed
+	loan.TotalInvestedAmount = money.Zero(loan.PrincipalAmount.Currency())
 
 	return s.loanRepo.Create(ctx, loan)
 }
@@ -97,14 +175,29 @@ func (s *loanServiceImpl) UpdateLoan(ctx context.Context, id int, loan *models.L
 		return err
 	}
 
+	// A rejection is final; the borrower has to apply again
+	if existingLoan.CurrentState == loanstate.Rejected {
+		return apperr.InvalidState("loan_rejected", "rejected loans cannot be modified")
+	}
+
 	// Prevent modification of certain fields based on state
-	if existingLoan.CurrentState != "proposed" {
+	if existingLoan.CurrentState != loanstate.Proposed {
 		// Only allow updating specific fields after loan is approved
 		loan.BorrowerID = existingLoan.BorrowerID
 		loan.PrincipalAmount = existingLoan.PrincipalAmount
 		loan.Rate = existingLoan.Rate
 		loan.ROI = existingLoan.ROI
 		loan.AgreementLetterLink = existingLoan.AgreementLetterLink
+		loan.TenorMonths = existingLoan.TenorMonths
+		loan.RepaymentMethod = existingLoan.RepaymentMethod
+	}
+
+	// Repayment terms are optional on update; keep the current ones when omitted
+	if loan.TenorMonths == 0 {
+		loan.TenorMonths = existingLoan.TenorMonths
+	}
+	if loan.RepaymentMethod == "" {
+		loan.RepaymentMethod = existingLoan.RepaymentMethod
 	}
 
 	// Update fields
@@ -121,15 +214,21 @@ func (s *loanServiceImpl) DeleteLoan(ctx context.Context, id int) error {
 		return err
 	}
 
-	if loan.CurrentState != "proposed" {
-		return errors.New("loan can only be deleted in proposed state")
+	// Rejected loans are kept with their rejection record
+	if loan.CurrentState == loanstate.Rejected {
+		return apperr.InvalidState("loan_rejected", "rejected loans cannot be deleted")
+	}
+
+	if loan.CurrentState != loanstate.Proposed {
+		return apperr.InvalidState("invalid_loan_state", "loan can only be deleted in proposed state")
 	}
 
 	return s.loanRepo.Delete(ctx, id)
 }
 
-func (s *loanServiceImpl) ListLoans(ctx context.Context, state *string, offset, limit int) ([]*models.Loan, error) {
-	return s.loanRepo.List(ctx, state, offset, limit)
+// ListLoans returns the page of loans matching filter that page asks for
+func (s *loanServiceImpl) ListLoans(ctx context.Context, filter models.LoanFilter, page models.PageRequest) (*models.Page[*models.Loan], error) {
+	return s.loanRepo.List(ctx, filter, page)
 }
 
 func (s *loanServiceImpl) GetLoansByState(ctx context.Context, state string) ([]*models.Loan, error) {
@@ -137,191 +236,432 @@ func (s *loanServiceImpl) GetLoansByState(ctx context.Context, state string) ([]
 }
 
 func (s *loanServiceImpl) ApproveLoan(ctx context.Context, loanID int, approvalData *models.LoanApproval) error {
-	// Get the loan
-	loan, err := s.loanRepo.GetByID(ctx, loanID)
+	err := s.accessPolicy.Authorize(ctx, authz.ApproveLoan, nil)
 	if err != nil {
-		return fmt.Errorf("loan not found: %w", err)
+		return err
 	}
 
-	// Check if loan is in proposed state
-	if loan.CurrentState != "proposed" {
-		return errors.New("loan must be in proposed state to be approved")
+	// The approving field validator is whoever is signed in, never what the request claims
+	validator, err := authz.StaffMember(ctx, authz.RoleFieldValidator)
+	if err != nil {
+		return err
 	}
+	approvalData.FieldValidatorEmployeeID = validator.UserID
 
-	// Validate approval data
-	if approvalData.FieldValidatorEmployeeID == "" {
-		return errors.New("field validator employee ID is required")
-	}
+	return s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		// Lock the loan row so a concurrent request sees the state this one leaves behind
+		loan, err := s.loanRepo.GetByIDForUpdate(ctx, loanID)
+		if err != nil {
+			return err
+		}
 
-	if approvalData.ProofImageUrl == "" {
-		return errors.New("proof image URL is required")
-	}
+		return s.transition(ctx, loan, loanstate.Approve, "Loan approved by staff", func(ctx context.Context, loan *models.Loan) error {
+			// Validate approval data
+			if approvalData.ProofImageUrl == "" {
+				return apperr.Validation("invalid_approval", "proof image URL is required")
+			}
+
+			// Create loan approval record
+			approvalData.LoanID = loanID
+			err := s.loanApprovalRepo.Create(ctx, approvalData)
+			if err != nil {
+				return fmt.Errorf("failed to create loan approval: %w", err)
+			}
+
+			return nil
+		})
+	})
+}
 
-	// Create loan approval record
-	approvalData.LoanID = loanID
-	err = s.loanApprovalRepo.Create(ctx, approvalData)
+func (s *loanServiceImpl) InvestInLoan(ctx context.Context, loanID int, investment *models.LoanInvestment) error {
+	// Investors may only invest as the investor record linked to their account
+	err := s.accessPolicy.Authorize(ctx, authz.InvestInLoan, &authz.Resource{OwnerInvestorID: investment.InvestorID})
 	if err != nil {
-		return fmt.Errorf("failed to create loan approval: %w", err)
+		return err
 	}
 
-	// Update loan state to approved
-	err = s.loanRepo.UpdateState(ctx, loanID, "approved")
+	_, err = s.investorRepo.GetByID(ctx, investment.InvestorID)
 	if err != nil {
-		return fmt.Errorf("failed to update loan state: %w", err)
+		return err
 	}
 
-	// Add state transition to history
-	stateHistory := &models.LoanStateHistory{
-		LoanID:           loanID,
-		PreviousState:    loan.CurrentState,
-		NewState:         "approved",
-		TransitionReason: "Loan approved by staff",
-	}
+	var loan *models.Loan
+	fullyInvested := false
 
-	err = s.loanStateHistoryRepo.Create(ctx, stateHistory)
-	if err != nil {
-		return fmt.Errorf("failed to create state history: %w", err)
-	}
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		// Lock the loan row so concurrent investments are checked against the latest total
+		var err error
+		loan, err = s.loanRepo.GetByIDForUpdate(ctx, loanID)
+		if err != nil {
+			return err
+		}
 
-	return nil
-}
+		// Investments are only accepted while the loan can still be funded
+		err = s.stateMachine.Allows(loan.CurrentState, loanstate.Fund)
+		if err != nil {
+			return err
+		}
 
-func (s *loanServiceImpl) InvestInLoan(ctx context.Context, loanID int, investment *models.LoanInvestment) error {
-	// Get the loan
-	loan, err := s.loanRepo.GetByID(ctx, loanID)
+		// Validate investment amount
+		if !investment.InvestmentAmount.IsPositive() {
+			return apperr.Validation("invalid_investment", "investment amount must be greater than 0")
+		}
+
+		if !investment.InvestmentAmount.SameCurrency(loan.PrincipalAmount) {
+			return apperr.Validation("currency_mismatch", "investment currency must be %s", loan.PrincipalAmount.Currency())
+		}
+
+		// Check if investment amount exceeds remaining principal
+		remainingPrincipal := loan.PrincipalAmount.Sub(loan.TotalInvestedAmount)
+		if investment.InvestmentAmount.GreaterThan(remainingPrincipal) {
+			return apperr.Validation("investment_exceeds_remaining", "investment amount exceeds remaining principal. Remaining: %s", remainingPrincipal)
+		}
+
+		// Check if investor already invested in this loan
+		existingInvestment, err := s.loanInvestmentRepo.GetByLoanAndInvestor(ctx, loanID, investment.InvestorID)
+		if err != nil && !errors.Is(err, apperr.ErrNotFound) {
+			return fmt.Errorf("failed to check existing investment: %w", err)
+		}
+		if existingInvestment != nil {
+			return apperr.Conflict("already_invested", "investor already invested in this loan")
+		}
+
+		// Create investment record
+		investment.LoanID = loanID
+		err = s.loanInvestmentRepo.Create(ctx, investment)
+		if err != nil {
+			return fmt.Errorf("failed to create investment: %w", err)
+		}
+
+		// The platform now holds the investor's money until the loan is disbursed
+		entry := ledger.NewEntry(loanID, fmt.Sprintf("loan_investment:%d", investment.ID), "Investor funds received").
+			Debit(ledger.AccountCash, investment.InvestmentAmount).
+			Credit(ledger.AccountInvestorFunds, investment.InvestmentAmount)
+		err = s.generalLedger.Post(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("failed to post investment to ledger: %w", err)
+		}
+
+		// Update total invested amount in loan
+		newTotal := loan.TotalInvestedAmount.Add(investment.InvestmentAmount)
+		err = s.loanRepo.UpdateTotalInvestedAmount(ctx, loanID, newTotal)
+		if err != nil {
+			return fmt.Errorf("failed to update total invested amount: %w", err)
+		}
+
+		loan.TotalInvestedAmount = newTotal
+
+		// Check if loan is fully invested
+		if newTotal.LessThan(loan.PrincipalAmount) {
+			return nil
+		}
+
+		err = s.transition(ctx, loan, loanstate.Fund, "Loan fully invested", nil)
+		if err != nil {
+			return err
+		}
+
+		fullyInvested = true
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("loan not found: %w", err)
+		return err
 	}
 
-	// Check if loan is in approved state
-	if loan.CurrentState != "approved" {
-		return errors.New("loan must be in approved state to receive investments")
+	// Notifications are sent only once the investment has been committed
+	if fullyInvested {
+		s.sendInvestmentConfirmations(ctx, loan)
 	}
 
-	// Validate investment amount
-	if investment.InvestmentAmount <= 0 {
-		return errors.New("investment amount must be greater than 0")
-	}
+	return nil
+}
 
-	// Check if investment amount exceeds remaining principal
-	remainingPrincipal := loan.PrincipalAmount - loan.TotalInvestedAmount
-	if investment.InvestmentAmount > remainingPrincipal {
-		return fmt.Errorf("investment amount exceeds remaining principal. Remaining: %f", remainingPrincipal)
+// sendInvestmentConfirmations emails every investor of a fully invested loan
+func (s *loanServiceImpl) sendInvestmentConfirmations(ctx context.Context, loan *models.Loan) {
+	investments, err := s.loanInvestmentRepo.GetByLoanID(ctx, loan.ID)
+	if err != nil {
+		return
 	}
 
-	// Check if investor already invested in this loan
-	existingInvestment, err := s.loanInvestmentRepo.GetByLoanAndInvestor(ctx, loanID, investment.InvestorID)
-	if err == nil && existingInvestment != nil {
-		return errors.New("investor already invested in this loan")
+	for _, inv := range investments {
+		investor, err := s.investorRepo.GetByID(ctx, inv.InvestorID)
+		if err != nil {
+			continue // Log error but continue with other investors
+		}
+
+		// Send investment confirmation email
+		agreementLink := ""
+		if loan.AgreementLetterLink.Valid {
+			agreementLink = loan.AgreementLetterLink.String
+		}
+		err = s.emailService.SendInvestmentConfirmation(ctx, investor.Email, agreementLink, fmt.Sprintf("Loan %s has been fully invested", loan.LoanID))
+		if err != nil {
+			// Log error but continue with other investors
+		}
 	}
+}
 
-	// Create investment record
-	investment.LoanID = loanID
-	err = s.loanInvestmentRepo.Create(ctx, investment)
+func (s *loanServiceImpl) DisburseLoan(ctx context.Context, loanID int, disbursementData *models.LoanDisbursement) error {
+	err := s.accessPolicy.Authorize(ctx, authz.DisburseLoan, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create investment: %w", err)
+		return err
 	}
 
-	// Update total invested amount in loan
-	newTotal := loan.TotalInvestedAmount + investment.InvestmentAmount
-	err = s.loanRepo.UpdateTotalInvestedAmount(ctx, loanID, newTotal)
+	// The disbursing field officer is whoever is signed in, never what the request claims
+	officer, err := authz.StaffMember(ctx, authz.RoleFieldOfficer)
 	if err != nil {
-		return fmt.Errorf("failed to update total invested amount: %w", err)
+		return err
 	}
+	disbursementData.FieldOfficerEmployeeID = officer.UserID
 
-	// Check if loan is fully invested
-	if newTotal >= loan.PrincipalAmount {
-		// Update loan state to invested
-		err = s.loanRepo.UpdateState(ctx, loanID, "invested")
+	return s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		// Lock the loan row so a concurrent request sees the state this one leaves behind
+		loan, err := s.loanRepo.GetByIDForUpdate(ctx, loanID)
 		if err != nil {
-			return fmt.Errorf("failed to update loan state: %w", err)
+			return err
 		}
 
-		// Add state transition to history
-		stateHistory := &models.LoanStateHistory{
-			LoanID:           loanID,
-			PreviousState:    loan.CurrentState,
-			NewState:         "invested",
-			TransitionReason: "Loan fully invested",
-		}
+		err = s.transition(ctx, loan, loanstate.Disburse, "Loan disbursed to borrower", func(ctx context.Context, loan *models.Loan) error {
+			// Validate disbursement data
+			if disbursementData.AgreementLetterSignedUrl == "" {
+				return apperr.Validation("invalid_disbursement", "signed agreement letter URL is required")
+			}
 
-		err = s.loanStateHistoryRepo.Create(ctx, stateHistory)
+			// Create loan disbursement record
+			disbursementData.LoanID = loanID
+			if disbursementData.DisbursementDate.IsZero() {
+				disbursementData.DisbursementDate = time.Now()
+			}
+			err := s.loanDisbursementRepo.Create(ctx, disbursementData)
+			if err != nil {
+				return fmt.Errorf("failed to create loan disbursement: %w", err)
+			}
+
+			// Cash goes out to the borrower and the investors' funds become capital lent out
+			entry := ledger.NewEntry(loanID, fmt.Sprintf("loan_disbursement:%d", disbursementData.ID), "Loan disbursed to borrower").
+				Debit(ledger.AccountLoansReceivable, loan.PrincipalAmount).
+				Credit(ledger.AccountCash, loan.PrincipalAmount).
+				Debit(ledger.AccountInvestorFunds, loan.PrincipalAmount).
+				Credit(ledger.AccountInvestorCapital, loan.PrincipalAmount)
+			err = s.generalLedger.Post(ctx, entry)
+			if err != nil {
+				return fmt.Errorf("failed to post disbursement to ledger: %w", err)
+			}
+
+			return nil
+		})
 		if err != nil {
-			return fmt.Errorf("failed to create state history: %w", err)
+			return err
 		}
 
-		// Send investment confirmation emails to all investors
-		investments, err := s.loanInvestmentRepo.GetByLoanID(ctx, loanID)
+		// The borrower starts owing from the disbursement date
+		installments, err := repayment.Generate(repayment.Terms{
+			Principal:   loan.PrincipalAmount,
+			AnnualRate:  loan.Rate,
+			TenorMonths: loan.TenorMonths,
+			Method:      repayment.Method(loan.RepaymentMethod),
+			StartDate:   disbursementData.DisbursementDate,
+		})
 		if err != nil {
-			return fmt.Errorf("failed to get loan investments: %w", err)
+			return fmt.Errorf("failed to generate repayment schedule: %w", err)
 		}
 
-		for _, inv := range investments {
-			investor, err := s.investorRepo.GetByID(ctx, inv.InvestorID)
+		for _, installment := range installments {
+			installment.LoanID = loanID
+			err = s.loanInstallmentRepo.Create(ctx, installment)
 			if err != nil {
-				continue // Log error but continue with other investors
+				return fmt.Errorf("failed to create loan installment: %w", err)
 			}
+		}
+
+		return nil
+	})
+}
+
+// RejectLoan turns down a loan before it is invested for a reason from the catalogue and
+// tells the borrower why
+func (s *loanServiceImpl) RejectLoan(ctx context.Context, loanID int, rejection *models.LoanRejection) error {
+	err := s.accessPolicy.Authorize(ctx, authz.RejectLoan, nil)
+	if err != nil {
+		return err
+	}
+
+	if rejection.ReasonCode == "" {
+		return apperr.Validation("invalid_rejection", "reason code is required")
+	}
+
+	// The rejecting employee is whoever is signed in
+	rejection.RejectedByEmployeeID = authz.Actor(ctx)
+
+	var loan *models.Loan
+	var reason *models.LoanRejectionReason
+
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		// Lock the loan row so the rejection cannot race an investment
+		var err error
+		loan, err = s.loanRepo.GetByIDForUpdate(ctx, loanID)
+		if err != nil {
+			return err
+		}
+
+		reason, err = s.rejectionReasonRepo.GetByCode(ctx, rejection.ReasonCode)
+		if errors.Is(err, apperr.ErrNotFound) {
+			return apperr.Validation("unknown_rejection_reason", "unknown rejection reason code: %s", rejection.ReasonCode)
+		}
+		if err != nil {
+			return err
+		}
+
+		if !reason.IsActive {
+			return apperr.Validation("rejection_reason_inactive", "rejection reason %s is no longer in use", rejection.ReasonCode)
+		}
 
-			// Send investment confirmation email
-			agreementLink := ""
-			if loan.AgreementLetterLink.Valid {
-				agreementLink = loan.AgreementLetterLink.String
+		transitionReason := reason.Description
+		if rejection.Notes != "" {
+			transitionReason += ": " + rejection.Notes
+		}
+
+		return s.transition(ctx, loan, loanstate.Reject, transitionReason, func(ctx context.Context, loan *models.Loan) error {
+			// Create loan rejection record
+			rejection.LoanID = loanID
+			if rejection.RejectionDate.IsZero() {
+				rejection.RejectionDate = time.Now()
 			}
-			err = s.emailService.SendInvestmentConfirmation(ctx, investor.Email, agreementLink, fmt.Sprintf("Loan %s has been fully invested", loan.LoanID))
+			err := s.loanRejectionRepo.Create(ctx, rejection)
 			if err != nil {
-				// Log error but continue with other investors
+				return fmt.Errorf("failed to create loan rejection: %w", err)
 			}
-		}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return err
 	}
 
+	// Notifications are sent only once the rejection has been committed
+	s.sendRejectionNotification(ctx, loan, reason)
+
 	return nil
 }
 
-func (s *loanServiceImpl) DisburseLoan(ctx context.Context, loanID int, disbursementData *models.LoanDisbursement) error {
-	// Get the loan
-	loan, err := s.loanRepo.GetByID(ctx, loanID)
+// sendRejectionNotification emails the borrower of a rejected loan
+func (s *loanServiceImpl) sendRejectionNotification(ctx context.Context, loan *models.Loan, reason *models.LoanRejectionReason) {
+	borrower, err := s.borrowerRepo.GetByID(ctx, loan.BorrowerID)
+	if err != nil {
+		return
+	}
+
+	err = s.emailService.SendRejectionNotification(ctx, borrower.Email, reason.Description, fmt.Sprintf("Loan %s has been rejected", loan.LoanID))
 	if err != nil {
-		return fmt.Errorf("loan not found: %w", err)
+		// Log error; the rejection itself has already been recorded
+	}
+}
+
+func (s *loanServiceImpl) ListRejectionReasons(ctx context.Context, activeOnly bool) ([]*models.LoanRejectionReason, error) {
+	return s.rejectionReasonRepo.List(ctx, activeOnly)
+}
+
+func (s *loanServiceImpl) CreateRejectionReason(ctx context.Context, reason *models.LoanRejectionReason) error {
+	if reason.Code == "" {
+		return apperr.Validation("invalid_rejection_reason", "reason code is required")
 	}
 
-	// Check if loan is in invested state
-	if loan.CurrentState != "invested" {
-		return errors.New("loan must be in invested state to be disbursed")
+	if reason.Description == "" {
+		return apperr.Validation("invalid_rejection_reason", "reason description is required")
 	}
 
-	// Check if total invested amount equals principal amount
-	if loan.TotalInvestedAmount != loan.PrincipalAmount {
-		return errors.New("total invested amount must equal principal amount for disbursement")
+	// New reasons are available straight away
+	reason.IsActive = true
+
+	return s.rejectionReasonRepo.Create(ctx, reason)
+}
+
+func (s *loanServiceImpl) UpdateRejectionReason(ctx context.Context, code string, reason *models.LoanRejectionReason) error {
+	if reason.Description == "" {
+		return apperr.Validation("invalid_rejection_reason", "reason description is required")
 	}
 
-	// Validate disbursement data
-	if disbursementData.FieldOfficerEmployeeID == "" {
-		return errors.New("field officer employee ID is required")
+	// Reasons are retired rather than deleted so past rejections keep their meaning
+	reason.Code = code
+
+	return s.rejectionReasonRepo.Update(ctx, reason)
+}
+
+// CancelLoan withdraws a loan before it is disbursed
+func (s *loanServiceImpl) CancelLoan(ctx context.Context, loanID int, reason string) error {
+	return s.transitionWithReason(ctx, loanID, loanstate.Cancel, authz.CancelLoan, reason)
+}
+
+// ExpireLoan ends a loan that was not funded in time
+func (s *loanServiceImpl) ExpireLoan(ctx context.Context, loanID int, reason string) error {
+	return s.transitionWithReason(ctx, loanID, loanstate.Expire, authz.ExpireLoan, reason)
+}
+
+// MarkLoanRepaid records that the borrower has paid every installment
+func (s *loanServiceImpl) MarkLoanRepaid(ctx context.Context, loanID int, reason string) error {
+	return s.transitionWithReason(ctx, loanID, loanstate.MarkRepaid, authz.MarkLoanRepaid, reason)
+}
+
+// CloseLoan closes a repaid loan
+func (s *loanServiceImpl) CloseLoan(ctx context.Context, loanID int, reason string) error {
+	return s.transitionWithReason(ctx, loanID, loanstate.Close, authz.CloseLoan, reason)
+}
+
+// DefaultLoan records that the borrower has stopped paying
+func (s *loanServiceImpl) DefaultLoan(ctx context.Context, loanID int, reason string) error {
+	return s.transitionWithReason(ctx, loanID, loanstate.Default, authz.DefaultLoan, reason)
+}
+
+// WriteOffLoan gives up on collecting a defaulted loan
+func (s *loanServiceImpl) WriteOffLoan(ctx context.Context, loanID int, reason string) error {
+	return s.transitionWithReason(ctx, loanID, loanstate.WriteOff, authz.WriteOffLoan, reason)
+}
+
+// transitionWithReason fires event on a loan whose only input is the reason recorded in
+// the state history, once the user is allowed to perform action
+func (s *loanServiceImpl) transitionWithReason(ctx context.Context, loanID int, event loanstate.Event, action authz.Action, reason string) error {
+	err := s.accessPolicy.Authorize(ctx, action, nil)
+	if err != nil {
+		return err
 	}
 
-	if disbursementData.AgreementLetterSignedUrl == "" {
-		return errors.New("signed agreement letter URL is required")
+	if strings.TrimSpace(reason) == "" {
+		return apperr.Validation("reason_required", "reason is required")
 	}
 
-	// Create loan disbursement record
-	disbursementData.LoanID = loanID
-	err = s.loanDisbursementRepo.Create(ctx, disbursementData)
+	return s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		// Lock the loan row so the transition cannot race an investment or repayment
+		loan, err := s.loanRepo.GetByIDForUpdate(ctx, loanID)
+		if err != nil {
+			return err
+		}
+
+		return s.transition(ctx, loan, event, reason, nil)
+	})
+}
+
+// transition fires event on loan through the state machine, running effect between the
+// guards and the hooks, then stores the new state and records reason in the state history.
+// It must run inside a unit of work so a failing step rolls back the others.
+func (s *loanServiceImpl) transition(ctx context.Context, loan *models.Loan, event loanstate.Event, reason string, effect loanstate.Hook) error {
+	newState, err := s.stateMachine.Fire(ctx, loan, event, effect)
 	if err != nil {
-		return fmt.Errorf("failed to create loan disbursement: %w", err)
+		return err
 	}
 
-	// Update loan state to disbursed
-	err = s.loanRepo.UpdateState(ctx, loanID, "disbursed")
+	err = s.loanRepo.UpdateState(ctx, loan.ID, newState)
 	if err != nil {
 		return fmt.Errorf("failed to update loan state: %w", err)
 	}
 
 	// Add state transition to history
 	stateHistory := &models.LoanStateHistory{
-		LoanID:           loanID,
+		LoanID:           loan.ID,
 		PreviousState:    loan.CurrentState,
-		NewState:         "disbursed",
-		TransitionReason: "Loan disbursed to borrower",
+		NewState:         newState,
+		TransitionReason: reason,
+		ChangedBy:        authz.Actor(ctx),
 	}
 
 	err = s.loanStateHistoryRepo.Create(ctx, stateHistory)
@@ -332,36 +672,110 @@ func (s *loanServiceImpl) DisburseLoan(ctx context.Context, loanID int, disburse
 	return nil
 }
 
-func (s *loanServiceImpl) GetTotalInvestedAmount(ctx context.Context, loanID int) (float64, error) {
-	return s.loanRepo.GetTotalInvestedAmount(ctx, loanID)
+// requireFullyInvested keeps a loan open for investment until its principal is covered
+func (s *loanServiceImpl) requireFullyInvested(ctx context.Context, loan *models.Loan) error {
+	if loan.TotalInvestedAmount.LessThan(loan.PrincipalAmount) {
+		return apperr.InvalidState("loan_not_fully_invested", "loan is not fully invested yet")
+	}
+	return nil
 }
 
-func (s *loanServiceImpl) CanTransitionToState(ctx context.Context, loanID int, newState string) (bool, error) {
-	loan, err := s.loanRepo.GetByID(ctx, loanID)
-	if err != nil {
-		return false, err
+// requireExactlyFunded refuses to disburse a loan whose investments do not match its principal
+func (s *loanServiceImpl) requireExactlyFunded(ctx context.Context, loan *models.Loan) error {
+	if !loan.TotalInvestedAmount.Equal(loan.PrincipalAmount) {
+		return apperr.InvalidState("loan_not_fully_invested", "total invested amount must equal principal amount for disbursement")
 	}
+	return nil
+}
 
-	currentState := loan.CurrentState
+// refundInvestors returns the money investors committed to a loan that will not be disbursed
+func (s *loanServiceImpl) refundInvestors(ctx context.Context, loan *models.Loan) error {
+	if !loan.TotalInvestedAmount.IsPositive() {
+		return nil
+	}
 
-	// Define valid state transitions
-	validTransitions := map[string][]string{
-		"proposed":  {"approved"},
-		"approved":  {"invested"},
-		"invested":  {"disbursed"},
-		"disbursed": {}, // No further transitions allowed
+	entry := ledger.NewEntry(loan.ID, fmt.Sprintf("loan_refund:%d", loan.ID), "Investor funds refunded").
+		Debit(ledger.AccountInvestorFunds, loan.TotalInvestedAmount).
+		Credit(ledger.AccountCash, loan.TotalInvestedAmount)
+	err := s.generalLedger.Post(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("failed to post refund to ledger: %w", err)
 	}
 
-	validStates, exists := validTransitions[currentState]
-	if !exists {
-		return false, fmt.Errorf("invalid current state: %s", currentState)
+	return nil
+}
+
+// requireFullyRepaid refuses to mark a loan repaid while any installment is unpaid
+func (s *loanServiceImpl) requireFullyRepaid(ctx context.Context, loan *models.Loan) error {
+	installments, err := s.loanInstallmentRepo.GetByLoanID(ctx, loan.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get installments: %w", err)
 	}
 
-	for _, state := range validStates {
-		if state == newState {
-			return true, nil
+	for _, installment := range installments {
+		if installment.Status != repayment.InstallmentStatusPaid {
+			return apperr.InvalidState("installments_unpaid", "installment %d is not paid yet", installment.InstallmentNumber)
 		}
 	}
 
-	return false, nil
+	return nil
+}
+
+// writeOffPrincipal posts the principal that will never be collected as a loss to the
+// investors who funded it
+func (s *loanServiceImpl) writeOffPrincipal(ctx context.Context, loan *models.Loan) error {
+	installments, err := s.loanInstallmentRepo.GetByLoanID(ctx, loan.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get installments: %w", err)
+	}
+
+	outstanding := money.Zero(loan.PrincipalAmount.Currency())
+	for _, installment := range installments {
+		outstanding = outstanding.Add(installment.PrincipalAmount.Sub(installment.PrincipalPaid))
+	}
+
+	if !outstanding.IsPositive() {
+		return nil
+	}
+
+	entry := ledger.NewEntry(loan.ID, fmt.Sprintf("loan_write_off:%d", loan.ID), "Outstanding principal written off").
+		Debit(ledger.AccountInvestorCapital, outstanding).
+		Credit(ledger.AccountLoansReceivable, outstanding)
+	err = s.generalLedger.Post(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("failed to post write-off to ledger: %w", err)
+	}
+
+	return nil
+}
+
+func (s *loanServiceImpl) GetRepaymentSchedule(ctx context.Context, loanID int) ([]*models.LoanInstallment, error) {
+	loan, err := s.loanRepo.GetByID(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	installments, err := s.loanInstallmentRepo.GetByLoanID(ctx, loan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(installments) == 0 {
+		return nil, apperr.InvalidState("loan_not_disbursed", "loan has no repayment schedule until it is disbursed")
+	}
+
+	return installments, nil
+}
+
+func (s *loanServiceImpl) GetTotalInvestedAmount(ctx context.Context, loanID int) (money.Money, error) {
+	return s.loanRepo.GetTotalInvestedAmount(ctx, loanID)
+}
+
+func (s *loanServiceImpl) CanTransitionToState(ctx context.Context, loanID int, newState string) (bool, error) {
+	loan, err := s.loanRepo.GetByID(ctx, loanID)
+	if err != nil {
+		return false, err
+	}
+
+	return s.stateMachine.CanTransition(loan.CurrentState, newState)
 }
@@ -4,100 +4,236 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/authz"
+	"github.com/sswastioyono18/loan-engine/internal/ledger"
+	ledgermocks "github.com/sswastioyono18/loan-engine/internal/ledger/mocks"
 	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/repositories"
 	"github.com/sswastioyono18/loan-engine/internal/repositories/mocks"
 	mocks2 "github.com/sswastioyono18/loan-engine/pkg/external/mocks"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
-func TestCreateLoan(t *testing.T) {
-	mockLoanRepo := mocks.NewLoanRepository(t)
-	mockApprovalRepo := mocks.NewLoanApprovalRepository(t)
-	mockDisbursementRepo := mocks.NewLoanDisbursementRepository(t)
-	mockInvestmentRepo := mocks.NewLoanInvestmentRepository(t)
-	mockStateHistoryRepo := mocks.NewLoanStateHistoryRepository(t)
-	mockInvestorRepo := mocks.NewInvestorRepository(t)
-	mockEmailService := mocks2.NewEmailService(t)
-	mockStorageService := mocks2.NewStorageService(t)
+// newMockUnitOfWork returns a UnitOfWork mock that runs fn on the caller's context,
+// so repository expectations registered with context.Background() still match
+func newMockUnitOfWork(t *testing.T) *mocks.UnitOfWork {
+	mockUnitOfWork := mocks.NewUnitOfWork(t)
+	mockUnitOfWork.On("Do", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}).Maybe()
+	return mockUnitOfWork
+}
 
-	service := NewLoanService(mockLoanRepo, mockApprovalRepo, mockDisbursementRepo, mockInvestmentRepo, mockStateHistoryRepo, mockInvestorRepo, mockEmailService, mockStorageService)
+// newRecordingUnitOfWork returns a UnitOfWork mock that expects exactly one unit of work and
+// records whether fn failed, which is when a real transaction is rolled back
+func newRecordingUnitOfWork(t *testing.T) (*mocks.UnitOfWork, *bool) {
+	rolledBack := false
+	mockUnitOfWork := mocks.NewUnitOfWork(t)
+	mockUnitOfWork.On("Do", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		err := fn(ctx)
+		rolledBack = err != nil
+		return err
+	}).Once()
+	return mockUnitOfWork, &rolledBack
+}
+
+// loanServiceFixture is a loan service built on a mock of each of its dependencies
+type loanServiceFixture struct {
+	loanRepo            *mocks.LoanRepository
+	approvalRepo        *mocks.LoanApprovalRepository
+	rejectionRepo       *mocks.LoanRejectionRepository
+	rejectionReasonRepo *mocks.LoanRejectionReasonRepository
+	disbursementRepo    *mocks.LoanDisbursementRepository
+	investmentRepo      *mocks.LoanInvestmentRepository
+	stateHistoryRepo    *mocks.LoanStateHistoryRepository
+	installmentRepo     *mocks.LoanInstallmentRepository
+	investorRepo        *mocks.InvestorRepository
+	borrowerRepo        *mocks.BorrowerRepository
+	unitOfWork          *mocks.UnitOfWork
+	ledger              *ledgermocks.Service
+	emailService        *mocks2.EmailService
+	storageService      *mocks2.StorageService
+	service             LoanService
+}
+
+// newLoanServiceFixture returns a loan service whose units of work run in place and whose
+// access is checked with the default policy
+func newLoanServiceFixture(t *testing.T) *loanServiceFixture {
+	return newLoanServiceFixtureWith(t, newMockUnitOfWork(t), authz.DefaultPolicy())
+}
+
+// newLoanServiceFixtureWith returns a loan service that runs its units of work through
+// unitOfWork and checks access with policy
+func newLoanServiceFixtureWith(t *testing.T, unitOfWork *mocks.UnitOfWork, policy authz.Policy) *loanServiceFixture {
+	f := &loanServiceFixture{
+		loanRepo:            mocks.NewLoanRepository(t),
+		approvalRepo:        mocks.NewLoanApprovalRepository(t),
+		rejectionRepo:       mocks.NewLoanRejectionRepository(t),
+		rejectionReasonRepo: mocks.NewLoanRejectionReasonRepository(t),
+		disbursementRepo:    mocks.NewLoanDisbursementRepository(t),
+		investmentRepo:      mocks.NewLoanInvestmentRepository(t),
+		stateHistoryRepo:    mocks.NewLoanStateHistoryRepository(t),
+		installmentRepo:     mocks.NewLoanInstallmentRepository(t),
+		investorRepo:        mocks.NewInvestorRepository(t),
+		borrowerRepo:        mocks.NewBorrowerRepository(t),
+		unitOfWork:          unitOfWork,
+		ledger:              ledgermocks.NewService(t),
+		emailService:        mocks2.NewEmailService(t),
+		storageService:      mocks2.NewStorageService(t),
+	}
+	f.service = NewLoanService(f.loanRepo, f.approvalRepo, f.rejectionRepo, f.rejectionReasonRepo, f.disbursementRepo, f.investmentRepo, f.stateHistoryRepo, f.installmentRepo, f.borrowerRepo, f.investorRepo, f.unitOfWork, f.ledger, f.emailService, f.storageService, policy)
+	return f
+}
+
+// staffIDs are the user IDs asUser gives each role
+var staffIDs = map[string]string{
+	authz.RoleAdmin:          "ADM001",
+	authz.RoleFieldValidator: "EMP001",
+	authz.RoleFieldOfficer:   "EMP002",
+}
+
+// asUser returns a context carrying an active user acting as role
+func asUser(role string) context.Context {
+	user := &models.User{ID: 1, UserID: staffIDs[role], Email: role + "@example.com", UserType: role, IsActive: true}
+	if role == authz.RoleFieldValidator || role == authz.RoleFieldOfficer {
+		user.UserType, user.StaffRole = authz.RoleStaff, role
+	}
+	return authz.WithUser(context.Background(), user)
+}
+
+// asInvestor returns a context carrying the user that owns investor
+func asInvestor(investor *models.Investor) context.Context {
+	user := &models.User{ID: 1, UserID: fmt.Sprintf("INV%03d", investor.ID), Email: investor.Email, UserType: authz.RoleInvestor, InvestorID: &investor.ID, IsActive: true}
+	return authz.WithUser(context.Background(), user)
+}
+
+// failAtStep returns err when step is the one selected to fail and nil otherwise
+func failAtStep(step, failAt int, err error) error {
+	if step == failAt {
+		return err
+	}
+	return nil
+}
+
+func TestCreateLoan(t *testing.T) {
+	f := newLoanServiceFixture(t)
 
 	loan := &models.Loan{
 		BorrowerID:          1,
-		PrincipalAmount:     10000.0,
-		Rate:                0.05,
-		ROI:                 0.08,
+		PrincipalAmount:     money.MustParse("10000.00"),
+		Rate:                money.MustParseRate("0.05"),
+		ROI:                 money.MustParseRate("0.08"),
 		AgreementLetterLink: sql.NullString{String: "https://example.com/agreement.pdf", Valid: true},
 	}
 
-	mockLoanRepo.On("Create", context.Background(), loan).Return(nil)
+	f.loanRepo.On("Create", context.Background(), loan).Return(nil)
 
-	err := service.CreateLoan(context.Background(), loan)
+	err := f.service.CreateLoan(context.Background(), loan)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "proposed", loan.CurrentState)
+	assert.Equal(t, 12, loan.TenorMonths)
+	assert.Equal(t, "flat", loan.RepaymentMethod)
 }
 
-func TestApproveLoan(t *testing.T) {
-	mockLoanRepo := mocks.NewLoanRepository(t)
-	mockApprovalRepo := mocks.NewLoanApprovalRepository(t)
-	mockDisbursementRepo := mocks.NewLoanDisbursementRepository(t)
-	mockInvestmentRepo := mocks.NewLoanInvestmentRepository(t)
-	mockStateHistoryRepo := mocks.NewLoanStateHistoryRepository(t)
-	mockInvestorRepo := mocks.NewInvestorRepository(t)
-	mockEmailService := mocks2.NewEmailService(t)
-	mockStorageService := mocks2.NewStorageService(t)
+func TestCreateLoanRejectsUnknownRepaymentMethod(t *testing.T) {
+	f := newLoanServiceFixture(t)
+
+	loan := &models.Loan{
+		BorrowerID:      1,
+		PrincipalAmount: money.MustParse("10000.00"),
+		Rate:            money.MustParseRate("0.05"),
+		ROI:             money.MustParseRate("0.08"),
+		TenorMonths:     6,
+		RepaymentMethod: "balloon",
+	}
+
+	err := f.service.CreateLoan(context.Background(), loan)
+
+	assert.Error(t, err)
+	f.loanRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestGetRepaymentSchedule(t *testing.T) {
+	f := newLoanServiceFixture(t)
+
+	loanID := 1
+	installments := []*models.LoanInstallment{
+		{ID: 1, LoanID: loanID, InstallmentNumber: 1, TotalAmount: money.MustParse("874.99"), Status: "pending"},
+	}
+
+	f.loanRepo.On("GetByID", context.Background(), loanID).Return(&models.Loan{ID: loanID, CurrentState: "disbursed"}, nil)
+	f.installmentRepo.On("GetByLoanID", context.Background(), loanID).Return(installments, nil)
+
+	schedule, err := f.service.GetRepaymentSchedule(context.Background(), loanID)
 
-	service := NewLoanService(mockLoanRepo, mockApprovalRepo, mockDisbursementRepo, mockInvestmentRepo, mockStateHistoryRepo, mockInvestorRepo, mockEmailService, mockStorageService)
+	assert.NoError(t, err)
+	assert.Equal(t, installments, schedule)
+}
+
+func TestGetRepaymentScheduleBeforeDisbursement(t *testing.T) {
+	f := newLoanServiceFixture(t)
+
+	loanID := 1
+	f.loanRepo.On("GetByID", context.Background(), loanID).Return(&models.Loan{ID: loanID, CurrentState: "approved"}, nil)
+	f.installmentRepo.On("GetByLoanID", context.Background(), loanID).Return([]*models.LoanInstallment{}, nil)
+
+	_, err := f.service.GetRepaymentSchedule(context.Background(), loanID)
+
+	assert.Error(t, err)
+}
+
+func TestApproveLoan(t *testing.T) {
+	ctx := asUser(authz.RoleFieldValidator)
+	f := newLoanServiceFixture(t)
 
 	loanID := 1
 	loan := &models.Loan{
 		ID:                  loanID,
 		BorrowerID:          1,
-		PrincipalAmount:     10000.0,
-		Rate:                0.05,
-		ROI:                 0.08,
+		PrincipalAmount:     money.MustParse("10000.00"),
+		Rate:                money.MustParseRate("0.05"),
+		ROI:                 money.MustParseRate("0.08"),
 		AgreementLetterLink: sql.NullString{String: "https://example.com/agreement.pdf", Valid: true},
 		CurrentState:        "proposed",
 	}
 
 	approval := &models.LoanApproval{
-		FieldValidatorEmployeeID: "emp001",
+		FieldValidatorEmployeeID: "emp999", // Overridden by the signed-in field validator
 		ProofImageUrl:            "https://example.com/proof.jpg",
 	}
 
-	mockLoanRepo.On("GetByID", context.Background(), loanID).Return(loan, nil)
-	mockApprovalRepo.On("Create", context.Background(), approval).Return(nil)
-	mockLoanRepo.On("UpdateState", context.Background(), loanID, "approved").Return(nil)
-	mockStateHistoryRepo.On("Create", context.Background(), mock.Anything).Return(nil)
+	f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil)
+	f.approvalRepo.On("Create", ctx, approval).Return(nil)
+	f.loanRepo.On("UpdateState", ctx, loanID, "approved").Return(nil)
+	f.stateHistoryRepo.On("Create", ctx, mock.MatchedBy(func(history *models.LoanStateHistory) bool {
+		return history.ChangedBy == "EMP001"
+	})).Return(nil)
 
-	err := service.ApproveLoan(context.Background(), loanID, approval)
+	err := f.service.ApproveLoan(ctx, loanID, approval)
 
 	assert.NoError(t, err)
+	assert.Equal(t, "EMP001", approval.FieldValidatorEmployeeID)
 }
 
 func TestApproveLoanInvalidState(t *testing.T) {
-	mockLoanRepo := mocks.NewLoanRepository(t)
-	mockApprovalRepo := mocks.NewLoanApprovalRepository(t)
-	mockDisbursementRepo := mocks.NewLoanDisbursementRepository(t)
-	mockInvestmentRepo := mocks.NewLoanInvestmentRepository(t)
-	mockStateHistoryRepo := mocks.NewLoanStateHistoryRepository(t)
-	mockInvestorRepo := mocks.NewInvestorRepository(t)
-	mockEmailService := mocks2.NewEmailService(t)
-	mockStorageService := mocks2.NewStorageService(t)
-
-	service := NewLoanService(mockLoanRepo, mockApprovalRepo, mockDisbursementRepo, mockInvestmentRepo, mockStateHistoryRepo, mockInvestorRepo, mockEmailService, mockStorageService)
+	ctx := asUser(authz.RoleFieldValidator)
+	f := newLoanServiceFixture(t)
 
 	loanID := 1
 	loan := &models.Loan{
 		ID:                  loanID,
 		BorrowerID:          1,
-		PrincipalAmount:     10000.0,
-		Rate:                0.05,
-		ROI:                 0.08,
+		PrincipalAmount:     money.MustParse("10000.00"),
+		Rate:                money.MustParseRate("0.05"),
+		ROI:                 money.MustParseRate("0.08"),
 		AgreementLetterLink: sql.NullString{String: "https://example.com/agreement.pdf", Valid: true},
 		CurrentState:        "approved", // Already approved
 	}
@@ -107,112 +243,124 @@ func TestApproveLoanInvalidState(t *testing.T) {
 		ProofImageUrl:            "https://example.com/proof.jpg",
 	}
 
-	mockLoanRepo.On("GetByID", context.Background(), loanID).Return(loan, nil)
+	f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil)
 
-	err := service.ApproveLoan(context.Background(), loanID, approval)
+	err := f.service.ApproveLoan(ctx, loanID, approval)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "loan must be in proposed state to be approved")
 }
 
 func TestInvestInLoan(t *testing.T) {
-	mockLoanRepo := mocks.NewLoanRepository(t)
-	mockApprovalRepo := mocks.NewLoanApprovalRepository(t)
-	mockDisbursementRepo := mocks.NewLoanDisbursementRepository(t)
-	mockInvestmentRepo := mocks.NewLoanInvestmentRepository(t)
-	mockStateHistoryRepo := mocks.NewLoanStateHistoryRepository(t)
-	mockInvestorRepo := mocks.NewInvestorRepository(t)
-	mockEmailService := mocks2.NewEmailService(t)
-	mockStorageService := mocks2.NewStorageService(t)
-
-	service := NewLoanService(mockLoanRepo, mockApprovalRepo, mockDisbursementRepo, mockInvestmentRepo, mockStateHistoryRepo, mockInvestorRepo, mockEmailService, mockStorageService)
+	f := newLoanServiceFixture(t)
+	investor := &models.Investor{ID: 1, Email: "investor@example.com"}
+	ctx := asInvestor(investor)
+	f.investorRepo.On("GetByID", ctx, investor.ID).Return(investor, nil)
 
 	loanID := 1
 	loan := &models.Loan{
 		ID:                  loanID,
 		BorrowerID:          1,
-		PrincipalAmount:     10000.0,
-		Rate:                0.05,
-		ROI:                 0.08,
+		PrincipalAmount:     money.MustParse("10000.00"),
+		Rate:                money.MustParseRate("0.05"),
+		ROI:                 money.MustParseRate("0.08"),
 		AgreementLetterLink: sql.NullString{String: "https://example.com/agreement.pdf", Valid: true},
 		CurrentState:        "approved",
-		TotalInvestedAmount: 0.0,
+		TotalInvestedAmount: money.MustParse("0.00"),
 	}
 
 	investment := &models.LoanInvestment{
 		InvestorID:       1,
-		InvestmentAmount: 5000.0,
+		InvestmentAmount: money.MustParse("5000.00"),
 	}
 
-	mockLoanRepo.On("GetByID", context.Background(), loanID).Return(loan, nil)
-	mockInvestmentRepo.On("GetByLoanAndInvestor", context.Background(), loanID, 1).Return(nil, errors.New("not found"))
-	mockInvestmentRepo.On("Create", context.Background(), investment).Return(nil)
-	mockLoanRepo.On("UpdateTotalInvestedAmount", context.Background(), loanID, 5000.0).Return(nil)
+	f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil)
+	f.investmentRepo.On("GetByLoanAndInvestor", ctx, loanID, 1).Return(nil, repositories.ErrLoanInvestmentNotFound)
+	f.investmentRepo.On("Create", ctx, investment).Return(nil)
+	f.ledger.On("Post", ctx, mock.MatchedBy(func(entry *ledger.JournalEntry) bool {
+		return len(entry.Postings) == 2 &&
+			entry.Postings[0].AccountCode == ledger.AccountCash && entry.Postings[0].Direction == ledger.Debit &&
+			entry.Postings[1].AccountCode == ledger.AccountInvestorFunds && entry.Postings[1].Direction == ledger.Credit &&
+			entry.Postings[0].Amount.Equal(money.MustParse("5000.00")) &&
+			entry.Validate() == nil
+	})).Return(nil).Once()
+	f.loanRepo.On("UpdateTotalInvestedAmount", ctx, loanID, money.MustParse("5000.00")).Return(nil)
 
-	err := service.InvestInLoan(context.Background(), loanID, investment)
+	err := f.service.InvestInLoan(ctx, loanID, investment)
 
 	assert.NoError(t, err)
 }
 
 func TestInvestInLoanExceedsPrincipal(t *testing.T) {
-	mockLoanRepo := mocks.NewLoanRepository(t)
-	mockApprovalRepo := mocks.NewLoanApprovalRepository(t)
-	mockDisbursementRepo := mocks.NewLoanDisbursementRepository(t)
-	mockInvestmentRepo := mocks.NewLoanInvestmentRepository(t)
-	mockStateHistoryRepo := mocks.NewLoanStateHistoryRepository(t)
-	mockInvestorRepo := mocks.NewInvestorRepository(t)
-	mockEmailService := mocks2.NewEmailService(t)
-	mockStorageService := mocks2.NewStorageService(t)
-
-	service := NewLoanService(mockLoanRepo, mockApprovalRepo, mockDisbursementRepo, mockInvestmentRepo, mockStateHistoryRepo, mockInvestorRepo, mockEmailService, mockStorageService)
+	f := newLoanServiceFixture(t)
+	investor := &models.Investor{ID: 1, Email: "investor@example.com"}
+	ctx := asInvestor(investor)
+	f.investorRepo.On("GetByID", ctx, investor.ID).Return(investor, nil)
 
 	loanID := 1
 	loan := &models.Loan{
 		ID:                  loanID,
 		BorrowerID:          1,
-		PrincipalAmount:     10000.0,
-		Rate:                0.05,
-		ROI:                 0.08,
+		PrincipalAmount:     money.MustParse("10000.00"),
+		Rate:                money.MustParseRate("0.05"),
+		ROI:                 money.MustParseRate("0.08"),
 		AgreementLetterLink: sql.NullString{String: "https://example.com/agreement.pdf", Valid: true},
 		CurrentState:        "approved",
-		TotalInvestedAmount: 5000.0,
+		TotalInvestedAmount: money.MustParse("5000.00"),
 	}
 
 	investment := &models.LoanInvestment{
 		InvestorID:       1,
-		InvestmentAmount: 6000.0, // Exceeds remaining principal (10000 - 5000 = 5000)
+		InvestmentAmount: money.MustParse("6000.00"), // Exceeds remaining principal (10000 - 5000 = 5000)
 	}
 
-	mockLoanRepo.On("GetByID", context.Background(), loanID).Return(loan, nil)
+	f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil)
 
-	err := service.InvestInLoan(context.Background(), loanID, investment)
+	err := f.service.InvestInLoan(ctx, loanID, investment)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "investment amount exceeds remaining principal")
 }
 
-func TestDisburseLoan(t *testing.T) {
-	mockLoanRepo := mocks.NewLoanRepository(t)
-	mockApprovalRepo := mocks.NewLoanApprovalRepository(t)
-	mockDisbursementRepo := mocks.NewLoanDisbursementRepository(t)
-	mockInvestmentRepo := mocks.NewLoanInvestmentRepository(t)
-	mockStateHistoryRepo := mocks.NewLoanStateHistoryRepository(t)
-	mockInvestorRepo := mocks.NewInvestorRepository(t)
-	mockEmailService := mocks2.NewEmailService(t)
-	mockStorageService := mocks2.NewStorageService(t)
+func TestInvestInLoanReturnsExistingInvestmentLookupErrors(t *testing.T) {
+	f := newLoanServiceFixture(t)
+	investor := &models.Investor{ID: 1, Email: "investor@example.com"}
+	ctx := asInvestor(investor)
+	f.investorRepo.On("GetByID", ctx, investor.ID).Return(investor, nil)
+
+	loan := &models.Loan{
+		ID:                  1,
+		PrincipalAmount:     money.MustParse("10000.00"),
+		CurrentState:        "approved",
+		TotalInvestedAmount: money.MustParse("0.00"),
+	}
+	f.loanRepo.On("GetByIDForUpdate", ctx, loan.ID).Return(loan, nil)
+
+	// A failed lookup must not be taken to mean the investor has not invested yet
+	f.investmentRepo.On("GetByLoanAndInvestor", ctx, loan.ID, investor.ID).Return(nil, errors.New("connection reset"))
 
-	service := NewLoanService(mockLoanRepo, mockApprovalRepo, mockDisbursementRepo, mockInvestmentRepo, mockStateHistoryRepo, mockInvestorRepo, mockEmailService, mockStorageService)
+	err := f.service.InvestInLoan(ctx, loan.ID, &models.LoanInvestment{InvestorID: investor.ID, InvestmentAmount: money.MustParse("1000.00")})
+
+	assert.ErrorContains(t, err, "connection reset")
+	f.investmentRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestDisburseLoan(t *testing.T) {
+	ctx := asUser(authz.RoleFieldOfficer)
+	f := newLoanServiceFixture(t)
 
 	loanID := 1
 	loan := &models.Loan{
 		ID:                  loanID,
 		BorrowerID:          1,
-		PrincipalAmount:     10000.0,
-		Rate:                0.05,
-		ROI:                 0.08,
+		PrincipalAmount:     money.MustParse("10000.00"),
+		Rate:                money.MustParseRate("0.05"),
+		ROI:                 money.MustParseRate("0.08"),
 		AgreementLetterLink: sql.NullString{String: "https://example.com/agreement.pdf", Valid: true},
 		CurrentState:        "invested",
-		TotalInvestedAmount: 10000.0,
+		TotalInvestedAmount: money.MustParse("10000.00"),
+		TenorMonths:         12,
+		RepaymentMethod:     "flat",
 	}
 
 	disbursement := &models.LoanDisbursement{
@@ -220,38 +368,52 @@ func TestDisburseLoan(t *testing.T) {
 		AgreementLetterSignedUrl: "https://example.com/signed-agreement.pdf",
 	}
 
-	mockLoanRepo.On("GetByID", context.Background(), loanID).Return(loan, nil)
-	mockDisbursementRepo.On("Create", context.Background(), disbursement).Return(nil)
-	mockLoanRepo.On("UpdateState", context.Background(), loanID, "disbursed").Return(nil)
-	mockStateHistoryRepo.On("Create", context.Background(), mock.Anything).Return(nil)
-
-	err := service.DisburseLoan(context.Background(), loanID, disbursement)
+	var installments []*models.LoanInstallment
+	f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil)
+	f.disbursementRepo.On("Create", ctx, disbursement).Return(nil)
+	var entry *ledger.JournalEntry
+	f.ledger.On("Post", ctx, mock.AnythingOfType("*ledger.JournalEntry")).Run(func(args mock.Arguments) {
+		entry = args.Get(1).(*ledger.JournalEntry)
+	}).Return(nil).Once()
+	f.loanRepo.On("UpdateState", ctx, loanID, "disbursed").Return(nil)
+	f.stateHistoryRepo.On("Create", ctx, mock.Anything).Return(nil)
+	f.installmentRepo.On("Create", ctx, mock.AnythingOfType("*models.LoanInstallment")).Run(func(args mock.Arguments) {
+		installments = append(installments, args.Get(1).(*models.LoanInstallment))
+	}).Return(nil).Times(12)
+
+	err := f.service.DisburseLoan(ctx, loanID, disbursement)
 
 	assert.NoError(t, err)
+	assert.False(t, disbursement.DisbursementDate.IsZero())
+
+	// Cash leaves for the borrower and the investors' funds become capital lent out
+	require.NotNil(t, entry)
+	assert.NoError(t, entry.Validate())
+	assert.Equal(t, loanID, *entry.LoanID)
+	assert.Len(t, entry.Postings, 4)
+
+	// 10000.00 at 5% flat over a year: 833.33 principal and 41.66 interest a month, rounding on the last
+	require.Len(t, installments, 12)
+	assert.Equal(t, loanID, installments[0].LoanID)
+	assert.Equal(t, money.MustParse("874.99"), installments[0].TotalAmount)
+	assert.Equal(t, money.MustParse("875.11"), installments[11].TotalAmount)
+	assert.Equal(t, "pending", installments[0].Status)
 }
 
 func TestDisburseLoanInvalidState(t *testing.T) {
-	mockLoanRepo := mocks.NewLoanRepository(t)
-	mockApprovalRepo := mocks.NewLoanApprovalRepository(t)
-	mockDisbursementRepo := mocks.NewLoanDisbursementRepository(t)
-	mockInvestmentRepo := mocks.NewLoanInvestmentRepository(t)
-	mockStateHistoryRepo := mocks.NewLoanStateHistoryRepository(t)
-	mockInvestorRepo := mocks.NewInvestorRepository(t)
-	mockEmailService := mocks2.NewEmailService(t)
-	mockStorageService := mocks2.NewStorageService(t)
-
-	service := NewLoanService(mockLoanRepo, mockApprovalRepo, mockDisbursementRepo, mockInvestmentRepo, mockStateHistoryRepo, mockInvestorRepo, mockEmailService, mockStorageService)
+	ctx := asUser(authz.RoleFieldOfficer)
+	f := newLoanServiceFixture(t)
 
 	loanID := 1
 	loan := &models.Loan{
 		ID:                  loanID,
 		BorrowerID:          1,
-		PrincipalAmount:     10000.0,
-		Rate:                0.05,
-		ROI:                 0.08,
+		PrincipalAmount:     money.MustParse("10000.00"),
+		Rate:                money.MustParseRate("0.05"),
+		ROI:                 money.MustParseRate("0.08"),
 		AgreementLetterLink: sql.NullString{String: "https://example.com/agreement.pdf", Valid: true},
 		CurrentState:        "proposed", // Not invested yet
-		TotalInvestedAmount: 0.0,
+		TotalInvestedAmount: money.MustParse("0.00"),
 	}
 
 	disbursement := &models.LoanDisbursement{
@@ -259,42 +421,33 @@ func TestDisburseLoanInvalidState(t *testing.T) {
 		AgreementLetterSignedUrl: "https://example.com/signed-agreement.pdf",
 	}
 
-	mockLoanRepo.On("GetByID", context.Background(), loanID).Return(loan, nil)
+	f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil)
 
-	err := service.DisburseLoan(context.Background(), loanID, disbursement)
+	err := f.service.DisburseLoan(ctx, loanID, disbursement)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "loan must be in invested state to be disbursed")
 }
 
 func TestInvestInLoanSendsEmailNotifications(t *testing.T) {
-	mockLoanRepo := mocks.NewLoanRepository(t)
-	mockApprovalRepo := mocks.NewLoanApprovalRepository(t)
-	mockDisbursementRepo := mocks.NewLoanDisbursementRepository(t)
-	mockInvestmentRepo := mocks.NewLoanInvestmentRepository(t)
-	mockStateHistoryRepo := mocks.NewLoanStateHistoryRepository(t)
-	mockInvestorRepo := mocks.NewInvestorRepository(t)
-	mockEmailService := mocks2.NewEmailService(t)
-	mockStorageService := mocks2.NewStorageService(t)
-
-	service := NewLoanService(mockLoanRepo, mockApprovalRepo, mockDisbursementRepo, mockInvestmentRepo, mockStateHistoryRepo, mockInvestorRepo, mockEmailService, mockStorageService)
+	f := newLoanServiceFixture(t)
 
 	loanID := 1
 	loan := &models.Loan{
 		ID:                  loanID,
 		BorrowerID:          1,
-		PrincipalAmount:     10000.0,
-		Rate:                0.05,
-		ROI:                 0.08,
+		PrincipalAmount:     money.MustParse("10000.00"),
+		Rate:                money.MustParseRate("0.05"),
+		ROI:                 money.MustParseRate("0.08"),
 		AgreementLetterLink: sql.NullString{String: "https://example.com/agreement.pdf", Valid: true},
 		CurrentState:        "approved",
-		TotalInvestedAmount: 5000.0, // Need 5000 more to reach full amount
+		TotalInvestedAmount: money.MustParse("5000.00"), // Need 5000 more to reach full amount
 		LoanID:              "LOAN001",
 	}
 
 	investment := &models.LoanInvestment{
 		InvestorID:       1,
-		InvestmentAmount: 5000.0, // This will make total invested = 10000 (equal to principal)
+		InvestmentAmount: money.MustParse("5000.00"), // This will make total invested = 10000 (equal to principal)
 	}
 
 	investor := &models.Investor{
@@ -302,45 +455,74 @@ func TestInvestInLoanSendsEmailNotifications(t *testing.T) {
 		Email:    "investor@example.com",
 		FullName: "Test Investor",
 	}
+	ctx := asInvestor(investor)
 
 	loanInvestments := []*models.LoanInvestment{
 		{
 			ID:             1,
 			InvestorID:     1,
-			InvestmentAmount: 5000.0,
+			InvestmentAmount: money.MustParse("5000.00"),
 		},
 	}
 
 	// Set up mocks
-	mockLoanRepo.On("GetByID", context.Background(), loanID).Return(loan, nil)
-	mockInvestmentRepo.On("GetByLoanAndInvestor", context.Background(), loanID, 1).Return(nil, errors.New("not found"))
-	mockInvestmentRepo.On("Create", context.Background(), investment).Return(nil)
-	mockLoanRepo.On("UpdateTotalInvestedAmount", context.Background(), loanID, 10000.0).Return(nil)
-	mockLoanRepo.On("UpdateState", context.Background(), loanID, "invested").Return(nil)
-	mockStateHistoryRepo.On("Create", context.Background(), mock.Anything).Return(nil)
-	mockInvestmentRepo.On("GetByLoanID", context.Background(), loanID).Return(loanInvestments, nil)
-	mockInvestorRepo.On("GetByID", context.Background(), 1).Return(investor, nil)
-	mockEmailService.On("SendInvestmentConfirmation", context.Background(), "investor@example.com", "https://example.com/agreement.pdf", "Loan LOAN001 has been fully invested").Return(nil)
-
-	err := service.InvestInLoan(context.Background(), loanID, investment)
+	f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil)
+	f.investmentRepo.On("GetByLoanAndInvestor", ctx, loanID, 1).Return(nil, repositories.ErrLoanInvestmentNotFound)
+	f.investmentRepo.On("Create", ctx, investment).Return(nil)
+	f.ledger.On("Post", ctx, mock.AnythingOfType("*ledger.JournalEntry")).Return(nil).Once()
+	f.loanRepo.On("UpdateTotalInvestedAmount", ctx, loanID, money.MustParse("10000.00")).Return(nil)
+	f.loanRepo.On("UpdateState", ctx, loanID, "invested").Return(nil)
+	f.stateHistoryRepo.On("Create", ctx, mock.Anything).Return(nil)
+	f.investmentRepo.On("GetByLoanID", ctx, loanID).Return(loanInvestments, nil)
+	f.investorRepo.On("GetByID", ctx, 1).Return(investor, nil)
+	f.emailService.On("SendInvestmentConfirmation", ctx, "investor@example.com", "https://example.com/agreement.pdf", "Loan LOAN001 has been fully invested").Return(nil)
+
+	err := f.service.InvestInLoan(ctx, loanID, investment)
 
 	assert.NoError(t, err)
 	// Note: The loan object in the test won't be updated by the service method, so we can't check loan.CurrentState directly
 	// The state update is handled by the repository, which is mocked
-	mockEmailService.AssertExpectations(t)
+	f.emailService.AssertExpectations(t)
 }
 
-func TestCanTransitionToState(t *testing.T) {
-	mockLoanRepo := mocks.NewLoanRepository(t)
-	mockApprovalRepo := mocks.NewLoanApprovalRepository(t)
-	mockDisbursementRepo := mocks.NewLoanDisbursementRepository(t)
-	mockInvestmentRepo := mocks.NewLoanInvestmentRepository(t)
-	mockStateHistoryRepo := mocks.NewLoanStateHistoryRepository(t)
-	mockInvestorRepo := mocks.NewInvestorRepository(t)
-	mockEmailService := mocks2.NewEmailService(t)
-	mockStorageService := mocks2.NewStorageService(t)
+func TestInvestInLoanFundsLoanWithExactDecimalSum(t *testing.T) {
+	f := newLoanServiceFixture(t)
+	investor := &models.Investor{ID: 2, Email: "investor@example.com"}
+	ctx := asInvestor(investor)
+	f.investorRepo.On("GetByID", ctx, investor.ID).Return(investor, nil)
 
-	service := NewLoanService(mockLoanRepo, mockApprovalRepo, mockDisbursementRepo, mockInvestmentRepo, mockStateHistoryRepo, mockInvestorRepo, mockEmailService, mockStorageService)
+	// 0.10 + 0.20 is not 0.30 in binary floating point, so this loan used to stay approved
+	loanID := 1
+	loan := &models.Loan{
+		ID:                  loanID,
+		PrincipalAmount:     money.MustParse("0.30"),
+		CurrentState:        "approved",
+		TotalInvestedAmount: money.MustParse("0.10"),
+		LoanID:              "LOAN001",
+	}
+
+	investment := &models.LoanInvestment{
+		InvestorID:       2,
+		InvestmentAmount: money.MustParse("0.20"),
+	}
+
+	f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil)
+	f.investmentRepo.On("GetByLoanAndInvestor", ctx, loanID, 2).Return(nil, repositories.ErrLoanInvestmentNotFound)
+	f.investmentRepo.On("Create", ctx, investment).Return(nil)
+	f.ledger.On("Post", ctx, mock.AnythingOfType("*ledger.JournalEntry")).Return(nil).Once()
+	f.loanRepo.On("UpdateTotalInvestedAmount", ctx, loanID, money.MustParse("0.30")).Return(nil)
+	f.loanRepo.On("UpdateState", ctx, loanID, "invested").Return(nil).Once()
+	f.stateHistoryRepo.On("Create", ctx, mock.Anything).Return(nil)
+	f.investmentRepo.On("GetByLoanID", ctx, loanID).Return([]*models.LoanInvestment{}, nil)
+
+	err := f.service.InvestInLoan(ctx, loanID, investment)
+
+	assert.NoError(t, err)
+	f.loanRepo.AssertCalled(t, "UpdateState", ctx, loanID, "invested")
+}
+
+func TestCanTransitionToState(t *testing.T) {
+	f := newLoanServiceFixture(t)
 
 	loanID := 1
 
@@ -394,6 +576,48 @@ func TestCanTransitionToState(t *testing.T) {
 			expectedResult: false,
 			shouldError:    false,
 		},
+		{
+			name:           "approved to rejected - valid",
+			currentState:   "approved",
+			targetState:    "rejected",
+			expectedResult: true,
+			shouldError:    false,
+		},
+		{
+			name:           "invested to rejected - invalid",
+			currentState:   "invested",
+			targetState:    "rejected",
+			expectedResult: false,
+			shouldError:    false,
+		},
+		{
+			name:           "disbursed to defaulted - valid",
+			currentState:   "disbursed",
+			targetState:    "defaulted",
+			expectedResult: true,
+			shouldError:    false,
+		},
+		{
+			name:           "repaid to closed - valid",
+			currentState:   "repaid",
+			targetState:    "closed",
+			expectedResult: true,
+			shouldError:    false,
+		},
+		{
+			name:           "written off to any - invalid",
+			currentState:   "written_off",
+			targetState:    "closed",
+			expectedResult: false,
+			shouldError:    false,
+		},
+		{
+			name:           "unknown state - error",
+			currentState:   "archived",
+			targetState:    "closed",
+			expectedResult: false,
+			shouldError:    true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -403,9 +627,9 @@ func TestCanTransitionToState(t *testing.T) {
 				CurrentState: tt.currentState,
 			}
 
-			mockLoanRepo.On("GetByID", context.Background(), loanID).Return(loan, nil).Once()
+			f.loanRepo.On("GetByID", context.Background(), loanID).Return(loan, nil).Once()
 
-			canTransition, err := service.CanTransitionToState(context.Background(), loanID, tt.targetState)
+			canTransition, err := f.service.CanTransitionToState(context.Background(), loanID, tt.targetState)
 
 			if tt.shouldError {
 				assert.Error(t, err)
@@ -418,27 +642,19 @@ func TestCanTransitionToState(t *testing.T) {
 }
 
 func TestStateHistoryRecordedDuringTransitions(t *testing.T) {
-	mockLoanRepo := mocks.NewLoanRepository(t)
-	mockApprovalRepo := mocks.NewLoanApprovalRepository(t)
-	mockDisbursementRepo := mocks.NewLoanDisbursementRepository(t)
-	mockInvestmentRepo := mocks.NewLoanInvestmentRepository(t)
-	mockStateHistoryRepo := mocks.NewLoanStateHistoryRepository(t)
-	mockInvestorRepo := mocks.NewInvestorRepository(t)
-	mockEmailService := mocks2.NewEmailService(t)
-	mockStorageService := mocks2.NewStorageService(t)
-
-	service := NewLoanService(mockLoanRepo, mockApprovalRepo, mockDisbursementRepo, mockInvestmentRepo, mockStateHistoryRepo, mockInvestorRepo, mockEmailService, mockStorageService)
+	f := newLoanServiceFixture(t)
 
 	loanID := 1
 
 	// Test state history during approval
 	t.Run("approval state history", func(t *testing.T) {
+		ctx := asUser(authz.RoleFieldValidator)
 		loan := &models.Loan{
 			ID:                  loanID,
 			BorrowerID:          1,
-			PrincipalAmount:     10000.0,
-			Rate:                0.05,
-			ROI:                 0.08,
+			PrincipalAmount:     money.MustParse("10000.00"),
+			Rate:                money.MustParseRate("0.05"),
+			ROI:                 money.MustParseRate("0.08"),
 			AgreementLetterLink: sql.NullString{String: "https://example.com/agreement.pdf", Valid: true},
 			CurrentState:        "proposed",
 		}
@@ -448,86 +664,94 @@ func TestStateHistoryRecordedDuringTransitions(t *testing.T) {
 			ProofImageUrl:            "https://example.com/proof.jpg",
 		}
 
-		mockLoanRepo.On("GetByID", context.Background(), loanID).Return(loan, nil).Once()
-		mockApprovalRepo.On("Create", context.Background(), approval).Return(nil).Once()
-		mockLoanRepo.On("UpdateState", context.Background(), loanID, "approved").Return(nil).Once()
-		mockStateHistoryRepo.On("Create", context.Background(), mock.MatchedBy(func(history *models.LoanStateHistory) bool {
+		f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil).Once()
+		f.approvalRepo.On("Create", ctx, approval).Return(nil).Once()
+		f.loanRepo.On("UpdateState", ctx, loanID, "approved").Return(nil).Once()
+		f.stateHistoryRepo.On("Create", ctx, mock.MatchedBy(func(history *models.LoanStateHistory) bool {
 			return history.LoanID == loanID &&
 				   history.PreviousState == "proposed" &&
 				   history.NewState == "approved" &&
-				   history.TransitionReason == "Loan approved by staff"
+				   history.TransitionReason == "Loan approved by staff" &&
+				   history.ChangedBy == "EMP001"
 		})).Return(nil).Once()
 
-		err := service.ApproveLoan(context.Background(), loanID, approval)
+		err := f.service.ApproveLoan(ctx, loanID, approval)
 
 		assert.NoError(t, err)
-		mockStateHistoryRepo.AssertExpectations(t)
+		f.stateHistoryRepo.AssertExpectations(t)
 	})
 
 	// Reset mocks for next test
-	mockStateHistoryRepo.ExpectedCalls = nil
-	mockLoanRepo.ExpectedCalls = nil
-	mockApprovalRepo.ExpectedCalls = nil
+	f.stateHistoryRepo.ExpectedCalls = nil
+	f.loanRepo.ExpectedCalls = nil
+	f.approvalRepo.ExpectedCalls = nil
 
 	// Test state history during investment to "invested" state
 	t.Run("investment state history", func(t *testing.T) {
+		investor := &models.Investor{ID: 1, Email: "investor@example.com"}
+		ctx := asInvestor(investor)
 		loan := &models.Loan{
 			ID:                  loanID,
 			BorrowerID:          1,
-			PrincipalAmount:     10000.0,
-			Rate:                0.05,
-			ROI:                 0.08,
+			PrincipalAmount:     money.MustParse("10000.00"),
+			Rate:                money.MustParseRate("0.05"),
+			ROI:                 money.MustParseRate("0.08"),
 			AgreementLetterLink: sql.NullString{String: "https://example.com/agreement.pdf", Valid: true},
 			CurrentState:        "approved",
-			TotalInvestedAmount: 5000.0,
+			TotalInvestedAmount: money.MustParse("5000.00"),
 		}
 
 		investment := &models.LoanInvestment{
 			InvestorID:       1,
-			InvestmentAmount: 5000.0, // This will make total invested = 10000 (equal to principal)
+			InvestmentAmount: money.MustParse("5000.00"), // This will make total invested = 10000 (equal to principal)
 		}
 
-		mockLoanRepo.On("GetByID", context.Background(), loanID).Return(loan, nil).Once()
-		mockInvestmentRepo.On("GetByLoanAndInvestor", context.Background(), loanID, 1).Return(nil, errors.New("not found")).Once()
-		mockInvestmentRepo.On("Create", context.Background(), investment).Return(nil).Once()
-		mockLoanRepo.On("UpdateTotalInvestedAmount", context.Background(), loanID, 10000.0).Return(nil).Once()
-		mockLoanRepo.On("UpdateState", context.Background(), loanID, "invested").Return(nil).Once()
-		mockStateHistoryRepo.On("Create", context.Background(), mock.MatchedBy(func(history *models.LoanStateHistory) bool {
+		f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil).Once()
+		f.investmentRepo.On("GetByLoanAndInvestor", ctx, loanID, 1).Return(nil, repositories.ErrLoanInvestmentNotFound).Once()
+		f.investmentRepo.On("Create", ctx, investment).Return(nil).Once()
+		f.ledger.On("Post", ctx, mock.AnythingOfType("*ledger.JournalEntry")).Return(nil).Once()
+		f.loanRepo.On("UpdateTotalInvestedAmount", ctx, loanID, money.MustParse("10000.00")).Return(nil).Once()
+		f.loanRepo.On("UpdateState", ctx, loanID, "invested").Return(nil).Once()
+		f.stateHistoryRepo.On("Create", ctx, mock.MatchedBy(func(history *models.LoanStateHistory) bool {
 			return history.LoanID == loanID &&
 				   history.PreviousState == "approved" &&
 				   history.NewState == "invested" &&
-				   history.TransitionReason == "Loan fully invested"
+				   history.TransitionReason == "Loan fully invested" &&
+				   history.ChangedBy == "INV001"
 		})).Return(nil).Once()
-		mockInvestmentRepo.On("GetByLoanID", context.Background(), loanID).Return([]*models.LoanInvestment{investment}, nil).Once()
-		mockInvestorRepo.On("GetByID", context.Background(), 1).Return(&models.Investor{ID: 1, Email: "investor@example.com"}, nil).Once()
-		mockEmailService.On("SendInvestmentConfirmation", context.Background(), "investor@example.com", "https://example.com/agreement.pdf", mock.Anything).Return(nil).Once()
+		f.investmentRepo.On("GetByLoanID", ctx, loanID).Return([]*models.LoanInvestment{investment}, nil).Once()
+		f.investorRepo.On("GetByID", ctx, 1).Return(investor, nil).Twice()
+		f.emailService.On("SendInvestmentConfirmation", ctx, "investor@example.com", "https://example.com/agreement.pdf", mock.Anything).Return(nil).Once()
 
-		err := service.InvestInLoan(context.Background(), loanID, investment)
+		err := f.service.InvestInLoan(ctx, loanID, investment)
 
 		assert.NoError(t, err)
 		// Note: The loan object in the test won't be updated by the service method, so we can't check loan.CurrentState directly
 		// The state update is handled by the repository, which is mocked
-		mockStateHistoryRepo.AssertExpectations(t)
+		f.stateHistoryRepo.AssertExpectations(t)
 	})
 
 	// Reset mocks for next test
-	mockStateHistoryRepo.ExpectedCalls = nil
-	mockLoanRepo.ExpectedCalls = nil
-	mockInvestmentRepo.ExpectedCalls = nil
-	mockEmailService.ExpectedCalls = nil
-	mockInvestorRepo.ExpectedCalls = nil
+	f.stateHistoryRepo.ExpectedCalls = nil
+	f.loanRepo.ExpectedCalls = nil
+	f.investmentRepo.ExpectedCalls = nil
+	f.emailService.ExpectedCalls = nil
+	f.investorRepo.ExpectedCalls = nil
 
 	// Test state history during disbursement
 	t.Run("disbursement state history", func(t *testing.T) {
+		ctx := asUser(authz.RoleFieldOfficer)
 		loan := &models.Loan{
 			ID:                  loanID,
 			BorrowerID:          1,
-			PrincipalAmount:     10000.0,
-			Rate:                0.05,
-			ROI:                 0.08,
+			PrincipalAmount:     money.MustParse("10000.00"),
+			Rate:                money.MustParseRate("0.05"),
+			ROI:                 money.MustParseRate("0.08"),
 			AgreementLetterLink: sql.NullString{String: "https://example.com/agreement.pdf", Valid: true},
 			CurrentState:        "invested",
-			TotalInvestedAmount: 10000.0,
+			TotalInvestedAmount: money.MustParse("10000.00"),
+			TenorMonths:         12,
+			RepaymentMethod:     "flat",
 		}
 
 		disbursement := &models.LoanDisbursement{
@@ -535,45 +759,39 @@ func TestStateHistoryRecordedDuringTransitions(t *testing.T) {
 			AgreementLetterSignedUrl: "https://example.com/signed-agreement.pdf",
 		}
 
-		mockLoanRepo.On("GetByID", context.Background(), loanID).Return(loan, nil).Once()
-		mockDisbursementRepo.On("Create", context.Background(), disbursement).Return(nil).Once()
-		mockLoanRepo.On("UpdateState", context.Background(), loanID, "disbursed").Return(nil).Once()
-		mockStateHistoryRepo.On("Create", context.Background(), mock.MatchedBy(func(history *models.LoanStateHistory) bool {
+		f.installmentRepo.On("Create", ctx, mock.Anything).Return(nil).Times(12)
+		f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil).Once()
+		f.disbursementRepo.On("Create", ctx, disbursement).Return(nil).Once()
+		f.ledger.On("Post", ctx, mock.AnythingOfType("*ledger.JournalEntry")).Return(nil).Once()
+		f.loanRepo.On("UpdateState", ctx, loanID, "disbursed").Return(nil).Once()
+		f.stateHistoryRepo.On("Create", ctx, mock.MatchedBy(func(history *models.LoanStateHistory) bool {
 			return history.LoanID == loanID &&
 				   history.PreviousState == "invested" &&
 				   history.NewState == "disbursed" &&
-				   history.TransitionReason == "Loan disbursed to borrower"
+				   history.TransitionReason == "Loan disbursed to borrower" &&
+				   history.ChangedBy == "EMP002"
 		})).Return(nil).Once()
 
-		err := service.DisburseLoan(context.Background(), loanID, disbursement)
+		err := f.service.DisburseLoan(ctx, loanID, disbursement)
 
 		assert.NoError(t, err)
-		mockStateHistoryRepo.AssertExpectations(t)
+		f.stateHistoryRepo.AssertExpectations(t)
 	})
 }
 
 func TestMultipleInvestorsInSameLoan(t *testing.T) {
-	mockLoanRepo := mocks.NewLoanRepository(t)
-	mockApprovalRepo := mocks.NewLoanApprovalRepository(t)
-	mockDisbursementRepo := mocks.NewLoanDisbursementRepository(t)
-	mockInvestmentRepo := mocks.NewLoanInvestmentRepository(t)
-	mockStateHistoryRepo := mocks.NewLoanStateHistoryRepository(t)
-	mockInvestorRepo := mocks.NewInvestorRepository(t)
-	mockEmailService := mocks2.NewEmailService(t)
-	mockStorageService := mocks2.NewStorageService(t)
-
-	service := NewLoanService(mockLoanRepo, mockApprovalRepo, mockDisbursementRepo, mockInvestmentRepo, mockStateHistoryRepo, mockInvestorRepo, mockEmailService, mockStorageService)
+	f := newLoanServiceFixture(t)
 
 	loanID := 1
 	loan := &models.Loan{
 		ID:                  loanID,
 		BorrowerID:          1,
-		PrincipalAmount:     10000.0,
-		Rate:                0.05,
-		ROI:                 0.08,
+		PrincipalAmount:     money.MustParse("10000.00"),
+		Rate:                money.MustParseRate("0.05"),
+		ROI:                 money.MustParseRate("0.08"),
 		AgreementLetterLink: sql.NullString{String: "https://example.com/agreement.pdf", Valid: true},
 		CurrentState:        "approved",
-		TotalInvestedAmount: 0.0,
+		TotalInvestedAmount: money.MustParse("0.00"),
 		LoanID:              "LOAN001",
 	}
 
@@ -591,34 +809,37 @@ func TestMultipleInvestorsInSameLoan(t *testing.T) {
 
 	investment1 := &models.LoanInvestment{
 		InvestorID:       1,
-		InvestmentAmount: 6000.0, // First investment
+		InvestmentAmount: money.MustParse("6000.00"), // First investment
 	}
 
 	investment2 := &models.LoanInvestment{
 		InvestorID:       2,
-		InvestmentAmount: 4000.0, // Second investment to reach principal
+		InvestmentAmount: money.MustParse("4000.00"), // Second investment to reach principal
 	}
 
 	loanInvestments := []*models.LoanInvestment{
 		{
 			ID:             1,
 			InvestorID:     1,
-			InvestmentAmount: 6000.0,
+			InvestmentAmount: money.MustParse("6000.00"),
 		},
 		{
 			ID:             2,
 			InvestorID:     2,
-			InvestmentAmount: 4000.0,
+			InvestmentAmount: money.MustParse("4000.00"),
 		},
 	}
 
 	// First investment - should succeed
-	mockLoanRepo.On("GetByID", context.Background(), loanID).Return(loan, nil).Once()
-	mockInvestmentRepo.On("GetByLoanAndInvestor", context.Background(), loanID, 1).Return(nil, errors.New("not found")).Once()
-	mockInvestmentRepo.On("Create", context.Background(), investment1).Return(nil).Once()
-	mockLoanRepo.On("UpdateTotalInvestedAmount", context.Background(), loanID, 6000.0).Return(nil).Once()
-
-	err := service.InvestInLoan(context.Background(), loanID, investment1)
+	ctx1 := asInvestor(investor1)
+	f.investorRepo.On("GetByID", ctx1, 1).Return(investor1, nil).Once()
+	f.loanRepo.On("GetByIDForUpdate", ctx1, loanID).Return(loan, nil).Once()
+	f.investmentRepo.On("GetByLoanAndInvestor", ctx1, loanID, 1).Return(nil, repositories.ErrLoanInvestmentNotFound).Once()
+	f.investmentRepo.On("Create", ctx1, investment1).Return(nil).Once()
+	f.ledger.On("Post", ctx1, mock.AnythingOfType("*ledger.JournalEntry")).Return(nil).Once()
+	f.loanRepo.On("UpdateTotalInvestedAmount", ctx1, loanID, money.MustParse("6000.00")).Return(nil).Once()
+
+	err := f.service.InvestInLoan(ctx1, loanID, investment1)
 	assert.NoError(t, err)
 
 	// Second investment - should make loan fully invested and trigger emails
@@ -626,36 +847,450 @@ func TestMultipleInvestorsInSameLoan(t *testing.T) {
 	loanAfterFirstInvestment := &models.Loan{
 		ID:                  loanID,
 		BorrowerID:          1,
-		PrincipalAmount:     10000.0,
-		Rate:                0.05,
-		ROI:                 0.08,
+		PrincipalAmount:     money.MustParse("10000.00"),
+		Rate:                money.MustParseRate("0.05"),
+		ROI:                 money.MustParseRate("0.08"),
 		AgreementLetterLink: sql.NullString{String: "https://example.com/agreement.pdf", Valid: true},
 		CurrentState:        "approved",
-		TotalInvestedAmount: 6000.0, // Updated after first investment
+		TotalInvestedAmount: money.MustParse("6000.00"), // Updated after first investment
 		LoanID:              "LOAN001",
 	}
 	
-	mockLoanRepo.On("GetByID", context.Background(), loanID).Return(loanAfterFirstInvestment, nil).Once()
-	mockInvestmentRepo.On("GetByLoanAndInvestor", context.Background(), loanID, 2).Return(nil, errors.New("not found")).Once()
-	mockInvestmentRepo.On("Create", context.Background(), investment2).Return(nil).Once()
-	mockLoanRepo.On("UpdateTotalInvestedAmount", context.Background(), loanID, 10000.0).Return(nil).Once()
-	mockLoanRepo.On("UpdateState", context.Background(), loanID, "invested").Return(nil).Once()
-	mockStateHistoryRepo.On("Create", context.Background(), mock.MatchedBy(func(history *models.LoanStateHistory) bool {
+	ctx2 := asInvestor(investor2)
+	f.loanRepo.On("GetByIDForUpdate", ctx2, loanID).Return(loanAfterFirstInvestment, nil).Once()
+	f.investmentRepo.On("GetByLoanAndInvestor", ctx2, loanID, 2).Return(nil, repositories.ErrLoanInvestmentNotFound).Once()
+	f.investmentRepo.On("Create", ctx2, investment2).Return(nil).Once()
+	f.ledger.On("Post", ctx2, mock.AnythingOfType("*ledger.JournalEntry")).Return(nil).Once()
+	f.loanRepo.On("UpdateTotalInvestedAmount", ctx2, loanID, money.MustParse("10000.00")).Return(nil).Once()
+	f.loanRepo.On("UpdateState", ctx2, loanID, "invested").Return(nil).Once()
+	f.stateHistoryRepo.On("Create", ctx2, mock.MatchedBy(func(history *models.LoanStateHistory) bool {
 		return history.LoanID == loanID &&
 			   history.PreviousState == "approved" &&
 			   history.NewState == "invested" &&
 			   history.TransitionReason == "Loan fully invested"
 	})).Return(nil).Once()
-	mockInvestmentRepo.On("GetByLoanID", context.Background(), loanID).Return(loanInvestments, nil).Once()
-	mockInvestorRepo.On("GetByID", context.Background(), 1).Return(investor1, nil).Once()
-	mockInvestorRepo.On("GetByID", context.Background(), 2).Return(investor2, nil).Once()
-	mockEmailService.On("SendInvestmentConfirmation", context.Background(), "investor1@example.com", "https://example.com/agreement.pdf", "Loan LOAN001 has been fully invested").Return(nil).Once()
-	mockEmailService.On("SendInvestmentConfirmation", context.Background(), "investor2@example.com", "https://example.com/agreement.pdf", "Loan LOAN001 has been fully invested").Return(nil).Once()
+	f.investmentRepo.On("GetByLoanID", ctx2, loanID).Return(loanInvestments, nil).Once()
+	f.investorRepo.On("GetByID", ctx2, 1).Return(investor1, nil).Once()
+	f.investorRepo.On("GetByID", ctx2, 2).Return(investor2, nil).Twice()
+	f.emailService.On("SendInvestmentConfirmation", ctx2, "investor1@example.com", "https://example.com/agreement.pdf", "Loan LOAN001 has been fully invested").Return(nil).Once()
+	f.emailService.On("SendInvestmentConfirmation", ctx2, "investor2@example.com", "https://example.com/agreement.pdf", "Loan LOAN001 has been fully invested").Return(nil).Once()
 
-	err = service.InvestInLoan(context.Background(), loanID, investment2)
+	err = f.service.InvestInLoan(ctx2, loanID, investment2)
 
 	assert.NoError(t, err)
 	// Note: The loan object in the test won't be updated by the service method, so we can't check loan.CurrentState directly
 	// The state update is handled by the repository, which is mocked
-	mockEmailService.AssertExpectations(t)
+	f.emailService.AssertExpectations(t)
+}
+
+func TestApproveLoanRollsBackOnFailure(t *testing.T) {
+	ctx := asUser(authz.RoleFieldValidator)
+	errInjected := errors.New("injected failure")
+	steps := []string{"create approval", "update state", "create state history"}
+
+	for failAt, step := range steps {
+		t.Run(step, func(t *testing.T) {
+			unitOfWork, rolledBack := newRecordingUnitOfWork(t)
+			f := newLoanServiceFixtureWith(t, unitOfWork, authz.DefaultPolicy())
+
+			loanID := 1
+			loan := &models.Loan{ID: loanID, CurrentState: "proposed"}
+			approval := &models.LoanApproval{
+				FieldValidatorEmployeeID: "emp001",
+				ProofImageUrl:            "https://example.com/proof.jpg",
+			}
+
+			// Steps after the failing one must never be reached
+			f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil).Once()
+			f.approvalRepo.On("Create", ctx, approval).Return(failAtStep(0, failAt, errInjected)).Once()
+			if failAt >= 1 {
+				f.loanRepo.On("UpdateState", ctx, loanID, "approved").Return(failAtStep(1, failAt, errInjected)).Once()
+			}
+			if failAt >= 2 {
+				f.stateHistoryRepo.On("Create", ctx, mock.Anything).Return(failAtStep(2, failAt, errInjected)).Once()
+			}
+
+			err := f.service.ApproveLoan(ctx, loanID, approval)
+
+			assert.ErrorIs(t, err, errInjected)
+			assert.True(t, *rolledBack)
+		})
+	}
+}
+
+func TestInvestInLoanRollsBackOnFailure(t *testing.T) {
+	errInjected := errors.New("injected failure")
+	steps := []string{"create investment", "post to ledger", "update total invested amount", "update state", "create state history"}
+
+	for failAt, step := range steps {
+		t.Run(step, func(t *testing.T) {
+			unitOfWork, rolledBack := newRecordingUnitOfWork(t)
+			f := newLoanServiceFixtureWith(t, unitOfWork, authz.DefaultPolicy())
+			investor := &models.Investor{ID: 1, Email: "investor@example.com"}
+			ctx := asInvestor(investor)
+			f.investorRepo.On("GetByID", ctx, investor.ID).Return(investor, nil)
+
+			loanID := 1
+			loan := &models.Loan{
+				ID:                  loanID,
+				PrincipalAmount:     money.MustParse("10000.00"),
+				CurrentState:        "approved",
+				TotalInvestedAmount: money.MustParse("5000.00"),
+			}
+			investment := &models.LoanInvestment{
+				InvestorID:       1,
+				InvestmentAmount: money.MustParse("5000.00"), // Completes the loan, so every step runs
+			}
+
+			// No confirmation email may be sent for a rolled back investment
+			f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil).Once()
+			f.investmentRepo.On("GetByLoanAndInvestor", ctx, loanID, 1).Return(nil, repositories.ErrLoanInvestmentNotFound).Once()
+			f.investmentRepo.On("Create", ctx, investment).Return(failAtStep(0, failAt, errInjected)).Once()
+			if failAt >= 1 {
+				f.ledger.On("Post", ctx, mock.AnythingOfType("*ledger.JournalEntry")).Return(failAtStep(1, failAt, errInjected)).Once()
+			}
+			if failAt >= 2 {
+				f.loanRepo.On("UpdateTotalInvestedAmount", ctx, loanID, money.MustParse("10000.00")).Return(failAtStep(2, failAt, errInjected)).Once()
+			}
+			if failAt >= 3 {
+				f.loanRepo.On("UpdateState", ctx, loanID, "invested").Return(failAtStep(3, failAt, errInjected)).Once()
+			}
+			if failAt >= 4 {
+				f.stateHistoryRepo.On("Create", ctx, mock.Anything).Return(failAtStep(4, failAt, errInjected)).Once()
+			}
+
+			err := f.service.InvestInLoan(ctx, loanID, investment)
+
+			assert.ErrorIs(t, err, errInjected)
+			assert.True(t, *rolledBack)
+		})
+	}
+}
+
+func TestDisburseLoanRollsBackOnFailure(t *testing.T) {
+	ctx := asUser(authz.RoleFieldOfficer)
+	errInjected := errors.New("injected failure")
+	steps := []string{"create disbursement", "post to ledger", "update state", "create state history", "create installment"}
+
+	for failAt, step := range steps {
+		t.Run(step, func(t *testing.T) {
+			unitOfWork, rolledBack := newRecordingUnitOfWork(t)
+			f := newLoanServiceFixtureWith(t, unitOfWork, authz.DefaultPolicy())
+
+			loanID := 1
+			loan := &models.Loan{
+				ID:                  loanID,
+				PrincipalAmount:     money.MustParse("10000.00"),
+				CurrentState:        "invested",
+				TotalInvestedAmount: money.MustParse("10000.00"),
+				TenorMonths:         12,
+				RepaymentMethod:     "flat",
+			}
+			disbursement := &models.LoanDisbursement{
+				FieldOfficerEmployeeID:   "emp002",
+				AgreementLetterSignedUrl: "https://example.com/signed-agreement.pdf",
+			}
+
+			f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil).Once()
+			f.disbursementRepo.On("Create", ctx, disbursement).Return(failAtStep(0, failAt, errInjected)).Once()
+			if failAt >= 1 {
+				f.ledger.On("Post", ctx, mock.AnythingOfType("*ledger.JournalEntry")).Return(failAtStep(1, failAt, errInjected)).Once()
+			}
+			if failAt >= 2 {
+				f.loanRepo.On("UpdateState", ctx, loanID, "disbursed").Return(failAtStep(2, failAt, errInjected)).Once()
+			}
+			if failAt >= 3 {
+				f.stateHistoryRepo.On("Create", ctx, mock.Anything).Return(failAtStep(3, failAt, errInjected)).Once()
+			}
+			if failAt >= 4 {
+				f.installmentRepo.On("Create", ctx, mock.Anything).Return(failAtStep(4, failAt, errInjected)).Once()
+			}
+
+			err := f.service.DisburseLoan(ctx, loanID, disbursement)
+
+			assert.ErrorIs(t, err, errInjected)
+			assert.True(t, *rolledBack)
+		})
+	}
+}
+
+func TestInvestInLoanLocksLoanInsideUnitOfWork(t *testing.T) {
+	f := newLoanServiceFixtureWith(t, mocks.NewUnitOfWork(t), authz.DefaultPolicy())
+	investor := &models.Investor{ID: 1, Email: "investor@example.com"}
+	ctx := asInvestor(investor)
+	f.investorRepo.On("GetByID", ctx, investor.ID).Return(investor, nil)
+
+	// The unit of work hands fn a context carrying the transaction; the lock must be taken with it
+	type txKey struct{}
+	inTx := mock.MatchedBy(func(ctx context.Context) bool {
+		return ctx.Value(txKey{}) != nil
+	})
+	f.unitOfWork.On("Do", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(context.WithValue(ctx, txKey{}, true))
+	}).Once()
+
+	loanID := 1
+	loan := &models.Loan{
+		ID:                  loanID,
+		PrincipalAmount:     money.MustParse("10000.00"),
+		CurrentState:        "approved",
+		TotalInvestedAmount: money.MustParse("0.00"),
+	}
+	investment := &models.LoanInvestment{
+		InvestorID:       1,
+		InvestmentAmount: money.MustParse("4000.00"),
+	}
+
+	f.loanRepo.On("GetByIDForUpdate", inTx, loanID).Return(loan, nil).Once()
+	f.investmentRepo.On("GetByLoanAndInvestor", inTx, loanID, 1).Return(nil, repositories.ErrLoanInvestmentNotFound).Once()
+	f.investmentRepo.On("Create", inTx, investment).Return(nil).Once()
+	f.ledger.On("Post", inTx, mock.AnythingOfType("*ledger.JournalEntry")).Return(nil).Once()
+	f.loanRepo.On("UpdateTotalInvestedAmount", inTx, loanID, money.MustParse("4000.00")).Return(nil).Once()
+
+	err := f.service.InvestInLoan(ctx, loanID, investment)
+
+	assert.NoError(t, err)
+}
+
+func TestTerminalTransitionRequiresReason(t *testing.T) {
+	ctx := asUser(authz.RoleAdmin)
+	f := newLoanServiceFixture(t)
+
+	err := f.service.CancelLoan(ctx, 1, "  ")
+
+	assert.EqualError(t, err, "reason is required")
+}
+
+func TestRejectLoanRecordsReasonAndNotifiesBorrower(t *testing.T) {
+	ctx := asUser(authz.RoleFieldValidator)
+	f := newLoanServiceFixture(t)
+
+	loanID := 1
+	loan := &models.Loan{
+		ID:                  loanID,
+		LoanID:              "LOAN001",
+		BorrowerID:          7,
+		PrincipalAmount:     money.MustParse("10000.00"),
+		CurrentState:        "proposed",
+		TotalInvestedAmount: money.MustParse("0.00"),
+	}
+	rejection := &models.LoanRejection{
+		ReasonCode:           "insufficient_income",
+		Notes:                "Payslips cover three months only",
+		RejectedByEmployeeID: "emp003", // Overridden by the signed-in user
+	}
+	reason := &models.LoanRejectionReason{
+		Code:        "insufficient_income",
+		Description: "Income is too low for the requested principal",
+		IsActive:    true,
+	}
+
+	f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil).Once()
+	f.rejectionReasonRepo.On("GetByCode", ctx, "insufficient_income").Return(reason, nil).Once()
+	f.rejectionRepo.On("Create", ctx, mock.MatchedBy(func(r *models.LoanRejection) bool {
+		return r.LoanID == loanID && r.ReasonCode == "insufficient_income" && r.RejectedByEmployeeID == "EMP001" && !r.RejectionDate.IsZero()
+	})).Return(nil).Once()
+	f.loanRepo.On("UpdateState", ctx, loanID, "rejected").Return(nil).Once()
+	f.stateHistoryRepo.On("Create", ctx, &models.LoanStateHistory{
+		LoanID:           loanID,
+		PreviousState:    "proposed",
+		NewState:         "rejected",
+		TransitionReason: "Income is too low for the requested principal: Payslips cover three months only",
+		ChangedBy:        "EMP001",
+	}).Return(nil).Once()
+	f.borrowerRepo.On("GetByID", ctx, 7).Return(&models.Borrower{ID: 7, Email: "borrower@example.com"}, nil).Once()
+	f.emailService.On("SendRejectionNotification", ctx, "borrower@example.com", "Income is too low for the requested principal", "Loan LOAN001 has been rejected").Return(nil).Once()
+
+	err := f.service.RejectLoan(ctx, loanID, rejection)
+
+	assert.NoError(t, err)
+}
+
+func TestRejectLoanAfterInvestment(t *testing.T) {
+	ctx := asUser(authz.RoleFieldValidator)
+	f := newLoanServiceFixture(t)
+
+	loanID := 1
+	loan := &models.Loan{
+		ID:                  loanID,
+		PrincipalAmount:     money.MustParse("10000.00"),
+		CurrentState:        "invested",
+		TotalInvestedAmount: money.MustParse("10000.00"),
+	}
+
+	f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil).Once()
+	f.rejectionReasonRepo.On("GetByCode", ctx, "other").Return(&models.LoanRejectionReason{Code: "other", Description: "Other", IsActive: true}, nil).Once()
+
+	err := f.service.RejectLoan(ctx, loanID, &models.LoanRejection{ReasonCode: "other", RejectedByEmployeeID: "emp003"})
+
+	assert.EqualError(t, err, "loan must be in proposed or approved state to be rejected")
+}
+
+func TestCancelLoanRefundsInvestors(t *testing.T) {
+	ctx := asUser(authz.RoleAdmin)
+	f := newLoanServiceFixture(t)
+
+	loanID := 1
+	loan := &models.Loan{
+		ID:                  loanID,
+		PrincipalAmount:     money.MustParse("10000.00"),
+		CurrentState:        "invested",
+		TotalInvestedAmount: money.MustParse("10000.00"),
+	}
+
+	f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil).Once()
+	f.ledger.On("Post", ctx, mock.MatchedBy(func(entry *ledger.JournalEntry) bool {
+		return entry.Reference == "loan_refund:1" &&
+			len(entry.Postings) == 2 &&
+			entry.Postings[0].AccountCode == ledger.AccountInvestorFunds && entry.Postings[0].Direction == ledger.Debit &&
+			entry.Postings[1].AccountCode == ledger.AccountCash && entry.Postings[1].Direction == ledger.Credit &&
+			entry.Postings[0].Amount.Equal(money.MustParse("10000.00")) &&
+			entry.Validate() == nil
+	})).Return(nil).Once()
+	f.loanRepo.On("UpdateState", ctx, loanID, "cancelled").Return(nil).Once()
+	f.stateHistoryRepo.On("Create", ctx, mock.AnythingOfType("*models.LoanStateHistory")).Return(nil).Once()
+
+	err := f.service.CancelLoan(ctx, loanID, "Borrower withdrew the application")
+
+	assert.NoError(t, err)
+}
+
+func TestMarkLoanRepaidRequiresEveryInstallmentPaid(t *testing.T) {
+	ctx := asUser(authz.RoleAdmin)
+	f := newLoanServiceFixture(t)
+
+	loanID := 1
+	loan := &models.Loan{ID: loanID, CurrentState: "disbursed"}
+	installments := []*models.LoanInstallment{
+		{LoanID: loanID, InstallmentNumber: 1, Status: "paid"},
+		{LoanID: loanID, InstallmentNumber: 2, Status: "partially_paid"},
+	}
+
+	f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil).Once()
+	f.installmentRepo.On("GetByLoanID", ctx, loanID).Return(installments, nil).Once()
+
+	err := f.service.MarkLoanRepaid(ctx, loanID, "Final installment received")
+
+	assert.EqualError(t, err, "installment 2 is not paid yet")
+}
+
+func TestWriteOffLoanPostsOutstandingPrincipal(t *testing.T) {
+	ctx := asUser(authz.RoleAdmin)
+	unitOfWork, rolledBack := newRecordingUnitOfWork(t)
+	f := newLoanServiceFixtureWith(t, unitOfWork, authz.DefaultPolicy())
+
+	loanID := 1
+	loan := &models.Loan{
+		ID:              loanID,
+		PrincipalAmount: money.MustParse("200.00"),
+		CurrentState:    "defaulted",
+	}
+	installments := []*models.LoanInstallment{
+		{LoanID: loanID, InstallmentNumber: 1, PrincipalAmount: money.MustParse("100.00"), PrincipalPaid: money.MustParse("100.00"), Status: "paid"},
+		{LoanID: loanID, InstallmentNumber: 2, PrincipalAmount: money.MustParse("100.00"), PrincipalPaid: money.MustParse("25.00"), Status: "overdue"},
+	}
+
+	var posted *ledger.JournalEntry
+	f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil).Once()
+	f.installmentRepo.On("GetByLoanID", ctx, loanID).Return(installments, nil).Once()
+	f.ledger.On("Post", ctx, mock.AnythingOfType("*ledger.JournalEntry")).Run(func(args mock.Arguments) {
+		posted = args.Get(1).(*ledger.JournalEntry)
+	}).Return(nil).Once()
+	f.loanRepo.On("UpdateState", ctx, loanID, "written_off").Return(errors.New("db down")).Once()
+
+	err := f.service.WriteOffLoan(ctx, loanID, "Borrower unreachable for 180 days")
+
+	assert.Error(t, err)
+	assert.True(t, *rolledBack)
+	require.NotNil(t, posted)
+	assert.Equal(t, "loan_write_off:1", posted.Reference)
+	assert.NoError(t, posted.Validate())
+	assert.Equal(t, ledger.AccountInvestorCapital, posted.Postings[0].AccountCode)
+	assert.Equal(t, ledger.AccountLoansReceivable, posted.Postings[1].AccountCode)
+	assert.Equal(t, "75.00", posted.Postings[1].Amount.String())
+}
+
+func TestRejectLoanRequiresActiveReasonCode(t *testing.T) {
+	ctx := asUser(authz.RoleFieldValidator)
+	f := newLoanServiceFixture(t)
+
+	loanID := 1
+	loan := &models.Loan{ID: loanID, CurrentState: "proposed"}
+
+	err := f.service.RejectLoan(ctx, loanID, &models.LoanRejection{RejectedByEmployeeID: "emp003"})
+	assert.EqualError(t, err, "reason code is required")
+
+	f.loanRepo.On("GetByIDForUpdate", ctx, loanID).Return(loan, nil).Twice()
+	f.rejectionReasonRepo.On("GetByCode", ctx, "bad_vibes").Return(nil, repositories.ErrRejectionReasonNotFound).Once()
+	f.rejectionReasonRepo.On("GetByCode", ctx, "legacy").Return(&models.LoanRejectionReason{Code: "legacy", Description: "Legacy", IsActive: false}, nil).Once()
+
+	err = f.service.RejectLoan(ctx, loanID, &models.LoanRejection{ReasonCode: "bad_vibes", RejectedByEmployeeID: "emp003"})
+	assert.EqualError(t, err, "unknown rejection reason code: bad_vibes")
+	assert.ErrorIs(t, err, apperr.ErrValidation)
+
+	err = f.service.RejectLoan(ctx, loanID, &models.LoanRejection{ReasonCode: "legacy", RejectedByEmployeeID: "emp003"})
+	assert.EqualError(t, err, "rejection reason legacy is no longer in use")
+}
+
+func TestRejectedLoanIsImmutable(t *testing.T) {
+	f := newLoanServiceFixture(t)
+
+	loanID := 1
+	loan := &models.Loan{ID: loanID, CurrentState: "rejected", PrincipalAmount: money.MustParse("10000.00")}
+	f.loanRepo.On("GetByID", context.Background(), loanID).Return(loan, nil).Twice()
+
+	err := f.service.UpdateLoan(context.Background(), loanID, &models.Loan{PrincipalAmount: money.MustParse("5000.00")})
+	assert.EqualError(t, err, "rejected loans cannot be modified")
+
+	err = f.service.DeleteLoan(context.Background(), loanID)
+	assert.EqualError(t, err, "rejected loans cannot be deleted")
+}
+
+func TestLoanLifecycleChecksPermissions(t *testing.T) {
+	f := newLoanServiceFixture(t)
+
+	approval := &models.LoanApproval{FieldValidatorEmployeeID: "EMP001", ProofImageUrl: "https://example.com/proof.jpg"}
+	disbursement := &models.LoanDisbursement{FieldOfficerEmployeeID: "EMP002", AgreementLetterSignedUrl: "https://example.com/signed.pdf"}
+
+	// No loan is loaded for any of these, so the repositories see no calls
+	err := f.service.ApproveLoan(context.Background(), 1, approval)
+	assert.ErrorIs(t, err, authz.ErrUnauthenticated)
+
+	err = f.service.ApproveLoan(asUser(authz.RoleFieldOfficer), 1, approval)
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+
+	err = f.service.DisburseLoan(asUser(authz.RoleFieldValidator), 1, disbursement)
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+
+	err = f.service.WriteOffLoan(asUser(authz.RoleFieldOfficer), 1, "borrower absconded")
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+
+	// Investors may only invest as the investor record linked to their account, even one
+	// sharing their email
+	ctx := asInvestor(&models.Investor{ID: 1, Email: "investor@example.com"})
+	err = f.service.InvestInLoan(ctx, 1, &models.LoanInvestment{InvestorID: 2, InvestmentAmount: money.MustParse("1000.00")})
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+
+	unlinked := authz.WithUser(context.Background(), &models.User{ID: 1, Email: "investor@example.com", UserType: authz.RoleInvestor, IsActive: true})
+	err = f.service.InvestInLoan(unlinked, 1, &models.LoanInvestment{InvestorID: 1, InvestmentAmount: money.MustParse("1000.00")})
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+}
+
+func TestApprovalAndDisbursementRequireTheRightStaffRole(t *testing.T) {
+	// Even a policy that lets admins approve and disburse cannot make them the employee of record
+	policy := authz.DefaultPolicy()
+	policy[authz.RoleAdmin][authz.ApproveLoan] = authz.ScopeAny
+	policy[authz.RoleAdmin][authz.DisburseLoan] = authz.ScopeAny
+
+	f := newLoanServiceFixtureWith(t, newMockUnitOfWork(t), policy)
+
+	ctx := asUser(authz.RoleAdmin)
+
+	err := f.service.ApproveLoan(ctx, 1, &models.LoanApproval{ProofImageUrl: "https://example.com/proof.jpg"})
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+	assert.Contains(t, err.Error(), "only active field validator staff")
+
+	err = f.service.DisburseLoan(ctx, 1, &models.LoanDisbursement{AgreementLetterSignedUrl: "https://example.com/signed.pdf"})
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+	assert.Contains(t, err.Error(), "only active field officer staff")
 }
@@ -8,6 +8,7 @@ import (
 	"context"
 
 	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/services"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -38,6 +39,75 @@ func (_m *AuthService) EXPECT() *AuthService_Expecter {
 	return &AuthService_Expecter{mock: &_m.Mock}
 }
 
+// ChangePassword provides a mock function for the type AuthService
+func (_mock *AuthService) ChangePassword(ctx context.Context, userID int, currentPassword string, newPassword string) error {
+	ret := _mock.Called(ctx, userID, currentPassword, newPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, string) error); ok {
+		r0 = returnFunc(ctx, userID, currentPassword, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthService_ChangePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangePassword'
+type AuthService_ChangePassword_Call struct {
+	*mock.Call
+}
+
+// ChangePassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - currentPassword string
+//   - newPassword string
+func (_e *AuthService_Expecter) ChangePassword(ctx interface{}, userID interface{}, currentPassword interface{}, newPassword interface{}) *AuthService_ChangePassword_Call {
+	return &AuthService_ChangePassword_Call{Call: _e.mock.On("ChangePassword", ctx, userID, currentPassword, newPassword)}
+}
+
+func (_c *AuthService_ChangePassword_Call) Run(run func(ctx context.Context, userID int, currentPassword string, newPassword string)) *AuthService_ChangePassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthService_ChangePassword_Call) Return(err error) *AuthService_ChangePassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthService_ChangePassword_Call) RunAndReturn(run func(ctx context.Context, userID int, currentPassword string, newPassword string) error) *AuthService_ChangePassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CheckPasswordHash provides a mock function for the type AuthService
 func (_mock *AuthService) CheckPasswordHash(password string, hash string) bool {
 	ret := _mock.Called(password, hash)
@@ -95,6 +165,211 @@ func (_c *AuthService_CheckPasswordHash_Call) RunAndReturn(run func(password str
 	return _c
 }
 
+// ConfirmTOTP provides a mock function for the type AuthService
+func (_mock *AuthService) ConfirmTOTP(ctx context.Context, userID int, code string) ([]string, error) {
+	ret := _mock.Called(ctx, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmTOTP")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) ([]string, error)); ok {
+		return returnFunc(ctx, userID, code)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) []string); ok {
+		r0 = returnFunc(ctx, userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = returnFunc(ctx, userID, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthService_ConfirmTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConfirmTOTP'
+type AuthService_ConfirmTOTP_Call struct {
+	*mock.Call
+}
+
+// ConfirmTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - code string
+func (_e *AuthService_Expecter) ConfirmTOTP(ctx interface{}, userID interface{}, code interface{}) *AuthService_ConfirmTOTP_Call {
+	return &AuthService_ConfirmTOTP_Call{Call: _e.mock.On("ConfirmTOTP", ctx, userID, code)}
+}
+
+func (_c *AuthService_ConfirmTOTP_Call) Run(run func(ctx context.Context, userID int, code string)) *AuthService_ConfirmTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthService_ConfirmTOTP_Call) Return(ss []string, err error) *AuthService_ConfirmTOTP_Call {
+	_c.Call.Return(ss, err)
+	return _c
+}
+
+func (_c *AuthService_ConfirmTOTP_Call) RunAndReturn(run func(ctx context.Context, userID int, code string) ([]string, error)) *AuthService_ConfirmTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DisableTOTP provides a mock function for the type AuthService
+func (_mock *AuthService) DisableTOTP(ctx context.Context, userID int, password string) error {
+	ret := _mock.Called(ctx, userID, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableTOTP")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = returnFunc(ctx, userID, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthService_DisableTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DisableTOTP'
+type AuthService_DisableTOTP_Call struct {
+	*mock.Call
+}
+
+// DisableTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - password string
+func (_e *AuthService_Expecter) DisableTOTP(ctx interface{}, userID interface{}, password interface{}) *AuthService_DisableTOTP_Call {
+	return &AuthService_DisableTOTP_Call{Call: _e.mock.On("DisableTOTP", ctx, userID, password)}
+}
+
+func (_c *AuthService_DisableTOTP_Call) Run(run func(ctx context.Context, userID int, password string)) *AuthService_DisableTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthService_DisableTOTP_Call) Return(err error) *AuthService_DisableTOTP_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthService_DisableTOTP_Call) RunAndReturn(run func(ctx context.Context, userID int, password string) error) *AuthService_DisableTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EnrolTOTP provides a mock function for the type AuthService
+func (_mock *AuthService) EnrolTOTP(ctx context.Context, userID int) (*services.TOTPEnrolment, error) {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnrolTOTP")
+	}
+
+	var r0 *services.TOTPEnrolment
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*services.TOTPEnrolment, error)); ok {
+		return returnFunc(ctx, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *services.TOTPEnrolment); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*services.TOTPEnrolment)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthService_EnrolTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnrolTOTP'
+type AuthService_EnrolTOTP_Call struct {
+	*mock.Call
+}
+
+// EnrolTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *AuthService_Expecter) EnrolTOTP(ctx interface{}, userID interface{}) *AuthService_EnrolTOTP_Call {
+	return &AuthService_EnrolTOTP_Call{Call: _e.mock.On("EnrolTOTP", ctx, userID)}
+}
+
+func (_c *AuthService_EnrolTOTP_Call) Run(run func(ctx context.Context, userID int)) *AuthService_EnrolTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthService_EnrolTOTP_Call) Return(tOTPEnrolment *services.TOTPEnrolment, err error) *AuthService_EnrolTOTP_Call {
+	_c.Call.Return(tOTPEnrolment, err)
+	return _c
+}
+
+func (_c *AuthService_EnrolTOTP_Call) RunAndReturn(run func(ctx context.Context, userID int) (*services.TOTPEnrolment, error)) *AuthService_EnrolTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // HashPassword provides a mock function for the type AuthService
 func (_mock *AuthService) HashPassword(password string) (string, error) {
 	ret := _mock.Called(password)
@@ -156,22 +431,24 @@ func (_c *AuthService_HashPassword_Call) RunAndReturn(run func(password string)
 }
 
 // LoginUser provides a mock function for the type AuthService
-func (_mock *AuthService) LoginUser(ctx context.Context, email string, password string) (string, error) {
+func (_mock *AuthService) LoginUser(ctx context.Context, email string, password string) (*services.LoginResult, error) {
 	ret := _mock.Called(ctx, email, password)
 
 	if len(ret) == 0 {
 		panic("no return value specified for LoginUser")
 	}
 
-	var r0 string
+	var r0 *services.LoginResult
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*services.LoginResult, error)); ok {
 		return returnFunc(ctx, email, password)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *services.LoginResult); ok {
 		r0 = returnFunc(ctx, email, password)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*services.LoginResult)
+		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
 		r1 = returnFunc(ctx, email, password)
@@ -217,55 +494,46 @@ func (_c *AuthService_LoginUser_Call) Run(run func(ctx context.Context, email st
 	return _c
 }
 
-func (_c *AuthService_LoginUser_Call) Return(s string, err error) *AuthService_LoginUser_Call {
-	_c.Call.Return(s, err)
+func (_c *AuthService_LoginUser_Call) Return(loginResult *services.LoginResult, err error) *AuthService_LoginUser_Call {
+	_c.Call.Return(loginResult, err)
 	return _c
 }
 
-func (_c *AuthService_LoginUser_Call) RunAndReturn(run func(ctx context.Context, email string, password string) (string, error)) *AuthService_LoginUser_Call {
+func (_c *AuthService_LoginUser_Call) RunAndReturn(run func(ctx context.Context, email string, password string) (*services.LoginResult, error)) *AuthService_LoginUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// RefreshToken provides a mock function for the type AuthService
-func (_mock *AuthService) RefreshToken(ctx context.Context, refreshToken string) (string, error) {
+// Logout provides a mock function for the type AuthService
+func (_mock *AuthService) Logout(ctx context.Context, refreshToken string) error {
 	ret := _mock.Called(ctx, refreshToken)
 
 	if len(ret) == 0 {
-		panic("no return value specified for RefreshToken")
+		panic("no return value specified for Logout")
 	}
 
-	var r0 string
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
-		return returnFunc(ctx, refreshToken)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
 		r0 = returnFunc(ctx, refreshToken)
 	} else {
-		r0 = ret.Get(0).(string)
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, refreshToken)
-	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// AuthService_RefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RefreshToken'
-type AuthService_RefreshToken_Call struct {
+// AuthService_Logout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Logout'
+type AuthService_Logout_Call struct {
 	*mock.Call
 }
 
-// RefreshToken is a helper method to define mock.On call
+// Logout is a helper method to define mock.On call
 //   - ctx context.Context
 //   - refreshToken string
-func (_e *AuthService_Expecter) RefreshToken(ctx interface{}, refreshToken interface{}) *AuthService_RefreshToken_Call {
-	return &AuthService_RefreshToken_Call{Call: _e.mock.On("RefreshToken", ctx, refreshToken)}
+func (_e *AuthService_Expecter) Logout(ctx interface{}, refreshToken interface{}) *AuthService_Logout_Call {
+	return &AuthService_Logout_Call{Call: _e.mock.On("Logout", ctx, refreshToken)}
 }
 
-func (_c *AuthService_RefreshToken_Call) Run(run func(ctx context.Context, refreshToken string)) *AuthService_RefreshToken_Call {
+func (_c *AuthService_Logout_Call) Run(run func(ctx context.Context, refreshToken string)) *AuthService_Logout_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -283,120 +551,114 @@ func (_c *AuthService_RefreshToken_Call) Run(run func(ctx context.Context, refre
 	return _c
 }
 
-func (_c *AuthService_RefreshToken_Call) Return(s string, err error) *AuthService_RefreshToken_Call {
-	_c.Call.Return(s, err)
+func (_c *AuthService_Logout_Call) Return(err error) *AuthService_Logout_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *AuthService_RefreshToken_Call) RunAndReturn(run func(ctx context.Context, refreshToken string) (string, error)) *AuthService_RefreshToken_Call {
+func (_c *AuthService_Logout_Call) RunAndReturn(run func(ctx context.Context, refreshToken string) error) *AuthService_Logout_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// RegisterUser provides a mock function for the type AuthService
-func (_mock *AuthService) RegisterUser(ctx context.Context, user *models.User, password string) error {
-	ret := _mock.Called(ctx, user, password)
+// LogoutAll provides a mock function for the type AuthService
+func (_mock *AuthService) LogoutAll(ctx context.Context, userID int) error {
+	ret := _mock.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for RegisterUser")
+		panic("no return value specified for LogoutAll")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.User, string) error); ok {
-		r0 = returnFunc(ctx, user, password)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = returnFunc(ctx, userID)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// AuthService_RegisterUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RegisterUser'
-type AuthService_RegisterUser_Call struct {
+// AuthService_LogoutAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LogoutAll'
+type AuthService_LogoutAll_Call struct {
 	*mock.Call
 }
 
-// RegisterUser is a helper method to define mock.On call
+// LogoutAll is a helper method to define mock.On call
 //   - ctx context.Context
-//   - user *models.User
-//   - password string
-func (_e *AuthService_Expecter) RegisterUser(ctx interface{}, user interface{}, password interface{}) *AuthService_RegisterUser_Call {
-	return &AuthService_RegisterUser_Call{Call: _e.mock.On("RegisterUser", ctx, user, password)}
+//   - userID int
+func (_e *AuthService_Expecter) LogoutAll(ctx interface{}, userID interface{}) *AuthService_LogoutAll_Call {
+	return &AuthService_LogoutAll_Call{Call: _e.mock.On("LogoutAll", ctx, userID)}
 }
 
-func (_c *AuthService_RegisterUser_Call) Run(run func(ctx context.Context, user *models.User, password string)) *AuthService_RegisterUser_Call {
+func (_c *AuthService_LogoutAll_Call) Run(run func(ctx context.Context, userID int)) *AuthService_LogoutAll_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *models.User
+		var arg1 int
 		if args[1] != nil {
-			arg1 = args[1].(*models.User)
-		}
-		var arg2 string
-		if args[2] != nil {
-			arg2 = args[2].(string)
+			arg1 = args[1].(int)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *AuthService_RegisterUser_Call) Return(err error) *AuthService_RegisterUser_Call {
+func (_c *AuthService_LogoutAll_Call) Return(err error) *AuthService_LogoutAll_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *AuthService_RegisterUser_Call) RunAndReturn(run func(ctx context.Context, user *models.User, password string) error) *AuthService_RegisterUser_Call {
+func (_c *AuthService_LogoutAll_Call) RunAndReturn(run func(ctx context.Context, userID int) error) *AuthService_LogoutAll_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ValidateToken provides a mock function for the type AuthService
-func (_mock *AuthService) ValidateToken(ctx context.Context, token string) (*models.User, error) {
-	ret := _mock.Called(ctx, token)
+// RefreshToken provides a mock function for the type AuthService
+func (_mock *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*services.TokenPair, error) {
+	ret := _mock.Called(ctx, refreshToken)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ValidateToken")
+		panic("no return value specified for RefreshToken")
 	}
 
-	var r0 *models.User
+	var r0 *services.TokenPair
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
-		return returnFunc(ctx, token)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*services.TokenPair, error)); ok {
+		return returnFunc(ctx, refreshToken)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
-		r0 = returnFunc(ctx, token)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *services.TokenPair); ok {
+		r0 = returnFunc(ctx, refreshToken)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.User)
+			r0 = ret.Get(0).(*services.TokenPair)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, token)
+		r1 = returnFunc(ctx, refreshToken)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// AuthService_ValidateToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateToken'
-type AuthService_ValidateToken_Call struct {
+// AuthService_RefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RefreshToken'
+type AuthService_RefreshToken_Call struct {
 	*mock.Call
 }
 
-// ValidateToken is a helper method to define mock.On call
+// RefreshToken is a helper method to define mock.On call
 //   - ctx context.Context
-//   - token string
-func (_e *AuthService_Expecter) ValidateToken(ctx interface{}, token interface{}) *AuthService_ValidateToken_Call {
-	return &AuthService_ValidateToken_Call{Call: _e.mock.On("ValidateToken", ctx, token)}
+//   - refreshToken string
+func (_e *AuthService_Expecter) RefreshToken(ctx interface{}, refreshToken interface{}) *AuthService_RefreshToken_Call {
+	return &AuthService_RefreshToken_Call{Call: _e.mock.On("RefreshToken", ctx, refreshToken)}
 }
 
-func (_c *AuthService_ValidateToken_Call) Run(run func(ctx context.Context, token string)) *AuthService_ValidateToken_Call {
+func (_c *AuthService_RefreshToken_Call) Run(run func(ctx context.Context, refreshToken string)) *AuthService_RefreshToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -414,12 +676,530 @@ func (_c *AuthService_ValidateToken_Call) Run(run func(ctx context.Context, toke
 	return _c
 }
 
-func (_c *AuthService_ValidateToken_Call) Return(user *models.User, err error) *AuthService_ValidateToken_Call {
-	_c.Call.Return(user, err)
+func (_c *AuthService_RefreshToken_Call) Return(tokenPair *services.TokenPair, err error) *AuthService_RefreshToken_Call {
+	_c.Call.Return(tokenPair, err)
 	return _c
 }
 
-func (_c *AuthService_ValidateToken_Call) RunAndReturn(run func(ctx context.Context, token string) (*models.User, error)) *AuthService_ValidateToken_Call {
+func (_c *AuthService_RefreshToken_Call) RunAndReturn(run func(ctx context.Context, refreshToken string) (*services.TokenPair, error)) *AuthService_RefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RegenerateRecoveryCodes provides a mock function for the type AuthService
+func (_mock *AuthService) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) ([]string, error) {
+	ret := _mock.Called(ctx, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegenerateRecoveryCodes")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) ([]string, error)); ok {
+		return returnFunc(ctx, userID, code)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) []string); ok {
+		r0 = returnFunc(ctx, userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = returnFunc(ctx, userID, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthService_RegenerateRecoveryCodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RegenerateRecoveryCodes'
+type AuthService_RegenerateRecoveryCodes_Call struct {
+	*mock.Call
+}
+
+// RegenerateRecoveryCodes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - code string
+func (_e *AuthService_Expecter) RegenerateRecoveryCodes(ctx interface{}, userID interface{}, code interface{}) *AuthService_RegenerateRecoveryCodes_Call {
+	return &AuthService_RegenerateRecoveryCodes_Call{Call: _e.mock.On("RegenerateRecoveryCodes", ctx, userID, code)}
+}
+
+func (_c *AuthService_RegenerateRecoveryCodes_Call) Run(run func(ctx context.Context, userID int, code string)) *AuthService_RegenerateRecoveryCodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthService_RegenerateRecoveryCodes_Call) Return(ss []string, err error) *AuthService_RegenerateRecoveryCodes_Call {
+	_c.Call.Return(ss, err)
+	return _c
+}
+
+func (_c *AuthService_RegenerateRecoveryCodes_Call) RunAndReturn(run func(ctx context.Context, userID int, code string) ([]string, error)) *AuthService_RegenerateRecoveryCodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RegisterInvestor provides a mock function for the type AuthService
+func (_mock *AuthService) RegisterInvestor(ctx context.Context, registration *services.InvestorRegistration) (*models.User, error) {
+	ret := _mock.Called(ctx, registration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegisterInvestor")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *services.InvestorRegistration) (*models.User, error)); ok {
+		return returnFunc(ctx, registration)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *services.InvestorRegistration) *models.User); ok {
+		r0 = returnFunc(ctx, registration)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *services.InvestorRegistration) error); ok {
+		r1 = returnFunc(ctx, registration)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthService_RegisterInvestor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RegisterInvestor'
+type AuthService_RegisterInvestor_Call struct {
+	*mock.Call
+}
+
+// RegisterInvestor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - registration *services.InvestorRegistration
+func (_e *AuthService_Expecter) RegisterInvestor(ctx interface{}, registration interface{}) *AuthService_RegisterInvestor_Call {
+	return &AuthService_RegisterInvestor_Call{Call: _e.mock.On("RegisterInvestor", ctx, registration)}
+}
+
+func (_c *AuthService_RegisterInvestor_Call) Run(run func(ctx context.Context, registration *services.InvestorRegistration)) *AuthService_RegisterInvestor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *services.InvestorRegistration
+		if args[1] != nil {
+			arg1 = args[1].(*services.InvestorRegistration)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthService_RegisterInvestor_Call) Return(user *models.User, err error) *AuthService_RegisterInvestor_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *AuthService_RegisterInvestor_Call) RunAndReturn(run func(ctx context.Context, registration *services.InvestorRegistration) (*models.User, error)) *AuthService_RegisterInvestor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RequestPasswordReset provides a mock function for the type AuthService
+func (_mock *AuthService) RequestPasswordReset(ctx context.Context, email string) error {
+	ret := _mock.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestPasswordReset")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthService_RequestPasswordReset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequestPasswordReset'
+type AuthService_RequestPasswordReset_Call struct {
+	*mock.Call
+}
+
+// RequestPasswordReset is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *AuthService_Expecter) RequestPasswordReset(ctx interface{}, email interface{}) *AuthService_RequestPasswordReset_Call {
+	return &AuthService_RequestPasswordReset_Call{Call: _e.mock.On("RequestPasswordReset", ctx, email)}
+}
+
+func (_c *AuthService_RequestPasswordReset_Call) Run(run func(ctx context.Context, email string)) *AuthService_RequestPasswordReset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthService_RequestPasswordReset_Call) Return(err error) *AuthService_RequestPasswordReset_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthService_RequestPasswordReset_Call) RunAndReturn(run func(ctx context.Context, email string) error) *AuthService_RequestPasswordReset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResendEmailVerification provides a mock function for the type AuthService
+func (_mock *AuthService) ResendEmailVerification(ctx context.Context, email string) error {
+	ret := _mock.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResendEmailVerification")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthService_ResendEmailVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResendEmailVerification'
+type AuthService_ResendEmailVerification_Call struct {
+	*mock.Call
+}
+
+// ResendEmailVerification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *AuthService_Expecter) ResendEmailVerification(ctx interface{}, email interface{}) *AuthService_ResendEmailVerification_Call {
+	return &AuthService_ResendEmailVerification_Call{Call: _e.mock.On("ResendEmailVerification", ctx, email)}
+}
+
+func (_c *AuthService_ResendEmailVerification_Call) Run(run func(ctx context.Context, email string)) *AuthService_ResendEmailVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthService_ResendEmailVerification_Call) Return(err error) *AuthService_ResendEmailVerification_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthService_ResendEmailVerification_Call) RunAndReturn(run func(ctx context.Context, email string) error) *AuthService_ResendEmailVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetPassword provides a mock function for the type AuthService
+func (_mock *AuthService) ResetPassword(ctx context.Context, resetToken string, newPassword string) error {
+	ret := _mock.Called(ctx, resetToken, newPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, resetToken, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthService_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
+type AuthService_ResetPassword_Call struct {
+	*mock.Call
+}
+
+// ResetPassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - resetToken string
+//   - newPassword string
+func (_e *AuthService_Expecter) ResetPassword(ctx interface{}, resetToken interface{}, newPassword interface{}) *AuthService_ResetPassword_Call {
+	return &AuthService_ResetPassword_Call{Call: _e.mock.On("ResetPassword", ctx, resetToken, newPassword)}
+}
+
+func (_c *AuthService_ResetPassword_Call) Run(run func(ctx context.Context, resetToken string, newPassword string)) *AuthService_ResetPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthService_ResetPassword_Call) Return(err error) *AuthService_ResetPassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthService_ResetPassword_Call) RunAndReturn(run func(ctx context.Context, resetToken string, newPassword string) error) *AuthService_ResetPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ValidateToken provides a mock function for the type AuthService
+func (_mock *AuthService) ValidateToken(ctx context.Context, token string) (*models.User, error) {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateToken")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
+		return returnFunc(ctx, token)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthService_ValidateToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateToken'
+type AuthService_ValidateToken_Call struct {
+	*mock.Call
+}
+
+// ValidateToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *AuthService_Expecter) ValidateToken(ctx interface{}, token interface{}) *AuthService_ValidateToken_Call {
+	return &AuthService_ValidateToken_Call{Call: _e.mock.On("ValidateToken", ctx, token)}
+}
+
+func (_c *AuthService_ValidateToken_Call) Run(run func(ctx context.Context, token string)) *AuthService_ValidateToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthService_ValidateToken_Call) Return(user *models.User, err error) *AuthService_ValidateToken_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *AuthService_ValidateToken_Call) RunAndReturn(run func(ctx context.Context, token string) (*models.User, error)) *AuthService_ValidateToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyEmail provides a mock function for the type AuthService
+func (_mock *AuthService) VerifyEmail(ctx context.Context, verificationToken string) error {
+	ret := _mock.Called(ctx, verificationToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyEmail")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, verificationToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthService_VerifyEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyEmail'
+type AuthService_VerifyEmail_Call struct {
+	*mock.Call
+}
+
+// VerifyEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - verificationToken string
+func (_e *AuthService_Expecter) VerifyEmail(ctx interface{}, verificationToken interface{}) *AuthService_VerifyEmail_Call {
+	return &AuthService_VerifyEmail_Call{Call: _e.mock.On("VerifyEmail", ctx, verificationToken)}
+}
+
+func (_c *AuthService_VerifyEmail_Call) Run(run func(ctx context.Context, verificationToken string)) *AuthService_VerifyEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthService_VerifyEmail_Call) Return(err error) *AuthService_VerifyEmail_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthService_VerifyEmail_Call) RunAndReturn(run func(ctx context.Context, verificationToken string) error) *AuthService_VerifyEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyLoginTOTP provides a mock function for the type AuthService
+func (_mock *AuthService) VerifyLoginTOTP(ctx context.Context, challengeToken string, code string) (*services.LoginResult, error) {
+	ret := _mock.Called(ctx, challengeToken, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyLoginTOTP")
+	}
+
+	var r0 *services.LoginResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*services.LoginResult, error)); ok {
+		return returnFunc(ctx, challengeToken, code)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *services.LoginResult); ok {
+		r0 = returnFunc(ctx, challengeToken, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*services.LoginResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, challengeToken, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthService_VerifyLoginTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyLoginTOTP'
+type AuthService_VerifyLoginTOTP_Call struct {
+	*mock.Call
+}
+
+// VerifyLoginTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - challengeToken string
+//   - code string
+func (_e *AuthService_Expecter) VerifyLoginTOTP(ctx interface{}, challengeToken interface{}, code interface{}) *AuthService_VerifyLoginTOTP_Call {
+	return &AuthService_VerifyLoginTOTP_Call{Call: _e.mock.On("VerifyLoginTOTP", ctx, challengeToken, code)}
+}
+
+func (_c *AuthService_VerifyLoginTOTP_Call) Run(run func(ctx context.Context, challengeToken string, code string)) *AuthService_VerifyLoginTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthService_VerifyLoginTOTP_Call) Return(loginResult *services.LoginResult, err error) *AuthService_VerifyLoginTOTP_Call {
+	_c.Call.Return(loginResult, err)
+	return _c
+}
+
+func (_c *AuthService_VerifyLoginTOTP_Call) RunAndReturn(run func(ctx context.Context, challengeToken string, code string) (*services.LoginResult, error)) *AuthService_VerifyLoginTOTP_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -289,27 +289,27 @@ func (_c *BorrowerService_GetBorrowerByID_Call) RunAndReturn(run func(ctx contex
 }
 
 // ListBorrowers provides a mock function for the type BorrowerService
-func (_mock *BorrowerService) ListBorrowers(ctx context.Context, offset int, limit int) ([]*models.Borrower, error) {
-	ret := _mock.Called(ctx, offset, limit)
+func (_mock *BorrowerService) ListBorrowers(ctx context.Context, page models.PageRequest) (*models.Page[*models.Borrower], error) {
+	ret := _mock.Called(ctx, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListBorrowers")
 	}
 
-	var r0 []*models.Borrower
+	var r0 *models.Page[*models.Borrower]
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]*models.Borrower, error)); ok {
-		return returnFunc(ctx, offset, limit)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PageRequest) (*models.Page[*models.Borrower], error)); ok {
+		return returnFunc(ctx, page)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []*models.Borrower); ok {
-		r0 = returnFunc(ctx, offset, limit)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PageRequest) *models.Page[*models.Borrower]); ok {
+		r0 = returnFunc(ctx, page)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Borrower)
+			r0 = ret.Get(0).(*models.Page[*models.Borrower])
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
-		r1 = returnFunc(ctx, offset, limit)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.PageRequest) error); ok {
+		r1 = returnFunc(ctx, page)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -323,41 +323,35 @@ type BorrowerService_ListBorrowers_Call struct {
 
 // ListBorrowers is a helper method to define mock.On call
 //   - ctx context.Context
-//   - offset int
-//   - limit int
-func (_e *BorrowerService_Expecter) ListBorrowers(ctx interface{}, offset interface{}, limit interface{}) *BorrowerService_ListBorrowers_Call {
-	return &BorrowerService_ListBorrowers_Call{Call: _e.mock.On("ListBorrowers", ctx, offset, limit)}
+//   - page models.PageRequest
+func (_e *BorrowerService_Expecter) ListBorrowers(ctx interface{}, page interface{}) *BorrowerService_ListBorrowers_Call {
+	return &BorrowerService_ListBorrowers_Call{Call: _e.mock.On("ListBorrowers", ctx, page)}
 }
 
-func (_c *BorrowerService_ListBorrowers_Call) Run(run func(ctx context.Context, offset int, limit int)) *BorrowerService_ListBorrowers_Call {
+func (_c *BorrowerService_ListBorrowers_Call) Run(run func(ctx context.Context, page models.PageRequest)) *BorrowerService_ListBorrowers_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 int
+		var arg1 models.PageRequest
 		if args[1] != nil {
-			arg1 = args[1].(int)
-		}
-		var arg2 int
-		if args[2] != nil {
-			arg2 = args[2].(int)
+			arg1 = args[1].(models.PageRequest)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *BorrowerService_ListBorrowers_Call) Return(borrowers []*models.Borrower, err error) *BorrowerService_ListBorrowers_Call {
-	_c.Call.Return(borrowers, err)
+func (_c *BorrowerService_ListBorrowers_Call) Return(v *models.Page[*models.Borrower], err error) *BorrowerService_ListBorrowers_Call {
+	_c.Call.Return(v, err)
 	return _c
 }
 
-func (_c *BorrowerService_ListBorrowers_Call) RunAndReturn(run func(ctx context.Context, offset int, limit int) ([]*models.Borrower, error)) *BorrowerService_ListBorrowers_Call {
+func (_c *BorrowerService_ListBorrowers_Call) RunAndReturn(run func(ctx context.Context, page models.PageRequest) (*models.Page[*models.Borrower], error)) *BorrowerService_ListBorrowers_Call {
 	_c.Call.Return(run)
 	return _c
 }
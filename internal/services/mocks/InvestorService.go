@@ -357,27 +357,27 @@ func (_c *InvestorService_GetInvestorByInvestorID_Call) RunAndReturn(run func(ct
 }
 
 // ListInvestors provides a mock function for the type InvestorService
-func (_mock *InvestorService) ListInvestors(ctx context.Context, offset int, limit int) ([]*models.Investor, error) {
-	ret := _mock.Called(ctx, offset, limit)
+func (_mock *InvestorService) ListInvestors(ctx context.Context, page models.PageRequest) (*models.Page[*models.Investor], error) {
+	ret := _mock.Called(ctx, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListInvestors")
 	}
 
-	var r0 []*models.Investor
+	var r0 *models.Page[*models.Investor]
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]*models.Investor, error)); ok {
-		return returnFunc(ctx, offset, limit)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PageRequest) (*models.Page[*models.Investor], error)); ok {
+		return returnFunc(ctx, page)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []*models.Investor); ok {
-		r0 = returnFunc(ctx, offset, limit)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PageRequest) *models.Page[*models.Investor]); ok {
+		r0 = returnFunc(ctx, page)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Investor)
+			r0 = ret.Get(0).(*models.Page[*models.Investor])
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
-		r1 = returnFunc(ctx, offset, limit)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.PageRequest) error); ok {
+		r1 = returnFunc(ctx, page)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -391,13 +391,80 @@ type InvestorService_ListInvestors_Call struct {
 
 // ListInvestors is a helper method to define mock.On call
 //   - ctx context.Context
-//   - offset int
-//   - limit int
-func (_e *InvestorService_Expecter) ListInvestors(ctx interface{}, offset interface{}, limit interface{}) *InvestorService_ListInvestors_Call {
-	return &InvestorService_ListInvestors_Call{Call: _e.mock.On("ListInvestors", ctx, offset, limit)}
+//   - page models.PageRequest
+func (_e *InvestorService_Expecter) ListInvestors(ctx interface{}, page interface{}) *InvestorService_ListInvestors_Call {
+	return &InvestorService_ListInvestors_Call{Call: _e.mock.On("ListInvestors", ctx, page)}
 }
 
-func (_c *InvestorService_ListInvestors_Call) Run(run func(ctx context.Context, offset int, limit int)) *InvestorService_ListInvestors_Call {
+func (_c *InvestorService_ListInvestors_Call) Run(run func(ctx context.Context, page models.PageRequest)) *InvestorService_ListInvestors_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.PageRequest
+		if args[1] != nil {
+			arg1 = args[1].(models.PageRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *InvestorService_ListInvestors_Call) Return(v *models.Page[*models.Investor], err error) *InvestorService_ListInvestors_Call {
+	_c.Call.Return(v, err)
+	return _c
+}
+
+func (_c *InvestorService_ListInvestors_Call) RunAndReturn(run func(ctx context.Context, page models.PageRequest) (*models.Page[*models.Investor], error)) *InvestorService_ListInvestors_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPayouts provides a mock function for the type InvestorService
+func (_mock *InvestorService) ListPayouts(ctx context.Context, id int) ([]*models.Payout, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPayouts")
+	}
+
+	var r0 []*models.Payout
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]*models.Payout, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []*models.Payout); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Payout)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// InvestorService_ListPayouts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPayouts'
+type InvestorService_ListPayouts_Call struct {
+	*mock.Call
+}
+
+// ListPayouts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *InvestorService_Expecter) ListPayouts(ctx interface{}, id interface{}) *InvestorService_ListPayouts_Call {
+	return &InvestorService_ListPayouts_Call{Call: _e.mock.On("ListPayouts", ctx, id)}
+}
+
+func (_c *InvestorService_ListPayouts_Call) Run(run func(ctx context.Context, id int)) *InvestorService_ListPayouts_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -407,25 +474,20 @@ func (_c *InvestorService_ListInvestors_Call) Run(run func(ctx context.Context,
 		if args[1] != nil {
 			arg1 = args[1].(int)
 		}
-		var arg2 int
-		if args[2] != nil {
-			arg2 = args[2].(int)
-		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *InvestorService_ListInvestors_Call) Return(investors []*models.Investor, err error) *InvestorService_ListInvestors_Call {
-	_c.Call.Return(investors, err)
+func (_c *InvestorService_ListPayouts_Call) Return(payouts []*models.Payout, err error) *InvestorService_ListPayouts_Call {
+	_c.Call.Return(payouts, err)
 	return _c
 }
 
-func (_c *InvestorService_ListInvestors_Call) RunAndReturn(run func(ctx context.Context, offset int, limit int) ([]*models.Investor, error)) *InvestorService_ListInvestors_Call {
+func (_c *InvestorService_ListPayouts_Call) RunAndReturn(run func(ctx context.Context, id int) ([]*models.Payout, error)) *InvestorService_ListPayouts_Call {
 	_c.Call.Return(run)
 	return _c
 }
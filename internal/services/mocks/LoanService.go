@@ -8,6 +8,7 @@ import (
 	"context"
 
 	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -173,6 +174,132 @@ func (_c *LoanService_CanTransitionToState_Call) RunAndReturn(run func(ctx conte
 	return _c
 }
 
+// CancelLoan provides a mock function for the type LoanService
+func (_mock *LoanService) CancelLoan(ctx context.Context, loanID int, reason string) error {
+	ret := _mock.Called(ctx, loanID, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelLoan")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = returnFunc(ctx, loanID, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// LoanService_CancelLoan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelLoan'
+type LoanService_CancelLoan_Call struct {
+	*mock.Call
+}
+
+// CancelLoan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - loanID int
+//   - reason string
+func (_e *LoanService_Expecter) CancelLoan(ctx interface{}, loanID interface{}, reason interface{}) *LoanService_CancelLoan_Call {
+	return &LoanService_CancelLoan_Call{Call: _e.mock.On("CancelLoan", ctx, loanID, reason)}
+}
+
+func (_c *LoanService_CancelLoan_Call) Run(run func(ctx context.Context, loanID int, reason string)) *LoanService_CancelLoan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *LoanService_CancelLoan_Call) Return(err error) *LoanService_CancelLoan_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *LoanService_CancelLoan_Call) RunAndReturn(run func(ctx context.Context, loanID int, reason string) error) *LoanService_CancelLoan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CloseLoan provides a mock function for the type LoanService
+func (_mock *LoanService) CloseLoan(ctx context.Context, loanID int, reason string) error {
+	ret := _mock.Called(ctx, loanID, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CloseLoan")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = returnFunc(ctx, loanID, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// LoanService_CloseLoan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CloseLoan'
+type LoanService_CloseLoan_Call struct {
+	*mock.Call
+}
+
+// CloseLoan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - loanID int
+//   - reason string
+func (_e *LoanService_Expecter) CloseLoan(ctx interface{}, loanID interface{}, reason interface{}) *LoanService_CloseLoan_Call {
+	return &LoanService_CloseLoan_Call{Call: _e.mock.On("CloseLoan", ctx, loanID, reason)}
+}
+
+func (_c *LoanService_CloseLoan_Call) Run(run func(ctx context.Context, loanID int, reason string)) *LoanService_CloseLoan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *LoanService_CloseLoan_Call) Return(err error) *LoanService_CloseLoan_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *LoanService_CloseLoan_Call) RunAndReturn(run func(ctx context.Context, loanID int, reason string) error) *LoanService_CloseLoan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateLoan provides a mock function for the type LoanService
 func (_mock *LoanService) CreateLoan(ctx context.Context, loan *models.Loan) error {
 	ret := _mock.Called(ctx, loan)
@@ -230,6 +357,126 @@ func (_c *LoanService_CreateLoan_Call) RunAndReturn(run func(ctx context.Context
 	return _c
 }
 
+// CreateRejectionReason provides a mock function for the type LoanService
+func (_mock *LoanService) CreateRejectionReason(ctx context.Context, reason *models.LoanRejectionReason) error {
+	ret := _mock.Called(ctx, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRejectionReason")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.LoanRejectionReason) error); ok {
+		r0 = returnFunc(ctx, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// LoanService_CreateRejectionReason_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRejectionReason'
+type LoanService_CreateRejectionReason_Call struct {
+	*mock.Call
+}
+
+// CreateRejectionReason is a helper method to define mock.On call
+//   - ctx context.Context
+//   - reason *models.LoanRejectionReason
+func (_e *LoanService_Expecter) CreateRejectionReason(ctx interface{}, reason interface{}) *LoanService_CreateRejectionReason_Call {
+	return &LoanService_CreateRejectionReason_Call{Call: _e.mock.On("CreateRejectionReason", ctx, reason)}
+}
+
+func (_c *LoanService_CreateRejectionReason_Call) Run(run func(ctx context.Context, reason *models.LoanRejectionReason)) *LoanService_CreateRejectionReason_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *models.LoanRejectionReason
+		if args[1] != nil {
+			arg1 = args[1].(*models.LoanRejectionReason)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *LoanService_CreateRejectionReason_Call) Return(err error) *LoanService_CreateRejectionReason_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *LoanService_CreateRejectionReason_Call) RunAndReturn(run func(ctx context.Context, reason *models.LoanRejectionReason) error) *LoanService_CreateRejectionReason_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DefaultLoan provides a mock function for the type LoanService
+func (_mock *LoanService) DefaultLoan(ctx context.Context, loanID int, reason string) error {
+	ret := _mock.Called(ctx, loanID, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DefaultLoan")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = returnFunc(ctx, loanID, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// LoanService_DefaultLoan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DefaultLoan'
+type LoanService_DefaultLoan_Call struct {
+	*mock.Call
+}
+
+// DefaultLoan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - loanID int
+//   - reason string
+func (_e *LoanService_Expecter) DefaultLoan(ctx interface{}, loanID interface{}, reason interface{}) *LoanService_DefaultLoan_Call {
+	return &LoanService_DefaultLoan_Call{Call: _e.mock.On("DefaultLoan", ctx, loanID, reason)}
+}
+
+func (_c *LoanService_DefaultLoan_Call) Run(run func(ctx context.Context, loanID int, reason string)) *LoanService_DefaultLoan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *LoanService_DefaultLoan_Call) Return(err error) *LoanService_DefaultLoan_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *LoanService_DefaultLoan_Call) RunAndReturn(run func(ctx context.Context, loanID int, reason string) error) *LoanService_DefaultLoan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteLoan provides a mock function for the type LoanService
 func (_mock *LoanService) DeleteLoan(ctx context.Context, id int) error {
 	ret := _mock.Called(ctx, id)
@@ -350,6 +597,69 @@ func (_c *LoanService_DisburseLoan_Call) RunAndReturn(run func(ctx context.Conte
 	return _c
 }
 
+// ExpireLoan provides a mock function for the type LoanService
+func (_mock *LoanService) ExpireLoan(ctx context.Context, loanID int, reason string) error {
+	ret := _mock.Called(ctx, loanID, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireLoan")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = returnFunc(ctx, loanID, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// LoanService_ExpireLoan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExpireLoan'
+type LoanService_ExpireLoan_Call struct {
+	*mock.Call
+}
+
+// ExpireLoan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - loanID int
+//   - reason string
+func (_e *LoanService_Expecter) ExpireLoan(ctx interface{}, loanID interface{}, reason interface{}) *LoanService_ExpireLoan_Call {
+	return &LoanService_ExpireLoan_Call{Call: _e.mock.On("ExpireLoan", ctx, loanID, reason)}
+}
+
+func (_c *LoanService_ExpireLoan_Call) Run(run func(ctx context.Context, loanID int, reason string)) *LoanService_ExpireLoan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *LoanService_ExpireLoan_Call) Return(err error) *LoanService_ExpireLoan_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *LoanService_ExpireLoan_Call) RunAndReturn(run func(ctx context.Context, loanID int, reason string) error) *LoanService_ExpireLoan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetLoanByID provides a mock function for the type LoanService
 func (_mock *LoanService) GetLoanByID(ctx context.Context, id int) (*models.Loan, error) {
 	ret := _mock.Called(ctx, id)
@@ -554,23 +864,25 @@ func (_c *LoanService_GetLoansByState_Call) RunAndReturn(run func(ctx context.Co
 	return _c
 }
 
-// GetTotalInvestedAmount provides a mock function for the type LoanService
-func (_mock *LoanService) GetTotalInvestedAmount(ctx context.Context, loanID int) (float64, error) {
+// GetRepaymentSchedule provides a mock function for the type LoanService
+func (_mock *LoanService) GetRepaymentSchedule(ctx context.Context, loanID int) ([]*models.LoanInstallment, error) {
 	ret := _mock.Called(ctx, loanID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTotalInvestedAmount")
+		panic("no return value specified for GetRepaymentSchedule")
 	}
 
-	var r0 float64
+	var r0 []*models.LoanInstallment
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (float64, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]*models.LoanInstallment, error)); ok {
 		return returnFunc(ctx, loanID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) float64); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []*models.LoanInstallment); ok {
 		r0 = returnFunc(ctx, loanID)
 	} else {
-		r0 = ret.Get(0).(float64)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.LoanInstallment)
+		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
 		r1 = returnFunc(ctx, loanID)
@@ -580,19 +892,19 @@ func (_mock *LoanService) GetTotalInvestedAmount(ctx context.Context, loanID int
 	return r0, r1
 }
 
-// LoanService_GetTotalInvestedAmount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTotalInvestedAmount'
-type LoanService_GetTotalInvestedAmount_Call struct {
+// LoanService_GetRepaymentSchedule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRepaymentSchedule'
+type LoanService_GetRepaymentSchedule_Call struct {
 	*mock.Call
 }
 
-// GetTotalInvestedAmount is a helper method to define mock.On call
+// GetRepaymentSchedule is a helper method to define mock.On call
 //   - ctx context.Context
 //   - loanID int
-func (_e *LoanService_Expecter) GetTotalInvestedAmount(ctx interface{}, loanID interface{}) *LoanService_GetTotalInvestedAmount_Call {
-	return &LoanService_GetTotalInvestedAmount_Call{Call: _e.mock.On("GetTotalInvestedAmount", ctx, loanID)}
+func (_e *LoanService_Expecter) GetRepaymentSchedule(ctx interface{}, loanID interface{}) *LoanService_GetRepaymentSchedule_Call {
+	return &LoanService_GetRepaymentSchedule_Call{Call: _e.mock.On("GetRepaymentSchedule", ctx, loanID)}
 }
 
-func (_c *LoanService_GetTotalInvestedAmount_Call) Run(run func(ctx context.Context, loanID int)) *LoanService_GetTotalInvestedAmount_Call {
+func (_c *LoanService_GetRepaymentSchedule_Call) Run(run func(ctx context.Context, loanID int)) *LoanService_GetRepaymentSchedule_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -610,29 +922,95 @@ func (_c *LoanService_GetTotalInvestedAmount_Call) Run(run func(ctx context.Cont
 	return _c
 }
 
-func (_c *LoanService_GetTotalInvestedAmount_Call) Return(f float64, err error) *LoanService_GetTotalInvestedAmount_Call {
-	_c.Call.Return(f, err)
+func (_c *LoanService_GetRepaymentSchedule_Call) Return(loanInstallments []*models.LoanInstallment, err error) *LoanService_GetRepaymentSchedule_Call {
+	_c.Call.Return(loanInstallments, err)
 	return _c
 }
 
-func (_c *LoanService_GetTotalInvestedAmount_Call) RunAndReturn(run func(ctx context.Context, loanID int) (float64, error)) *LoanService_GetTotalInvestedAmount_Call {
+func (_c *LoanService_GetRepaymentSchedule_Call) RunAndReturn(run func(ctx context.Context, loanID int) ([]*models.LoanInstallment, error)) *LoanService_GetRepaymentSchedule_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// InvestInLoan provides a mock function for the type LoanService
-func (_mock *LoanService) InvestInLoan(ctx context.Context, loanID int, investment *models.LoanInvestment) error {
-	ret := _mock.Called(ctx, loanID, investment)
+// GetTotalInvestedAmount provides a mock function for the type LoanService
+func (_mock *LoanService) GetTotalInvestedAmount(ctx context.Context, loanID int) (money.Money, error) {
+	ret := _mock.Called(ctx, loanID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for InvestInLoan")
+		panic("no return value specified for GetTotalInvestedAmount")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, *models.LoanInvestment) error); ok {
-		r0 = returnFunc(ctx, loanID, investment)
-	} else {
-		r0 = ret.Error(0)
+	var r0 money.Money
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (money.Money, error)); ok {
+		return returnFunc(ctx, loanID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) money.Money); ok {
+		r0 = returnFunc(ctx, loanID)
+	} else {
+		r0 = ret.Get(0).(money.Money)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, loanID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// LoanService_GetTotalInvestedAmount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTotalInvestedAmount'
+type LoanService_GetTotalInvestedAmount_Call struct {
+	*mock.Call
+}
+
+// GetTotalInvestedAmount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - loanID int
+func (_e *LoanService_Expecter) GetTotalInvestedAmount(ctx interface{}, loanID interface{}) *LoanService_GetTotalInvestedAmount_Call {
+	return &LoanService_GetTotalInvestedAmount_Call{Call: _e.mock.On("GetTotalInvestedAmount", ctx, loanID)}
+}
+
+func (_c *LoanService_GetTotalInvestedAmount_Call) Run(run func(ctx context.Context, loanID int)) *LoanService_GetTotalInvestedAmount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *LoanService_GetTotalInvestedAmount_Call) Return(money money.Money, err error) *LoanService_GetTotalInvestedAmount_Call {
+	_c.Call.Return(money, err)
+	return _c
+}
+
+func (_c *LoanService_GetTotalInvestedAmount_Call) RunAndReturn(run func(ctx context.Context, loanID int) (money.Money, error)) *LoanService_GetTotalInvestedAmount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InvestInLoan provides a mock function for the type LoanService
+func (_mock *LoanService) InvestInLoan(ctx context.Context, loanID int, investment *models.LoanInvestment) error {
+	ret := _mock.Called(ctx, loanID, investment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InvestInLoan")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, *models.LoanInvestment) error); ok {
+		r0 = returnFunc(ctx, loanID, investment)
+	} else {
+		r0 = ret.Error(0)
 	}
 	return r0
 }
@@ -684,27 +1062,27 @@ func (_c *LoanService_InvestInLoan_Call) RunAndReturn(run func(ctx context.Conte
 }
 
 // ListLoans provides a mock function for the type LoanService
-func (_mock *LoanService) ListLoans(ctx context.Context, state *string, offset int, limit int) ([]*models.Loan, error) {
-	ret := _mock.Called(ctx, state, offset, limit)
+func (_mock *LoanService) ListLoans(ctx context.Context, filter models.LoanFilter, page models.PageRequest) (*models.Page[*models.Loan], error) {
+	ret := _mock.Called(ctx, filter, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListLoans")
 	}
 
-	var r0 []*models.Loan
+	var r0 *models.Page[*models.Loan]
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *string, int, int) ([]*models.Loan, error)); ok {
-		return returnFunc(ctx, state, offset, limit)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.LoanFilter, models.PageRequest) (*models.Page[*models.Loan], error)); ok {
+		return returnFunc(ctx, filter, page)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *string, int, int) []*models.Loan); ok {
-		r0 = returnFunc(ctx, state, offset, limit)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.LoanFilter, models.PageRequest) *models.Page[*models.Loan]); ok {
+		r0 = returnFunc(ctx, filter, page)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Loan)
+			r0 = ret.Get(0).(*models.Page[*models.Loan])
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *string, int, int) error); ok {
-		r1 = returnFunc(ctx, state, offset, limit)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.LoanFilter, models.PageRequest) error); ok {
+		r1 = returnFunc(ctx, filter, page)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -718,47 +1096,235 @@ type LoanService_ListLoans_Call struct {
 
 // ListLoans is a helper method to define mock.On call
 //   - ctx context.Context
-//   - state *string
-//   - offset int
-//   - limit int
-func (_e *LoanService_Expecter) ListLoans(ctx interface{}, state interface{}, offset interface{}, limit interface{}) *LoanService_ListLoans_Call {
-	return &LoanService_ListLoans_Call{Call: _e.mock.On("ListLoans", ctx, state, offset, limit)}
+//   - filter models.LoanFilter
+//   - page models.PageRequest
+func (_e *LoanService_Expecter) ListLoans(ctx interface{}, filter interface{}, page interface{}) *LoanService_ListLoans_Call {
+	return &LoanService_ListLoans_Call{Call: _e.mock.On("ListLoans", ctx, filter, page)}
 }
 
-func (_c *LoanService_ListLoans_Call) Run(run func(ctx context.Context, state *string, offset int, limit int)) *LoanService_ListLoans_Call {
+func (_c *LoanService_ListLoans_Call) Run(run func(ctx context.Context, filter models.LoanFilter, page models.PageRequest)) *LoanService_ListLoans_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *string
+		var arg1 models.LoanFilter
 		if args[1] != nil {
-			arg1 = args[1].(*string)
+			arg1 = args[1].(models.LoanFilter)
 		}
-		var arg2 int
+		var arg2 models.PageRequest
 		if args[2] != nil {
-			arg2 = args[2].(int)
+			arg2 = args[2].(models.PageRequest)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *LoanService_ListLoans_Call) Return(v *models.Page[*models.Loan], err error) *LoanService_ListLoans_Call {
+	_c.Call.Return(v, err)
+	return _c
+}
+
+func (_c *LoanService_ListLoans_Call) RunAndReturn(run func(ctx context.Context, filter models.LoanFilter, page models.PageRequest) (*models.Page[*models.Loan], error)) *LoanService_ListLoans_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRejectionReasons provides a mock function for the type LoanService
+func (_mock *LoanService) ListRejectionReasons(ctx context.Context, activeOnly bool) ([]*models.LoanRejectionReason, error) {
+	ret := _mock.Called(ctx, activeOnly)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRejectionReasons")
+	}
+
+	var r0 []*models.LoanRejectionReason
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bool) ([]*models.LoanRejectionReason, error)); ok {
+		return returnFunc(ctx, activeOnly)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bool) []*models.LoanRejectionReason); ok {
+		r0 = returnFunc(ctx, activeOnly)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.LoanRejectionReason)
 		}
-		var arg3 int
-		if args[3] != nil {
-			arg3 = args[3].(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, bool) error); ok {
+		r1 = returnFunc(ctx, activeOnly)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// LoanService_ListRejectionReasons_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRejectionReasons'
+type LoanService_ListRejectionReasons_Call struct {
+	*mock.Call
+}
+
+// ListRejectionReasons is a helper method to define mock.On call
+//   - ctx context.Context
+//   - activeOnly bool
+func (_e *LoanService_Expecter) ListRejectionReasons(ctx interface{}, activeOnly interface{}) *LoanService_ListRejectionReasons_Call {
+	return &LoanService_ListRejectionReasons_Call{Call: _e.mock.On("ListRejectionReasons", ctx, activeOnly)}
+}
+
+func (_c *LoanService_ListRejectionReasons_Call) Run(run func(ctx context.Context, activeOnly bool)) *LoanService_ListRejectionReasons_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 bool
+		if args[1] != nil {
+			arg1 = args[1].(bool)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *LoanService_ListRejectionReasons_Call) Return(loanRejectionReasons []*models.LoanRejectionReason, err error) *LoanService_ListRejectionReasons_Call {
+	_c.Call.Return(loanRejectionReasons, err)
+	return _c
+}
+
+func (_c *LoanService_ListRejectionReasons_Call) RunAndReturn(run func(ctx context.Context, activeOnly bool) ([]*models.LoanRejectionReason, error)) *LoanService_ListRejectionReasons_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkLoanRepaid provides a mock function for the type LoanService
+func (_mock *LoanService) MarkLoanRepaid(ctx context.Context, loanID int, reason string) error {
+	ret := _mock.Called(ctx, loanID, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkLoanRepaid")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = returnFunc(ctx, loanID, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// LoanService_MarkLoanRepaid_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkLoanRepaid'
+type LoanService_MarkLoanRepaid_Call struct {
+	*mock.Call
+}
+
+// MarkLoanRepaid is a helper method to define mock.On call
+//   - ctx context.Context
+//   - loanID int
+//   - reason string
+func (_e *LoanService_Expecter) MarkLoanRepaid(ctx interface{}, loanID interface{}, reason interface{}) *LoanService_MarkLoanRepaid_Call {
+	return &LoanService_MarkLoanRepaid_Call{Call: _e.mock.On("MarkLoanRepaid", ctx, loanID, reason)}
+}
+
+func (_c *LoanService_MarkLoanRepaid_Call) Run(run func(ctx context.Context, loanID int, reason string)) *LoanService_MarkLoanRepaid_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *LoanService_ListLoans_Call) Return(loans []*models.Loan, err error) *LoanService_ListLoans_Call {
-	_c.Call.Return(loans, err)
+func (_c *LoanService_MarkLoanRepaid_Call) Return(err error) *LoanService_MarkLoanRepaid_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *LoanService_MarkLoanRepaid_Call) RunAndReturn(run func(ctx context.Context, loanID int, reason string) error) *LoanService_MarkLoanRepaid_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RejectLoan provides a mock function for the type LoanService
+func (_mock *LoanService) RejectLoan(ctx context.Context, loanID int, rejection *models.LoanRejection) error {
+	ret := _mock.Called(ctx, loanID, rejection)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RejectLoan")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, *models.LoanRejection) error); ok {
+		r0 = returnFunc(ctx, loanID, rejection)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// LoanService_RejectLoan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RejectLoan'
+type LoanService_RejectLoan_Call struct {
+	*mock.Call
+}
+
+// RejectLoan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - loanID int
+//   - rejection *models.LoanRejection
+func (_e *LoanService_Expecter) RejectLoan(ctx interface{}, loanID interface{}, rejection interface{}) *LoanService_RejectLoan_Call {
+	return &LoanService_RejectLoan_Call{Call: _e.mock.On("RejectLoan", ctx, loanID, rejection)}
+}
+
+func (_c *LoanService_RejectLoan_Call) Run(run func(ctx context.Context, loanID int, rejection *models.LoanRejection)) *LoanService_RejectLoan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 *models.LoanRejection
+		if args[2] != nil {
+			arg2 = args[2].(*models.LoanRejection)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
 	return _c
 }
 
-func (_c *LoanService_ListLoans_Call) RunAndReturn(run func(ctx context.Context, state *string, offset int, limit int) ([]*models.Loan, error)) *LoanService_ListLoans_Call {
+func (_c *LoanService_RejectLoan_Call) Return(err error) *LoanService_RejectLoan_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *LoanService_RejectLoan_Call) RunAndReturn(run func(ctx context.Context, loanID int, rejection *models.LoanRejection) error) *LoanService_RejectLoan_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -825,3 +1391,129 @@ func (_c *LoanService_UpdateLoan_Call) RunAndReturn(run func(ctx context.Context
 	_c.Call.Return(run)
 	return _c
 }
+
+// UpdateRejectionReason provides a mock function for the type LoanService
+func (_mock *LoanService) UpdateRejectionReason(ctx context.Context, code string, reason *models.LoanRejectionReason) error {
+	ret := _mock.Called(ctx, code, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRejectionReason")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *models.LoanRejectionReason) error); ok {
+		r0 = returnFunc(ctx, code, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// LoanService_UpdateRejectionReason_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRejectionReason'
+type LoanService_UpdateRejectionReason_Call struct {
+	*mock.Call
+}
+
+// UpdateRejectionReason is a helper method to define mock.On call
+//   - ctx context.Context
+//   - code string
+//   - reason *models.LoanRejectionReason
+func (_e *LoanService_Expecter) UpdateRejectionReason(ctx interface{}, code interface{}, reason interface{}) *LoanService_UpdateRejectionReason_Call {
+	return &LoanService_UpdateRejectionReason_Call{Call: _e.mock.On("UpdateRejectionReason", ctx, code, reason)}
+}
+
+func (_c *LoanService_UpdateRejectionReason_Call) Run(run func(ctx context.Context, code string, reason *models.LoanRejectionReason)) *LoanService_UpdateRejectionReason_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 *models.LoanRejectionReason
+		if args[2] != nil {
+			arg2 = args[2].(*models.LoanRejectionReason)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *LoanService_UpdateRejectionReason_Call) Return(err error) *LoanService_UpdateRejectionReason_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *LoanService_UpdateRejectionReason_Call) RunAndReturn(run func(ctx context.Context, code string, reason *models.LoanRejectionReason) error) *LoanService_UpdateRejectionReason_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WriteOffLoan provides a mock function for the type LoanService
+func (_mock *LoanService) WriteOffLoan(ctx context.Context, loanID int, reason string) error {
+	ret := _mock.Called(ctx, loanID, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WriteOffLoan")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = returnFunc(ctx, loanID, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// LoanService_WriteOffLoan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WriteOffLoan'
+type LoanService_WriteOffLoan_Call struct {
+	*mock.Call
+}
+
+// WriteOffLoan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - loanID int
+//   - reason string
+func (_e *LoanService_Expecter) WriteOffLoan(ctx interface{}, loanID interface{}, reason interface{}) *LoanService_WriteOffLoan_Call {
+	return &LoanService_WriteOffLoan_Call{Call: _e.mock.On("WriteOffLoan", ctx, loanID, reason)}
+}
+
+func (_c *LoanService_WriteOffLoan_Call) Run(run func(ctx context.Context, loanID int, reason string)) *LoanService_WriteOffLoan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *LoanService_WriteOffLoan_Call) Return(err error) *LoanService_WriteOffLoan_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *LoanService_WriteOffLoan_Call) RunAndReturn(run func(ctx context.Context, loanID int, reason string) error) *LoanService_WriteOffLoan_Call {
+	_c.Call.Return(run)
+	return _c
+}
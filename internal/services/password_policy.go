@@ -0,0 +1,50 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+)
+
+// Password policy: long enough to resist guessing, short enough for bcrypt, which ignores
+// everything past 72 bytes, and mixing letters with digits
+const (
+	minPasswordLength = 12
+	maxPasswordBytes  = 72
+)
+
+var ErrWeakPassword = apperr.Validation("weak_password", "password does not meet the password policy")
+
+// ValidatePassword checks a new password for the account with the given email against the password policy
+func ValidatePassword(password, email string) error {
+	if utf8.RuneCountInString(password) < minPasswordLength {
+		return fmt.Errorf("%w: it must be at least %d characters long", ErrWeakPassword, minPasswordLength)
+	}
+
+	if len(password) > maxPasswordBytes {
+		return fmt.Errorf("%w: it must be at most %d bytes long", ErrWeakPassword, maxPasswordBytes)
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	if !hasLetter || !hasDigit {
+		return fmt.Errorf("%w: it must contain both letters and digits", ErrWeakPassword)
+	}
+
+	local, _, _ := strings.Cut(strings.ToLower(email), "@")
+	if len(local) >= 3 && strings.Contains(strings.ToLower(password), local) {
+		return fmt.Errorf("%w: it must not contain the email address", ErrWeakPassword)
+	}
+
+	return nil
+}
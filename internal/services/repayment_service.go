@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/ledger"
+	"github.com/sswastioyono18/loan-engine/internal/loanstate"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/repayment"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+)
+
+type RepaymentService interface {
+	RecordRepayment(ctx context.Context, loanID int, loanRepayment *models.LoanRepayment) error
+	ListRepayments(ctx context.Context, loanID int) ([]*models.LoanRepayment, error)
+}
+
+type repaymentServiceImpl struct {
+	loanRepo            LoanRepository
+	loanInstallmentRepo LoanInstallmentRepository
+	loanRepaymentRepo   LoanRepaymentRepository
+	borrowerRepo        BorrowerRepository
+	loanInvestmentRepo  LoanInvestmentRepository
+	payoutRepo          PayoutRepository
+	revenueRepo         PlatformRevenueRepository
+	unitOfWork          UnitOfWork
+	generalLedger       Ledger
+	policy              repayment.Policy
+}
+
+func NewRepaymentService(
+	loanRepo LoanRepository,
+	loanInstallmentRepo LoanInstallmentRepository,
+	loanRepaymentRepo LoanRepaymentRepository,
+	borrowerRepo BorrowerRepository,
+	loanInvestmentRepo LoanInvestmentRepository,
+	payoutRepo PayoutRepository,
+	revenueRepo PlatformRevenueRepository,
+	unitOfWork UnitOfWork,
+	generalLedger Ledger,
+	policy repayment.Policy,
+) RepaymentService {
+	return &repaymentServiceImpl{
+		loanRepo:            loanRepo,
+		loanInstallmentRepo: loanInstallmentRepo,
+		loanRepaymentRepo:   loanRepaymentRepo,
+		borrowerRepo:        borrowerRepo,
+		loanInvestmentRepo:  loanInvestmentRepo,
+		payoutRepo:          payoutRepo,
+		revenueRepo:         revenueRepo,
+		unitOfWork:          unitOfWork,
+		generalLedger:       generalLedger,
+		policy:              policy,
+	}
+}
+
+// RecordRepayment allocates a borrower payment across the loan's installments using the
+// configured waterfall. Whatever is left once every installment is settled is added to
+// the borrower's credit balance. The principal and interest collected are paid out to the
+// loan's investors and the platform's share is recorded as revenue.
+func (s *repaymentServiceImpl) RecordRepayment(ctx context.Context, loanID int, loanRepayment *models.LoanRepayment) error {
+	if !loanRepayment.Amount.IsPositive() {
+		return apperr.Validation("invalid_repayment", "repayment amount must be greater than 0")
+	}
+
+	if loanRepayment.PaidAt.IsZero() {
+		loanRepayment.PaidAt = time.Now()
+	}
+
+	return s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		// Lock the loan row so concurrent repayments see each other's allocations
+		loan, err := s.loanRepo.GetByIDForUpdate(ctx, loanID)
+		if err != nil {
+			return err
+		}
+
+		if loan.CurrentState != loanstate.Disbursed {
+			return apperr.InvalidState("invalid_loan_state", "loan must be in disbursed state to receive repayments")
+		}
+
+		if !loanRepayment.Amount.SameCurrency(loan.PrincipalAmount) {
+			return apperr.Validation("currency_mismatch", "repayment currency must be %s", loan.PrincipalAmount.Currency())
+		}
+
+		installments, err := s.loanInstallmentRepo.GetByLoanID(ctx, loanID)
+		if err != nil {
+			return fmt.Errorf("failed to get installments: %w", err)
+		}
+
+		allocation := repayment.Apply(loanRepayment.Amount, installments, loanRepayment.PaidAt, s.policy)
+
+		for _, installment := range allocation.Changed {
+			err = s.loanInstallmentRepo.Update(ctx, installment)
+			if err != nil {
+				return fmt.Errorf("failed to update installment: %w", err)
+			}
+		}
+
+		loanRepayment.LoanID = loanID
+		loanRepayment.PenaltyAmount = allocation.Penalty
+		loanRepayment.InterestAmount = allocation.Interest
+		loanRepayment.PrincipalAmount = allocation.Principal
+		loanRepayment.CreditAmount = allocation.Excess
+		err = s.loanRepaymentRepo.Create(ctx, loanRepayment)
+		if err != nil {
+			return fmt.Errorf("failed to create repayment: %w", err)
+		}
+
+		entry := ledger.NewEntry(loanID, fmt.Sprintf("loan_repayment:%d", loanRepayment.ID), "Repayment received from borrower").
+			Debit(ledger.AccountCash, loanRepayment.Amount).
+			Credit(ledger.AccountFeeIncome, loanRepayment.PenaltyAmount).
+			Credit(ledger.AccountInterestIncome, loanRepayment.InterestAmount).
+			Credit(ledger.AccountLoansReceivable, loanRepayment.PrincipalAmount).
+			Credit(ledger.AccountBorrowerCredit, loanRepayment.CreditAmount)
+		err = s.generalLedger.Post(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("failed to post repayment to ledger: %w", err)
+		}
+
+		if allocation.Excess.IsPositive() {
+			err = s.borrowerRepo.AddCreditBalance(ctx, loan.BorrowerID, allocation.Excess)
+			if err != nil {
+				return fmt.Errorf("failed to credit borrower: %w", err)
+			}
+		}
+
+		return s.distribute(ctx, loan, loanRepayment)
+	})
+}
+
+// distribute pays the investors their pro rata share of a repayment and records the platform revenue
+func (s *repaymentServiceImpl) distribute(ctx context.Context, loan *models.Loan, loanRepayment *models.LoanRepayment) error {
+	investments, err := s.loanInvestmentRepo.GetByLoanID(ctx, loan.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get investments: %w", err)
+	}
+
+	distribution, err := repayment.Distribute(loan, loanRepayment, investments)
+	if err != nil {
+		return fmt.Errorf("failed to distribute repayment: %w", err)
+	}
+
+	principal := money.Zero(loanRepayment.Amount.Currency())
+	interest := money.Zero(loanRepayment.Amount.Currency())
+	for _, payout := range distribution.Payouts {
+		err = s.payoutRepo.Create(ctx, payout)
+		if err != nil {
+			return fmt.Errorf("failed to create payout: %w", err)
+		}
+		principal = principal.Add(payout.PrincipalAmount)
+		interest = interest.Add(payout.InterestAmount)
+	}
+
+	// Investors get their capital back plus interest at the ROI
+	if total := principal.Add(interest); total.IsPositive() {
+		entry := ledger.NewEntry(loan.ID, fmt.Sprintf("loan_repayment:%d:payouts", loanRepayment.ID), "Repayment paid out to investors").
+			Debit(ledger.AccountInvestorCapital, principal).
+			Debit(ledger.AccountInvestorInterest, interest).
+			Credit(ledger.AccountCash, total)
+		err = s.generalLedger.Post(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("failed to post payouts to ledger: %w", err)
+		}
+	}
+
+	if distribution.Revenue.Amount.IsPositive() {
+		err = s.revenueRepo.Create(ctx, distribution.Revenue)
+		if err != nil {
+			return fmt.Errorf("failed to record platform revenue: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *repaymentServiceImpl) ListRepayments(ctx context.Context, loanID int) ([]*models.LoanRepayment, error) {
+	if _, err := s.loanRepo.GetByID(ctx, loanID); err != nil {
+		return nil, err
+	}
+
+	return s.loanRepaymentRepo.GetByLoanID(ctx, loanID)
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/ledger"
+	ledgermocks "github.com/sswastioyono18/loan-engine/internal/ledger/mocks"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/repayment"
+	"github.com/sswastioyono18/loan-engine/internal/repositories/mocks"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestInstallments(t *testing.T, loanID int) []*models.LoanInstallment {
+	installments, err := repayment.Generate(repayment.Terms{
+		Principal:   money.MustParse("200.00"),
+		AnnualRate:  money.MustParseRate("0.60"),
+		TenorMonths: 2,
+		Method:      repayment.MethodFlat,
+		StartDate:   time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	for i, installment := range installments {
+		installment.ID = i + 1
+		installment.LoanID = loanID
+	}
+	return installments
+}
+
+func newTestInvestments(loanID int) []*models.LoanInvestment {
+	return []*models.LoanInvestment{
+		{ID: 1, LoanID: loanID, InvestorID: 11, InvestmentAmount: money.MustParse("50.00")},
+		{ID: 2, LoanID: loanID, InvestorID: 12, InvestmentAmount: money.MustParse("150.00")},
+	}
+}
+
+func TestRecordRepayment(t *testing.T) {
+	mockLoanRepo := mocks.NewLoanRepository(t)
+	mockInstallmentRepo := mocks.NewLoanInstallmentRepository(t)
+	mockRepaymentRepo := mocks.NewLoanRepaymentRepository(t)
+	mockBorrowerRepo := mocks.NewBorrowerRepository(t)
+	mockInvestmentRepo := mocks.NewLoanInvestmentRepository(t)
+	mockPayoutRepo := mocks.NewPayoutRepository(t)
+	mockRevenueRepo := mocks.NewPlatformRevenueRepository(t)
+	mockUnitOfWork := newMockUnitOfWork(t)
+	mockLedger := ledgermocks.NewService(t)
+
+	service := NewRepaymentService(mockLoanRepo, mockInstallmentRepo, mockRepaymentRepo, mockBorrowerRepo, mockInvestmentRepo, mockPayoutRepo, mockRevenueRepo, mockUnitOfWork, mockLedger, repayment.DefaultPolicy())
+
+	loanID := 1
+	loan := &models.Loan{ID: loanID, BorrowerID: 7, PrincipalAmount: money.MustParse("200.00"), Rate: money.MustParseRate("0.60"), ROI: money.MustParseRate("0.48"), CurrentState: "disbursed"}
+	installments := newTestInstallments(t, loanID)
+
+	loanRepayment := &models.LoanRepayment{
+		Amount: money.MustParse("110.00"),
+		PaidAt: time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC),
+	}
+
+	mockLoanRepo.On("GetByIDForUpdate", context.Background(), loanID).Return(loan, nil)
+	mockInstallmentRepo.On("GetByLoanID", context.Background(), loanID).Return(installments, nil)
+	mockInstallmentRepo.On("Update", context.Background(), mock.MatchedBy(func(installment *models.LoanInstallment) bool {
+		return installment.ID == 1 && installment.Status == "paid"
+	})).Return(nil).Once()
+	mockRepaymentRepo.On("Create", context.Background(), loanRepayment).Return(nil)
+	mockInvestmentRepo.On("GetByLoanID", context.Background(), loanID).Return(newTestInvestments(loanID), nil)
+
+	// 10.00 interest at ROI 0.48 of Rate 0.60 leaves 8.00 for investors and 2.00 for the platform
+	var payouts []*models.Payout
+	mockPayoutRepo.On("Create", context.Background(), mock.AnythingOfType("*models.Payout")).
+		Run(func(args mock.Arguments) { payouts = append(payouts, args.Get(1).(*models.Payout)) }).
+		Return(nil).Times(2)
+	mockRevenueRepo.On("Create", context.Background(), mock.MatchedBy(func(revenue *models.PlatformRevenue) bool {
+		return revenue.LoanID == loanID && revenue.Amount.Equal(money.MustParse("2.00"))
+	})).Return(nil).Once()
+
+	var entries []*ledger.JournalEntry
+	mockLedger.On("Post", context.Background(), mock.AnythingOfType("*ledger.JournalEntry")).
+		Run(func(args mock.Arguments) { entries = append(entries, args.Get(1).(*ledger.JournalEntry)) }).
+		Return(nil).Times(2)
+
+	err := service.RecordRepayment(context.Background(), loanID, loanRepayment)
+
+	assert.NoError(t, err)
+	require.Len(t, payouts, 2)
+	assert.Equal(t, 11, payouts[0].InvestorID)
+	assert.Equal(t, money.MustParse("27.00"), payouts[0].Amount)
+	assert.Equal(t, 12, payouts[1].InvestorID)
+	assert.Equal(t, money.MustParse("81.00"), payouts[1].Amount)
+	assert.Equal(t, loanID, loanRepayment.LoanID)
+
+	// One entry for the cash received and one for the cash paid out to investors
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		assert.NoError(t, entry.Validate())
+	}
+	assert.Equal(t, "loan_repayment:0", entries[0].Reference)
+	assert.Equal(t, "loan_repayment:0:payouts", entries[1].Reference)
+	assert.Len(t, entries[1].Postings, 3)
+	assert.Equal(t, money.MustParse("10.00"), loanRepayment.InterestAmount)
+	assert.Equal(t, money.MustParse("100.00"), loanRepayment.PrincipalAmount)
+	assert.True(t, loanRepayment.CreditAmount.IsZero())
+	mockBorrowerRepo.AssertNotCalled(t, "AddCreditBalance", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecordRepaymentCreditsOverpayment(t *testing.T) {
+	mockLoanRepo := mocks.NewLoanRepository(t)
+	mockInstallmentRepo := mocks.NewLoanInstallmentRepository(t)
+	mockRepaymentRepo := mocks.NewLoanRepaymentRepository(t)
+	mockBorrowerRepo := mocks.NewBorrowerRepository(t)
+	mockInvestmentRepo := mocks.NewLoanInvestmentRepository(t)
+	mockPayoutRepo := mocks.NewPayoutRepository(t)
+	mockRevenueRepo := mocks.NewPlatformRevenueRepository(t)
+	mockUnitOfWork := newMockUnitOfWork(t)
+	mockLedger := ledgermocks.NewService(t)
+
+	service := NewRepaymentService(mockLoanRepo, mockInstallmentRepo, mockRepaymentRepo, mockBorrowerRepo, mockInvestmentRepo, mockPayoutRepo, mockRevenueRepo, mockUnitOfWork, mockLedger, repayment.DefaultPolicy())
+
+	loanID := 1
+	loan := &models.Loan{ID: loanID, BorrowerID: 7, PrincipalAmount: money.MustParse("200.00"), Rate: money.MustParseRate("0.60"), ROI: money.MustParseRate("0.48"), CurrentState: "disbursed"}
+	installments := newTestInstallments(t, loanID)
+
+	loanRepayment := &models.LoanRepayment{
+		Amount: money.MustParse("250.00"),
+		PaidAt: time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	mockLoanRepo.On("GetByIDForUpdate", context.Background(), loanID).Return(loan, nil)
+	mockInstallmentRepo.On("GetByLoanID", context.Background(), loanID).Return(installments, nil)
+	mockInstallmentRepo.On("Update", context.Background(), mock.AnythingOfType("*models.LoanInstallment")).Return(nil).Times(2)
+	mockRepaymentRepo.On("Create", context.Background(), loanRepayment).Return(nil)
+	mockBorrowerRepo.On("AddCreditBalance", context.Background(), 7, money.MustParse("30.00")).Return(nil).Once()
+	mockInvestmentRepo.On("GetByLoanID", context.Background(), loanID).Return(newTestInvestments(loanID), nil)
+	mockPayoutRepo.On("Create", context.Background(), mock.AnythingOfType("*models.Payout")).Return(nil).Times(2)
+	mockRevenueRepo.On("Create", context.Background(), mock.AnythingOfType("*models.PlatformRevenue")).Return(nil).Once()
+	mockLedger.On("Post", context.Background(), mock.MatchedBy(func(entry *ledger.JournalEntry) bool {
+		return entry.Validate() == nil
+	})).Return(nil).Times(2)
+
+	err := service.RecordRepayment(context.Background(), loanID, loanRepayment)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("30.00"), loanRepayment.CreditAmount)
+}
+
+func TestRecordRepaymentRequiresDisbursedLoan(t *testing.T) {
+	mockLoanRepo := mocks.NewLoanRepository(t)
+	mockInstallmentRepo := mocks.NewLoanInstallmentRepository(t)
+	mockRepaymentRepo := mocks.NewLoanRepaymentRepository(t)
+	mockBorrowerRepo := mocks.NewBorrowerRepository(t)
+	mockInvestmentRepo := mocks.NewLoanInvestmentRepository(t)
+	mockPayoutRepo := mocks.NewPayoutRepository(t)
+	mockRevenueRepo := mocks.NewPlatformRevenueRepository(t)
+	mockUnitOfWork := newMockUnitOfWork(t)
+	mockLedger := ledgermocks.NewService(t)
+
+	service := NewRepaymentService(mockLoanRepo, mockInstallmentRepo, mockRepaymentRepo, mockBorrowerRepo, mockInvestmentRepo, mockPayoutRepo, mockRevenueRepo, mockUnitOfWork, mockLedger, repayment.DefaultPolicy())
+
+	loanID := 1
+	mockLoanRepo.On("GetByIDForUpdate", context.Background(), loanID).Return(&models.Loan{ID: loanID, CurrentState: "invested"}, nil)
+
+	err := service.RecordRepayment(context.Background(), loanID, &models.LoanRepayment{Amount: money.MustParse("10.00")})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "disbursed")
+	mockRepaymentRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestRecordRepaymentRejectsNonPositiveAmount(t *testing.T) {
+	mockLoanRepo := mocks.NewLoanRepository(t)
+	mockInstallmentRepo := mocks.NewLoanInstallmentRepository(t)
+	mockRepaymentRepo := mocks.NewLoanRepaymentRepository(t)
+	mockBorrowerRepo := mocks.NewBorrowerRepository(t)
+	mockInvestmentRepo := mocks.NewLoanInvestmentRepository(t)
+	mockPayoutRepo := mocks.NewPayoutRepository(t)
+	mockRevenueRepo := mocks.NewPlatformRevenueRepository(t)
+	mockUnitOfWork := newMockUnitOfWork(t)
+	mockLedger := ledgermocks.NewService(t)
+
+	service := NewRepaymentService(mockLoanRepo, mockInstallmentRepo, mockRepaymentRepo, mockBorrowerRepo, mockInvestmentRepo, mockPayoutRepo, mockRevenueRepo, mockUnitOfWork, mockLedger, repayment.DefaultPolicy())
+
+	err := service.RecordRepayment(context.Background(), 1, &models.LoanRepayment{Amount: money.MustParse("0.00")})
+
+	assert.Error(t, err)
+	mockLoanRepo.AssertNotCalled(t, "GetByIDForUpdate", mock.Anything, mock.Anything)
+}
+
+func TestListRepayments(t *testing.T) {
+	mockLoanRepo := mocks.NewLoanRepository(t)
+	mockInstallmentRepo := mocks.NewLoanInstallmentRepository(t)
+	mockRepaymentRepo := mocks.NewLoanRepaymentRepository(t)
+	mockBorrowerRepo := mocks.NewBorrowerRepository(t)
+	mockInvestmentRepo := mocks.NewLoanInvestmentRepository(t)
+	mockPayoutRepo := mocks.NewPayoutRepository(t)
+	mockRevenueRepo := mocks.NewPlatformRevenueRepository(t)
+	mockUnitOfWork := newMockUnitOfWork(t)
+	mockLedger := ledgermocks.NewService(t)
+
+	service := NewRepaymentService(mockLoanRepo, mockInstallmentRepo, mockRepaymentRepo, mockBorrowerRepo, mockInvestmentRepo, mockPayoutRepo, mockRevenueRepo, mockUnitOfWork, mockLedger, repayment.DefaultPolicy())
+
+	loanID := 1
+	repayments := []*models.LoanRepayment{{ID: 1, LoanID: loanID, Amount: money.MustParse("110.00")}}
+
+	mockLoanRepo.On("GetByID", context.Background(), loanID).Return(&models.Loan{ID: loanID}, nil)
+	mockRepaymentRepo.On("GetByLoanID", context.Background(), loanID).Return(repayments, nil)
+
+	result, err := service.ListRepayments(context.Background(), loanID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, repayments, result)
+}
@@ -2,18 +2,22 @@ package services
 
 import (
 	"context"
+	"time"
 
+	"github.com/sswastioyono18/loan-engine/internal/ledger"
 	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/pkg/money"
 )
 
-// BorrowerRepository defines the specific methods that BorrowerService needs from the repository
+// BorrowerRepository defines the specific methods that BorrowerService and other services need from the repository
 type BorrowerRepository interface {
 	Create(ctx context.Context, borrower *models.Borrower) error
 	GetByID(ctx context.Context, id int) (*models.Borrower, error)
 	GetByBorrowerIDNumber(ctx context.Context, borrowerIDNumber string) (*models.Borrower, error)
 	Update(ctx context.Context, borrower *models.Borrower) error
 	Delete(ctx context.Context, id int) error
-	List(ctx context.Context, offset, limit int) ([]*models.Borrower, error)
+	List(ctx context.Context, page models.PageRequest) (*models.Page[*models.Borrower], error)
+	AddCreditBalance(ctx context.Context, id int, amount money.Money) error
 }
 
 // InvestorRepository defines the specific methods that InvestorService and other services need from the investor repository
@@ -24,10 +28,10 @@ type InvestorRepository interface {
 	Create(ctx context.Context, investor *models.Investor) error
 	Update(ctx context.Context, investor *models.Investor) error
 	Delete(ctx context.Context, id int) error
-	List(ctx context.Context, offset, limit int) ([]*models.Investor, error)
+	List(ctx context.Context, page models.PageRequest) (*models.Page[*models.Investor], error)
 }
 
-// UserRepository defines the specific methods that AuthService needs from the repository
+// UserRepository defines the specific methods that AuthService and UserService need from the repository
 type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
 	GetByID(ctx context.Context, id int) (*models.User, error)
@@ -35,21 +39,89 @@ type UserRepository interface {
 	GetByUserID(ctx context.Context, userID string) (*models.User, error)
 	Update(ctx context.Context, user *models.User) error
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
 	UpdatePassword(ctx context.Context, id int, hashedPassword string) error
+	MarkEmailVerified(ctx context.Context, id int) error
+	List(ctx context.Context, filter models.UserFilter, offset, limit int) ([]*models.User, error)
+	Count(ctx context.Context, filter models.UserFilter) (int, error)
+	RecordFailedLogin(ctx context.Context, id int) (int, error)
+	LockUntil(ctx context.Context, id int, until time.Time) error
+	ResetFailedLogins(ctx context.Context, id int) error
+	SetTOTPSecret(ctx context.Context, id int, secret string) error
+	EnableTOTP(ctx context.Context, id int) error
+	DisableTOTP(ctx context.Context, id int) error
+	ClaimTOTPStep(ctx context.Context, id int, step int64) (bool, error)
+}
+
+// RefreshTokenRepository defines the specific methods that AuthService needs to store and rotate refresh tokens
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	MarkUsed(ctx context.Context, id int) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID int) error
+}
+
+// PasswordResetTokenRepository defines the specific methods that AuthService needs for the password reset flow
+type PasswordResetTokenRepository interface {
+	Create(ctx context.Context, token *models.PasswordResetToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error)
+	MarkUsed(ctx context.Context, id int) (bool, error)
+	InvalidateForUser(ctx context.Context, userID int) error
+}
+
+// EmailVerificationTokenRepository defines the specific methods that AuthService needs to verify the email of new investors
+type EmailVerificationTokenRepository interface {
+	Create(ctx context.Context, token *models.EmailVerificationToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*models.EmailVerificationToken, error)
+	MarkUsed(ctx context.Context, id int) (bool, error)
+	InvalidateForUser(ctx context.Context, userID int) error
+}
+
+// RecoveryCodeRepository defines the specific methods that AuthService needs for TOTP recovery codes
+type RecoveryCodeRepository interface {
+	ReplaceForUser(ctx context.Context, userID int, codeHashes []string) error
+	Use(ctx context.Context, userID int, codeHash string) (bool, error)
+	DeleteForUser(ctx context.Context, userID int) error
+}
+
+// APIKeyRepository defines the specific methods that APIKeyService needs
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	GetByID(ctx context.Context, id int) (*models.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	Update(ctx context.Context, key *models.APIKey) error
+	Revoke(ctx context.Context, id int) error
+	TouchLastUsed(ctx context.Context, id int) error
+	List(ctx context.Context, offset, limit int) ([]*models.APIKey, error)
+}
+
+// IdempotencyKeyRepository defines the specific methods that IdempotencyService needs
+type IdempotencyKeyRepository interface {
+	Claim(ctx context.Context, key *models.IdempotencyKey, staleBefore time.Time) (bool, error)
+	GetByKey(ctx context.Context, owner, key string) (*models.IdempotencyKey, error)
+	Complete(ctx context.Context, key *models.IdempotencyKey) error
+	Release(ctx context.Context, key *models.IdempotencyKey) error
+}
+
+// SecurityEventRepository defines the specific methods that AuthService needs to write the security audit trail
+type SecurityEventRepository interface {
+	Create(ctx context.Context, event *models.SecurityEvent) error
 }
 
 // LoanRepository defines the specific methods that LoanService needs from the loan repository
 type LoanRepository interface {
 	Create(ctx context.Context, loan *models.Loan) error
 	GetByID(ctx context.Context, id int) (*models.Loan, error)
+	GetByIDForUpdate(ctx context.Context, id int) (*models.Loan, error)
 	GetByLoanID(ctx context.Context, loanID string) (*models.Loan, error)
 	Update(ctx context.Context, loan *models.Loan) error
 	Delete(ctx context.Context, id int) error
-	List(ctx context.Context, state *string, offset, limit int) ([]*models.Loan, error)
+	List(ctx context.Context, filter models.LoanFilter, page models.PageRequest) (*models.Page[*models.Loan], error)
 	UpdateState(ctx context.Context, id int, newState string) error
-	UpdateTotalInvestedAmount(ctx context.Context, loanID int, amount float64) error
+	UpdateTotalInvestedAmount(ctx context.Context, loanID int, amount money.Money) error
 	GetByState(ctx context.Context, state string) ([]*models.Loan, error)
-	GetTotalInvestedAmount(ctx context.Context, loanID int) (float64, error)
+	GetTotalInvestedAmount(ctx context.Context, loanID int) (money.Money, error)
 }
 
 // LoanApprovalRepository defines the specific methods that LoanService needs from the loan approval repository
@@ -58,13 +130,27 @@ type LoanApprovalRepository interface {
 	GetByLoanID(ctx context.Context, loanID int) (*models.LoanApproval, error)
 }
 
+// LoanRejectionRepository defines the specific methods that LoanService needs from the loan rejection repository
+type LoanRejectionRepository interface {
+	Create(ctx context.Context, rejection *models.LoanRejection) error
+	GetByLoanID(ctx context.Context, loanID int) (*models.LoanRejection, error)
+}
+
+// LoanRejectionReasonRepository defines the specific methods that LoanService needs from the rejection reason catalogue
+type LoanRejectionReasonRepository interface {
+	Create(ctx context.Context, reason *models.LoanRejectionReason) error
+	GetByCode(ctx context.Context, code string) (*models.LoanRejectionReason, error)
+	Update(ctx context.Context, reason *models.LoanRejectionReason) error
+	List(ctx context.Context, activeOnly bool) ([]*models.LoanRejectionReason, error)
+}
+
 // LoanDisbursementRepository defines the specific methods that LoanService needs from the loan disbursement repository
 type LoanDisbursementRepository interface {
 	Create(ctx context.Context, disbursement *models.LoanDisbursement) error
 	GetByLoanID(ctx context.Context, loanID int) (*models.LoanDisbursement, error)
 }
 
-// LoanInvestmentRepository defines the specific methods that LoanService needs from the loan investment repository
+// LoanInvestmentRepository defines the specific methods that LoanService and RepaymentService need from the loan investment repository
 type LoanInvestmentRepository interface {
 	Create(ctx context.Context, investment *models.LoanInvestment) error
 	GetByLoanID(ctx context.Context, loanID int) ([]*models.LoanInvestment, error)
@@ -76,3 +162,39 @@ type LoanStateHistoryRepository interface {
 	Create(ctx context.Context, history *models.LoanStateHistory) error
 	GetByLoanID(ctx context.Context, loanID int) ([]*models.LoanStateHistory, error)
 }
+
+// LoanInstallmentRepository defines the specific methods that LoanService and RepaymentService need from the loan installment repository
+type LoanInstallmentRepository interface {
+	Create(ctx context.Context, installment *models.LoanInstallment) error
+	GetByLoanID(ctx context.Context, loanID int) ([]*models.LoanInstallment, error)
+	Update(ctx context.Context, installment *models.LoanInstallment) error
+}
+
+// LoanRepaymentRepository defines the specific methods that RepaymentService needs from the loan repayment repository
+type LoanRepaymentRepository interface {
+	Create(ctx context.Context, repayment *models.LoanRepayment) error
+	GetByLoanID(ctx context.Context, loanID int) ([]*models.LoanRepayment, error)
+}
+
+// PayoutRepository defines the specific methods that RepaymentService and InvestorService need from the payout repository
+type PayoutRepository interface {
+	Create(ctx context.Context, payout *models.Payout) error
+	GetByInvestorID(ctx context.Context, investorID int) ([]*models.Payout, error)
+}
+
+// PlatformRevenueRepository defines the specific methods that RepaymentService needs from the platform revenue repository
+type PlatformRevenueRepository interface {
+	Create(ctx context.Context, revenue *models.PlatformRevenue) error
+	GetByLoanID(ctx context.Context, loanID int) ([]*models.PlatformRevenue, error)
+}
+
+// Ledger defines the specific methods that LoanService and RepaymentService need from the general ledger
+type Ledger interface {
+	Post(ctx context.Context, entry *ledger.JournalEntry) error
+}
+
+// UnitOfWork defines the transaction boundary that LoanService runs its state transitions in.
+// Repository calls made with the context passed to fn join the same transaction.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
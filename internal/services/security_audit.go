@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/sswastioyono18/loan-engine/internal/models"
+)
+
+// Security event types written to the audit trail
+const (
+	SecurityEventLoginSucceeded           = "login_succeeded"
+	SecurityEventLoginFailed              = "login_failed"
+	SecurityEventLoginBlocked             = "login_blocked"
+	SecurityEventAccountLocked            = "account_locked"
+	SecurityEventRefreshTokenReused       = "refresh_token_reused"
+	SecurityEventPasswordChanged          = "password_changed"
+	SecurityEventPasswordResetRequested   = "password_reset_requested"
+	SecurityEventPasswordReset            = "password_reset"
+	SecurityEventTOTPEnabled              = "totp_enabled"
+	SecurityEventTOTPDisabled             = "totp_disabled"
+	SecurityEventRecoveryCodeUsed         = "recovery_code_used"
+	SecurityEventRecoveryCodesRegenerated = "recovery_codes_regenerated"
+	SecurityEventUserRegistered           = "user_registered"
+	SecurityEventEmailVerified            = "email_verified"
+	SecurityEventUserCreated              = "user_created"
+	SecurityEventUserDeactivated          = "user_deactivated"
+	SecurityEventUserActivated            = "user_activated"
+	SecurityEventUserDeleted              = "user_deleted"
+	SecurityEventUserRestored             = "user_restored"
+	SecurityEventUserRoleChanged          = "user_role_changed"
+	SecurityEventPasswordResetForced      = "password_reset_forced"
+	SecurityEventAPIKeyCreated            = "api_key_created"
+	SecurityEventAPIKeyUpdated            = "api_key_updated"
+	SecurityEventAPIKeyRevoked            = "api_key_revoked"
+)
+
+// RequestInfo describes the client a request came from, for the audit trail
+type RequestInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo returns a copy of ctx that carries the client's request info
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+func requestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
+
+// securityAudit writes security events. Writes are best effort: a failing audit write is
+// logged but never fails or reverts the action being audited.
+type securityAudit struct {
+	repo SecurityEventRepository
+}
+
+// record writes an event about user, or about email when no account matched it
+func (a securityAudit) record(ctx context.Context, eventType string, user *models.User, email, detail string) {
+	info := requestInfoFromContext(ctx)
+	event := &models.SecurityEvent{
+		Email:     email,
+		EventType: eventType,
+		Detail:    detail,
+		IPAddress: info.IPAddress,
+		UserAgent: info.UserAgent,
+	}
+	if user != nil {
+		event.UserID = &user.ID
+		event.Email = user.Email
+	}
+
+	if err := a.repo.Create(ctx, event); err != nil {
+		log.Printf("failed to write %s security event for %s: %v", eventType, event.Email, err)
+	}
+}
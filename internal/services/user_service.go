@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sswastioyono18/loan-engine/internal/apperr"
+	"github.com/sswastioyono18/loan-engine/internal/authz"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/pkg/external"
+)
+
+// unusablePasswordHash is stored for accounts that have no password yet or had theirs
+// revoked. It is not a bcrypt hash, so no password ever matches it.
+const unusablePasswordHash = "!"
+
+var (
+	ErrInvalidUserType  = apperr.Validation("invalid_user_type", "user type must be staff or admin; investors register themselves")
+	ErrInvalidStaffRole = apperr.Validation("invalid_staff_role", "staff role must be field_validator, field_officer or empty, and only staff have one")
+)
+
+// UserPage is one page of a listing of users
+type UserPage struct {
+	Users  []*models.User `json:"users"`
+	Total  int            `json:"total"`
+	Offset int            `json:"offset"`
+	Limit  int            `json:"limit"`
+}
+
+// UserService lets admins manage accounts: staff and admin accounts are only ever created
+// here, and any account can be deactivated or have its password reset
+type UserService interface {
+	ListUsers(ctx context.Context, filter models.UserFilter, offset, limit int) (*UserPage, error)
+	GetUser(ctx context.Context, id int) (*models.User, error)
+	CreateUser(ctx context.Context, user *models.User) error
+	DeactivateUser(ctx context.Context, id int) (*models.User, error)
+	ActivateUser(ctx context.Context, id int) (*models.User, error)
+	DeleteUser(ctx context.Context, id int) error
+	RestoreUser(ctx context.Context, id int) error
+	ChangeRole(ctx context.Context, id int, userType, staffRole string) (*models.User, error)
+	ForcePasswordReset(ctx context.Context, id int) error
+	BootstrapAdmin(ctx context.Context, email, fullName string) error
+}
+
+type userServiceImpl struct {
+	userRepo         UserRepository
+	refreshTokenRepo RefreshTokenRepository
+	unitOfWork       UnitOfWork
+	resets           passwordResets
+	audit            securityAudit
+}
+
+func NewUserService(
+	userRepo UserRepository,
+	refreshTokenRepo RefreshTokenRepository,
+	resetTokenRepo PasswordResetTokenRepository,
+	securityEventRepo SecurityEventRepository,
+	unitOfWork UnitOfWork,
+	emailService external.EmailService,
+) UserService {
+	return &userServiceImpl{
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		unitOfWork:       unitOfWork,
+		resets:           passwordResets{repo: resetTokenRepo, unitOfWork: unitOfWork, emailService: emailService},
+		audit:            securityAudit{repo: securityEventRepo},
+	}
+}
+
+func (s *userServiceImpl) ListUsers(ctx context.Context, filter models.UserFilter, offset, limit int) (*UserPage, error) {
+	users, err := s.userRepo.List(ctx, filter, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.userRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserPage{Users: users, Total: total, Offset: offset, Limit: limit}, nil
+}
+
+func (s *userServiceImpl) GetUser(ctx context.Context, id int) (*models.User, error) {
+	return s.userRepo.GetByID(ctx, id)
+}
+
+// CreateUser creates a staff or admin account without a password and mails the user a
+// password reset token to choose one with. The admin vouches for the email address.
+func (s *userServiceImpl) CreateUser(ctx context.Context, user *models.User) error {
+	if err := validateStaffAccount(user.UserType, user.StaffRole); err != nil {
+		return err
+	}
+
+	existingUser, err := s.userRepo.GetByEmail(ctx, user.Email)
+	if err == nil && existingUser != nil {
+		return apperr.Conflict("email_taken", "user with email %s already exists", user.Email)
+	}
+
+	if user.UserID == "" {
+		if user.UserID, err = newPublicID("USR"); err != nil {
+			return err
+		}
+	}
+
+	user.PasswordHash = unusablePasswordHash
+	user.IsActive = true
+	user.EmailVerified = true
+	user.InvestorID = nil
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	s.audit.record(ctx, SecurityEventUserCreated, user, "", fmt.Sprintf("as %s by %s", authz.RoleOf(user), authz.Actor(ctx)))
+
+	return s.resets.send(ctx, user)
+}
+
+// DeactivateUser stops the user from logging in and ends all of their sessions
+func (s *userServiceImpl) DeactivateUser(ctx context.Context, id int) (*models.User, error) {
+	user, err := s.otherUser(ctx, id, "deactivate")
+	if err != nil {
+		return nil, err
+	}
+
+	user.IsActive = false
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		return s.refreshTokenRepo.RevokeAllForUser(ctx, user.ID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to deactivate user: %w", err)
+	}
+
+	s.audit.record(ctx, SecurityEventUserDeactivated, user, "", fmt.Sprintf("by %s", authz.Actor(ctx)))
+
+	return user, nil
+}
+
+func (s *userServiceImpl) ActivateUser(ctx context.Context, id int) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	user.IsActive = true
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to activate user: %w", err)
+	}
+
+	s.audit.record(ctx, SecurityEventUserActivated, user, "", fmt.Sprintf("by %s", authz.Actor(ctx)))
+
+	return user, nil
+}
+
+// DeleteUser soft-deletes the user and ends all of their sessions. The user is kept for the
+// audit trail and can be restored.
+func (s *userServiceImpl) DeleteUser(ctx context.Context, id int) error {
+	user, err := s.otherUser(ctx, id, "delete")
+	if err != nil {
+		return err
+	}
+
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Delete(ctx, user.ID); err != nil {
+			return err
+		}
+
+		return s.refreshTokenRepo.RevokeAllForUser(ctx, user.ID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	s.audit.record(ctx, SecurityEventUserDeleted, user, "", fmt.Sprintf("by %s", authz.Actor(ctx)))
+
+	return nil
+}
+
+// RestoreUser undoes DeleteUser; the user comes back active or deactivated, as they were
+func (s *userServiceImpl) RestoreUser(ctx context.Context, id int) error {
+	if err := s.userRepo.Restore(ctx, id); err != nil {
+		return fmt.Errorf("failed to restore user: %w", err)
+	}
+
+	s.audit.record(ctx, SecurityEventUserRestored, &models.User{ID: id}, "", fmt.Sprintf("by %s", authz.Actor(ctx)))
+
+	return nil
+}
+
+// ChangeRole moves a staff or admin account to another staff role or user type. Investor
+// accounts belong to an investor record and keep their role.
+func (s *userServiceImpl) ChangeRole(ctx context.Context, id int, userType, staffRole string) (*models.User, error) {
+	if err := validateStaffAccount(userType, staffRole); err != nil {
+		return nil, err
+	}
+
+	user, err := s.otherUser(ctx, id, "change the role of")
+	if err != nil {
+		return nil, err
+	}
+
+	if user.UserType == authz.RoleInvestor {
+		return nil, apperr.InvalidState("investor_role_fixed", "investor accounts cannot change role")
+	}
+
+	from := authz.RoleOf(user)
+	user.UserType = userType
+	user.StaffRole = staffRole
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to change role: %w", err)
+	}
+
+	s.audit.record(ctx, SecurityEventUserRoleChanged, user, "", fmt.Sprintf("from %s to %s by %s", from, authz.RoleOf(user), authz.Actor(ctx)))
+
+	return user, nil
+}
+
+// ForcePasswordReset revokes the user's password and sessions and mails them a password
+// reset token, for example when the account may be compromised
+func (s *userServiceImpl) ForcePasswordReset(ctx context.Context, id int) error {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.UpdatePassword(ctx, user.ID, unusablePasswordHash); err != nil {
+			return err
+		}
+
+		return s.refreshTokenRepo.RevokeAllForUser(ctx, user.ID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke password: %w", err)
+	}
+
+	s.audit.record(ctx, SecurityEventPasswordResetForced, user, "", fmt.Sprintf("by %s", authz.Actor(ctx)))
+
+	return s.resets.send(ctx, user)
+}
+
+// BootstrapAdmin creates the first admin account at startup, since only admins can create
+// admin accounts. It does nothing when a user with email already exists.
+func (s *userServiceImpl) BootstrapAdmin(ctx context.Context, email, fullName string) error {
+	if _, err := s.userRepo.GetByEmail(ctx, email); err == nil {
+		return nil
+	}
+
+	if fullName == "" {
+		fullName = "Administrator"
+	}
+
+	return s.CreateUser(ctx, &models.User{
+		Email:    email,
+		UserType: authz.RoleAdmin,
+		FullName: fullName,
+	})
+}
+
+// otherUser loads the user with id, refusing when it is the signed-in admin themselves, so
+// an admin cannot lock themselves out
+func (s *userServiceImpl) otherUser(ctx context.Context, id int, action string) (*models.User, error) {
+	if actor, ok := authz.UserFromContext(ctx); ok && actor.ID == id {
+		return nil, fmt.Errorf("%w: you cannot %s your own account", authz.ErrForbidden, action)
+	}
+
+	return s.userRepo.GetByID(ctx, id)
+}
+
+// validateStaffAccount checks the user type and staff role of an account created or changed
+// by an admin
+func validateStaffAccount(userType, staffRole string) error {
+	switch userType {
+	case authz.RoleAdmin:
+		if staffRole != "" {
+			return ErrInvalidStaffRole
+		}
+	case authz.RoleStaff:
+		if staffRole != "" && staffRole != authz.RoleFieldValidator && staffRole != authz.RoleFieldOfficer {
+			return ErrInvalidStaffRole
+		}
+	default:
+		return ErrInvalidUserType
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sswastioyono18/loan-engine/internal/authz"
+	"github.com/sswastioyono18/loan-engine/internal/models"
+	"github.com/sswastioyono18/loan-engine/internal/repositories"
+	"github.com/sswastioyono18/loan-engine/internal/repositories/mocks"
+	mocks2 "github.com/sswastioyono18/loan-engine/pkg/external/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testAdmin = &models.User{ID: 100, UserID: "ADM001", Email: "admin@example.com", UserType: authz.RoleAdmin, IsActive: true}
+
+func adminContext() context.Context {
+	return authz.WithUser(context.Background(), testAdmin)
+}
+
+func TestCreateUser(t *testing.T) {
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockResetTokenRepo := mocks.NewPasswordResetTokenRepository(t)
+	mockEmailService := mocks2.NewEmailService(t)
+	mockSecurityEventRepo, events := recordSecurityEvents(t)
+	service := NewUserService(mockUserRepo, nil, mockResetTokenRepo, mockSecurityEventRepo, newMockUnitOfWork(t), mockEmailService)
+	ctx := adminContext()
+
+	user := &models.User{
+		Email:     "validator@example.com",
+		UserType:  authz.RoleStaff,
+		StaffRole: authz.RoleFieldValidator,
+		FullName:  "Val Idator",
+	}
+
+	mockUserRepo.On("GetByEmail", ctx, user.Email).Return(nil, repositories.ErrUserNotFound)
+	mockUserRepo.On("Create", ctx, user).Return(nil)
+	mockResetTokenRepo.On("InvalidateForUser", ctx, user.ID).Return(nil)
+	mockResetTokenRepo.On("Create", ctx, mock.AnythingOfType("*models.PasswordResetToken")).Return(nil)
+	mockEmailService.On("SendPasswordReset", ctx, user.Email, mock.AnythingOfType("string"), time.Hour).Return(nil)
+
+	err := service.CreateUser(ctx, user)
+
+	require.NoError(t, err)
+	assert.Regexp(t, `^USR-[0-9A-F]{10}$`, user.UserID)
+	assert.True(t, user.IsActive)
+	assert.True(t, user.EmailVerified)
+	assert.Nil(t, user.InvestorID)
+	// No password works until the user sets one with the mailed token
+	assert.Equal(t, unusablePasswordHash, user.PasswordHash)
+	assert.Equal(t, []string{SecurityEventUserCreated}, eventTypes(*events))
+	assert.Equal(t, "as field_validator by ADM001", (*events)[0].Detail)
+}
+
+func TestCreateUserValidatesRole(t *testing.T) {
+	service := NewUserService(nil, nil, nil, newMockSecurityEvents(t), nil, nil)
+
+	tests := []struct {
+		name      string
+		userType  string
+		staffRole string
+		want      error
+	}{
+		{"investor", authz.RoleInvestor, "", ErrInvalidUserType},
+		{"unknown type", "superuser", "", ErrInvalidUserType},
+		{"unknown staff role", authz.RoleStaff, "cashier", ErrInvalidStaffRole},
+		{"admin with staff role", authz.RoleAdmin, authz.RoleFieldOfficer, ErrInvalidStaffRole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.CreateUser(adminContext(), &models.User{Email: "new@example.com", UserType: tt.userType, StaffRole: tt.staffRole})
+
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestDeactivateUser(t *testing.T) {
+	t.Run("deactivates and signs out the user", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+		service := NewUserService(mockUserRepo, mockRefreshTokenRepo, nil, newMockSecurityEvents(t), newMockUnitOfWork(t), nil)
+		ctx := adminContext()
+
+		user := &models.User{ID: 5, Email: "officer@example.com", UserType: authz.RoleStaff, IsActive: true}
+		mockUserRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+		mockUserRepo.On("Update", ctx, mock.MatchedBy(func(u *models.User) bool { return u.ID == user.ID && !u.IsActive })).Return(nil)
+		mockRefreshTokenRepo.On("RevokeAllForUser", ctx, user.ID).Return(nil)
+
+		deactivated, err := service.DeactivateUser(ctx, user.ID)
+
+		require.NoError(t, err)
+		assert.False(t, deactivated.IsActive)
+	})
+
+	t.Run("admins cannot deactivate themselves", func(t *testing.T) {
+		service := NewUserService(nil, nil, nil, newMockSecurityEvents(t), nil, nil)
+
+		_, err := service.DeactivateUser(adminContext(), testAdmin.ID)
+
+		assert.ErrorIs(t, err, authz.ErrForbidden)
+	})
+}
+
+func TestListUsers(t *testing.T) {
+	mockUserRepo := mocks.NewUserRepository(t)
+	service := NewUserService(mockUserRepo, nil, nil, nil, nil, nil)
+	ctx := adminContext()
+
+	filter := models.UserFilter{Search: "example.com", UserType: authz.RoleStaff}
+	users := []*models.User{{ID: 5}, {ID: 6}}
+	mockUserRepo.On("List", ctx, filter, 10, 2).Return(users, nil)
+	mockUserRepo.On("Count", ctx, filter).Return(12, nil)
+
+	page, err := service.ListUsers(ctx, filter, 10, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, &UserPage{Users: users, Total: 12, Offset: 10, Limit: 2}, page)
+}
+
+func TestDeleteUser(t *testing.T) {
+	t.Run("soft-deletes and signs out the user", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+		mockSecurityEventRepo, events := recordSecurityEvents(t)
+		service := NewUserService(mockUserRepo, mockRefreshTokenRepo, nil, mockSecurityEventRepo, newMockUnitOfWork(t), nil)
+		ctx := adminContext()
+
+		user := &models.User{ID: 5, Email: "officer@example.com", UserType: authz.RoleStaff, IsActive: true}
+		mockUserRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+		mockUserRepo.On("Delete", ctx, user.ID).Return(nil)
+		mockRefreshTokenRepo.On("RevokeAllForUser", ctx, user.ID).Return(nil)
+
+		require.NoError(t, service.DeleteUser(ctx, user.ID))
+		assert.Equal(t, []string{SecurityEventUserDeleted}, eventTypes(*events))
+	})
+
+	t.Run("admins cannot delete themselves", func(t *testing.T) {
+		service := NewUserService(nil, nil, nil, newMockSecurityEvents(t), nil, nil)
+
+		err := service.DeleteUser(adminContext(), testAdmin.ID)
+
+		assert.ErrorIs(t, err, authz.ErrForbidden)
+	})
+}
+
+func TestChangeRole(t *testing.T) {
+	t.Run("moves staff to another role", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockSecurityEventRepo, events := recordSecurityEvents(t)
+		service := NewUserService(mockUserRepo, nil, nil, mockSecurityEventRepo, nil, nil)
+		ctx := adminContext()
+
+		user := &models.User{ID: 5, UserType: authz.RoleStaff, StaffRole: authz.RoleFieldValidator, IsActive: true}
+		mockUserRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+		mockUserRepo.On("Update", ctx, user).Return(nil)
+
+		changed, err := service.ChangeRole(ctx, user.ID, authz.RoleStaff, authz.RoleFieldOfficer)
+
+		require.NoError(t, err)
+		assert.Equal(t, authz.RoleFieldOfficer, changed.StaffRole)
+		assert.Equal(t, "from field_validator to field_officer by ADM001", (*events)[0].Detail)
+	})
+
+	t.Run("investors keep their role", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		service := NewUserService(mockUserRepo, nil, nil, newMockSecurityEvents(t), nil, nil)
+		ctx := adminContext()
+
+		mockUserRepo.On("GetByID", ctx, 6).Return(&models.User{ID: 6, UserType: authz.RoleInvestor, IsActive: true}, nil)
+
+		_, err := service.ChangeRole(ctx, 6, authz.RoleAdmin, "")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("admins cannot demote themselves", func(t *testing.T) {
+		service := NewUserService(nil, nil, nil, newMockSecurityEvents(t), nil, nil)
+
+		_, err := service.ChangeRole(adminContext(), testAdmin.ID, authz.RoleStaff, "")
+
+		assert.ErrorIs(t, err, authz.ErrForbidden)
+	})
+}
+
+func TestForcePasswordReset(t *testing.T) {
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockRefreshTokenRepo := mocks.NewRefreshTokenRepository(t)
+	mockResetTokenRepo := mocks.NewPasswordResetTokenRepository(t)
+	mockEmailService := mocks2.NewEmailService(t)
+	mockSecurityEventRepo, events := recordSecurityEvents(t)
+	service := NewUserService(mockUserRepo, mockRefreshTokenRepo, mockResetTokenRepo, mockSecurityEventRepo, newMockUnitOfWork(t), mockEmailService)
+	ctx := adminContext()
+
+	user := &models.User{ID: 5, Email: "officer@example.com", UserType: authz.RoleStaff, IsActive: true}
+	mockUserRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	mockUserRepo.On("UpdatePassword", ctx, user.ID, unusablePasswordHash).Return(nil)
+	mockRefreshTokenRepo.On("RevokeAllForUser", ctx, user.ID).Return(nil)
+	mockResetTokenRepo.On("InvalidateForUser", ctx, user.ID).Return(nil)
+	mockResetTokenRepo.On("Create", ctx, mock.AnythingOfType("*models.PasswordResetToken")).Return(nil)
+	mockEmailService.On("SendPasswordReset", ctx, user.Email, mock.AnythingOfType("string"), time.Hour).Return(nil)
+
+	err := service.ForcePasswordReset(ctx, user.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{SecurityEventPasswordResetForced}, eventTypes(*events))
+}
+
+func TestBootstrapAdminSkipsExistingUser(t *testing.T) {
+	mockUserRepo := mocks.NewUserRepository(t)
+	service := NewUserService(mockUserRepo, nil, nil, newMockSecurityEvents(t), nil, nil)
+
+	mockUserRepo.On("GetByEmail", context.Background(), testAdmin.Email).Return(testAdmin, nil)
+
+	assert.NoError(t, service.BootstrapAdmin(context.Background(), testAdmin.Email, ""))
+}
@@ -0,0 +1,118 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// manifest is the JSON file that lists the signing keys, for example:
+//
+//	{"keys": [
+//	  {"kid": "2026-09", "private_key_file": "2026-09.pem", "active_from": "2026-09-01T00:00:00Z", "retire_at": "2026-10-02T00:00:00Z"},
+//	  {"kid": "2026-10", "private_key_file": "2026-10.pem", "active_from": "2026-10-01T00:00:00Z"}
+//	]}
+//
+// Relative file paths are resolved against the manifest's directory.
+type manifest struct {
+	Keys []struct {
+		ID             string    `json:"kid"`
+		PrivateKeyFile string    `json:"private_key_file"`
+		SecretFile     string    `json:"secret_file"`
+		ActiveFrom     time.Time `json:"active_from"`
+		RetireAt       time.Time `json:"retire_at"`
+	} `json:"keys"`
+}
+
+// LoadManifest reads the key manifest at path and the key files it points to. Private keys
+// are PEM encoded, either PKCS#8 (RSA or Ed25519) or PKCS#1 (RSA); HS256 secrets are read
+// from secret_file instead.
+func LoadManifest(path string) (*Manager, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key manifest: %w", err)
+	}
+
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse key manifest: %w", err)
+	}
+
+	if len(m.Keys) == 0 {
+		return nil, errors.New("key manifest lists no keys")
+	}
+
+	dir := filepath.Dir(path)
+	keys := make([]*Key, 0, len(m.Keys))
+	for _, entry := range m.Keys {
+		var material interface{}
+		switch {
+		case entry.PrivateKeyFile != "" && entry.SecretFile != "":
+			return nil, fmt.Errorf("key %s: set either private_key_file or secret_file, not both", entry.ID)
+		case entry.PrivateKeyFile != "":
+			material, err = readPrivateKey(resolve(dir, entry.PrivateKeyFile))
+		case entry.SecretFile != "":
+			material, err = readSecret(resolve(dir, entry.SecretFile))
+		default:
+			err = errors.New("private_key_file or secret_file is required")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", entry.ID, err)
+		}
+
+		key, err := NewKey(entry.ID, material)
+		if err != nil {
+			return nil, err
+		}
+		key.ActiveFrom = entry.ActiveFrom
+		key.RetireAt = entry.RetireAt
+
+		keys = append(keys, key)
+	}
+
+	return NewManager(keys...)
+}
+
+func resolve(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
+
+func readPrivateKey(path string) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not PEM encoded", path)
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%s holds an unsupported %q block", path, block.Type)
+	}
+}
+
+func readSecret(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSpace(data), nil
+}
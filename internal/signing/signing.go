@@ -0,0 +1,195 @@
+// Package signing holds the keys access tokens are signed and verified with. Several keys
+// can be live at once: each has an activation time, the newest active key signs, and every
+// key that has not been retired still verifies. Keys therefore rotate on a schedule without
+// invalidating the tokens that are already out.
+package signing
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms. HS256 keys are shared secrets and are never published.
+const (
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+	HS256 = "HS256"
+)
+
+var (
+	ErrNoSigningKey  = errors.New("no active signing key")
+	ErrUnknownKey    = errors.New("unknown signing key")
+	ErrDefaultSecret = errors.New("JWT secret is empty or a known placeholder; set a random secret or configure signing keys")
+)
+
+// placeholderSecrets are secrets that have shipped in sample configuration and must never sign tokens
+var placeholderSecrets = map[string]bool{
+	"":                         true,
+	"your_jwt_secret_key_here": true,
+	"change-me":                true,
+}
+
+// Key is one signing key. It signs from ActiveFrom until a newer key activates, and
+// verifies until RetireAt; a zero RetireAt means it is never retired.
+type Key struct {
+	ID         string
+	Algorithm  string
+	ActiveFrom time.Time
+	RetireAt   time.Time
+
+	signKey   crypto.PrivateKey
+	verifyKey crypto.PublicKey
+}
+
+// NewKey wraps private key material: an *rsa.PrivateKey (RS256), an ed25519.PrivateKey
+// (EdDSA) or a []byte secret (HS256)
+func NewKey(id string, private crypto.PrivateKey) (*Key, error) {
+	if id == "" {
+		return nil, errors.New("key ID is required")
+	}
+
+	switch k := private.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("key %s: RSA keys must be at least 2048 bits", id)
+		}
+		return &Key{ID: id, Algorithm: RS256, signKey: k, verifyKey: &k.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &Key{ID: id, Algorithm: EdDSA, signKey: k, verifyKey: k.Public()}, nil
+	case []byte:
+		if placeholderSecrets[string(k)] {
+			return nil, fmt.Errorf("key %s: %w", id, ErrDefaultSecret)
+		}
+		return &Key{ID: id, Algorithm: HS256, signKey: k, verifyKey: k}, nil
+	default:
+		return nil, fmt.Errorf("key %s: unsupported key type %T", id, private)
+	}
+}
+
+func (k *Key) method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+func (k *Key) retiredAt(t time.Time) bool {
+	return !k.RetireAt.IsZero() && !t.Before(k.RetireAt)
+}
+
+func (k *Key) signsAt(t time.Time) bool {
+	return !t.Before(k.ActiveFrom) && !k.retiredAt(t)
+}
+
+// Manager signs tokens with the current key and verifies them against every live key
+type Manager struct {
+	keys []*Key
+	now  func() time.Time
+}
+
+// NewManager fails unless key IDs are unique and at least one key can sign right now
+func NewManager(keys ...*Key) (*Manager, error) {
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if seen[key.ID] {
+			return nil, fmt.Errorf("duplicate key ID %s", key.ID)
+		}
+		seen[key.ID] = true
+	}
+
+	m := &Manager{keys: keys, now: time.Now}
+	if _, err := m.SigningKey(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// NewHMACManager signs with a single shared secret, refusing placeholder secrets
+func NewHMACManager(secret string) (*Manager, error) {
+	key, err := NewKey("default", []byte(secret))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewManager(key)
+}
+
+// Load builds the manager from the key manifest at keysFile when one is configured,
+// and from the shared secret otherwise
+func Load(keysFile, secret string) (*Manager, error) {
+	if keysFile != "" {
+		return LoadManifest(keysFile)
+	}
+
+	return NewHMACManager(secret)
+}
+
+// SigningKey returns the most recently activated key that is not retired
+func (m *Manager) SigningKey() (*Key, error) {
+	now := m.now()
+
+	var current *Key
+	for _, key := range m.keys {
+		if key.signsAt(now) && (current == nil || key.ActiveFrom.After(current.ActiveFrom)) {
+			current = key
+		}
+	}
+
+	if current == nil {
+		return nil, ErrNoSigningKey
+	}
+
+	return current, nil
+}
+
+// Sign signs claims with the current key and names the key in the "kid" header
+func (m *Manager) Sign(claims jwt.Claims) (string, error) {
+	key, err := m.SigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.signKey)
+}
+
+// Parse verifies tokenString against the key its "kid" header names and decodes it into claims
+func (m *Manager) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, m.keyFor, jwt.WithValidMethods(m.algorithms()))
+}
+
+func (m *Manager) keyFor(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	now := m.now()
+
+	for _, key := range m.keys {
+		if key.ID != kid || key.retiredAt(now) {
+			continue
+		}
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("key %s does not sign %s tokens", kid, token.Method.Alg())
+		}
+		return key.verifyKey, nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+}
+
+func (m *Manager) algorithms() []string {
+	seen := map[string]bool{}
+	var algorithms []string
+	for _, key := range m.keys {
+		if !seen[key.Algorithm] {
+			seen[key.Algorithm] = true
+			algorithms = append(algorithms, key.Algorithm)
+		}
+	}
+
+	return algorithms
+}
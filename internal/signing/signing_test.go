@@ -0,0 +1,181 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEd25519Key(t *testing.T, id string) *Key {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := NewKey(id, private)
+	require.NoError(t, err)
+	return key
+}
+
+func newRSAKey(t *testing.T, id string) *Key {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	key, err := NewKey(id, private)
+	require.NoError(t, err)
+	return key
+}
+
+func TestSignAndParse(t *testing.T) {
+	for _, key := range []*Key{newRSAKey(t, "rsa"), newEd25519Key(t, "ed"), {ID: "hmac", Algorithm: HS256, signKey: []byte("s3cret"), verifyKey: []byte("s3cret")}} {
+		t.Run(key.Algorithm, func(t *testing.T) {
+			m, err := NewManager(key)
+			require.NoError(t, err)
+
+			signed, err := m.Sign(jwt.RegisteredClaims{Subject: "42"})
+			require.NoError(t, err)
+
+			claims := &jwt.RegisteredClaims{}
+			token, err := m.Parse(signed, claims)
+			require.NoError(t, err)
+			assert.Equal(t, key.ID, token.Header["kid"])
+			assert.Equal(t, key.Algorithm, token.Method.Alg())
+			assert.Equal(t, "42", claims.Subject)
+		})
+	}
+}
+
+func TestParseRejectsForeignTokens(t *testing.T) {
+	m, err := NewManager(newEd25519Key(t, "current"))
+	require.NoError(t, err)
+
+	other, err := NewManager(newEd25519Key(t, "other"))
+	require.NoError(t, err)
+	foreign, err := other.Sign(jwt.RegisteredClaims{})
+	require.NoError(t, err)
+
+	_, err = m.Parse(foreign, &jwt.RegisteredClaims{})
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	// A token that claims a known kid but a different algorithm must not verify
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{})
+	forged.Header["kid"] = "current"
+	signed, err := forged.SignedString([]byte("guess"))
+	require.NoError(t, err)
+
+	_, err = m.Parse(signed, &jwt.RegisteredClaims{})
+	assert.Error(t, err)
+}
+
+func TestScheduledRotation(t *testing.T) {
+	start := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	old := newEd25519Key(t, "2026-09")
+	old.ActiveFrom = start
+	old.RetireAt = start.AddDate(0, 1, 1)
+	next := newRSAKey(t, "2026-10")
+	next.ActiveFrom = start.AddDate(0, 1, 0)
+
+	m := &Manager{keys: []*Key{old, next}}
+	at := func(t time.Time) { m.now = func() time.Time { return t } }
+
+	// Before the rotation the old key signs and both are published
+	at(start.AddDate(0, 0, 15))
+	key, err := m.SigningKey()
+	require.NoError(t, err)
+	assert.Equal(t, "2026-09", key.ID)
+	assert.Len(t, m.JWKS().Keys, 2)
+	issuedBefore, err := m.Sign(jwt.RegisteredClaims{})
+	require.NoError(t, err)
+
+	// After it the new key signs, and tokens from the old one still verify
+	at(next.ActiveFrom.Add(time.Hour))
+	key, err = m.SigningKey()
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10", key.ID)
+	_, err = m.Parse(issuedBefore, &jwt.RegisteredClaims{})
+	assert.NoError(t, err)
+
+	// Once retired the old key neither verifies nor is published
+	at(old.RetireAt)
+	_, err = m.Parse(issuedBefore, &jwt.RegisteredClaims{})
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	jwks := m.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "2026-10", jwks.Keys[0].KeyID)
+}
+
+func TestNewManagerNeedsAnActiveKey(t *testing.T) {
+	key := newEd25519Key(t, "later")
+	key.ActiveFrom = time.Now().Add(time.Hour)
+
+	_, err := NewManager(key)
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+
+	_, err = NewManager(newEd25519Key(t, "same"), newEd25519Key(t, "same"))
+	assert.Error(t, err)
+}
+
+func TestJWKSNeverPublishesSecrets(t *testing.T) {
+	m, err := NewHMACManager("a-long-random-secret")
+	require.NoError(t, err)
+
+	assert.Empty(t, m.JWKS().Keys)
+
+	m, err = NewManager(newEd25519Key(t, "ed"), newRSAKey(t, "rsa"))
+	require.NoError(t, err)
+
+	jwks := m.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, JWK{KeyType: "OKP", KeyID: "ed", Use: "sig", Algorithm: EdDSA, Curve: "Ed25519", X: jwks.Keys[0].X}, jwks.Keys[0])
+	assert.Equal(t, "RSA", jwks.Keys[1].KeyType)
+	assert.Equal(t, "AQAB", jwks.Keys[1].E)
+}
+
+func TestNewHMACManagerRefusesPlaceholderSecrets(t *testing.T) {
+	for _, secret := range []string{"", "your_jwt_secret_key_here", "change-me"} {
+		_, err := NewHMACManager(secret)
+		assert.ErrorIs(t, err, ErrDefaultSecret, "secret %q", secret)
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+	writeFile(t, dir, "ed.pem", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}))
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	writeFile(t, dir, "rsa.pem", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}))
+
+	writeFile(t, dir, "keys.json", []byte(`{"keys": [
+		{"kid": "old", "private_key_file": "rsa.pem", "active_from": "2026-01-01T00:00:00Z"},
+		{"kid": "new", "private_key_file": "ed.pem", "active_from": "2026-02-01T00:00:00Z"}
+	]}`))
+
+	m, err := LoadManifest(filepath.Join(dir, "keys.json"))
+	require.NoError(t, err)
+
+	key, err := m.SigningKey()
+	require.NoError(t, err)
+	assert.Equal(t, "new", key.ID)
+	assert.Equal(t, EdDSA, key.Algorithm)
+	assert.Len(t, m.JWKS().Keys, 2)
+
+	writeFile(t, dir, "bad.json", []byte(`{"keys": [{"kid": "k"}]}`))
+	_, err = LoadManifest(filepath.Join(dir, "bad.json"))
+	assert.Error(t, err)
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+}
@@ -0,0 +1,80 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The SHA1 test vectors from RFC 6238 appendix B, for the ASCII key "12345678901234567890"
+func TestHOTPMatchesRFC6238Vectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.code, hotp(key, Step(time.Unix(tt.unix, 0)), 8), "T=%d", tt.unix)
+	}
+}
+
+func TestCodeAndVerify(t *testing.T) {
+	secret := encoding.EncodeToString([]byte("12345678901234567890"))
+	at := time.Unix(1111111109, 0)
+
+	code, err := Code(secret, at)
+	require.NoError(t, err)
+	assert.Equal(t, "081804", code)
+
+	step, ok := Verify(secret, code, at)
+	assert.True(t, ok)
+	assert.Equal(t, Step(at), step)
+
+	// One step of drift either way is accepted, two are not
+	_, ok = Verify(secret, code, at.Add(Period))
+	assert.True(t, ok)
+	_, ok = Verify(secret, code, at.Add(-Period))
+	assert.True(t, ok)
+	_, ok = Verify(secret, code, at.Add(2*Period))
+	assert.False(t, ok)
+
+	_, ok = Verify(secret, "000000", at)
+	assert.False(t, ok)
+	_, ok = Verify(secret, "81804", at)
+	assert.False(t, ok)
+	_, ok = Verify("not base32!", code, at)
+	assert.False(t, ok)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	other, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+
+	// Authenticator apps may show the secret in lower case and in groups
+	code, err := Code(secret, time.Now())
+	require.NoError(t, err)
+	_, ok := Verify(strings.ToLower(secret[:16])+" "+secret[16:], code, time.Now())
+	assert.True(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("JBSWY3DPEHPK3PXP", "Loan Engine", "jane@example.com")
+
+	assert.Equal(t, "otpauth://totp/Loan%20Engine:jane@example.com?algorithm=SHA1&digits=6&issuer=Loan+Engine&period=30&secret=JBSWY3DPEHPK3PXP", uri)
+}
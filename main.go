@@ -1,19 +1,22 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 
+	"github.com/sswastioyono18/loan-engine/internal/authz"
 	"github.com/sswastioyono18/loan-engine/internal/handlers"
+	"github.com/sswastioyono18/loan-engine/internal/repayment"
 	"github.com/sswastioyono18/loan-engine/internal/repositories"
 	"github.com/sswastioyono18/loan-engine/internal/services"
+	"github.com/sswastioyono18/loan-engine/internal/signing"
 	"github.com/sswastioyono18/loan-engine/pkg/external"
 	"github.com/sswastioyono18/loan-engine/pkg/util"
 
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
 	"github.com/joho/godotenv"
 )
 
@@ -29,71 +32,59 @@ func main() {
 	}
 	defer db.Close()
 
-	// Initialize repositories
-	borrowerRepo := repositories.NewBorrowerRepository(db)
-	loanRepo := repositories.NewLoanRepository(db)
-	loanApprovalRepo := repositories.NewLoanApprovalRepository(db)
-	loanDisbursementRepo := repositories.NewLoanDisbursementRepository(db)
-	investorRepo := repositories.NewInvestorRepository(db)
-	loanInvestmentRepo := repositories.NewLoanInvestmentRepository(db)
-	loanStateHistoryRepo := repositories.NewLoanStateHistoryRepository(db)
-	_ = repositories.NewUserRepository(db) // Initialize for potential future use
-
 	// Initialize external services (mocks for now)
 	emailService := external.NewEmailService()
 	storageService := external.NewStorageService()
 
-	// Initialize services
-	borrowerService := services.NewBorrowerService(borrowerRepo)
-	loanService := services.NewLoanService(loanRepo, loanApprovalRepo, loanDisbursementRepo, loanInvestmentRepo, loanStateHistoryRepo, investorRepo, emailService, storageService)
-	investorService := services.NewInvestorService(investorRepo)
-
-	// Initialize handlers
-	borrowerHandler := handlers.NewBorrowerHandler(borrowerService)
-	loanHandler := handlers.NewLoanHandler(loanService, emailService, storageService)
-	investorHandler := handlers.NewInvestorHandler(investorService)
-
-	// Set up router
-	r := chi.NewRouter()
-
-	// Middleware
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
-	r.Use(middleware.AllowContentType("application/json"))
-
-	// Health check endpoint
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
-
-	// API routes
-	r.Route("/api/v1", func(r chi.Router) {
-		// Borrower routes
-		r.Post("/borrowers", borrowerHandler.CreateBorrower)
-		r.Get("/borrowers/{id}", borrowerHandler.GetBorrowerByID)
-		r.Put("/borrowers/{id}", borrowerHandler.UpdateBorrower)
-		r.Delete("/borrowers/{id}", borrowerHandler.DeleteBorrower)
-		r.Get("/borrowers", borrowerHandler.ListBorrowers)
-
-		// Loan routes
-		r.Post("/loans", loanHandler.CreateLoan)
-		r.Get("/loans/{id}", loanHandler.GetLoanByID)
-		r.Put("/loans/{id}", loanHandler.UpdateLoan)
-		r.Delete("/loans/{id}", loanHandler.DeleteLoan)
-		r.Get("/loans", loanHandler.ListLoans)
-		r.Post("/loans/{id}/approve", loanHandler.ApproveLoan)
-		r.Post("/loans/{id}/invest", loanHandler.InvestInLoan)
-		r.Post("/loans/{id}/disburse", loanHandler.DisburseLoan)
-		r.Get("/loans/state/{state}", loanHandler.GetLoansByState)
-
-		// Investor routes
-		r.Post("/investors", investorHandler.CreateInvestor)
-		r.Get("/investors/{id}", investorHandler.GetInvestorByID)
-		r.Put("/investors/{id}", investorHandler.UpdateInvestor)
-		r.Delete("/investors/{id}", investorHandler.DeleteInvestor)
-		r.Get("/investors", investorHandler.ListInvestors)
-	})
+	// Load the access token signing keys; never start with a placeholder secret
+	signingKeys, err := signing.Load(os.Getenv("JWT_KEYS_FILE"), os.Getenv("JWT_SECRET"))
+	if err != nil {
+		log.Fatal("Invalid signing key configuration:", err)
+	}
+
+	// Initialize service factory
+	serviceFactory := services.NewServiceFactory(
+		repositories.NewRepositoryFactory(db),
+		emailService,
+		storageService,
+		signingKeys,
+	)
+
+	// Configure how repayments are allocated
+	serviceFactory.RepaymentPolicy, err = repayment.ParsePolicy(os.Getenv("REPAYMENT_WATERFALL"), os.Getenv("LATE_FEE_RATE"))
+	if err != nil {
+		log.Fatal("Invalid REPAYMENT_WATERFALL or LATE_FEE_RATE:", err)
+	}
+
+	// Load the permission matrix, falling back to the built-in one
+	if policyFile := os.Getenv("AUTHZ_POLICY_FILE"); policyFile != "" {
+		serviceFactory.AccessPolicy, err = authz.LoadPolicy(policyFile)
+		if err != nil {
+			log.Fatal("Invalid AUTHZ_POLICY_FILE:", err)
+		}
+	}
+
+	// Create the first admin account; everyone else is created by an admin or registers
+	if adminEmail := os.Getenv("BOOTSTRAP_ADMIN_EMAIL"); adminEmail != "" {
+		if err := serviceFactory.UserService().BootstrapAdmin(context.Background(), adminEmail, ""); err != nil {
+			log.Fatal("Failed to bootstrap admin:", err)
+		}
+	}
+
+	// Check requests against the OpenAPI document before they reach a handler
+	var routerOptions []handlers.RouterOption
+	if value := os.Getenv("OPENAPI_VALIDATE_REQUESTS"); value != "" {
+		validate, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatal("Invalid OPENAPI_VALIDATE_REQUESTS:", err)
+		}
+		if validate {
+			routerOptions = append(routerOptions, handlers.WithRequestValidation())
+		}
+	}
+
+	// The same routes cmd/server serves
+	router := handlers.NewRouter(serviceFactory, routerOptions...)
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -102,7 +93,8 @@ func main() {
 	}
 
 	fmt.Printf("Server starting on port %s\n", port)
-	if err := http.ListenAndServe(":"+port, r); err != nil {
+	if err := http.ListenAndServe(":"+port, router); err != nil {
 		log.Fatal("Server failed to start:", err)
 	}
 }
+
@@ -0,0 +1,65 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE loans
+    ADD COLUMN IF NOT EXISTS tenor_months INTEGER NOT NULL DEFAULT 12,
+    ADD COLUMN IF NOT EXISTS repayment_method VARCHAR(20) NOT NULL DEFAULT 'flat';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE loans
+    ADD CONSTRAINT loans_tenor_months_check CHECK (tenor_months BETWEEN 1 AND 360),
+    ADD CONSTRAINT loans_repayment_method_check CHECK (repayment_method IN ('flat', 'annuity', 'bullet'));
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS loan_installments (
+    id SERIAL PRIMARY KEY,
+    loan_id INTEGER NOT NULL,
+    installment_number INTEGER NOT NULL,
+    due_date DATE NOT NULL,
+    principal_amount DECIMAL(15, 2) NOT NULL,
+    interest_amount DECIMAL(15, 2) NOT NULL,
+    total_amount DECIMAL(15, 2) NOT NULL,
+    status VARCHAR(20) DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (loan_id) REFERENCES loans(id) ON DELETE CASCADE,
+    UNIQUE(loan_id, installment_number),
+    CHECK (total_amount = principal_amount + interest_amount)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_loan_installments_loan_id ON loan_installments(loan_id);
+CREATE INDEX IF NOT EXISTS idx_loan_installments_due_date ON loan_installments(due_date);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS update_loan_installments_updated_at ON loan_installments;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER update_loan_installments_updated_at BEFORE UPDATE ON loan_installments FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS update_loan_installments_updated_at ON loan_installments;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_loan_installments_due_date;
+DROP INDEX IF EXISTS idx_loan_installments_loan_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS loan_installments;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE loans
+    DROP CONSTRAINT IF EXISTS loans_repayment_method_check,
+    DROP CONSTRAINT IF EXISTS loans_tenor_months_check,
+    DROP COLUMN IF EXISTS repayment_method,
+    DROP COLUMN IF EXISTS tenor_months;
+-- +goose StatementEnd
@@ -0,0 +1,68 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE loan_installments
+    ADD COLUMN IF NOT EXISTS penalty_amount DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    ADD COLUMN IF NOT EXISTS principal_paid DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    ADD COLUMN IF NOT EXISTS interest_paid DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    ADD COLUMN IF NOT EXISTS penalty_paid DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    ADD COLUMN IF NOT EXISTS paid_at TIMESTAMP WITH TIME ZONE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE loan_installments
+    ADD CONSTRAINT loan_installments_paid_check CHECK (
+        principal_paid BETWEEN 0 AND principal_amount
+        AND interest_paid BETWEEN 0 AND interest_amount
+        AND penalty_paid BETWEEN 0 AND penalty_amount
+    );
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE borrowers
+    ADD COLUMN IF NOT EXISTS credit_balance DECIMAL(15, 2) NOT NULL DEFAULT 0.00;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS loan_repayments (
+    id SERIAL PRIMARY KEY,
+    loan_id INTEGER NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL,
+    penalty_amount DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    interest_amount DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    principal_amount DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    credit_amount DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    paid_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (loan_id) REFERENCES loans(id) ON DELETE CASCADE,
+    CHECK (amount > 0),
+    CHECK (amount = penalty_amount + interest_amount + principal_amount + credit_amount)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_loan_repayments_loan_id ON loan_repayments(loan_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_loan_repayments_loan_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS loan_repayments;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE borrowers
+    DROP COLUMN IF EXISTS credit_balance;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE loan_installments
+    DROP CONSTRAINT IF EXISTS loan_installments_paid_check,
+    DROP COLUMN IF EXISTS paid_at,
+    DROP COLUMN IF EXISTS penalty_paid,
+    DROP COLUMN IF EXISTS interest_paid,
+    DROP COLUMN IF EXISTS principal_paid,
+    DROP COLUMN IF EXISTS penalty_amount;
+-- +goose StatementEnd
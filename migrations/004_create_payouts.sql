@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS payouts (
+    id SERIAL PRIMARY KEY,
+    loan_repayment_id INTEGER NOT NULL,
+    loan_id INTEGER NOT NULL,
+    loan_investment_id INTEGER NOT NULL,
+    investor_id INTEGER NOT NULL,
+    principal_amount DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    interest_amount DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    amount DECIMAL(15, 2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (loan_repayment_id) REFERENCES loan_repayments(id) ON DELETE CASCADE,
+    FOREIGN KEY (loan_id) REFERENCES loans(id) ON DELETE CASCADE,
+    FOREIGN KEY (loan_investment_id) REFERENCES loan_investments(id) ON DELETE CASCADE,
+    FOREIGN KEY (investor_id) REFERENCES investors(id) ON DELETE CASCADE,
+    UNIQUE(loan_repayment_id, loan_investment_id),
+    CHECK (principal_amount >= 0 AND interest_amount >= 0),
+    CHECK (amount = principal_amount + interest_amount)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS platform_revenues (
+    id SERIAL PRIMARY KEY,
+    loan_repayment_id INTEGER NOT NULL UNIQUE,
+    loan_id INTEGER NOT NULL,
+    margin_amount DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    penalty_amount DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    amount DECIMAL(15, 2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (loan_repayment_id) REFERENCES loan_repayments(id) ON DELETE CASCADE,
+    FOREIGN KEY (loan_id) REFERENCES loans(id) ON DELETE CASCADE,
+    CHECK (margin_amount >= 0 AND penalty_amount >= 0),
+    CHECK (amount = margin_amount + penalty_amount)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_payouts_investor_id ON payouts(investor_id);
+CREATE INDEX IF NOT EXISTS idx_payouts_loan_id ON payouts(loan_id);
+CREATE INDEX IF NOT EXISTS idx_platform_revenues_loan_id ON platform_revenues(loan_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_platform_revenues_loan_id;
+DROP INDEX IF EXISTS idx_payouts_loan_id;
+DROP INDEX IF EXISTS idx_payouts_investor_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS platform_revenues;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS payouts;
+-- +goose StatementEnd
@@ -0,0 +1,114 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (type IN ('asset', 'liability', 'equity', 'revenue', 'expense'))
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO ledger_accounts (code, name, type) VALUES
+    ('cash', 'Cash', 'asset'),
+    ('loans_receivable', 'Loans receivable', 'asset'),
+    ('investor_funds', 'Investor funds awaiting disbursement', 'liability'),
+    ('investor_capital', 'Investor capital lent out', 'liability'),
+    ('borrower_credit', 'Borrower credit balances', 'liability'),
+    ('interest_income', 'Interest income', 'revenue'),
+    ('fee_income', 'Late fee income', 'revenue'),
+    ('investor_interest_expense', 'Interest paid to investors', 'expense')
+ON CONFLICT (code) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS journal_entries (
+    id SERIAL PRIMARY KEY,
+    reference VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    loan_id INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (loan_id) REFERENCES loans(id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS ledger_postings (
+    id SERIAL PRIMARY KEY,
+    journal_entry_id INTEGER NOT NULL,
+    account_id INTEGER NOT NULL,
+    direction VARCHAR(6) NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL,
+    FOREIGN KEY (journal_entry_id) REFERENCES journal_entries(id),
+    FOREIGN KEY (account_id) REFERENCES ledger_accounts(id),
+    CHECK (direction IN ('debit', 'credit')),
+    CHECK (amount > 0)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_journal_entries_loan_id ON journal_entries(loan_id);
+CREATE INDEX IF NOT EXISTS idx_journal_entries_reference ON journal_entries(reference);
+CREATE INDEX IF NOT EXISTS idx_ledger_postings_journal_entry_id ON ledger_postings(journal_entry_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_postings_account_id ON ledger_postings(account_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- The ledger is append-only: mistakes are corrected with reversing entries
+CREATE OR REPLACE FUNCTION prevent_ledger_modification()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'Ledger records are append-only';
+END;
+$$ language 'plpgsql';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER journal_entries_append_only BEFORE UPDATE OR DELETE ON journal_entries FOR EACH ROW EXECUTE FUNCTION prevent_ledger_modification();
+CREATE TRIGGER ledger_postings_append_only BEFORE UPDATE OR DELETE ON ledger_postings FOR EACH ROW EXECUTE FUNCTION prevent_ledger_modification();
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Checked at commit so every posting of an entry can be inserted first
+CREATE OR REPLACE FUNCTION validate_journal_entry_balance()
+RETURNS TRIGGER AS $$
+DECLARE
+    difference DECIMAL(15, 2);
+BEGIN
+    SELECT COALESCE(SUM(CASE WHEN direction = 'debit' THEN amount ELSE -amount END), 0)
+    INTO difference
+    FROM ledger_postings
+    WHERE journal_entry_id = NEW.journal_entry_id;
+
+    IF difference <> 0 THEN
+        RAISE EXCEPTION 'Journal entry % is unbalanced by %', NEW.journal_entry_id, difference;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE CONSTRAINT TRIGGER ledger_postings_balanced
+    AFTER INSERT ON ledger_postings
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION validate_journal_entry_balance();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS ledger_postings_balanced ON ledger_postings;
+DROP TRIGGER IF EXISTS ledger_postings_append_only ON ledger_postings;
+DROP TRIGGER IF EXISTS journal_entries_append_only ON journal_entries;
+DROP FUNCTION IF EXISTS validate_journal_entry_balance();
+DROP FUNCTION IF EXISTS prevent_ledger_modification();
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS ledger_postings;
+DROP TABLE IF EXISTS journal_entries;
+DROP TABLE IF EXISTS ledger_accounts;
+-- +goose StatementEnd
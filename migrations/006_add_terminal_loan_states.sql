@@ -0,0 +1,76 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE loans ADD CONSTRAINT loans_current_state_check CHECK (current_state IN (
+    'proposed', 'approved', 'invested', 'disbursed',
+    'repaid', 'closed', 'defaulted', 'written_off',
+    'rejected', 'cancelled', 'expired'
+));
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION validate_loan_state_transition()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.current_state = OLD.current_state THEN
+        RETURN NEW;
+    END IF;
+
+    -- Mirrors the loan service's transition table
+    IF NOT (
+        (OLD.current_state = 'proposed' AND NEW.current_state IN ('approved', 'rejected', 'cancelled', 'expired')) OR
+        (OLD.current_state = 'approved' AND NEW.current_state IN ('invested', 'rejected', 'cancelled', 'expired')) OR
+        (OLD.current_state = 'invested' AND NEW.current_state IN ('disbursed', 'cancelled')) OR
+        (OLD.current_state = 'disbursed' AND NEW.current_state IN ('repaid', 'defaulted')) OR
+        (OLD.current_state = 'repaid' AND NEW.current_state = 'closed') OR
+        (OLD.current_state = 'defaulted' AND NEW.current_state = 'written_off')
+    ) THEN
+        RAISE EXCEPTION 'Loan cannot move from % state to % state', OLD.current_state, NEW.current_state;
+    END IF;
+
+    -- Insert record into loan_state_history
+    INSERT INTO loan_state_history (loan_id, old_state, new_state, changed_by, reason)
+    VALUES (NEW.id, OLD.current_state, NEW.current_state, 'system', 'State transition');
+
+    RETURN NEW;
+END;
+$$ LANGUAGE 'plpgsql';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION validate_loan_state_transition()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- Only allow state transitions to move forward
+    IF NEW.current_state = 'proposed' THEN
+        -- Cannot go back to proposed
+        RETURN OLD;
+    ELSIF NEW.current_state = 'approved' THEN
+        -- Can only transition from proposed
+        IF OLD.current_state != 'proposed' THEN
+            RAISE EXCEPTION 'Loan can only be approved from proposed state';
+        END IF;
+    ELSIF NEW.current_state = 'invested' THEN
+        -- Can only transition from approved
+        IF OLD.current_state != 'approved' THEN
+            RAISE EXCEPTION 'Loan can only be invested from approved state';
+        END IF;
+    ELSIF NEW.current_state = 'disbursed' THEN
+        -- Can only transition from invested
+        IF OLD.current_state != 'invested' THEN
+            RAISE EXCEPTION 'Loan can only be disbursed from invested state';
+        END IF;
+    END IF;
+
+    -- Insert record into loan_state_history
+    INSERT INTO loan_state_history (loan_id, old_state, new_state, changed_by, reason)
+    VALUES (NEW.id, OLD.current_state, NEW.current_state, 'system', 'State transition');
+
+    RETURN NEW;
+END;
+$$ LANGUAGE 'plpgsql';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE loans DROP CONSTRAINT IF EXISTS loans_current_state_check;
+-- +goose StatementEnd
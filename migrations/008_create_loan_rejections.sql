@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS loan_rejection_reasons (
+    code VARCHAR(50) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO loan_rejection_reasons (code, description) VALUES
+    ('insufficient_income', 'Income is too low for the requested principal'),
+    ('incomplete_documents', 'Required documents are missing or incomplete'),
+    ('poor_credit_history', 'Credit history does not meet the lending criteria'),
+    ('unverifiable_identity', 'Borrower identity could not be verified'),
+    ('failed_field_validation', 'Field validation found the application inaccurate'),
+    ('other', 'Other reason, described in the notes')
+ON CONFLICT (code) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS loan_rejections (
+    id SERIAL PRIMARY KEY,
+    loan_id INTEGER NOT NULL UNIQUE,
+    reason_code VARCHAR(50) NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    rejected_by_employee_id VARCHAR(50) NOT NULL,
+    rejected_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (loan_id) REFERENCES loans(id) ON DELETE CASCADE,
+    FOREIGN KEY (reason_code) REFERENCES loan_rejection_reasons(code)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_loan_rejections_reason_code ON loan_rejections(reason_code);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER update_loan_rejection_reasons_updated_at BEFORE UPDATE ON loan_rejection_reasons FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS update_loan_rejection_reasons_updated_at ON loan_rejection_reasons;
+DROP INDEX IF EXISTS idx_loan_rejections_reason_code;
+DROP TABLE IF EXISTS loan_rejections;
+DROP TABLE IF EXISTS loan_rejection_reasons;
+-- +goose StatementEnd
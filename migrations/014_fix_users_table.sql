@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+-- models.User has always read user_type and is_active, but the table was created with a role
+-- column instead. Carry role over; the old default 'user' meant an ordinary investor.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS user_type VARCHAR(20);
+UPDATE users SET user_type = CASE WHEN role IN ('admin', 'staff', 'investor') THEN role ELSE 'investor' END
+WHERE user_type IS NULL;
+ALTER TABLE users ALTER COLUMN user_type SET NOT NULL;
+ALTER TABLE users ADD CONSTRAINT users_user_type_check CHECK (user_type IN ('admin', 'staff', 'investor'));
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_active BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- staff_role is scanned into a string, so it cannot be NULL
+UPDATE users SET staff_role = '' WHERE staff_role IS NULL;
+ALTER TABLE users ALTER COLUMN staff_role SET DEFAULT '';
+ALTER TABLE users ALTER COLUMN staff_role SET NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Deleted users are kept for the audit trail, and their email can be registered again
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_not_deleted ON users(email) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_users_user_type ON users(user_type);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_users_user_type;
+DROP INDEX IF EXISTS idx_users_email_not_deleted;
+-- The old schema has no soft delete, and deleted users may share an email with a live one
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE users ALTER COLUMN staff_role DROP NOT NULL;
+ALTER TABLE users ALTER COLUMN staff_role DROP DEFAULT;
+
+ALTER TABLE users DROP COLUMN IF EXISTS is_active;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) DEFAULT 'user';
+UPDATE users SET role = user_type;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_user_type_check;
+ALTER TABLE users DROP COLUMN IF EXISTS user_type;
+-- +goose StatementEnd
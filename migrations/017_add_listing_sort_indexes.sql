@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Keyset pagination compares sort values, which NULLs would slip past
+UPDATE loans SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+UPDATE borrowers SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+UPDATE investors SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+ALTER TABLE loans ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE borrowers ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE investors ALTER COLUMN created_at SET NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Listings sort by a field and then by id; these back the default and common sorts
+CREATE INDEX IF NOT EXISTS idx_loans_created_at_id ON loans(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_loans_principal_amount_id ON loans(principal_amount, id);
+CREATE INDEX IF NOT EXISTS idx_borrowers_created_at_id ON borrowers(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_investors_created_at_id ON investors(created_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_investors_created_at_id;
+DROP INDEX IF EXISTS idx_borrowers_created_at_id;
+DROP INDEX IF EXISTS idx_loans_principal_amount_id;
+DROP INDEX IF EXISTS idx_loans_created_at_id;
+ALTER TABLE investors ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE borrowers ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE loans ALTER COLUMN created_at DROP NOT NULL;
+-- +goose StatementEnd
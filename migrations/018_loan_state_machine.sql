@@ -0,0 +1,66 @@
+-- Code generated by cmd/loanstategen from internal/loanstate; DO NOT EDIT.
+
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE loans DROP CONSTRAINT IF EXISTS loans_current_state_check;
+ALTER TABLE loans ADD CONSTRAINT loans_current_state_check CHECK (current_state IN ('proposed', 'approved', 'invested', 'disbursed', 'repaid', 'closed', 'defaulted', 'written_off', 'rejected', 'cancelled', 'expired'));
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION validate_loan_state_transition()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.current_state = OLD.current_state THEN
+        RAISE EXCEPTION 'Loan is already in % state', OLD.current_state;
+    END IF;
+
+    IF NOT (
+        (OLD.current_state = 'proposed' AND NEW.current_state IN ('approved', 'rejected', 'cancelled', 'expired')) OR
+        (OLD.current_state = 'approved' AND NEW.current_state IN ('invested', 'rejected', 'cancelled', 'expired')) OR
+        (OLD.current_state = 'invested' AND NEW.current_state IN ('disbursed', 'cancelled')) OR
+        (OLD.current_state = 'disbursed' AND NEW.current_state IN ('repaid', 'defaulted')) OR
+        (OLD.current_state = 'repaid' AND NEW.current_state IN ('closed')) OR
+        (OLD.current_state = 'defaulted' AND NEW.current_state IN ('written_off'))
+    ) THEN
+        RAISE EXCEPTION 'Loan cannot move from % state to % state', OLD.current_state, NEW.current_state;
+    END IF;
+
+    -- The loan service records the transition in loan_state_history along with who made it
+    RETURN NEW;
+END;
+$$ LANGUAGE 'plpgsql';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE loans DROP CONSTRAINT IF EXISTS loans_current_state_check;
+ALTER TABLE loans ADD CONSTRAINT loans_current_state_check CHECK (current_state IN ('proposed', 'approved', 'invested', 'disbursed', 'repaid', 'closed', 'defaulted', 'written_off', 'rejected', 'cancelled', 'expired'));
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION validate_loan_state_transition()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.current_state = OLD.current_state THEN
+        RETURN NEW;
+    END IF;
+
+    IF NOT (
+        (OLD.current_state = 'proposed' AND NEW.current_state IN ('approved', 'rejected', 'cancelled', 'expired')) OR
+        (OLD.current_state = 'approved' AND NEW.current_state IN ('invested', 'rejected', 'cancelled', 'expired')) OR
+        (OLD.current_state = 'invested' AND NEW.current_state IN ('disbursed', 'cancelled')) OR
+        (OLD.current_state = 'disbursed' AND NEW.current_state IN ('repaid', 'defaulted')) OR
+        (OLD.current_state = 'repaid' AND NEW.current_state IN ('closed')) OR
+        (OLD.current_state = 'defaulted' AND NEW.current_state IN ('written_off'))
+    ) THEN
+        RAISE EXCEPTION 'Loan cannot move from % state to % state', OLD.current_state, NEW.current_state;
+    END IF;
+
+    -- Insert record into loan_state_history
+    INSERT INTO loan_state_history (loan_id, old_state, new_state, changed_by, reason)
+    VALUES (NEW.id, OLD.current_state, NEW.current_state, 'system', 'State transition');
+
+    RETURN NEW;
+END;
+$$ LANGUAGE 'plpgsql';
+-- +goose StatementEnd
@@ -4,12 +4,16 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 )
 
 type EmailService interface {
 	SendInvestmentConfirmation(ctx context.Context, toEmail, agreementLink, loanDetails string) error
 	SendDisbursementNotification(ctx context.Context, toEmail, loanDetails string) error
 	SendApprovalNotification(ctx context.Context, toEmail, loanDetails string) error
+	SendRejectionNotification(ctx context.Context, toEmail, reason, loanDetails string) error
+	SendPasswordReset(ctx context.Context, toEmail, resetToken string, expiresIn time.Duration) error
+	SendEmailVerification(ctx context.Context, toEmail, verificationToken string, expiresIn time.Duration) error
 }
 
 type MockEmailService struct {
@@ -74,6 +78,45 @@ func (m *MockEmailService) SendApprovalNotification(ctx context.Context, toEmail
 	return nil
 }
 
+func (m *MockEmailService) SendRejectionNotification(ctx context.Context, toEmail, reason, loanDetails string) error {
+	email := SentEmail{
+		To:      toEmail,
+		Subject: "Loan Rejection Notification",
+		Body:    fmt.Sprintf("Loan has been rejected. Reason: %s. Details: %s", reason, loanDetails),
+	}
+
+	m.SentEmails = append(m.SentEmails, email)
+	log.Printf("[MOCK] Sent rejection notification to %s", toEmail)
+
+	return nil
+}
+
+func (m *MockEmailService) SendPasswordReset(ctx context.Context, toEmail, resetToken string, expiresIn time.Duration) error {
+	email := SentEmail{
+		To:      toEmail,
+		Subject: "Password Reset",
+		Body:    fmt.Sprintf("Use this token to reset your password within %s: %s. If you did not ask for a reset, ignore this email.", expiresIn, resetToken),
+	}
+
+	m.SentEmails = append(m.SentEmails, email)
+	log.Printf("[MOCK] Sent password reset to %s with token: %s", toEmail, resetToken)
+
+	return nil
+}
+
+func (m *MockEmailService) SendEmailVerification(ctx context.Context, toEmail, verificationToken string, expiresIn time.Duration) error {
+	email := SentEmail{
+		To:      toEmail,
+		Subject: "Verify Your Email",
+		Body:    fmt.Sprintf("Use this token to verify your email within %s: %s. If you did not register, ignore this email.", expiresIn, verificationToken),
+	}
+
+	m.SentEmails = append(m.SentEmails, email)
+	log.Printf("[MOCK] Sent email verification to %s with token: %s", toEmail, verificationToken)
+
+	return nil
+}
+
 // Helper method to get sent emails for testing
 func (m *MockEmailService) GetSentEmails() []SentEmail {
 	return m.SentEmails
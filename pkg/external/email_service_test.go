@@ -3,6 +3,7 @@ package external
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -63,6 +64,64 @@ func TestMockEmailServiceSendApprovalNotification(t *testing.T) {
 	assert.Contains(t, sentEmail.Body, loanDetails)
 }
 
+func TestMockEmailServiceSendRejectionNotification(t *testing.T) {
+	emailService := NewMockEmailService()
+
+	ctx := context.Background()
+	toEmail := "borrower@example.com"
+	reason := "Income is too low for the requested principal"
+	loanDetails := "Loan has been rejected"
+
+	err := emailService.SendRejectionNotification(ctx, toEmail, reason, loanDetails)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(emailService.SentEmails))
+
+	sentEmail := emailService.SentEmails[0]
+	assert.Equal(t, toEmail, sentEmail.To)
+	assert.Equal(t, "Loan Rejection Notification", sentEmail.Subject)
+	assert.Contains(t, sentEmail.Body, reason)
+	assert.Contains(t, sentEmail.Body, loanDetails)
+}
+
+func TestMockEmailServiceSendPasswordReset(t *testing.T) {
+	emailService := NewMockEmailService()
+
+	ctx := context.Background()
+	toEmail := "user@example.com"
+	resetToken := "reset-token"
+
+	err := emailService.SendPasswordReset(ctx, toEmail, resetToken, time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(emailService.SentEmails))
+
+	sentEmail := emailService.SentEmails[0]
+	assert.Equal(t, toEmail, sentEmail.To)
+	assert.Equal(t, "Password Reset", sentEmail.Subject)
+	assert.Contains(t, sentEmail.Body, resetToken)
+	assert.Contains(t, sentEmail.Body, "1h0m0s")
+}
+
+func TestMockEmailServiceSendEmailVerification(t *testing.T) {
+	emailService := NewMockEmailService()
+
+	ctx := context.Background()
+	toEmail := "investor@example.com"
+	verificationToken := "verification-token"
+
+	err := emailService.SendEmailVerification(ctx, toEmail, verificationToken, 24*time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(emailService.SentEmails))
+
+	sentEmail := emailService.SentEmails[0]
+	assert.Equal(t, toEmail, sentEmail.To)
+	assert.Equal(t, "Verify Your Email", sentEmail.Subject)
+	assert.Contains(t, sentEmail.Body, verificationToken)
+	assert.Contains(t, sentEmail.Body, "24h0m0s")
+}
+
 func TestMockEmailServiceMultipleEmails(t *testing.T) {
 	emailService := NewMockEmailService()
 
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -163,6 +164,75 @@ func (_c *EmailService_SendDisbursementNotification_Call) RunAndReturn(run func(
 	return _c
 }
 
+// SendEmailVerification provides a mock function for the type EmailService
+func (_mock *EmailService) SendEmailVerification(ctx context.Context, toEmail string, verificationToken string, expiresIn time.Duration) error {
+	ret := _mock.Called(ctx, toEmail, verificationToken, expiresIn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendEmailVerification")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) error); ok {
+		r0 = returnFunc(ctx, toEmail, verificationToken, expiresIn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// EmailService_SendEmailVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendEmailVerification'
+type EmailService_SendEmailVerification_Call struct {
+	*mock.Call
+}
+
+// SendEmailVerification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - toEmail string
+//   - verificationToken string
+//   - expiresIn time.Duration
+func (_e *EmailService_Expecter) SendEmailVerification(ctx interface{}, toEmail interface{}, verificationToken interface{}, expiresIn interface{}) *EmailService_SendEmailVerification_Call {
+	return &EmailService_SendEmailVerification_Call{Call: _e.mock.On("SendEmailVerification", ctx, toEmail, verificationToken, expiresIn)}
+}
+
+func (_c *EmailService_SendEmailVerification_Call) Run(run func(ctx context.Context, toEmail string, verificationToken string, expiresIn time.Duration)) *EmailService_SendEmailVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 time.Duration
+		if args[3] != nil {
+			arg3 = args[3].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *EmailService_SendEmailVerification_Call) Return(err error) *EmailService_SendEmailVerification_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *EmailService_SendEmailVerification_Call) RunAndReturn(run func(ctx context.Context, toEmail string, verificationToken string, expiresIn time.Duration) error) *EmailService_SendEmailVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SendInvestmentConfirmation provides a mock function for the type EmailService
 func (_mock *EmailService) SendInvestmentConfirmation(ctx context.Context, toEmail string, agreementLink string, loanDetails string) error {
 	ret := _mock.Called(ctx, toEmail, agreementLink, loanDetails)
@@ -231,3 +301,141 @@ func (_c *EmailService_SendInvestmentConfirmation_Call) RunAndReturn(run func(ct
 	_c.Call.Return(run)
 	return _c
 }
+
+// SendPasswordReset provides a mock function for the type EmailService
+func (_mock *EmailService) SendPasswordReset(ctx context.Context, toEmail string, resetToken string, expiresIn time.Duration) error {
+	ret := _mock.Called(ctx, toEmail, resetToken, expiresIn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendPasswordReset")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) error); ok {
+		r0 = returnFunc(ctx, toEmail, resetToken, expiresIn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// EmailService_SendPasswordReset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendPasswordReset'
+type EmailService_SendPasswordReset_Call struct {
+	*mock.Call
+}
+
+// SendPasswordReset is a helper method to define mock.On call
+//   - ctx context.Context
+//   - toEmail string
+//   - resetToken string
+//   - expiresIn time.Duration
+func (_e *EmailService_Expecter) SendPasswordReset(ctx interface{}, toEmail interface{}, resetToken interface{}, expiresIn interface{}) *EmailService_SendPasswordReset_Call {
+	return &EmailService_SendPasswordReset_Call{Call: _e.mock.On("SendPasswordReset", ctx, toEmail, resetToken, expiresIn)}
+}
+
+func (_c *EmailService_SendPasswordReset_Call) Run(run func(ctx context.Context, toEmail string, resetToken string, expiresIn time.Duration)) *EmailService_SendPasswordReset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 time.Duration
+		if args[3] != nil {
+			arg3 = args[3].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *EmailService_SendPasswordReset_Call) Return(err error) *EmailService_SendPasswordReset_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *EmailService_SendPasswordReset_Call) RunAndReturn(run func(ctx context.Context, toEmail string, resetToken string, expiresIn time.Duration) error) *EmailService_SendPasswordReset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SendRejectionNotification provides a mock function for the type EmailService
+func (_mock *EmailService) SendRejectionNotification(ctx context.Context, toEmail string, reason string, loanDetails string) error {
+	ret := _mock.Called(ctx, toEmail, reason, loanDetails)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendRejectionNotification")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = returnFunc(ctx, toEmail, reason, loanDetails)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// EmailService_SendRejectionNotification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendRejectionNotification'
+type EmailService_SendRejectionNotification_Call struct {
+	*mock.Call
+}
+
+// SendRejectionNotification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - toEmail string
+//   - reason string
+//   - loanDetails string
+func (_e *EmailService_Expecter) SendRejectionNotification(ctx interface{}, toEmail interface{}, reason interface{}, loanDetails interface{}) *EmailService_SendRejectionNotification_Call {
+	return &EmailService_SendRejectionNotification_Call{Call: _e.mock.On("SendRejectionNotification", ctx, toEmail, reason, loanDetails)}
+}
+
+func (_c *EmailService_SendRejectionNotification_Call) Run(run func(ctx context.Context, toEmail string, reason string, loanDetails string)) *EmailService_SendRejectionNotification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *EmailService_SendRejectionNotification_Call) Return(err error) *EmailService_SendRejectionNotification_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *EmailService_SendRejectionNotification_Call) RunAndReturn(run func(ctx context.Context, toEmail string, reason string, loanDetails string) error) *EmailService_SendRejectionNotification_Call {
+	_c.Call.Return(run)
+	return _c
+}
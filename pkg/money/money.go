@@ -0,0 +1,274 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// DefaultCurrency is the currency of every amount stored by the engine. The amount
+// columns carry no currency of their own, so scanned values are tagged with it.
+const DefaultCurrency = "IDR"
+
+// minorUnitsPerMajor matches the two decimal places of the DECIMAL(15, 2) amount columns
+const minorUnitsPerMajor = 100
+
+// decimalLiteral is the only form amounts and rates are read in: no fractions such as
+// "1/2", no exponents such as "1e5" and no surrounding spaces
+var decimalLiteral = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// Money is an exact amount of a currency held in minor units (cents). It is stored
+// as a decimal column and marshalled to JSON as a decimal string such as "1500.00".
+type Money struct {
+	amount   int64
+	currency string
+}
+
+// New returns an amount of minor units in the given currency
+func New(amount int64, currency string) Money {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	return Money{amount: amount, currency: currency}
+}
+
+// Zero returns a zero amount in the given currency
+func Zero(currency string) Money {
+	return New(0, currency)
+}
+
+// Parse reads a decimal amount such as "1500", "1500.5" or "1500.50". Amounts with
+// more precision than the currency's minor unit are rejected rather than rounded.
+func Parse(s, currency string) (Money, error) {
+	if !decimalLiteral.MatchString(s) {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+
+	minor := r.Mul(r, big.NewRat(minorUnitsPerMajor, 1))
+	if !minor.IsInt() {
+		return Money{}, fmt.Errorf("amount %q has more than 2 decimal places", s)
+	}
+	if !minor.Num().IsInt64() {
+		return Money{}, fmt.Errorf("amount %q is out of range", s)
+	}
+
+	return New(minor.Num().Int64(), currency), nil
+}
+
+// MustParse is like Parse in the default currency but panics on invalid input.
+// It is intended for constants and tests.
+func MustParse(s string) Money {
+	m, err := Parse(s, DefaultCurrency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Amount returns the amount in minor units
+func (m Money) Amount() int64 {
+	return m.amount
+}
+
+// Currency returns the ISO 4217 currency code, which is DefaultCurrency for the zero value
+func (m Money) Currency() string {
+	if m.currency == "" {
+		return DefaultCurrency
+	}
+	return m.currency
+}
+
+// SameCurrency reports whether m and other can be combined
+func (m Money) SameCurrency(other Money) bool {
+	return m.Currency() == other.Currency()
+}
+
+// Add returns m + other. Mixing currencies is a programming error and panics.
+func (m Money) Add(other Money) Money {
+	m.mustMatch(other)
+	return New(m.amount+other.amount, m.Currency())
+}
+
+// Sub returns m - other. Mixing currencies is a programming error and panics.
+func (m Money) Sub(other Money) Money {
+	m.mustMatch(other)
+	return New(m.amount-other.amount, m.Currency())
+}
+
+// Cmp returns -1, 0 or +1 as m is less than, equal to or greater than other
+func (m Money) Cmp(other Money) int {
+	m.mustMatch(other)
+	switch {
+	case m.amount < other.amount:
+		return -1
+	case m.amount > other.amount:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (m Money) Equal(other Money) bool {
+	return m.SameCurrency(other) && m.amount == other.amount
+}
+
+func (m Money) LessThan(other Money) bool {
+	return m.Cmp(other) < 0
+}
+
+func (m Money) GreaterThan(other Money) bool {
+	return m.Cmp(other) > 0
+}
+
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.amount > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+// Allocate splits m in proportion to weights using the largest remainder method, so
+// the parts always add back up to m. Each part gets its floored share and the cents
+// left over go one at a time to the largest remainders, earlier weights winning ties.
+// Weights must be non-negative with a positive sum; anything else panics.
+func (m Money) Allocate(weights []int64) []Money {
+	total := new(big.Int)
+	for _, weight := range weights {
+		if weight < 0 {
+			panic("money: allocation weights must not be negative")
+		}
+		total.Add(total, big.NewInt(weight))
+	}
+	if total.Sign() == 0 {
+		panic("money: allocation weights must have a positive sum")
+	}
+
+	parts := make([]Money, len(weights))
+	remainders := make([]*big.Int, len(weights))
+	allocated := int64(0)
+	for i, weight := range weights {
+		share := new(big.Int).Mul(big.NewInt(m.amount), big.NewInt(weight))
+		remainders[i] = new(big.Int)
+		share.DivMod(share, total, remainders[i])
+		parts[i] = New(share.Int64(), m.Currency())
+		allocated += share.Int64()
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].Cmp(remainders[order[b]]) > 0
+	})
+	for i := int64(0); i < m.amount-allocated; i++ {
+		parts[order[i]].amount++
+	}
+
+	return parts
+}
+
+// String formats the amount as a plain decimal, e.g. "1500.00"
+func (m Money) String() string {
+	sign := ""
+	amount := m.amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/minorUnitsPerMajor, amount%minorUnitsPerMajor)
+}
+
+func (m Money) mustMatch(other Money) {
+	if !m.SameCurrency(other) {
+		panic(fmt.Sprintf("money: currency mismatch %s vs %s", m.Currency(), other.Currency()))
+	}
+}
+
+// Value implements driver.Valuer; Postgres parses the decimal string into NUMERIC exactly
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns
+func (m *Money) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*m = Zero(DefaultCurrency)
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("cannot scan %T into money.Money", src)
+	}
+
+	parsed, err := Parse(s, DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// MarshalJSON emits the amount as a decimal string so clients never see a float
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON accepts a decimal string or a JSON number. Numbers are read from
+// their literal text, so 0.1 is exactly ten cents.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	s, err := decimalText(data)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := Parse(s, DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// decimalText returns the decimal literal of a JSON string or number
+func decimalText(data []byte) (string, error) {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return "", err
+		}
+		return s, nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return "", err
+	}
+	return n.String(), nil
+}
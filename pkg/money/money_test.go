@@ -0,0 +1,131 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		minor int64
+	}{
+		{"1500", 150000},
+		{"1500.5", 150050},
+		{"1500.50", 150050},
+		{"0.01", 1},
+		{"-12.34", -1234},
+	}
+
+	for _, tt := range tests {
+		m, err := Parse(tt.input, "")
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.minor, m.Amount(), tt.input)
+		assert.Equal(t, DefaultCurrency, m.Currency())
+	}
+}
+
+func TestParseRejectsInvalidAmounts(t *testing.T) {
+	// Only plain decimal literals are amounts
+	for _, input := range []string{"10.005", "ten", "1/2", "1e5", " 1", "1 ", "+1", "1.", ".5", ""} {
+		_, err := Parse(input, DefaultCurrency)
+		assert.Error(t, err, "%q", input)
+	}
+}
+
+func TestArithmeticIsExact(t *testing.T) {
+	total := Zero(DefaultCurrency)
+	for i := 0; i < 10; i++ {
+		total = total.Add(MustParse("0.10"))
+	}
+
+	assert.True(t, total.Equal(MustParse("1.00")))
+	assert.Equal(t, "1.00", total.String())
+	assert.Equal(t, "-0.05", MustParse("0.10").Sub(MustParse("0.15")).String())
+}
+
+func TestAllocateKeepsEveryCent(t *testing.T) {
+	parts := MustParse("100.00").Allocate([]int64{1, 1, 1})
+	assert.Equal(t, []Money{MustParse("33.34"), MustParse("33.33"), MustParse("33.33")}, parts)
+
+	// The leftover cent goes to the largest remainder, not the first weight
+	parts = MustParse("0.01").Allocate([]int64{1, 2})
+	assert.Equal(t, []Money{MustParse("0.00"), MustParse("0.01")}, parts)
+
+	parts = MustParse("1000.01").Allocate([]int64{300000, 0, 700000})
+	assert.Equal(t, []Money{MustParse("300.00"), MustParse("0.00"), MustParse("700.01")}, parts)
+
+	assert.Panics(t, func() { MustParse("1.00").Allocate([]int64{0, 0}) })
+}
+
+func TestZeroValueUsesDefaultCurrency(t *testing.T) {
+	var m Money
+
+	assert.Equal(t, DefaultCurrency, m.Currency())
+	assert.Equal(t, MustParse("5.00"), m.Add(MustParse("5.00")))
+}
+
+func TestCurrencyMismatchPanics(t *testing.T) {
+	usd := New(100, "USD")
+
+	assert.False(t, usd.SameCurrency(MustParse("1.00")))
+	assert.Panics(t, func() { usd.Add(MustParse("1.00")) })
+}
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := json.Marshal(MustParse("1000000"))
+	require.NoError(t, err)
+	assert.Equal(t, `"1000000.00"`, string(data))
+
+	var fromString, fromNumber Money
+	require.NoError(t, json.Unmarshal([]byte(`"0.30"`), &fromString))
+	require.NoError(t, json.Unmarshal([]byte(`0.3`), &fromNumber))
+	assert.Equal(t, fromString, fromNumber)
+
+	assert.Error(t, json.Unmarshal([]byte(`0.001`), &fromNumber))
+}
+
+func TestMoneyScanAndValue(t *testing.T) {
+	var m Money
+	require.NoError(t, m.Scan([]byte("2500.75")))
+	assert.Equal(t, int64(250075), m.Amount())
+
+	value, err := m.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "2500.75", value)
+
+	require.NoError(t, m.Scan(nil))
+	assert.True(t, m.IsZero())
+	assert.Error(t, m.Scan(true))
+}
+
+func TestRate(t *testing.T) {
+	rate, err := ParseRate("0.05")
+	require.NoError(t, err)
+	assert.Equal(t, Rate(500), rate)
+	assert.Equal(t, "0.0500", rate.String())
+
+	for _, input := range []string{"0.00005", "1/2", "1e-2", " 0.05"} {
+		_, err = ParseRate(input)
+		assert.Error(t, err, "%q", input)
+	}
+}
+
+func TestRateJSONAndScan(t *testing.T) {
+	data, err := json.Marshal(MustParseRate("0.125"))
+	require.NoError(t, err)
+	assert.Equal(t, `"0.1250"`, string(data))
+
+	var fromString, fromNumber Rate
+	require.NoError(t, json.Unmarshal([]byte(`"0.08"`), &fromString))
+	require.NoError(t, json.Unmarshal([]byte(`0.08`), &fromNumber))
+	assert.Equal(t, Rate(800), fromString)
+	assert.Equal(t, fromString, fromNumber)
+
+	var scanned Rate
+	require.NoError(t, scanned.Scan([]byte("0.0525")))
+	assert.Equal(t, Rate(525), scanned)
+}
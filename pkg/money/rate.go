@@ -0,0 +1,125 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// basisPointsPerUnit converts between a fraction (0.05) and basis points (500)
+const basisPointsPerUnit = 10000
+
+// Rate is a percentage held exactly in basis points, so 500 is 5.00%. It is stored
+// in the DECIMAL(5, 4) rate columns as a fraction ("0.0500") and marshalled to JSON
+// the same way.
+type Rate int64
+
+// OneHundredPercent is the largest rate a loan may carry
+const OneHundredPercent Rate = basisPointsPerUnit
+
+// ParseRate reads a fraction such as "0.05" or "0.0525". Rates finer than one basis
+// point are rejected rather than rounded.
+func ParseRate(s string) (Rate, error) {
+	if !decimalLiteral.MatchString(s) {
+		return 0, fmt.Errorf("invalid rate %q", s)
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("invalid rate %q", s)
+	}
+
+	bps := r.Mul(r, big.NewRat(basisPointsPerUnit, 1))
+	if !bps.IsInt() {
+		return 0, fmt.Errorf("rate %q is finer than one basis point", s)
+	}
+	if !bps.Num().IsInt64() {
+		return 0, fmt.Errorf("rate %q is out of range", s)
+	}
+
+	return Rate(bps.Num().Int64()), nil
+}
+
+// MustParseRate is like ParseRate but panics on invalid input. It is intended for
+// constants and tests.
+func MustParseRate(s string) Rate {
+	r, err := ParseRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// BasisPoints returns the rate in basis points
+func (r Rate) BasisPoints() int64 {
+	return int64(r)
+}
+
+// String formats the rate as a fraction with four decimal places, e.g. "0.0500"
+func (r Rate) String() string {
+	sign := ""
+	bps := int64(r)
+	if bps < 0 {
+		sign = "-"
+		bps = -bps
+	}
+	return fmt.Sprintf("%s%d.%04d", sign, bps/basisPointsPerUnit, bps%basisPointsPerUnit)
+}
+
+// Value implements driver.Valuer
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns
+func (r *Rate) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*r = 0
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("cannot scan %T into money.Rate", src)
+	}
+
+	parsed, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// MarshalJSON emits the rate as a fraction string
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON accepts a fraction as a string or a JSON number
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	s, err := decimalText(data)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}